@@ -12,7 +12,11 @@ import (
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/notify"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -36,6 +40,9 @@ func main() {
 	ctx := context.Background()
 	dbConfig := env.NewDatabaseConfig(&ctx)
 	restConfig := env.NewRestConfig(&ctx)
+	fxConfig := env.NewFXConfig(&ctx)
+	notifyConfig := env.NewNotifyConfig(&ctx)
+	scheduleConfig := env.NewScheduleConfig(&ctx)
 
 	// Set up a database connection pool
 	db, err := sql.Open(dbConfig.Driver, dbConfig.DataSourceName())
@@ -55,13 +62,91 @@ func main() {
 	db.SetMaxOpenConns(dbConfig.MaxOpenConns)
 	db.SetMaxIdleConns(dbConfig.MaxIdleConns)
 
+	rateProvider, err := fx.New(&fxConfig)
+	if err != nil {
+		log.Fatal().
+			Caller().
+			Err(err).
+			Str("fx_provider", fxConfig.Provider).
+			Msg("Unable to set up the exchange rate provider")
+	}
+
+	notifier, err := notify.New(&notifyConfig)
+	if err != nil {
+		log.Fatal().
+			Caller().
+			Err(err).
+			Str("notifier", notifyConfig.Notifier).
+			Msg("Unable to set up the notifier")
+	}
+
+	// An empty amount disables the step-up authentication of transfers
+	stepUpPolicy, err := service.ParseStepUpPolicy(restConfig.StepUpAmount)
+	if err != nil {
+		log.Fatal().
+			Caller().
+			Err(err).
+			Str("step_up_amount", restConfig.StepUpAmount).
+			Msg("Unable to parse the step-up amount")
+	}
+
+	if restConfig.BcryptCost < bcrypt.MinCost || restConfig.BcryptCost > bcrypt.MaxCost {
+		log.Fatal().
+			Caller().
+			Int("bcrypt_cost", restConfig.BcryptCost).
+			Msgf("The bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	secretPolicy := service.SecretPolicy{
+		Strength: validation.SecretStrength{
+			MinLength:    restConfig.SecretMinLength,
+			MinClasses:   restConfig.SecretClasses,
+			RejectCommon: restConfig.SecretCommon,
+		},
+		Cost: restConfig.BcryptCost,
+	}
+
 	// Initializes the application dependency tree
 	txr := repository.NewTxr(db)
 	accountRepo := mysql.NewAccount(&txr)
 	transferRepo := mysql.NewTransfer(&txr)
-	accountServ := service.NewAccount(&txr, &accountRepo)
-	transferServ := service.NewTransfer(&txr, &transferRepo, &accountRepo)
-	server := rest.NewServer(&accountServ, &transferServ)
+	ledgerRepo := mysql.NewLedger(&txr)
+	idempotencyRepo := mysql.NewIdempotency(&txr)
+	refreshTokenRepo := mysql.NewRefreshToken(&txr)
+	revokedTokenRepo := mysql.NewRevokedToken(&txr)
+	loginAttemptRepo := mysql.NewLoginAttempt(&txr)
+	totpRepo := mysql.NewTOTP(&txr)
+	recoveryCodeRepo := mysql.NewRecoveryCode(&txr)
+	secretResetTokenRepo := mysql.NewSecretResetToken(&txr)
+	clientRepo := mysql.NewClient(&txr)
+	statusChangeRepo := mysql.NewStatusChange(&txr)
+	cashOperationRepo := mysql.NewCashOperation(&txr)
+	scheduledTransferRepo := mysql.NewScheduledTransfer(&txr)
+	standingOrderRepo := mysql.NewStandingOrder(&txr)
+	standingOrderExecutionRepo := mysql.NewStandingOrderExecution(&txr)
+	accountServ := service.NewAccount(&txr, &accountRepo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+	transferServ := service.NewTransfer(&txr, &transferRepo, &accountRepo, &ledgerRepo, &rateProvider)
+	scheduledTransferServ := service.NewScheduledTransfer(&txr, &scheduledTransferRepo, &accountRepo, &transferServ)
+	standingOrderServ := service.NewStandingOrder(&txr, &standingOrderRepo, &standingOrderExecutionRepo, &accountRepo, &transferServ)
+	statementServ := service.NewStatement(&txr, &accountRepo, &transferRepo, &ledgerRepo, &cashOperationRepo)
+	idempotencyServ := service.NewIdempotency(&idempotencyRepo, time.Hour*time.Duration(restConfig.IdempotencyTTL))
+	sessionServ := service.NewSession(&txr, &refreshTokenRepo, &revokedTokenRepo, time.Hour*time.Duration(restConfig.RefreshTokenTTL))
+	loginPolicy := service.LoginPolicy{
+		MaxAttempts: restConfig.LoginAttempts,
+		Backoff:     time.Second * time.Duration(restConfig.LoginBackoff),
+		Lockout:     time.Minute * time.Duration(restConfig.LoginLockout),
+	}
+	loginGuardServ := service.NewLoginGuard(&loginAttemptRepo, &accountRepo, loginPolicy)
+	twoFactorServ := service.NewTwoFactor(&txr, &totpRepo, &recoveryCodeRepo, &accountRepo, &loginAttemptRepo, restConfig.TOTPIssuer, stepUpPolicy, loginPolicy)
+	secretServ := service.NewSecret(&txr, &accountRepo, &secretResetTokenRepo, &refreshTokenRepo, &revokedTokenRepo, &notifier, secretPolicy, time.Minute*time.Duration(restConfig.SecretResetTTL))
+	clientServ := service.NewClient(&clientRepo)
+	cashServ := service.NewCash(&txr, &cashOperationRepo, &accountRepo, &ledgerRepo)
+	server := rest.NewServer(&accountServ, &transferServ, &scheduledTransferServ, &standingOrderServ, &statementServ, &idempotencyServ, &sessionServ, &loginGuardServ, &twoFactorServ, &secretServ, &clientServ, &cashServ)
+
+	// Executes the due scheduled transfers and standing orders in background, unless disabled on this replica
+	if scheduleConfig.Interval > 0 {
+		go scheduledTransferServ.Run(ctx, time.Second*time.Duration(scheduleConfig.Interval))
+		go standingOrderServ.Run(ctx, time.Second*time.Duration(scheduleConfig.Interval))
+	}
 
 	server.Use(middleware.Logger, middleware.Recoverer)
 
@@ -0,0 +1,25 @@
+package body
+
+import "github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+
+// RoleRequest holds the role granted to an account
+type RoleRequest struct {
+	Role entity.Role `json:"role" validation:"required" enums:"customer,support,admin"`
+}
+
+// StatusRequest holds the status an account is moved to along with the reason of the change
+type StatusRequest struct {
+	Status entity.Status `json:"status" validation:"required" enums:"active,blocked,closed"`
+	Reason string        `json:"reason" validation:"required" maxLength:"255"`
+}
+
+// TOTPActivationRequest holds a code generated from the enrolled secret, proving the authenticator was set up
+type TOTPActivationRequest struct {
+	Code string `json:"code" validation:"required" minLength:"6" maxLength:"6" example:"123456"`
+}
+
+// SecretChangeRequest holds the current account secret along with its replacement
+type SecretChangeRequest struct {
+	CurrentSecret string `json:"current_secret" validation:"required" minLength:"1" maxLength:"50"`
+	Secret        string `json:"secret" validation:"required" minLength:"1" maxLength:"50"`
+}
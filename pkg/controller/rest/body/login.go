@@ -1,14 +1,38 @@
 package body
 
-// LoginRequest holds the required fields for the login operation
+// LoginRequest holds the required fields for the login operation.
+// TOTP is only required by the accounts that enabled the second factor, taking either an authenticator or a recovery code
 type LoginRequest struct {
 	CPF    string `json:"cpf" validation:"required" minLength:"11" maxLength:"11"`
 	Secret string `json:"secret" validation:"required" minLength:"1" maxLength:"50"`
+	TOTP   string `json:"totp,omitempty" example:"123456"`
 }
 
-// LoginResponse maintains the response body of a successful login
+// LoginResponse maintains the response body of a successful login or token refresh
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshRequest holds the refresh token exchanged for a new access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validation:"required"`
+}
+
+// LogoutRequest holds the refresh token of the session being ended, if any
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SecretResetRequest holds the CPF of the account whose holder is sent a secret reset token
+type SecretResetRequest struct {
+	CPF string `json:"cpf" validation:"required" minLength:"11" maxLength:"11"`
+}
+
+// SecretResetConfirmation holds the reset token delivered to the account holder along with the new secret
+type SecretResetConfirmation struct {
+	Token  string `json:"token" validation:"required"`
+	Secret string `json:"secret" validation:"required" minLength:"1" maxLength:"50"`
 }
@@ -0,0 +1,15 @@
+package body
+
+// TokenResponse maintains the response body of a successful client credentials grant, as defined by RFC 6749
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope" example:"accounts:read transfers:read"`
+}
+
+// OAuthError contains the error template of the token endpoint, as defined by RFC 6749
+type OAuthError struct {
+	Error       string `json:"error" example:"invalid_client"`
+	Description string `json:"error_description,omitempty"`
+}
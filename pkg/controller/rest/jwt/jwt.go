@@ -2,56 +2,212 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rs/zerolog/log"
 )
 
-// Handler exposes JWT related functions
+// Scopes granted to the tokens, each one allowing a kind of operation over a resource
+const (
+	ScopeAccountsRead     = entity.ScopeAccountsRead
+	ScopeTransfersRead    = entity.ScopeTransfersRead
+	ScopeTransfersWrite   = entity.ScopeTransfersWrite
+	ScopeTransfersReverse = entity.ScopeTransfersReverse
+	ScopeCashWrite        = entity.ScopeCashWrite
+)
+
+// RoleScopes returns the scopes granted to an account of the given role logged in with its own credentials.
+// Support staff never send transfers, so they're only granted the reversal of the ones sent by mistake besides reading data
+func RoleScopes(role entity.Role) []string {
+	if role == entity.RoleSupport {
+		return []string{ScopeAccountsRead, ScopeTransfersRead, ScopeTransfersReverse}
+	}
+	return []string{ScopeAccountsRead, ScopeTransfersRead, ScopeTransfersWrite, ScopeTransfersReverse}
+}
+
+// Claims are the registered claims of the tokens along with the role of the account and the scopes granted to them.
+// The subject holds the id of the account the token was issued to, unless the token was issued to a client through
+// the client credentials grant, in which case both the subject and ClientID hold the client id
+type Claims struct {
+	jwtgo.StandardClaims
+	Role     entity.Role `json:"role,omitempty"`
+	Scopes   []string    `json:"scopes,omitempty"`
+	ClientID string      `json:"client_id,omitempty"`
+}
+
+// Handler exposes JWT related functions.
+// Tokens are signed with the asymmetric keys found at the configured keys dir, falling back to an HMAC secret when none is set
 type Handler struct {
+	mu         sync.RWMutex
 	secret     []byte
+	keysDir    string
+	keys       map[string]key
+	signingKey key
+	issuer     string
+	audience   string
 	expTimeout time.Duration
 }
 
-// NewHandler creates a new JWT Handler
-func NewHandler(config *env.RestConfig) *Handler {
-	return &Handler{
+// NewHandler creates a new JWT Handler, loading its signing keys from config.KeysDir when it's set
+func NewHandler(config *env.RestConfig) (*Handler, error) {
+	h := &Handler{
 		secret:     config.Secret,
+		keysDir:    config.KeysDir,
+		issuer:     config.Issuer,
+		audience:   config.Audience,
 		expTimeout: time.Duration(config.TokenExpTimeout),
 	}
+	if h.issuer == "" || h.audience == "" {
+		return nil, fmt.Errorf("both JWT_ISSUER and JWT_AUDIENCE must be set")
+	}
+	if h.keysDir == "" {
+		if len(h.secret) == 0 {
+			return nil, fmt.Errorf("either JWT_KEYS_DIR or JWT_SECRET must be set")
+		}
+		return h, nil
+	}
+	if err := h.Reload(); err != nil {
+		return nil, err
+	}
+	return h, nil
 }
 
-// Generate creates a new JWT token with the given id
-func (h *Handler) Generate(id int64) (string, *jwtgo.StandardClaims, error) {
-	claims := jwtgo.StandardClaims{
-		ExpiresAt: time.Now().Add(h.expTimeout * time.Minute).Unix(),
-		IssuedAt:  time.Now().Unix(),
-		Issuer:    strconv.FormatInt(id, 10),
-	}
-	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, &claims)
-	signedString, err := token.SignedString(h.secret)
+// Reload replaces the handler keys with the ones currently found at the keys dir.
+// Every key verifies tokens, while only the private key with the greatest kid signs new ones,
+// so kids are expected to sort in the order keys are rotated in, e.g. '2021-06-01'
+func (h *Handler) Reload() error {
+	if h.keysDir == "" {
+		return nil
+	}
+	loaded, err := loadKeys(h.keysDir)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]key, len(loaded))
+	var signingKey key
+	for _, k := range loaded {
+		keys[k.kid] = k
+		if k.private != nil && k.kid > signingKey.kid {
+			signingKey = k
+		}
+	}
+	if signingKey.private == nil {
+		return fmt.Errorf("no private jwt key found at '%s'", h.keysDir)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.keys = keys
+	h.signingKey = signingKey
+	return nil
+}
+
+// Watch reloads the handler keys at every interval until ctx is done, which rotates the signing key
+// as soon as a newer one is placed at the keys dir. Failed reloads keep the current keys
+func (h *Handler) Watch(ctx context.Context, interval time.Duration) {
+	if h.keysDir == "" || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.Reload(); err != nil {
+				log.Error().Caller().Err(err).Str("keys_dir", h.keysDir).Msg("unable to reload the jwt keys")
+			}
+		}
+	}
+}
+
+// JWKS returns the public keys that verify the tokens generated by the handler.
+// The set is empty when tokens are signed with an HMAC secret
+func (h *Handler) JWKS() JWKSet {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	set := JWKSet{Keys: make([]JWK, 0, len(h.keys))}
+	for _, k := range h.keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// Generate creates a new JWT token whose subject is the given account id of the given role, granting it the given scopes.
+// Each token is identified by a random jti so that it can be revoked before it expires
+func (h *Handler) Generate(id int64, role entity.Role, scopes []string) (string, *Claims, error) {
+	return h.sign(Claims{
+		StandardClaims: jwtgo.StandardClaims{Subject: strconv.FormatInt(id, 10)},
+		Role:           role,
+		Scopes:         scopes,
+	})
+}
+
+// GenerateClient creates a new JWT token issued to the given client, granting it the given scopes
+func (h *Handler) GenerateClient(clientID string, scopes []string) (string, *Claims, error) {
+	return h.sign(Claims{
+		StandardClaims: jwtgo.StandardClaims{Subject: clientID},
+		Scopes:         scopes,
+		ClientID:       clientID,
+	})
+}
+
+// sign completes the registered claims of the token and signs it with the current signing key
+func (h *Handler) sign(claims Claims) (string, *Claims, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", nil, types.NewErr(types.InternalErr, "unable to generate the jwt token id", err)
+	}
+	now := time.Now()
+	claims.Id = hex.EncodeToString(jti)
+	claims.Issuer = h.issuer
+	claims.Audience = h.audience
+	claims.ExpiresAt = now.Add(h.expTimeout * time.Minute).Unix()
+	claims.IssuedAt = now.Unix()
+	h.mu.RLock()
+	signingKey := h.signingKey
+	h.mu.RUnlock()
+	if signingKey.private == nil {
+		token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, &claims)
+		signedString, err := token.SignedString(h.secret)
+		return signedString, &claims, err
+	}
+	token := jwtgo.NewWithClaims(signingKey.method, &claims)
+	token.Header["kid"] = signingKey.kid
+	signedString, err := token.SignedString(signingKey.private)
 	return signedString, &claims, err
 }
 
-// Parse receives a token string, parses it, validates its content, and returns a token claims
-func (h *Handler) Parse(tokenString string) (*jwtgo.StandardClaims, error) {
-	claims := &jwtgo.StandardClaims{}
+// Parse receives a token string, parses it, validates its content, and returns a token claims.
+// Tokens must have been issued by the configured issuer to the configured audience
+func (h *Handler) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
 	token, err := jwtgo.ParseWithClaims(tokenString, claims, func(token *jwtgo.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwtgo.SigningMethodHMAC); !ok {
-			return nil, types.NewErr(types.AuthenticationErr, "invalid jwt token signature", nil)
-		}
-		if _, ok := token.Claims.(*jwtgo.StandardClaims); !ok {
+		if _, ok := token.Claims.(*Claims); !ok {
 			return nil, types.NewErr(types.AuthenticationErr, "unexpected token content", nil)
 		}
-		return h.secret, nil
+		return h.verificationKey(token)
 	})
 	if ve, ok := err.(*jwtgo.ValidationError); ok {
 		switch {
 		case ve.Errors&jwtgo.ValidationErrorMalformed != 0:
 			return nil, types.NewErr(types.AuthenticationErr, "malformed jwt token", nil)
+		case ve.Errors&jwtgo.ValidationErrorUnverifiable != 0:
+			if customErr, ok := ve.Inner.(*types.Err); ok {
+				return nil, customErr
+			}
 		case ve.Errors&(jwtgo.ValidationErrorExpired|jwtgo.ValidationErrorNotValidYet) != 0:
 			return nil, types.NewErr(types.AuthenticationErr, "expired or premature jwt token", nil)
 		case ve.Errors&jwtgo.ValidationErrorSignatureInvalid != 0:
@@ -61,5 +217,33 @@ func (h *Handler) Parse(tokenString string) (*jwtgo.StandardClaims, error) {
 	if err != nil || !token.Valid {
 		return nil, types.NewErr(types.AuthenticationErr, "unexpected token format", err)
 	}
+	if !claims.VerifyIssuer(h.issuer, true) {
+		return nil, types.NewErr(types.AuthenticationErr, "invalid jwt token issuer", nil)
+	}
+	if !claims.VerifyAudience(h.audience, true) {
+		return nil, types.NewErr(types.AuthenticationErr, "invalid jwt token audience", nil)
+	}
 	return claims, nil
 }
+
+// verificationKey returns the key that verifies the token signature.
+// The token algorithm must match the one bound to the key identified by its kid, so that a public key is never taken as an HMAC secret
+func (h *Handler) verificationKey(token *jwtgo.Token) (interface{}, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.keys == nil {
+		if _, ok := token.Method.(*jwtgo.SigningMethodHMAC); !ok {
+			return nil, types.NewErr(types.AuthenticationErr, "invalid jwt token signature", nil)
+		}
+		return h.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	k, ok := h.keys[kid]
+	if !ok {
+		return nil, types.NewErr(types.AuthenticationErr, "unknown jwt signing key", nil)
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, types.NewErr(types.AuthenticationErr, "invalid jwt token signature", nil)
+	}
+	return k.public, nil
+}
@@ -2,11 +2,13 @@ package jwt_test
 
 import (
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	jwtgo "github.com/dgrijalva/jwt-go"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
@@ -17,29 +19,49 @@ func TestGenerate(t *testing.T) {
 		name   string
 		config *env.RestConfig
 		input  int64
+		role   entity.Role
+		scopes []string
 	}{
 		{
 			name: "generate jwt token successfully",
 			config: &env.RestConfig{
 				TokenExpTimeout: 30,
 				Secret:          []byte("secret"),
+				Issuer:          "stn-accounts",
+				Audience:        "stn-gateway",
 			},
-			input: 1,
+			input:  1,
+			role:   entity.RoleSupport,
+			scopes: jwt.RoleScopes(entity.RoleSupport),
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			h := jwt.NewHandler(tc.config)
-			if token, claims, err := h.Generate(tc.input); err == nil {
+			h, err := jwt.NewHandler(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token, claims, err := h.Generate(tc.input, tc.role, tc.scopes); err == nil {
 				if len(token) == 0 {
 					t.Error("expected not empty token")
 				}
-				testutil.AssertEq(t, "token issuer", claims.Issuer, strconv.FormatInt(tc.input, 10))
+				testutil.AssertEq(t, "token subject", strconv.FormatInt(tc.input, 10), claims.Subject)
+				testutil.AssertEq(t, "token issuer", tc.config.Issuer, claims.Issuer)
+				testutil.AssertEq(t, "token audience", tc.config.Audience, claims.Audience)
+				testutil.AssertEq(t, "token scopes", strings.Join(tc.scopes, " "), strings.Join(claims.Scopes, " "))
+				testutil.AssertEq(t, "token id length", 32, len(claims.Id))
 				currentTimeout := claims.ExpiresAt - claims.IssuedAt
 				expectedTimeout := time.Duration(tc.config.TokenExpTimeout) * time.Minute
 
 				testutil.AssertEq(t, "token timeout", int64(expectedTimeout.Seconds()), currentTimeout)
+
+				parsed, err := h.Parse(token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEq(t, "parsed role", tc.role, parsed.Role)
+				testutil.AssertEq(t, "parsed scopes", strings.Join(tc.scopes, " "), strings.Join(parsed.Scopes, " "))
 			} else {
 				t.Error(err)
 			}
@@ -48,73 +70,147 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateClient(t *testing.T) {
+	h, err := jwt.NewHandler(&env.RestConfig{
+		TokenExpTimeout: 30,
+		Secret:          []byte("secret"),
+		Issuer:          "stn-accounts",
+		Audience:        "stn-gateway",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, claims, err := h.GenerateClient("batch", []string{jwt.ScopeTransfersRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEq(t, "token subject", "batch", claims.Subject)
+	testutil.AssertEq(t, "token id length", 32, len(claims.Id))
+
+	parsed, err := h.Parse(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEq(t, "parsed client id", "batch", parsed.ClientID)
+	testutil.AssertEq(t, "parsed role", entity.Role(""), parsed.Role)
+	testutil.AssertEq(t, "parsed scopes", jwt.ScopeTransfersRead, strings.Join(parsed.Scopes, " "))
+}
+
 func TestParse(t *testing.T) {
-	generate := func(claims *jwtgo.StandardClaims) (string, error) {
+	generate := func(claims *jwt.Claims) (string, error) {
 		token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims)
 		return token.SignedString([]byte("secret"))
 	}
+	config := func(secret string) *env.RestConfig {
+		return &env.RestConfig{
+			Secret:   []byte(secret),
+			Issuer:   "stn-accounts",
+			Audience: "stn-accounts",
+		}
+	}
+	standardClaims := func(exp time.Duration) jwtgo.StandardClaims {
+		return jwtgo.StandardClaims{
+			ExpiresAt: time.Now().Add(exp).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Subject:   "1",
+			Issuer:    "stn-accounts",
+			Audience:  "stn-accounts",
+		}
+	}
 
 	tt := []struct {
 		name      string
-		input     *jwtgo.StandardClaims
+		input     func() *jwt.Claims
 		config    *env.RestConfig
 		assertErr func(*testing.T, error)
 	}{
 		{
 			name: "parse jwt token successfully",
-			input: &jwtgo.StandardClaims{
-				ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
-				IssuedAt:  time.Now().Unix(),
-				Issuer:    "1",
-			},
-			config: &env.RestConfig{
-				Secret: []byte("secret"),
+			input: func() *jwt.Claims {
+				return &jwt.Claims{StandardClaims: standardClaims(5 * time.Minute)}
 			},
+			config:    config("secret"),
 			assertErr: func(t *testing.T, e error) { t.Error(e) },
 		},
 		{
 			name: "parse jwt token expired",
-			input: &jwtgo.StandardClaims{
-				ExpiresAt: time.Now().Add(-time.Minute).Unix(),
-				IssuedAt:  time.Now().Add(-time.Hour).Unix(),
-				Issuer:    "1",
-			},
-			config: &env.RestConfig{
-				Secret: []byte("secret"),
+			input: func() *jwt.Claims {
+				return &jwt.Claims{StandardClaims: standardClaims(-time.Minute)}
 			},
+			config: config("secret"),
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "expired or premature jwt token")
 			},
 		},
 		{
-			name: "parse jwt token ...",
-			input: &jwtgo.StandardClaims{
-				ExpiresAt: time.Now().Add(time.Minute).Unix(),
-				IssuedAt:  time.Now().Unix(),
-				Issuer:    "1",
-			},
-			config: &env.RestConfig{
-				Secret: []byte("oba"),
+			name: "parse jwt token with another secret",
+			input: func() *jwt.Claims {
+				return &jwt.Claims{StandardClaims: standardClaims(time.Minute)}
 			},
+			config: config("oba"),
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid token signature")
 			},
 		},
+		{
+			name: "parse jwt token from another issuer",
+			input: func() *jwt.Claims {
+				claims := &jwt.Claims{StandardClaims: standardClaims(time.Minute)}
+				claims.Issuer = "1"
+				return claims
+			},
+			config: config("secret"),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid jwt token issuer")
+			},
+		},
+		{
+			name: "parse jwt token without audience",
+			input: func() *jwt.Claims {
+				claims := &jwt.Claims{StandardClaims: standardClaims(time.Minute)}
+				claims.Audience = ""
+				return claims
+			},
+			config: config("secret"),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid jwt token audience")
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			h := jwt.NewHandler(tc.config)
-			token, err := generate(tc.input)
+			h, err := jwt.NewHandler(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input := tc.input()
+			token, err := generate(input)
 			if err != nil {
 				t.Error(err)
 				return
 			}
 			if claims, err := h.Parse(token); err == nil {
-				testutil.AssertEq(t, "token issuer", tc.input.Issuer, claims.Issuer)
+				testutil.AssertEq(t, "token subject", input.Subject, claims.Subject)
 			} else {
 				tc.assertErr(t, err)
 			}
 		})
 	}
 }
+
+func TestRoleScopes(t *testing.T) {
+	tt := []struct {
+		role   entity.Role
+		scopes string
+	}{
+		{role: entity.RoleCustomer, scopes: "accounts:read transfers:read transfers:write transfers:reverse"},
+		{role: entity.RoleSupport, scopes: "accounts:read transfers:read transfers:reverse"},
+		{role: entity.RoleAdmin, scopes: "accounts:read transfers:read transfers:write transfers:reverse"},
+	}
+	for _, tc := range tt {
+		t.Run(string(tc.role), func(t *testing.T) {
+			testutil.AssertEq(t, "scopes", tc.scopes, strings.Join(jwt.RoleScopes(tc.role), " "))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+// key is a PEM loaded key identified by its kid.
+// Keys loaded from a public key can only verify tokens, whereas private keys can also sign them
+type key struct {
+	kid     string
+	method  jwtgo.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// JWK is the JSON Web Key representation of a public key, see RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet is the set of public keys that verify the tokens generated by the application
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// loadKeys reads every .pem file of dir as a key whose kid is the file name without extension.
+// RSA keys sign with RS256 whereas EC keys sign with ES256, ES384 or ES512 depending on their curve
+func loadKeys(dir string) ([]key, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]key, 0, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		k, err := parseKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the jwt key '%s', %v", f, err)
+		}
+		k.kid = strings.TrimSuffix(filepath.Base(f), ".pem")
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// parseKey parses a PEM encoded RSA or EC key, either a private key in PKCS #1, PKCS #8 or SEC 1 form or a PKIX public key
+func parseKey(b []byte) (key, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return key{}, fmt.Errorf("no PEM data found")
+	}
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return key{}, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+	if err != nil {
+		return key{}, err
+	}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return key{method: jwtgo.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return key{method: jwtgo.SigningMethodRS256, public: k}, nil
+	case *ecdsa.PrivateKey:
+		method, err := ecMethod(k.Curve)
+		return key{method: method, private: k, public: &k.PublicKey}, err
+	case *ecdsa.PublicKey:
+		method, err := ecMethod(k.Curve)
+		return key{method: method, public: k}, err
+	}
+	return key{}, fmt.Errorf("unsupported key, expected an RSA or EC key")
+}
+
+// ecMethod returns the ECDSA signing method bound to the curve
+func ecMethod(curve elliptic.Curve) (jwtgo.SigningMethod, error) {
+	switch curve {
+	case elliptic.P256():
+		return jwtgo.SigningMethodES256, nil
+	case elliptic.P384():
+		return jwtgo.SigningMethodES384, nil
+	case elliptic.P521():
+		return jwtgo.SigningMethodES512, nil
+	}
+	return nil, fmt.Errorf("unsupported elliptic curve '%s'", curve.Params().Name)
+}
+
+// jwk returns the public part of the key as a JWK
+func (k key) jwk() JWK {
+	jwk := JWK{Kid: k.kid, Use: "sig", Alg: k.method.Alg()}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(padded(public.X, size))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(padded(public.Y, size))
+	}
+	return jwk
+}
+
+// padded returns the big-endian bytes of v left padded with zeros up to size, as required by RFC 7518 for EC coordinates
+func padded(v *big.Int, size int) []byte {
+	b := v.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
@@ -0,0 +1,323 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// writeKey stores the PEM encoding of the key at dir as <kid>.pem, only its public part when public is true
+func writeKey(t *testing.T, dir string, kid string, k interface{}, public bool) {
+	var block *pem.Block
+	switch {
+	case public:
+		var pub interface{}
+		switch k := k.(type) {
+		case *rsa.PrivateKey:
+			pub = &k.PublicKey
+		case *ecdsa.PrivateKey:
+			pub = &k.PublicKey
+		}
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: b}
+	default:
+		b, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: b}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newKeysDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "jwt-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestNewHandler(t *testing.T) {
+	tt := []struct {
+		name      string
+		config    func(*testing.T) *env.RestConfig
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "create handler with no issuer",
+			config: func(t *testing.T) *env.RestConfig {
+				return &env.RestConfig{Audience: "stn-accounts", Secret: []byte("secret")}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertEq(t, "err", "both JWT_ISSUER and JWT_AUDIENCE must be set", err.Error())
+			},
+		},
+		{
+			name: "create handler with no keys dir nor secret",
+			config: func(t *testing.T) *env.RestConfig {
+				return &env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts"}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertEq(t, "err", "either JWT_KEYS_DIR or JWT_SECRET must be set", err.Error())
+			},
+		},
+		{
+			name: "create handler with only public keys",
+			config: func(t *testing.T) *env.RestConfig {
+				dir := newKeysDir(t)
+				writeKey(t, dir, "2021-01-01", newRSAKey(t), true)
+				return &env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir}
+			},
+			assertErr: func(t *testing.T, err error) {
+				if err == nil || !strings.HasPrefix(err.Error(), "no private jwt key found at") {
+					t.Errorf("expected no private key err, got %v", err)
+				}
+			},
+		},
+		{
+			name: "create handler with an invalid key file",
+			config: func(t *testing.T) *env.RestConfig {
+				dir := newKeysDir(t)
+				if err := ioutil.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a key"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				return &env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir}
+			},
+			assertErr: func(t *testing.T, err error) {
+				if err == nil || !strings.HasPrefix(err.Error(), "unable to parse the jwt key") {
+					t.Errorf("expected parse err, got %v", err)
+				}
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwt.NewHandler(tc.config(t))
+			tc.assertErr(t, err)
+		})
+	}
+}
+
+func TestGenerateWithKeys(t *testing.T) {
+	tt := []struct {
+		name string
+		key  func(*testing.T) interface{}
+		alg  string
+	}{
+		{
+			name: "generate RS256 jwt token successfully",
+			key:  func(t *testing.T) interface{} { return newRSAKey(t) },
+			alg:  "RS256",
+		},
+		{
+			name: "generate ES256 jwt token successfully",
+			key:  func(t *testing.T) interface{} { return newECKey(t) },
+			alg:  "ES256",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := newKeysDir(t)
+			writeKey(t, dir, "2021-01-01", tc.key(t), false)
+			h, err := jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir, TokenExpTimeout: 30})
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, _, err := h.Generate(1, entity.RoleCustomer, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, _, err := new(jwtgo.Parser).ParseUnverified(token, &jwtgo.StandardClaims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertEq(t, "alg", tc.alg, parsed.Method.Alg())
+			testutil.AssertEq(t, "kid", "2021-01-01", parsed.Header["kid"])
+			claims, err := h.Parse(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertEq(t, "token subject", "1", claims.Subject)
+		})
+	}
+}
+
+func TestParseWithKeys(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	dir := newKeysDir(t)
+	writeKey(t, dir, "2021-01-01", rsaKey, false)
+	h, err := jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir, TokenExpTimeout: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM, err := ioutil.ReadFile(filepath.Join(dir, "2021-01-01.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := jwtgo.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix(), Subject: "1", Issuer: "stn-accounts", Audience: "stn-accounts"}
+
+	tt := []struct {
+		name     string
+		generate func() (string, error)
+		msg      string
+	}{
+		{
+			name: "parse jwt token with unknown kid",
+			generate: func() (string, error) {
+				token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, &claims)
+				token.Header["kid"] = "2020-01-01"
+				return token.SignedString(rsaKey)
+			},
+			msg: "unknown jwt signing key",
+		},
+		{
+			name: "parse jwt token with no kid",
+			generate: func() (string, error) {
+				return jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, &claims).SignedString(rsaKey)
+			},
+			msg: "unknown jwt signing key",
+		},
+		{
+			name: "parse jwt token signed with the key as an hmac secret",
+			generate: func() (string, error) {
+				token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, &claims)
+				token.Header["kid"] = "2021-01-01"
+				return token.SignedString(publicPEM)
+			},
+			msg: "invalid jwt token signature",
+		},
+		{
+			name: "parse jwt token signed by another key",
+			generate: func() (string, error) {
+				token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, &claims)
+				token.Header["kid"] = "2021-01-01"
+				return token.SignedString(newRSAKey(t))
+			},
+			msg: "invalid token signature",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := tc.generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = h.Parse(token)
+			testutil.AssertCustomErr(t, types.AuthenticationErr, err, tc.msg)
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := newKeysDir(t)
+	writeKey(t, dir, "2021-01-01", newRSAKey(t), false)
+	h, err := jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir, TokenExpTimeout: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, _, err := h.Generate(1, entity.RoleCustomer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeKey(t, dir, "2021-02-01", newECKey(t), false)
+	if err = h.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	current, _, err := h.Generate(1, entity.RoleCustomer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := new(jwtgo.Parser).ParseUnverified(current, &jwtgo.StandardClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEq(t, "kid", "2021-02-01", parsed.Header["kid"])
+	for _, token := range []string{previous, current} {
+		if _, err = h.Parse(token); err != nil {
+			t.Errorf("expected the token to be verified after the rotation, got %v", err)
+		}
+	}
+
+	if err = os.Remove(filepath.Join(dir, "2021-01-01.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = h.Parse(previous)
+	testutil.AssertCustomErr(t, types.AuthenticationErr, err, "unknown jwt signing key")
+}
+
+func TestJWKS(t *testing.T) {
+	dir := newKeysDir(t)
+	writeKey(t, dir, "2021-01-01", newRSAKey(t), true)
+	writeKey(t, dir, "2021-02-01", newECKey(t), false)
+	h, err := jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := h.JWKS()
+	testutil.AssertEq(t, "keys", 2, len(set.Keys))
+
+	rsaJWK := set.Keys[0]
+	testutil.AssertEq(t, "rsa kid", "2021-01-01", rsaJWK.Kid)
+	testutil.AssertEq(t, "rsa kty", "RSA", rsaJWK.Kty)
+	testutil.AssertEq(t, "rsa alg", "RS256", rsaJWK.Alg)
+	testutil.AssertEq(t, "rsa use", "sig", rsaJWK.Use)
+	testutil.AssertEq(t, "rsa e", "AQAB", rsaJWK.E)
+	testutil.AssertEq(t, "rsa n length", 342, len(rsaJWK.N))
+
+	ecJWK := set.Keys[1]
+	testutil.AssertEq(t, "ec kid", "2021-02-01", ecJWK.Kid)
+	testutil.AssertEq(t, "ec kty", "EC", ecJWK.Kty)
+	testutil.AssertEq(t, "ec alg", "ES256", ecJWK.Alg)
+	testutil.AssertEq(t, "ec crv", "P-256", ecJWK.Crv)
+	testutil.AssertEq(t, "ec x length", 43, len(ecJWK.X))
+	testutil.AssertEq(t, "ec y length", 43, len(ecJWK.Y))
+
+	h, err = jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", Secret: []byte("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEq(t, "hmac keys", 0, len(h.JWKS().Keys))
+}
@@ -6,21 +6,69 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
 )
 
 type key string
 
 // Keys used to set request context values
 const (
-	CtxAccountID key = "CtxAccountID"
+	CtxPrincipal key = "CtxPrincipal"
 )
 
-// NewAuthenticated creates a middleware that requires JWT Authorization Token Header
-func NewAuthenticated(jwtH *jwt.Handler) func(http.Handler) http.Handler {
+// PrincipalType tells whom an authorization token was issued to
+type PrincipalType string
+
+// List of the principal types
+const (
+	PrincipalAccount PrincipalType = "account" // PrincipalAccount logged in with the credentials of an account
+	PrincipalClient  PrincipalType = "client"  // PrincipalClient was granted a token through the client credentials grant
+)
+
+// Principal is the authenticated party of a request, as asserted by its authorization token.
+// Either AccountID and Role or ClientID are set, depending on its Type
+type Principal struct {
+	Type      PrincipalType
+	AccountID int64
+	Role      entity.Role
+	ClientID  string
+	TokenID   string
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+// Requester returns the principal as the dto.Requester of service operations
+func (p Principal) Requester() dto.Requester {
+	return dto.Requester{ID: p.AccountID, Role: p.Role, ClientID: p.ClientID}
+}
+
+// HasScope tells whether the principal token was granted the given scope
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PrincipalFrom returns the Principal set into the context by the authentication middleware
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(CtxPrincipal).(Principal)
+	return p, ok
+}
+
+// NewAuthenticated creates a middleware that requires JWT Authorization Token Header.
+// It accepts the tokens issued to both accounts and clients, telling them apart by the principal type.
+// Tokens revoked through the session service are rejected, including the ones revoked along with every token of their account
+func NewAuthenticated(jwtH *jwt.Handler, sessionSrv *service.Session) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -38,14 +86,52 @@ func NewAuthenticated(jwtH *jwt.Handler) func(http.Handler) http.Handler {
 				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "unable to parse the authorization token", err))
 				return
 			}
-			id, err := strconv.ParseInt(claims.Issuer, 10, 64)
+			principal, err := newPrincipal(claims)
 			if err != nil {
-				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "unable to parse the token issuer", err))
+				response.WriteErr(w, r, err)
 				return
 			}
-			ctx := context.WithValue(r.Context(), CtxAccountID, id)
+			if claims.Id == "" {
+				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the authorization token has no id", nil))
+				return
+			}
+			revoked, err := (*sessionSrv).Revoked(r.Context(), claims.Id, principal.AccountID, time.Unix(claims.IssuedAt, 0))
+			if err != nil {
+				response.WriteErr(w, r, err)
+				return
+			}
+			if revoked {
+				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the authorization token was revoked", nil))
+				return
+			}
+			ctx := context.WithValue(r.Context(), CtxPrincipal, principal)
 			next.ServeHTTP(w, r.WithContext(ctx))
 
 		})
 	}
 }
+
+// newPrincipal returns the principal asserted by the claims, which is a client when they carry a client id
+func newPrincipal(claims *jwt.Claims) (Principal, error) {
+	principal := Principal{
+		Type:      PrincipalAccount,
+		TokenID:   claims.Id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		Scopes:    claims.Scopes,
+	}
+	if claims.ClientID != "" {
+		if claims.Subject != claims.ClientID || claims.Role != "" {
+			return principal, types.NewErr(types.AuthenticationErr, "unexpected client token content", nil)
+		}
+		principal.Type = PrincipalClient
+		principal.ClientID = claims.ClientID
+		return principal, nil
+	}
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return principal, types.NewErr(types.AuthenticationErr, "unable to parse the token subject", err)
+	}
+	principal.AccountID = id
+	principal.Role = claims.Role
+	return principal, nil
+}
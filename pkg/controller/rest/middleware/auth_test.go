@@ -1,9 +1,11 @@
 package middleware_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,36 +13,155 @@ import (
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
 
 func TestAuthorizationRequest(t *testing.T) {
-	generate := func(claims *jwtgo.StandardClaims) (string, error) {
+	generate := func(claims *jwt.Claims) (string, error) {
 		token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims)
 		return token.SignedString([]byte("secret"))
 	}
 
-	jwtHandler := jwt.NewHandler(&env.RestConfig{
+	jwtHandler, err := jwt.NewHandler(&env.RestConfig{
 		TokenExpTimeout: 30,
 		Secret:          []byte("secret"),
+		Issuer:          "stn-accounts",
+		Audience:        "stn-accounts",
 	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sessionSrv service.Session = &testutil.SessionServMock{
+		ExpectRevoked: func(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+			// The tokens of the account 2 issued up to a minute ago are revoked
+			return jti == "revoked" || (account == 2 && !issuedAt.After(time.Now().Add(-time.Minute))), nil
+		},
+	}
 
 	tt := []struct {
 		name           string
-		claims         *jwtgo.StandardClaims
+		claims         *jwt.Claims
 		assertResponse func(*testing.T, *http.Response)
 	}{
 		{
 			name: "intercept request with valid auth header",
-			claims: &jwtgo.StandardClaims{
-				ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
-				IssuedAt:  time.Now().Unix(),
-				Issuer:    "1",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+				Scopes: []string{jwt.ScopeAccountsRead},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusOK, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with valid client auth header",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "batch",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+				Scopes:   []string{jwt.ScopeAccountsRead},
+				ClientID: "batch",
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusOK, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with client auth header of another subject",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+				Scopes:   []string{jwt.ScopeAccountsRead},
+				ClientID: "batch",
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with revoked auth header",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "revoked",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with auth header issued before its account tokens were revoked",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Add(-2 * time.Minute).Unix(),
+					Subject:   "2",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with auth header issued after its account tokens were revoked",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "2",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+				Scopes: []string{jwt.ScopeAccountsRead},
 			},
 			assertResponse: func(t *testing.T, r *http.Response) {
 				testutil.AssertEq(t, "status code", http.StatusOK, r.StatusCode)
 			},
 		},
+		{
+			name: "intercept request with auth header without jti",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					IssuedAt:  time.Now().Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
 		{
 			name: "intercept request with no auth header",
 			assertResponse: func(t *testing.T, r *http.Response) {
@@ -49,19 +170,58 @@ func TestAuthorizationRequest(t *testing.T) {
 		},
 		{
 			name: "intercept request with expired auth header",
-			claims: &jwtgo.StandardClaims{
-				ExpiresAt: time.Now().Add(-time.Minute).Unix(),
-				IssuedAt:  time.Now().Add(-time.Hour).Unix(),
-				Issuer:    "1",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+					IssuedAt:  time.Now().Add(-time.Hour).Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with invalid subject",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					Subject:   "foo",
+					Issuer:    "stn-accounts",
+					Audience:  "stn-accounts",
+				},
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with account id as issuer",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					Issuer:    "1",
+					Audience:  "stn-accounts",
+				},
 			},
 			assertResponse: func(t *testing.T, r *http.Response) {
 				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
 			},
 		},
 		{
-			name: "intercept request with invalid issuer",
-			claims: &jwtgo.StandardClaims{
-				Issuer: "foo",
+			name: "intercept request with another audience",
+			claims: &jwt.Claims{
+				StandardClaims: jwtgo.StandardClaims{
+					Id:        "jti",
+					ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+					Subject:   "1",
+					Issuer:    "stn-accounts",
+					Audience:  "another-service",
+				},
 			},
 			assertResponse: func(t *testing.T, r *http.Response) {
 				testutil.AssertEq(t, "status code", http.StatusUnauthorized, r.StatusCode)
@@ -72,13 +232,24 @@ func TestAuthorizationRequest(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 
-			m := middleware.NewAuthenticated(jwtHandler)
+			m := middleware.NewAuthenticated(jwtHandler, &sessionSrv)
 
 			handler := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if id, ok := r.Context().Value(middleware.CtxAccountID).(int64); ok {
-					testutil.AssertEq(t, "issuer id", tc.claims.Issuer, strconv.FormatInt(id, 10))
+				if principal, ok := middleware.PrincipalFrom(r.Context()); ok {
+					if tc.claims.ClientID != "" {
+						testutil.AssertEq(t, "principal type", middleware.PrincipalClient, principal.Type)
+						testutil.AssertEq(t, "client id", tc.claims.ClientID, principal.ClientID)
+						testutil.AssertEq(t, "subject id", int64(0), principal.AccountID)
+					} else {
+						testutil.AssertEq(t, "principal type", middleware.PrincipalAccount, principal.Type)
+						testutil.AssertEq(t, "subject id", tc.claims.Subject, strconv.FormatInt(principal.AccountID, 10))
+					}
+					testutil.AssertEq(t, "token id", tc.claims.Id, principal.TokenID)
+					testutil.AssertEq(t, "expires at", tc.claims.ExpiresAt, principal.ExpiresAt.Unix())
+					testutil.AssertEq(t, "scopes", strings.Join(tc.claims.Scopes, " "), strings.Join(principal.Scopes, " "))
+					testutil.AssertEq(t, "has accounts:read scope", true, principal.HasScope(jwt.ScopeAccountsRead))
 				} else {
-					t.Errorf("unabled to retrieve issuer id from request")
+					t.Errorf("unabled to retrieve the principal from request")
 				}
 			}))
 
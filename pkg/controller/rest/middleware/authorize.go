@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// NewScoped creates a middleware that requires the authenticated principal to be granted every given scope.
+// It must be chained after the middleware returned by NewAuthenticated
+func NewScoped(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFrom(r.Context())
+			if !ok {
+				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the request isn't authenticated", nil))
+				return
+			}
+			for _, scope := range scopes {
+				if !principal.HasScope(scope) {
+					response.WriteErr(w, r, types.NewErr(types.AuthorizationErr, fmt.Sprintf("the authorization token lacks the '%s' scope", scope), nil))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewRoleRestricted creates a middleware that requires the authenticated principal to be an account having any of the given roles.
+// It must be chained after the middleware returned by NewAuthenticated
+func NewRoleRestricted(roles ...entity.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFrom(r.Context())
+			if !ok {
+				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the request isn't authenticated", nil))
+				return
+			}
+			if principal.Type == PrincipalClient {
+				response.WriteErr(w, r, clientForbiddenErr(principal))
+				return
+			}
+			for _, role := range roles {
+				if principal.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			response.WriteErr(w, r, types.NewErr(types.AuthorizationErr, fmt.Sprintf("the role '%s' isn't allowed to access the resource", principal.Role), nil))
+		})
+	}
+}
+
+// NewAccountRestricted creates a middleware that requires the authenticated principal to be an account,
+// guarding the resources that act on the account of the principal itself.
+// It must be chained after the middleware returned by NewAuthenticated
+func NewAccountRestricted() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFrom(r.Context())
+			if !ok {
+				response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the request isn't authenticated", nil))
+				return
+			}
+			if principal.Type == PrincipalClient {
+				response.WriteErr(w, r, clientForbiddenErr(principal))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientForbiddenErr(principal Principal) error {
+	return types.NewErr(types.AuthorizationErr, fmt.Sprintf("the client '%s' isn't allowed to access the resource", principal.ClientID), nil)
+}
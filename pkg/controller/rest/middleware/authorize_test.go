@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestAuthorizeRequest(t *testing.T) {
+	tt := []struct {
+		name       string
+		principal  *middleware.Principal
+		middleware func(http.Handler) http.Handler
+		status     int
+	}{
+		{
+			name:       "intercept request granted the required scope",
+			principal:  &middleware.Principal{AccountID: 1, Role: entity.RoleCustomer, Scopes: jwt.RoleScopes(entity.RoleCustomer)},
+			middleware: middleware.NewScoped(jwt.ScopeTransfersWrite),
+			status:     http.StatusOK,
+		},
+		{
+			name:       "intercept request lacking the required scope",
+			principal:  &middleware.Principal{AccountID: 1, Role: entity.RoleSupport, Scopes: jwt.RoleScopes(entity.RoleSupport)},
+			middleware: middleware.NewScoped(jwt.ScopeTransfersWrite),
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "intercept unauthenticated request requiring a scope",
+			middleware: middleware.NewScoped(jwt.ScopeAccountsRead),
+			status:     http.StatusUnauthorized,
+		},
+		{
+			name:       "intercept request of an allowed role",
+			principal:  &middleware.Principal{AccountID: 1, Role: entity.RoleSupport},
+			middleware: middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin),
+			status:     http.StatusOK,
+		},
+		{
+			name:       "intercept request of a forbidden role",
+			principal:  &middleware.Principal{AccountID: 1, Role: entity.RoleCustomer},
+			middleware: middleware.NewRoleRestricted(entity.RoleAdmin),
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "intercept unauthenticated request requiring a role",
+			middleware: middleware.NewRoleRestricted(entity.RoleAdmin),
+			status:     http.StatusUnauthorized,
+		},
+		{
+			name:       "intercept client request requiring a role",
+			principal:  &middleware.Principal{Type: middleware.PrincipalClient, ClientID: "batch", Scopes: entity.ClientScopes},
+			middleware: middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin),
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "intercept account request requiring an account",
+			principal:  &middleware.Principal{Type: middleware.PrincipalAccount, AccountID: 1, Role: entity.RoleCustomer},
+			middleware: middleware.NewAccountRestricted(),
+			status:     http.StatusOK,
+		},
+		{
+			name:       "intercept client request requiring an account",
+			principal:  &middleware.Principal{Type: middleware.PrincipalClient, ClientID: "batch", Scopes: entity.ClientScopes},
+			middleware: middleware.NewAccountRestricted(),
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "intercept unauthenticated request requiring an account",
+			middleware: middleware.NewAccountRestricted(),
+			status:     http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := tc.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.principal != nil {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.CtxPrincipal, *tc.principal))
+			}
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
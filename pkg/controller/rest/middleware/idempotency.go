@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rs/zerolog/log"
+)
+
+// Headers used by idempotent requests
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// recorder keeps a copy of the response written by the next handler
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// NewIdempotent creates a middleware that replays the original response of requests retried with the same Idempotency-Key header.
+// Keys are scoped by method, path and the authenticated account or client, if any, stored as the SHA-256 digest of the scope
+// so that it fits its column however long the path and the client id are. Requests without the header are processed as usual
+func NewIdempotent(idempotencySrv *service.Idempotency) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to read the request body", err))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			scope := r.Method + " " + r.URL.Path
+			if principal, ok := PrincipalFrom(r.Context()); ok && principal.Type == PrincipalClient {
+				scope = fmt.Sprintf("%s %s", scope, principal.ClientID)
+			} else if ok {
+				scope = fmt.Sprintf("%s %d", scope, principal.AccountID)
+			}
+			scope = digest([]byte(scope))
+			stored, err := (*idempotencySrv).Begin(r.Context(), scope, key, digest(body))
+			if err != nil {
+				response.WriteErr(w, r, err)
+				return
+			}
+			if stored != nil {
+				replay(w, stored)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w}
+			// Failed requests roll their tx back, so the key is released for the client to retry.
+			// Deferred so that a panicking handler doesn't leave the key pending until it expires
+			defer func() {
+				if rec.status != 0 && rec.status < http.StatusInternalServerError {
+					return
+				}
+				if err := (*idempotencySrv).Release(r.Context(), scope, key); err != nil {
+					log.Error().Caller().Err(err).Str("idempotency_key", key).Msg("unable to release the idempotency key")
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+				return
+			}
+			err = (*idempotencySrv).Complete(r.Context(), entity.IdempotencyKey{
+				Scope:        scope,
+				Key:          key,
+				StatusCode:   rec.status,
+				Location:     w.Header().Get("Location"),
+				ResponseBody: rec.body.Bytes(),
+			})
+			// The key is kept pending rather than released, as retrying would process the request twice
+			if err != nil {
+				log.Error().Caller().Err(err).Str("idempotency_key", key).Int("status_code", rec.status).Msg("unable to complete the idempotency key")
+			}
+		})
+	}
+}
+
+// digest returns the hex encoded SHA-256 digest of b
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func replay(w http.ResponseWriter, e *entity.IdempotencyKey) {
+	header := w.Header()
+	header.Set("Content-Type", "application/json")
+	header.Set(IdempotentReplayedHeader, "true")
+	if e.Location != "" {
+		header.Set("Location", e.Location)
+	}
+	w.WriteHeader(e.StatusCode)
+	w.Write(e.ResponseBody)
+}
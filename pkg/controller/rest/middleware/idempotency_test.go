@@ -0,0 +1,256 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// scopeDigest returns the digest the idempotency keys of the given scope are stored under
+func scopeDigest(scope string) string {
+	sum := sha256.Sum256([]byte(scope))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestIdempotentRequest(t *testing.T) {
+	tt := []struct {
+		name           string
+		path           string
+		key            string
+		principal      *middleware.Principal
+		service        func() service.Idempotency
+		status         int
+		handled        bool
+		assertResponse func(*testing.T, *http.Response)
+	}{
+		{
+			name:    "intercept request without idempotency key",
+			service: func() service.Idempotency { return &testutil.IdempotencyServMock{} },
+			status:  http.StatusCreated,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+			},
+		},
+		{
+			name:      "intercept request with idempotency key of a client",
+			key:       "k1",
+			principal: &middleware.Principal{Type: middleware.PrincipalClient, ClientID: "c1"},
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						testutil.AssertEq(t, "scope", scopeDigest("POST /transfers c1"), scope)
+						return nil, nil
+					},
+					ExpectComplete: func(c context.Context, e entity.IdempotencyKey) error {
+						return nil
+					},
+				}
+			},
+			status:  http.StatusCreated,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+			},
+		},
+		{
+			name:      "intercept request with idempotency key of a client on a long path",
+			path:      "/accounts/1234567890123/withdrawals",
+			key:       "k1",
+			principal: &middleware.Principal{Type: middleware.PrincipalClient, ClientID: "0123456789abcdef0123456789abcdef"},
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						testutil.AssertEq(t, "scope", scopeDigest("POST /accounts/1234567890123/withdrawals 0123456789abcdef0123456789abcdef"), scope)
+						testutil.AssertEq(t, "scope length", 64, len(scope))
+						return nil, nil
+					},
+					ExpectComplete: func(c context.Context, e entity.IdempotencyKey) error {
+						testutil.AssertEq(t, "scope", scopeDigest("POST /accounts/1234567890123/withdrawals 0123456789abcdef0123456789abcdef"), e.Scope)
+						testutil.AssertEq(t, "status code", http.StatusCreated, e.StatusCode)
+						return nil
+					},
+				}
+			},
+			status:  http.StatusCreated,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept first request with idempotency key",
+			key:  "k1",
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						testutil.AssertEq(t, "scope", scopeDigest("POST /transfers 7"), scope)
+						testutil.AssertEq(t, "key", "k1", key)
+						testutil.AssertEq(t, "hash length", 64, len(hash))
+						return nil, nil
+					},
+					ExpectComplete: func(c context.Context, e entity.IdempotencyKey) error {
+						testutil.AssertEq(t, "status code", http.StatusCreated, e.StatusCode)
+						testutil.AssertEq(t, "location", "/transfers/1", e.Location)
+						testutil.AssertEq(t, "body", `{"id":1}`, string(e.ResponseBody))
+						return nil
+					},
+				}
+			},
+			status:  http.StatusCreated,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+				testutil.AssertEq(t, "replayed header", "", r.Header.Get(middleware.IdempotentReplayedHeader))
+			},
+		},
+		{
+			name: "intercept retried request with idempotency key",
+			key:  "k1",
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						return &entity.IdempotencyKey{StatusCode: http.StatusCreated, Location: "/transfers/1", ResponseBody: []byte(`{"id":1}`)}, nil
+					},
+				}
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+				testutil.AssertEq(t, "replayed header", "true", r.Header.Get(middleware.IdempotentReplayedHeader))
+				testutil.AssertEq(t, "location", "/transfers/1", r.Header.Get("Location"))
+				b, _ := ioutil.ReadAll(r.Body)
+				testutil.AssertEq(t, "body", `{"id":1}`, string(b))
+			},
+		},
+		{
+			name: "intercept request with idempotency key reused by a different request",
+			key:  "k1",
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						return nil, types.NewErr(types.ConflictErr, "idempotency key 'k1' was already used with a different request", nil)
+					},
+				}
+			},
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusConflict, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept failed request with idempotency key",
+			key:  "k1",
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						return nil, nil
+					},
+					ExpectRelease: func(c context.Context, scope, key string) error {
+						testutil.AssertEq(t, "key", "k1", key)
+						return nil
+					},
+				}
+			},
+			status:  http.StatusInternalServerError,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusInternalServerError, r.StatusCode)
+			},
+		},
+		{
+			name: "intercept request with idempotency key failing to be completed",
+			key:  "k1",
+			service: func() service.Idempotency {
+				return &testutil.IdempotencyServMock{
+					ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+						return nil, nil
+					},
+					ExpectComplete: func(c context.Context, e entity.IdempotencyKey) error {
+						return types.NewErr(types.InternalErr, "unable to complete the idempotency key", nil)
+					},
+				}
+			},
+			status:  http.StatusCreated,
+			handled: true,
+			assertResponse: func(t *testing.T, r *http.Response) {
+				testutil.AssertEq(t, "status code", http.StatusCreated, r.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.service()
+			m := middleware.NewIdempotent(&s)
+			handled := false
+			handler := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handled = true
+				b, _ := ioutil.ReadAll(r.Body)
+				testutil.AssertEq(t, "request body", `{"amount":"1.00"}`, string(b))
+				w.Header().Set("Location", "/transfers/1")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"id":1}`))
+			}))
+
+			path := tc.path
+			if path == "" {
+				path = "/transfers"
+			}
+			request, err := http.NewRequest(http.MethodPost, path, strings.NewReader(`{"amount":"1.00"}`))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if tc.key != "" {
+				request.Header.Set(middleware.IdempotencyKeyHeader, tc.key)
+			}
+			principal := middleware.Principal{Type: middleware.PrincipalAccount, AccountID: 7}
+			if tc.principal != nil {
+				principal = *tc.principal
+			}
+			request = request.WithContext(context.WithValue(request.Context(), middleware.CtxPrincipal, principal))
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			testutil.AssertEq(t, "handled", tc.handled, handled)
+			tc.assertResponse(t, response.Result())
+		})
+	}
+}
+
+func TestIdempotentRequestPanicking(t *testing.T) {
+	released := false
+	var s service.Idempotency = &testutil.IdempotencyServMock{
+		ExpectBegin: func(c context.Context, scope, key, hash string) (*entity.IdempotencyKey, error) {
+			return nil, nil
+		},
+		ExpectRelease: func(c context.Context, scope, key string) error {
+			testutil.AssertEq(t, "key", "k1", key)
+			released = true
+			return nil
+		},
+	}
+	handler := middleware.NewIdempotent(&s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("unexpected failure")
+	}))
+	request, err := http.NewRequest(http.MethodPost, "/transfers", strings.NewReader(`{"amount":"1.00"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+
+	defer func() {
+		testutil.AssertEq(t, "panic", "unexpected failure", recover())
+		testutil.AssertEq(t, "released", true, released)
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+}
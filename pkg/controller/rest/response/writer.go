@@ -2,9 +2,12 @@
 package response
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
@@ -28,6 +31,14 @@ func WriteErr(w http.ResponseWriter, r *http.Request, err error) error {
 			status = http.StatusUnauthorized
 		case types.ConflictErr:
 			status = http.StatusConflict
+		case types.AuthorizationErr:
+			status = http.StatusForbidden
+		case types.LockedErr:
+			status = http.StatusTooManyRequests
+		case types.PreconditionErr:
+			status = http.StatusPreconditionFailed
+		case types.NoPreconditionErr:
+			status = http.StatusPreconditionRequired
 		}
 	}
 
@@ -46,6 +57,14 @@ func WriteErr(w http.ResponseWriter, r *http.Request, err error) error {
 	})
 }
 
+// WriteOAuthErr writes an error of the token endpoint with the given RFC 6749 error code, such as 'invalid_client'
+func WriteOAuthErr(w http.ResponseWriter, status int, code string, description string) error {
+	appendHeaders(w.Header())
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(&body.OAuthError{Error: code, Description: description})
+}
+
 // WriteSuccess writes to the response with appropriate status and headers
 func WriteSuccess(w http.ResponseWriter, r *http.Request, b interface{}, id interface{}) error {
 	appendHeaders(w.Header())
@@ -62,8 +81,63 @@ func WriteSuccess(w http.ResponseWriter, r *http.Request, b interface{}, id inte
 	return json.NewEncoder(w).Encode(b)
 }
 
+// WriteLocated writes the resource found elsewhere than under the request URI with the given status, location pointing to it
+func WriteLocated(w http.ResponseWriter, status int, b interface{}, location string) error {
+	appendHeaders(w.Header())
+	w.Header().Set("Location", location)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(b)
+}
+
+// WriteCSV writes the records as a CSV attachment named after filename
+func WriteCSV(w http.ResponseWriter, filename string, records [][]string) error {
+	w.Header().Set("Content-Type", CSV+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	return cw.WriteAll(records)
+}
+
+// Media types the application is able to write
+const (
+	JSON = "application/json"
+	CSV  = "text/csv"
+)
+
+// Negotiate returns the offered media type that is preferred by the Accept request header.
+// The first offer is returned when the header is missing or accepts none of them
+func Negotiate(r *http.Request, offers ...string) string {
+	best, bestQ := offers[0], 0.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		params := strings.Split(accepted, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+		for _, p := range params[1:] {
+			if v := strings.TrimSpace(p); strings.HasPrefix(v, "q=") {
+				if parsed, err := strconv.ParseFloat(v[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		for _, offer := range offers {
+			if q > bestQ && mediaTypeMatch(mediaType, offer) {
+				best, bestQ = offer, q
+			}
+		}
+	}
+	return best
+}
+
+// mediaTypeMatch tells whether the offer satisfies the accepted media range, such as "text/*" or "*/*"
+func mediaTypeMatch(accepted string, offer string) bool {
+	if accepted == "*/*" || accepted == offer {
+		return true
+	}
+	return strings.HasSuffix(accepted, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(accepted, "*"))
+}
+
 func appendHeaders(header http.Header) {
-	header.Set("Content-Type", "application/json")
-	header.Set("Accept", "application/json")
+	header.Set("Content-Type", JSON)
+	header.Set("Accept", JSON)
 	header.Set("Accept-Charset", "utf-8")
 }
@@ -117,6 +117,26 @@ func TestWriteErr(t *testing.T) {
 			statusCode: http.StatusConflict,
 			err:        types.NewErr(types.ConflictErr, "ConflictErr", nil),
 		},
+		{
+			name:       "write response with AuthorizationErr error type",
+			statusCode: http.StatusForbidden,
+			err:        types.NewErr(types.AuthorizationErr, "AuthorizationErr", nil),
+		},
+		{
+			name:       "write response with LockedErr error type",
+			statusCode: http.StatusTooManyRequests,
+			err:        types.NewErr(types.LockedErr, "LockedErr", nil),
+		},
+		{
+			name:       "write response with PreconditionErr error type",
+			statusCode: http.StatusPreconditionFailed,
+			err:        types.NewErr(types.PreconditionErr, "PreconditionErr", nil),
+		},
+		{
+			name:       "write response with NoPreconditionErr error type",
+			statusCode: http.StatusPreconditionRequired,
+			err:        types.NewErr(types.NoPreconditionErr, "NoPreconditionErr", nil),
+		},
 	}
 
 	for _, tc := range tt {
@@ -139,3 +159,59 @@ func TestWriteErr(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCSV(t *testing.T) {
+	res := httptest.NewRecorder()
+	err := response.WriteCSV(res, "foo.csv", [][]string{{"name", "balance"}, {"Ana, Jr", "1.00"}})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "status code", http.StatusOK, res.Code)
+	testutil.AssertEq(t, "content type", "text/csv; charset=utf-8", res.Header().Get("Content-Type"))
+	testutil.AssertEq(t, "content disposition", `attachment; filename="foo.csv"`, res.Header().Get("Content-Disposition"))
+	testutil.AssertEq(t, "response body", "name,balance\n\"Ana, Jr\",1.00\n", res.Body.String())
+}
+
+func TestNegotiate(t *testing.T) {
+	tt := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{
+			name:     "negotiate without accept header",
+			expected: response.JSON,
+		},
+		{
+			name:     "negotiate exact media type",
+			accept:   "text/csv",
+			expected: response.CSV,
+		},
+		{
+			name:     "negotiate media range",
+			accept:   "text/*",
+			expected: response.CSV,
+		},
+		{
+			name:     "negotiate by quality value",
+			accept:   "text/csv;q=0.5, application/json",
+			expected: response.JSON,
+		},
+		{
+			name:     "negotiate unsupported media type",
+			accept:   "application/pdf",
+			expected: response.JSON,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/foo", nil)
+			if err != nil {
+				t.Fatalf("unabled to create http request, %v", err)
+			}
+			if tc.accept != "" {
+				request.Header.Set("Accept", tc.accept)
+			}
+			testutil.AssertEq(t, "media type", tc.expected, response.Negotiate(request, response.JSON, response.CSV))
+		})
+	}
+}
@@ -3,69 +3,240 @@ package routing
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rs/zerolog/log"
 )
 
 type accountHandler struct {
-	accountSrv *service.Account
+	accountSrv    *service.Account
+	statementSrv  *service.Statement
+	loginGuardSrv *service.LoginGuard
+	twoFactorSrv  *service.TwoFactor
+	secretSrv     *service.Secret
+	cashSrv       *service.Cash
 }
 
 // Accounts handle the requests related to entity.Account
-func Accounts(accountSrv *service.Account) func(chi.Router) {
-	h := accountHandler{accountSrv: accountSrv}
+func Accounts(accountSrv *service.Account, statementSrv *service.Statement, idempotencySrv *service.Idempotency, sessionSrv *service.Session, loginGuardSrv *service.LoginGuard, twoFactorSrv *service.TwoFactor, secretSrv *service.Secret, cashSrv *service.Cash, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := accountHandler{accountSrv: accountSrv, statementSrv: statementSrv, loginGuardSrv: loginGuardSrv, twoFactorSrv: twoFactorSrv, secretSrv: secretSrv, cashSrv: cashSrv}
+	cashWritable := middleware.NewScoped(jwt.ScopeCashWrite)
+	authenticated := middleware.NewAuthenticated(jwtHandler, sessionSrv)
+	readable := middleware.NewScoped(jwt.ScopeAccountsRead)
+	accountOnly := middleware.NewAccountRestricted()
 	return func(r chi.Router) {
-		r.Get("/", h.get)
-		r.Post("/", h.post)
-		r.Get("/{id:[\\d]+}/balance", h.getBalance)
+		r.With(authenticated, readable, middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin)).Get("/", h.get)
+		r.With(middleware.NewIdempotent(idempotencySrv)).Post("/", h.post)
+		r.With(authenticated, readable).Get("/{id:[\\d]+}", h.getByID)
+		r.With(authenticated, accountOnly).Patch("/{id:[\\d]+}", h.patch)
+		r.With(authenticated, readable).Get("/{id:[\\d]+}/statement", h.getStatement)
+		r.With(authenticated, readable).Get("/{id:[\\d]+}/balance", h.getBalance)
+		r.With(authenticated, readable, middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin)).Get("/{id:[\\d]+}/reconciliation", h.getReconciliation)
+		r.With(authenticated, middleware.NewRoleRestricted(entity.RoleAdmin)).Put("/{id:[\\d]+}/role", h.putRole)
+		r.With(authenticated, middleware.NewRoleRestricted(entity.RoleAdmin)).Put("/{id:[\\d]+}/status", h.putStatus)
+		r.With(authenticated, readable, middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin)).Get("/{id:[\\d]+}/status-changes", h.getStatusChanges)
+		r.With(authenticated, middleware.NewRoleRestricted(entity.RoleSupport, entity.RoleAdmin)).Delete("/{id:[\\d]+}/lock", h.deleteLock)
+		r.With(authenticated, cashWritable, middleware.NewIdempotent(idempotencySrv)).Post("/{id:[\\d]+}/deposits", h.postDeposit)
+		r.With(authenticated, cashWritable, middleware.NewIdempotent(idempotencySrv)).Post("/{id:[\\d]+}/withdrawals", h.postWithdrawal)
+		r.With(authenticated, accountOnly).Put("/{id:[\\d]+}/secret", h.putSecret)
+		r.With(authenticated, accountOnly).Post("/{id:[\\d]+}/totp", h.postTOTP)
+		r.With(authenticated, accountOnly).Post("/{id:[\\d]+}/totp/activation", h.postTOTPActivation)
 	}
 }
 
-// @Summary Fetches a list of application accounts
+// @Summary Fetches a page of application accounts
+// @Description Only available to support and admin staff
 // @tags v1
 // @ID fetch-account-list
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} dto.AccountView
+// @Param limit query int false "Page size" minimum(1) maximum(200) default(50)
+// @Param cursor query string false "Cursor returned along the previous page"
+// @Param sort query string false "Sort column, prefixed by '-' when descending" Enums(created_at, -created_at, name, -name)
+// @Param name query string false "Account name prefix"
+// @Param created_from query string false "Inclusive creation date lower bound, RFC 3339 date-time or full-date"
+// @Param created_to query string false "Exclusive creation date upper bound, RFC 3339 date-time or full-date"
+// @Header 200 {string} Link "Link to the next page, if there's any"
+// @Success 200 {object} dto.AccountPage
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
 // @Failure 500 {object} body.JSONError
 // @Router /accounts [get]
+// @Security ApiKeyAuth
 func (h *accountHandler) get(w http.ResponseWriter, r *http.Request) {
-	accounts, err := (*h.accountSrv).Fetch(r.Context())
+	accountFilter, err := parseAccountFilter(r.URL.Query())
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	page, err := (*h.accountSrv).Fetch(r.Context(), accountFilter)
 	if err != nil {
 		response.WriteErr(w, r, err)
 		return
 	}
-	if err = response.WriteSuccess(w, r, accounts, nil); err != nil {
+	page.Next = nextLink(w, r, page.NextCursor)
+	if err = response.WriteSuccess(w, r, page, nil); err != nil {
 		log.Error().Caller().Err(err).Msg("unable to encode the accounts into the response")
 		response.WriteErr(w, r, err)
 	}
 }
 
+// @Summary Gets the details of the current authenticated account
+// @tags v1
+// @ID get-account
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Header 200 {string} ETag "Version of the account profile, required by the If-Match header of its updates"
+// @Success 200 {object} dto.AccountView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id} [get]
+// @Security ApiKeyAuth
+func (h *accountHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.accountSrv).Get(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(view.Version))
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the account into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Updates the profile of the account specified by the given ID
+// @Description Takes a JSON Merge Patch (RFC 7396) of the mutable fields, the cpf and the balance never changing. Only available to the account owner and to admin staff
+// @tags v1
+// @ID patch-account
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param If-Match header string true "ETag of the account version the changes are based on"
+// @Param req body dto.AccountPatch true "Account Merge Patch"
+// @Header 200 {string} ETag "Version of the updated account profile"
+// @Success 200 {object} dto.AccountView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 412 {object} body.JSONError
+// @Failure 428 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id} [patch]
+// @Security ApiKeyAuth
+func (h *accountHandler) patch(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	var patch dto.AccountPatch
+	if err = json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode the account patch from the request body")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	view, err := (*h.accountSrv).Patch(r.Context(), principal.Requester(), id, version, patch)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(view.Version))
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the account into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// etag formats the account version as a strong entity tag
+func etag(version int64) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch returns the account version held by the If-Match header, which is mandatory so that no update is lost
+func parseIfMatch(r *http.Request) (int64, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return 0, types.NewErr(types.NoPreconditionErr, "the If-Match header is required", nil)
+	}
+	if len(ifMatch) < 2 || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+		return 0, types.NewErr(types.PreconditionErr, "the If-Match header doesn't hold an account ETag", nil)
+	}
+	version, err := strconv.ParseInt(ifMatch[1:len(ifMatch)-1], 10, 64)
+	if err != nil {
+		return 0, types.NewErr(types.PreconditionErr, "the If-Match header doesn't hold an account ETag", nil)
+	}
+	return version, nil
+}
+
 // @Summary Gets the current account balance specified by the given ID
 // @tags v1
 // @ID get-account-balance
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Account ID"
-// @Success 200 {object} float64
+// @Success 200 {object} dto.BalanceView
 // @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
 // @Failure 404 {object} body.JSONError
 // @Failure 500 {object} body.JSONError
 // @Router /accounts/{id}/balance [get]
+// @Security ApiKeyAuth
 func (h *accountHandler) getBalance(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
 		response.WriteErr(w, r, err)
 		return
 	}
-	balance, err := (*h.accountSrv).GetBalance(r.Context(), id)
+	balance, err := (*h.accountSrv).GetBalance(r.Context(), principal.Requester(), id)
 	if err != nil {
 		response.WriteErr(w, r, err)
 		return
@@ -82,6 +253,7 @@ func (h *accountHandler) getBalance(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce  json
 // @Param req body dto.AccountCreation required "Account Creation Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
 // @Header 201 {string} Location "/accounts/1"
 // @Success 201 {object} dto.AccountView
 // @Failure 400 {object} body.JSONError
@@ -93,7 +265,7 @@ func (h *accountHandler) post(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&accountCreation)
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("unable to decode the account creation from the request body")
-		response.WriteErr(w, r, nil)
+		response.WriteErr(w, r, decodeErr(err))
 		return
 	}
 	view, err := (*h.accountSrv).Create(r.Context(), accountCreation)
@@ -106,3 +278,322 @@ func (h *accountHandler) post(w http.ResponseWriter, r *http.Request) {
 		response.WriteErr(w, r, err)
 	}
 }
+
+func parseAccountFilter(q url.Values) (f dto.AccountFilter, err error) {
+	if f.PageRequest, err = parsePage(q); err != nil {
+		return f, err
+	}
+	f.NamePrefix = q.Get("name")
+	if f.CreatedFrom, err = parseTimeParam(q, "created_from"); err != nil {
+		return f, err
+	}
+	f.CreatedTo, err = parseTimeParam(q, "created_to")
+	return f, err
+}
+
+// @Summary Grants a role to the account specified by the given ID
+// @Description Only available to admin staff
+// @tags v1
+// @ID put-account-role
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param req body body.RoleRequest true "Role Request"
+// @Success 200 {object} dto.AccountView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/role [put]
+// @Security ApiKeyAuth
+func (h *accountHandler) putRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	requestBody := body.RoleRequest{}
+	if err = json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.RoleRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	view, err := (*h.accountSrv).UpdateRole(r.Context(), id, requestBody.Role)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the account into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Moves the account specified by the given ID to another status
+// @Description Blocked accounts can neither send nor receive transfers. Closing requires a zero balance and can't be undone. Every change is audited along with its reason. Only available to admin staff
+// @tags v1
+// @ID put-account-status
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param req body body.StatusRequest true "Status Request"
+// @Success 200 {object} dto.AccountView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/status [put]
+// @Security ApiKeyAuth
+func (h *accountHandler) putStatus(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	requestBody := body.StatusRequest{}
+	if err = json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.StatusRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	view, err := (*h.accountSrv).UpdateStatus(r.Context(), principal.Requester(), id, requestBody.Status, requestBody.Reason)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the account into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Lists the status changes of the account specified by the given ID
+// @Description Sorted from the oldest change to the newest. Only available to support and admin staff
+// @tags v1
+// @ID get-account-status-changes
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {array} dto.StatusChangeView
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/status-changes [get]
+// @Security ApiKeyAuth
+func (h *accountHandler) getStatusChanges(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	views, err := (*h.accountSrv).FetchStatusChanges(r.Context(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, views, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the status changes into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Reconciles the balance of the account specified by the given ID with its ledger
+// @Description Tells whether the account balance matches the one derived from its ledger entries. Only available to support and admin staff
+// @tags v1
+// @ID get-account-reconciliation
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {object} dto.ReconciliationView
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/reconciliation [get]
+// @Security ApiKeyAuth
+func (h *accountHandler) getReconciliation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.accountSrv).Reconcile(r.Context(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the reconciliation into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Unlocks the login of the account specified by the given ID
+// @Description Lifts the lockout imposed after too many failed logins and resets the failure counter of the account. Only available to support and admin staff
+// @tags v1
+// @ID delete-account-lock
+// @Param id path int true "Account ID"
+// @Success 204
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/lock [delete]
+// @Security ApiKeyAuth
+func (h *accountHandler) deleteLock(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = (*h.loginGuardSrv).Unlock(r.Context(), id); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	log.Info().Int64("id", id).Int64("unlocked_by", principal.AccountID).Msg("account login unlocked")
+	if err = response.WriteSuccess(w, r, nil, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the unlock response")
+	}
+}
+
+// @Summary Changes the secret of the current authenticated account
+// @Description Requires the current secret. Every session of the account is ended and the access tokens already issued are revoked, so that a new login is required
+// @tags v1
+// @ID put-account-secret
+// @Accept  json
+// @Param id path int true "Account ID"
+// @Param req body body.SecretChangeRequest true "Secret Change Request"
+// @Success 204
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/secret [put]
+// @Security ApiKeyAuth
+func (h *accountHandler) putSecret(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	requestBody := body.SecretChangeRequest{}
+	if err = json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.SecretChangeRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	if err = (*h.secretSrv).Change(r.Context(), principal.Requester(), id, requestBody.CurrentSecret, requestBody.Secret); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	log.Info().Int64("id", id).Msg("account secret changed")
+	if err = response.WriteSuccess(w, r, nil, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the secret change response")
+	}
+}
+
+// @Summary Enrolls a TOTP second factor for the current authenticated account
+// @Description Generates a new secret, replacing any pending enrollment. The second factor is only required once activated
+// @tags v1
+// @ID post-account-totp
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {object} dto.TOTPEnrollment
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/totp [post]
+// @Security ApiKeyAuth
+func (h *accountHandler) postTOTP(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.twoFactorSrv).Enroll(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the totp enrollment into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Activates the enrolled TOTP second factor of the current authenticated account
+// @Description Takes a code generated from the enrolled secret and returns the recovery codes, which aren't disclosed again
+// @tags v1
+// @ID post-account-totp-activation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param req body body.TOTPActivationRequest true "TOTP Activation Request"
+// @Success 200 {object} dto.RecoveryCodes
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/totp/activation [post]
+// @Security ApiKeyAuth
+func (h *accountHandler) postTOTPActivation(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	requestBody := body.TOTPActivationRequest{}
+	if err = json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.TOTPActivationRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	view, err := (*h.twoFactorSrv).Activate(r.Context(), principal.Requester(), id, requestBody.Code)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the recovery codes into the response")
+		response.WriteErr(w, r, err)
+	}
+}
@@ -7,12 +7,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
@@ -23,35 +27,94 @@ func TestRoutingAccountFetch(t *testing.T) {
 		service   func() service.Account
 		status    int
 		path      string
+		role      entity.Role
 		assertRes func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
 			name:   "get '/' with no result successfully",
 			status: http.StatusOK,
 			path:   "/",
+			role:   entity.RoleSupport,
 			service: func() service.Account {
 				return &testutil.AccountServMock{
-					ExpectFetch: func(c context.Context) ([]dto.AccountView, error) {
-						return []dto.AccountView{}, nil
+					ExpectFetch: func(c context.Context, f dto.AccountFilter) (dto.AccountPage, error) {
+						return dto.AccountPage{Data: []dto.AccountView{}}, nil
 					},
 				}
 			},
+			assertRes: func(t *testing.T, res *httptest.ResponseRecorder) {
+				testutil.AssertEq(t, "link header", "", res.Header().Get("Link"))
+			},
 		},
 		{
 			name:   "get '/' with results successfully",
 			status: http.StatusOK,
-			path:   "/",
+			role:   entity.RoleAdmin,
+			path:   "/?limit=3&sort=-name&name=S&created_from=2021-01-01&created_to=2021-02-01T10:00:00Z",
 			service: func() service.Account {
 				return &testutil.AccountServMock{
-					ExpectFetch: func(c context.Context) ([]dto.AccountView, error) {
-						return []dto.AccountView{
-							testutil.NewAccountView(1, "Jose", "00000000000", 5, time.Now()),
-							testutil.NewAccountView(2, "Silva", "11111111111", 10, time.Now()),
-							testutil.NewAccountView(3, "Sousa", "22222222222", 20, time.Now()),
+					ExpectFetch: func(c context.Context, f dto.AccountFilter) (dto.AccountPage, error) {
+						testutil.AssertEq(t, "limit", 3, f.Limit)
+						testutil.AssertEq(t, "sort", "-name", f.Sort)
+						testutil.AssertEq(t, "name prefix", "S", f.NamePrefix)
+						testutil.AssertEq(t, "created from", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), f.CreatedFrom)
+						testutil.AssertEq(t, "created to", time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC), f.CreatedTo)
+						return dto.AccountPage{
+							Data: []dto.AccountView{
+								testutil.NewAccountView(1, "Sousa", "00000000000", 5, time.Now()),
+								testutil.NewAccountView(2, "Silva", "11111111111", 10, time.Now()),
+								testutil.NewAccountView(3, "Santos", "22222222222", 20, time.Now()),
+							},
+							NextCursor: "abc",
 						}, nil
 					},
 				}
 			},
+			assertRes: func(t *testing.T, res *httptest.ResponseRecorder) {
+				next := "/?created_from=2021-01-01&created_to=2021-02-01T10%3A00%3A00Z&cursor=abc&limit=3&name=S&sort=-name"
+				testutil.AssertEq(t, "link header", "<"+next+">; rel=\"next\"", res.Header().Get("Link"))
+				var page dto.AccountPage
+				if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEq(t, "page size", 3, len(page.Data))
+				testutil.AssertEq(t, "next", next, page.Next)
+			},
+		},
+		{
+			name:   "get '/' with invalid limit",
+			status: http.StatusBadRequest,
+			path:   "/?limit=ten",
+			role:   entity.RoleSupport,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "get '/' with invalid creation date",
+			status: http.StatusBadRequest,
+			path:   "/?created_from=01/01/2021",
+			role:   entity.RoleSupport,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "get '/' as customer",
+			status: http.StatusForbidden,
+			path:   "/",
+			role:   entity.RoleCustomer,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "get '/' without auth header",
+			status: http.StatusUnauthorized,
+			path:   "/",
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
 		},
 	}
 
@@ -59,26 +122,110 @@ func TestRoutingAccountFetch(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service()
-			r.Route("/", routing.Accounts(&s))
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			if tc.role != "" {
+				token, _, _ := jwtHandler.Generate(1, tc.role, jwt.RoleScopes(tc.role))
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.assertRes != nil {
+				tc.assertRes(t, res)
+			}
+		})
+	}
+}
+
+func TestRoutingAccountGet(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	tt := []struct {
+		name    string
+		service func(t *testing.T) service.Account
+		status  int
+		path    string
+		headers map[string]string
+	}{
+		{
+			name:   "get '/{id}' without auth header",
+			status: http.StatusUnauthorized,
+			path:   "/1",
+			service: func(t *testing.T) service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "get '/{id}' successfully",
+			status: http.StatusOK,
+			path:   "/1",
+			service: func(t *testing.T) service.Account {
+				return &testutil.AccountServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.AccountView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(1), id)
+						return dto.AccountView{ID: id, Name: "Kim", Currency: types.BRL, Balance: "1.00", Version: 3}, nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "get '/{id}' of another account",
+			status: http.StatusNotFound,
+			path:   "/2",
+			service: func(t *testing.T) service.Account {
+				return &testutil.AccountServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.AccountView, error) {
+						return dto.AccountView{}, types.NewErr(types.NotFoundErr, "account '2' was not found", nil)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service(t)
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
 
 			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
 			if err != nil {
 				t.Fatalf("unable to create testcase request")
 			}
+			for k, v := range tc.headers {
+				req.Header.Add(k, v)
+			}
 			res := httptest.NewRecorder()
 			r.ServeHTTP(res, req)
 
 			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.status == http.StatusOK {
+				testutil.AssertEq(t, "etag", `"3"`, res.Header().Get("ETag"))
+			}
 		})
 	}
 }
 
 func TestRoutingAccountGetBalance(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
 	tt := []struct {
 		name      string
 		service   func(t *testing.T) service.Account
 		status    int
 		path      string
+		headers   map[string]string
 		assertRes func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
@@ -87,14 +234,26 @@ func TestRoutingAccountGetBalance(t *testing.T) {
 			path:   "/1/balance",
 			service: func(t *testing.T) service.Account {
 				return &testutil.AccountServMock{
-					ExpectGetBalance: func(c context.Context, i int64) (float64, error) {
+					ExpectGetBalance: func(c context.Context, requester dto.Requester, i int64) (dto.BalanceView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
 						testutil.AssertEq(t, "id", int64(1), i)
-						return 50, nil
+						return dto.BalanceView{Balance: "50.00", Currency: types.BRL}, nil
 					},
 				}
 			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
 			assertRes: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				testutil.AssertEq(t, "response balance", "50", string(bytes.TrimSpace(rec.Body.Bytes())))
+				testutil.AssertEq(t, "response balance", `{"balance":50.00,"currency":"BRL"}`, string(bytes.TrimSpace(rec.Body.Bytes())))
+			},
+		},
+		{
+			name:   "get '/{id}/balance' without auth header",
+			status: http.StatusUnauthorized,
+			path:   "/1/balance",
+			service: func(t *testing.T) service.Account {
+				return &testutil.AccountServMock{}
 			},
 		},
 	}
@@ -103,17 +262,22 @@ func TestRoutingAccountGetBalance(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service(t)
-			r.Route("/", routing.Accounts(&s))
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
 
 			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
 			if err != nil {
 				t.Fatalf("unable to create testcase request")
 			}
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
 			res := httptest.NewRecorder()
 			r.ServeHTTP(res, req)
 
 			testutil.AssertEq(t, "status code", tc.status, res.Code)
-			tc.assertRes(t, res)
+			if tc.assertRes != nil {
+				tc.assertRes(t, res)
+			}
 		})
 	}
 }
@@ -153,7 +317,7 @@ func TestRoutingAccountCreate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service()
-			r.Route("/", routing.Accounts(&s))
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
 			buffer, err := tc.reader()
 			if err != nil {
 				t.Fatalf("unable to create request body, %v", err)
@@ -170,3 +334,585 @@ func TestRoutingAccountCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestRoutingAccountPatch(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(2, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	tt := []struct {
+		name    string
+		body    string
+		ifMatch string
+		patch   func(context.Context, dto.Requester, int64, int64, dto.AccountPatch) (dto.AccountView, error)
+		status  int
+		etag    string
+	}{
+		{
+			name:    "patch '/{id}' successfully",
+			body:    `{"name":"Lia Sousa"}`,
+			ifMatch: `"1"`,
+			patch: func(c context.Context, r dto.Requester, id int64, version int64, p dto.AccountPatch) (dto.AccountView, error) {
+				testutil.AssertEq(t, "id", int64(2), id)
+				testutil.AssertEq(t, "version", int64(1), version)
+				testutil.AssertEq(t, "name", "Lia Sousa", *p.Name)
+				view := testutil.NewAccountView(id, *p.Name, "00000000000", 0, time.Now())
+				view.Version = 2
+				return view, nil
+			},
+			status: http.StatusOK,
+			etag:   `"2"`,
+		},
+		{
+			name:    "patch '/{id}' clearing the name",
+			body:    `{"name":null}`,
+			ifMatch: `"1"`,
+			patch: func(c context.Context, r dto.Requester, id int64, version int64, p dto.AccountPatch) (dto.AccountView, error) {
+				testutil.AssertEq(t, "name", "", *p.Name)
+				return dto.AccountView{}, types.NewErr(types.ValidationErr, "field 'name' is required", nil)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "patch '/{id}' based on an outdated version",
+			body:    `{"name":"Lia Sousa"}`,
+			ifMatch: `"1"`,
+			patch: func(c context.Context, r dto.Requester, id int64, version int64, p dto.AccountPatch) (dto.AccountView, error) {
+				return dto.AccountView{}, types.NewErr(types.PreconditionErr, "account '2' was modified since version 1", nil)
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "patch '/{id}' without If-Match",
+			body:   `{"name":"Lia Sousa"}`,
+			status: http.StatusPreconditionRequired,
+		},
+		{
+			name:    "patch '/{id}' with a weak ETag",
+			body:    `{"name":"Lia Sousa"}`,
+			ifMatch: `W/"1"`,
+			status:  http.StatusPreconditionFailed,
+		},
+		{
+			name:    "patch '/{id}' changing the cpf",
+			body:    `{"name":"Lia Sousa","cpf":"41112075020"}`,
+			ifMatch: `"1"`,
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "patch '/{id}' with malformed body",
+			body:    `{"name":`,
+			ifMatch: `"1"`,
+			status:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{ExpectPatch: tc.patch}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPatch, "/2", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "etag", tc.etag, res.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestRoutingAccountPutRole(t *testing.T) {
+	adminToken, _, _ := jwtHandler.Generate(1, entity.RoleAdmin, jwt.RoleScopes(entity.RoleAdmin))
+	supportToken, _, _ := jwtHandler.Generate(1, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
+	tt := []struct {
+		name    string
+		service func() service.Account
+		status  int
+		body    string
+		token   string
+	}{
+		{
+			name:   "put '/{id}/role' successfully",
+			status: http.StatusOK,
+			body:   `{"role":"support"}`,
+			token:  adminToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{
+					ExpectUpdateRole: func(c context.Context, id int64, role entity.Role) (dto.AccountView, error) {
+						testutil.AssertEq(t, "id", int64(2), id)
+						testutil.AssertEq(t, "role", entity.RoleSupport, role)
+						view := testutil.NewAccountView(id, "Lia", "00000000000", 0, time.Now())
+						view.Role = role
+						return view, nil
+					},
+				}
+			},
+		},
+		{
+			name:   "put '/{id}/role' with invalid body",
+			status: http.StatusBadRequest,
+			body:   `{"role":`,
+			token:  adminToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "put '/{id}/role' as support",
+			status: http.StatusForbidden,
+			body:   `{"role":"admin"}`,
+			token:  supportToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service()
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPut, "/2/role", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
+
+func TestRoutingAccountPutStatus(t *testing.T) {
+	adminToken, _, _ := jwtHandler.Generate(1, entity.RoleAdmin, jwt.RoleScopes(entity.RoleAdmin))
+	supportToken, _, _ := jwtHandler.Generate(1, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
+	tt := []struct {
+		name    string
+		service func() service.Account
+		status  int
+		body    string
+		token   string
+	}{
+		{
+			name:   "put '/{id}/status' successfully",
+			status: http.StatusOK,
+			body:   `{"status":"blocked","reason":"fraud investigation"}`,
+			token:  adminToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{
+					ExpectUpdateStatus: func(c context.Context, r dto.Requester, id int64, status entity.Status, reason string) (dto.AccountView, error) {
+						testutil.AssertEq(t, "requester", int64(1), r.ID)
+						testutil.AssertEq(t, "id", int64(2), id)
+						testutil.AssertEq(t, "status", entity.StatusBlocked, status)
+						testutil.AssertEq(t, "reason", "fraud investigation", reason)
+						view := testutil.NewAccountView(id, "Lia", "00000000000", 0, time.Now())
+						view.Status = status
+						return view, nil
+					},
+				}
+			},
+		},
+		{
+			name:   "put '/{id}/status' of a closed account",
+			status: http.StatusConflict,
+			body:   `{"status":"active","reason":"requested by the holder"}`,
+			token:  adminToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{
+					ExpectUpdateStatus: func(c context.Context, r dto.Requester, id int64, status entity.Status, reason string) (dto.AccountView, error) {
+						return dto.AccountView{}, types.NewErr(types.ConflictErr, "account '2' is closed and can't be reopened", nil)
+					},
+				}
+			},
+		},
+		{
+			name:   "put '/{id}/status' with invalid body",
+			status: http.StatusBadRequest,
+			body:   `{"status":`,
+			token:  adminToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+		{
+			name:   "put '/{id}/status' as support",
+			status: http.StatusForbidden,
+			body:   `{"status":"blocked","reason":"fraud investigation"}`,
+			token:  supportToken,
+			service: func() service.Account {
+				return &testutil.AccountServMock{}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service()
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPut, "/2/status", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
+
+func TestRoutingAccountGetStatusChanges(t *testing.T) {
+	tt := []struct {
+		name    string
+		role    entity.Role
+		status  int
+		fetch   func(ctx context.Context, id int64) ([]dto.StatusChangeView, error)
+		changes int
+	}{
+		{
+			name:   "get '/{id}/status-changes' successfully",
+			role:   entity.RoleSupport,
+			status: http.StatusOK,
+			fetch: func(ctx context.Context, id int64) ([]dto.StatusChangeView, error) {
+				testutil.AssertEq(t, "id", int64(2), id)
+				return []dto.StatusChangeView{
+					{From: entity.StatusActive, To: entity.StatusBlocked, Reason: "fraud investigation", ChangedBy: 1},
+					{From: entity.StatusBlocked, To: entity.StatusActive, Reason: "investigation closed", ChangedBy: 1},
+				}, nil
+			},
+			changes: 2,
+		},
+		{
+			name:   "get '/{id}/status-changes' of a nonexistent account",
+			role:   entity.RoleAdmin,
+			status: http.StatusNotFound,
+			fetch: func(ctx context.Context, id int64) ([]dto.StatusChangeView, error) {
+				return nil, types.NewErr(types.EmptyResultErr, "account '2' was not found", nil)
+			},
+		},
+		{
+			name:   "get '/{id}/status-changes' as customer",
+			role:   entity.RoleCustomer,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{ExpectFetchStatusChanges: tc.fetch}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodGet, "/2/status-changes", nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			token, _, _ := jwtHandler.Generate(1, tc.role, jwt.RoleScopes(tc.role))
+			req.Header.Set("Authorization", "Bearer "+token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.status == http.StatusOK {
+				var changes []dto.StatusChangeView
+				if err = json.NewDecoder(res.Body).Decode(&changes); err != nil {
+					t.Fatalf("unable to decode the response body: %v", err)
+				}
+				testutil.AssertEq(t, "changes", tc.changes, len(changes))
+			}
+		})
+	}
+}
+
+func TestRoutingAccountGetReconciliation(t *testing.T) {
+	tt := []struct {
+		name      string
+		role      entity.Role
+		status    int
+		reconcile func(ctx context.Context, id int64) (dto.ReconciliationView, error)
+		body      string
+	}{
+		{
+			name:   "get '/{id}/reconciliation' successfully",
+			role:   entity.RoleSupport,
+			status: http.StatusOK,
+			reconcile: func(ctx context.Context, id int64) (dto.ReconciliationView, error) {
+				testutil.AssertEq(t, "id", int64(2), id)
+				return dto.ReconciliationView{Balance: "500.00", LedgerBalance: "500.00", Currency: types.BRL, Reconciled: true}, nil
+			},
+			body: `{"balance":500.00,"ledger_balance":500.00,"currency":"BRL","reconciled":true}`,
+		},
+		{
+			name:   "get '/{id}/reconciliation' of a nonexistent account",
+			role:   entity.RoleAdmin,
+			status: http.StatusNotFound,
+			reconcile: func(ctx context.Context, id int64) (dto.ReconciliationView, error) {
+				return dto.ReconciliationView{}, types.NewErr(types.EmptyResultErr, "no sql rows", nil)
+			},
+		},
+		{
+			name:   "get '/{id}/reconciliation' as customer",
+			role:   entity.RoleCustomer,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{ExpectReconcile: tc.reconcile}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodGet, "/2/reconciliation", nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			token, _, _ := jwtHandler.Generate(1, tc.role, jwt.RoleScopes(tc.role))
+			req.Header.Set("Authorization", "Bearer "+token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.status == http.StatusOK {
+				testutil.AssertEq(t, "body", tc.body, strings.TrimSpace(res.Body.String()))
+			}
+		})
+	}
+}
+
+func TestRoutingAccountDeleteLock(t *testing.T) {
+	tt := []struct {
+		name   string
+		role   entity.Role
+		status int
+		unlock func(ctx context.Context, account int64) error
+	}{
+		{
+			name:   "delete '/{id}/lock' successfully",
+			role:   entity.RoleSupport,
+			status: http.StatusNoContent,
+			unlock: func(ctx context.Context, account int64) error {
+				testutil.AssertEq(t, "account", int64(2), account)
+				return nil
+			},
+		},
+		{
+			name:   "delete '/{id}/lock' of a nonexistent account",
+			role:   entity.RoleAdmin,
+			status: http.StatusNotFound,
+			unlock: func(ctx context.Context, account int64) error {
+				return types.NewErr(types.EmptyResultErr, "no result getting account", nil)
+			},
+		},
+		{
+			name:   "delete '/{id}/lock' as customer",
+			role:   entity.RoleCustomer,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{}
+			var guard service.LoginGuard = &testutil.LoginGuardServMock{ExpectUnlock: tc.unlock}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &guard, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodDelete, "/2/lock", nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			token, _, _ := jwtHandler.Generate(1, tc.role, jwt.RoleScopes(tc.role))
+			req.Header.Set("Authorization", "Bearer "+token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
+
+func TestRoutingAccountTOTP(t *testing.T) {
+	tt := []struct {
+		name      string
+		path      string
+		body      string
+		account   int64
+		twoFactor service.TwoFactor
+		status    int
+	}{
+		{
+			name:    "post '/{id}/totp' successfully",
+			path:    "/1/totp",
+			account: 1,
+			twoFactor: &testutil.TwoFactorServMock{
+				ExpectEnroll: func(ctx context.Context, requester dto.Requester, id int64) (dto.TOTPEnrollment, error) {
+					testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+					testutil.AssertEq(t, "id", int64(1), id)
+					return dto.TOTPEnrollment{Secret: "GEZDGNBVGY3TQOJQ", URI: "otpauth://totp/stn-accounts:1"}, nil
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "post '/{id}/totp' of another account",
+			path:    "/2/totp",
+			account: 1,
+			twoFactor: &testutil.TwoFactorServMock{
+				ExpectEnroll: func(ctx context.Context, requester dto.Requester, id int64) (dto.TOTPEnrollment, error) {
+					return dto.TOTPEnrollment{}, types.NewErr(types.AuthorizationErr, "account '1' isn't allowed to access account '2'", nil)
+				},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:      "post '/{id}/totp' without auth header",
+			path:      "/1/totp",
+			twoFactor: &testutil.TwoFactorServMock{},
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:    "post '/{id}/totp/activation' successfully",
+			path:    "/1/totp/activation",
+			body:    `{"code":"123456"}`,
+			account: 1,
+			twoFactor: &testutil.TwoFactorServMock{
+				ExpectActivate: func(ctx context.Context, requester dto.Requester, id int64, code string) (dto.RecoveryCodes, error) {
+					testutil.AssertEq(t, "code", "123456", code)
+					return dto.RecoveryCodes{Codes: []string{"abcd-efgh"}}, nil
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "post '/{id}/totp/activation' with wrong code",
+			path:    "/1/totp/activation",
+			body:    `{"code":"000000"}`,
+			account: 1,
+			twoFactor: &testutil.TwoFactorServMock{
+				ExpectActivate: func(ctx context.Context, requester dto.Requester, id int64, code string) (dto.RecoveryCodes, error) {
+					return dto.RecoveryCodes{}, types.NewErr(types.ValidationErr, "field 'code' doesn't match the enrolled secret", nil)
+				},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:      "post '/{id}/totp/activation' with malformed body",
+			path:      "/1/totp/activation",
+			body:      `{"code":`,
+			account:   1,
+			twoFactor: &testutil.TwoFactorServMock{},
+			status:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &tc.twoFactor, &secretSrv, &cashSrv, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			if tc.account != 0 {
+				token, _, _ := jwtHandler.Generate(tc.account, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
+
+func TestRoutingAccountPutSecret(t *testing.T) {
+	tt := []struct {
+		name    string
+		path    string
+		body    string
+		account int64
+		change  func(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error
+		status  int
+	}{
+		{
+			name:    "put '/{id}/secret' successfully",
+			path:    "/1/secret",
+			body:    `{"current_secret":"old","secret":"new"}`,
+			account: 1,
+			change: func(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error {
+				testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+				testutil.AssertEq(t, "id", int64(1), id)
+				testutil.AssertEq(t, "current secret", "old", current)
+				testutil.AssertEq(t, "secret", "new", secret)
+				return nil
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "put '/{id}/secret' with wrong current secret",
+			path:    "/1/secret",
+			body:    `{"current_secret":"wrong","secret":"new"}`,
+			account: 1,
+			change: func(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error {
+				return types.NewErr(types.ValidationErr, "field 'current_secret' doesn't match the account's secret", nil)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "put '/{id}/secret' of another account",
+			path:    "/2/secret",
+			body:    `{"current_secret":"old","secret":"new"}`,
+			account: 1,
+			change: func(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error {
+				return types.NewErr(types.AuthorizationErr, "account '1' isn't allowed to access account '2'", nil)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:    "put '/{id}/secret' with malformed body",
+			path:    "/1/secret",
+			body:    `{"secret":`,
+			account: 1,
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:   "put '/{id}/secret' without auth header",
+			path:   "/1/secret",
+			body:   `{"current_secret":"old","secret":"new"}`,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{}
+			var secret service.Secret = &testutil.SecretServMock{ExpectChange: tc.change}
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secret, &cashSrv, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body))
+			if tc.account != 0 {
+				token, _, _ := jwtHandler.Generate(tc.account, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
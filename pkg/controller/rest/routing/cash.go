@@ -0,0 +1,89 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Deposits money into the account specified by the given ID
+// @Description Reported by back-office clients granted the 'cash:write' scope. The reference identifies the deposit at the external system and can't be reported twice
+// @tags v1
+// @ID post-account-deposit
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param req body dto.CashOperationCreation true "Deposit Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
+// @Success 200 {object} dto.CashOperationView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/deposits [post]
+// @Security ApiKeyAuth
+func (h *accountHandler) postDeposit(w http.ResponseWriter, r *http.Request) {
+	h.postCashOperation(w, r, (*h.cashSrv).Deposit)
+}
+
+// @Summary Withdraws money from the account specified by the given ID
+// @Description Reported by back-office clients granted the 'cash:write' scope. The reference identifies the withdrawal at the external system and can't be reported twice
+// @tags v1
+// @ID post-account-withdrawal
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param req body dto.CashOperationCreation true "Withdrawal Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
+// @Success 200 {object} dto.CashOperationView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/withdrawals [post]
+// @Security ApiKeyAuth
+func (h *accountHandler) postWithdrawal(w http.ResponseWriter, r *http.Request) {
+	h.postCashOperation(w, r, (*h.cashSrv).Withdraw)
+}
+
+// postCashOperation decodes the cash operation from the request body and reports it through the given service operation
+func (h *accountHandler) postCashOperation(w http.ResponseWriter, r *http.Request, operate func(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error)) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	var cashCreation dto.CashOperationCreation
+	if err = json.NewDecoder(r.Body).Decode(&cashCreation); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode the cash operation from the request body")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	view, err := operate(r.Context(), principal.Requester(), id, cashCreation)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the cash operation into the response")
+		response.WriteErr(w, r, err)
+	}
+}
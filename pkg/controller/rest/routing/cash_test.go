@@ -0,0 +1,126 @@
+package routing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingCashOperation(t *testing.T) {
+	clientToken, _, _ := jwtHandler.GenerateClient("branch", []string{jwt.ScopeCashWrite})
+	readOnlyToken, _, _ := jwtHandler.GenerateClient("batch", []string{jwt.ScopeAccountsRead})
+	adminToken, _, _ := jwtHandler.Generate(1, entity.RoleAdmin, jwt.RoleScopes(entity.RoleAdmin))
+	operate := func(t *testing.T, expected entity.CashType) func(context.Context, dto.Requester, int64, dto.CashOperationCreation) (dto.CashOperationView, error) {
+		return func(c context.Context, r dto.Requester, account int64, d dto.CashOperationCreation) (dto.CashOperationView, error) {
+			testutil.AssertEq(t, "client id", "branch", r.ClientID)
+			testutil.AssertEq(t, "account", int64(2), account)
+			testutil.AssertEq(t, "amount", types.Decimal("100.00"), d.Amount)
+			testutil.AssertEq(t, "reference", "branch-0042-000981", d.Reference)
+			return dto.CashOperationView{ID: 1, AccountID: account, Type: expected, Amount: d.Amount, Currency: types.BRL, Reference: d.Reference, CreatedAt: time.Now()}, nil
+		}
+	}
+	tt := []struct {
+		name    string
+		path    string
+		token   string
+		body    string
+		service func(*testing.T) service.Cash
+		status  int
+	}{
+		{
+			name:  "post '/{id}/deposits' successfully",
+			path:  "/2/deposits",
+			token: clientToken,
+			body:  `{"amount":"100.00","reference":"branch-0042-000981"}`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{ExpectDeposit: operate(t, entity.Deposit)}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "post '/{id}/withdrawals' successfully",
+			path:  "/2/withdrawals",
+			token: clientToken,
+			body:  `{"amount":"100.00","reference":"branch-0042-000981"}`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{ExpectWithdraw: operate(t, entity.Withdrawal)}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "post '/{id}/deposits' with a reported reference",
+			path:  "/2/deposits",
+			token: clientToken,
+			body:  `{"amount":"100.00","reference":"branch-0042-000981"}`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{
+					ExpectDeposit: func(c context.Context, r dto.Requester, account int64, d dto.CashOperationCreation) (dto.CashOperationView, error) {
+						return dto.CashOperationView{}, types.NewErr(types.ConflictErr, "cash operation reference already exists", nil)
+					},
+				}
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:  "post '/{id}/withdrawals' with malformed body",
+			path:  "/2/withdrawals",
+			token: clientToken,
+			body:  `{"amount":`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{}
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:  "post '/{id}/deposits' without the cash scope",
+			path:  "/2/deposits",
+			token: readOnlyToken,
+			body:  `{"amount":"100.00","reference":"branch-0042-000981"}`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{}
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:  "post '/{id}/withdrawals' as admin",
+			path:  "/2/withdrawals",
+			token: adminToken,
+			body:  `{"amount":"100.00","reference":"branch-0042-000981"}`,
+			service: func(t *testing.T) service.Cash {
+				return &testutil.CashServMock{}
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{}
+			c := tc.service(t)
+			r.Route("/", routing.Accounts(&s, &statementSrv, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &c, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rs/zerolog/log"
+)
+
+type clientHandler struct {
+	clientSrv *service.Client
+}
+
+// Clients handles the requests related to entity.Client, which are only available to admin staff
+func Clients(clientSrv *service.Client, sessionSrv *service.Session, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := clientHandler{clientSrv: clientSrv}
+	return func(r chi.Router) {
+		r.Use(middleware.NewAuthenticated(jwtHandler, sessionSrv), middleware.NewRoleRestricted(entity.RoleAdmin))
+		r.Post("/", h.post)
+		r.Delete("/{id}", h.delete)
+	}
+}
+
+// @Summary Registers a new client, which is granted tokens at /oauth/token
+// @Description Only available to admin staff. The client secret is only disclosed in this response.
+// @tags v1
+// @ID post-client-create
+// @Accept  json
+// @Produce  json
+// @Param req body dto.ClientCreation required "Client Creation Request"
+// @Header 201 {string} Location "/clients/9f86d081884c7d659a2feaa0c55ad015"
+// @Success 201 {object} dto.ClientCredentials
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /clients [post]
+// @Security ApiKeyAuth
+func (h *clientHandler) post(w http.ResponseWriter, r *http.Request) {
+	var clientCreation dto.ClientCreation
+	if err := json.NewDecoder(r.Body).Decode(&clientCreation); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode the client creation from the request body")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	credentials, err := (*h.clientSrv).Create(r.Context(), clientCreation)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	log.Info().Str("client_id", credentials.ID).Str("name", credentials.Name).Msg("client registered")
+	if err = response.WriteSuccess(w, r, credentials, credentials.ID); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the new client into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @Summary Disables the client specified by the given ID, which is no longer granted tokens
+// @Description Only available to admin staff. The tokens already granted to the client remain valid until they expire.
+// @tags v1
+// @ID delete-client
+// @Param id path string true "Client ID"
+// @Success 204
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /clients/{id} [delete]
+// @Security ApiKeyAuth
+func (h *clientHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := (*h.clientSrv).Disable(r.Context(), id); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	log.Info().Str("client_id", id).Msg("client disabled")
+	if err := response.WriteSuccess(w, r, nil, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the client deletion response")
+	}
+}
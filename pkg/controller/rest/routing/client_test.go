@@ -0,0 +1,114 @@
+package routing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingClients(t *testing.T) {
+	adminToken, _, _ := jwtHandler.Generate(1, entity.RoleAdmin, jwt.RoleScopes(entity.RoleAdmin))
+	supportToken, _, _ := jwtHandler.Generate(2, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
+	var clientSrv service.Client = &testutil.ClientServMock{
+		ExpectCreate: func(ctx context.Context, clientCreation dto.ClientCreation) (dto.ClientCredentials, error) {
+			testutil.AssertEq(t, "name", "Batch", clientCreation.Name)
+			testutil.AssertEq(t, "scopes", "transfers:read", strings.Join(clientCreation.Scopes, " "))
+			return dto.ClientCredentials{ClientView: dto.ClientView{ID: "c1", Name: clientCreation.Name, Scopes: clientCreation.Scopes}, Secret: "s3cr3t"}, nil
+		},
+		ExpectDisable: func(ctx context.Context, id string) error {
+			if id != "c1" {
+				return types.NewErr(types.EmptyResultErr, "no enabled client with id '"+id+"'", nil)
+			}
+			return nil
+		},
+	}
+	tt := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		token  string
+		status int
+	}{
+		{
+			name:   "post '/' successfully",
+			method: http.MethodPost,
+			target: "/",
+			body:   `{"name":"Batch","scopes":["transfers:read"]}`,
+			token:  adminToken,
+			status: http.StatusCreated,
+		},
+		{
+			name:   "post '/' as support staff",
+			method: http.MethodPost,
+			target: "/",
+			body:   `{"name":"Batch","scopes":["transfers:read"]}`,
+			token:  supportToken,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "post '/' as a client",
+			method: http.MethodPost,
+			target: "/",
+			body:   `{"name":"Batch","scopes":["transfers:read"]}`,
+			token:  clientToken,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "post '/' with invalid body",
+			method: http.MethodPost,
+			target: "/",
+			body:   `{"name":`,
+			token:  adminToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "delete '/c1' successfully",
+			method: http.MethodDelete,
+			target: "/c1",
+			token:  adminToken,
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "delete '/unknown'",
+			method: http.MethodDelete,
+			target: "/unknown",
+			token:  adminToken,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "delete '/c1' without auth header",
+			method: http.MethodDelete,
+			target: "/c1",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Route("/", routing.Clients(&clientSrv, &sessionSrv, jwtHandler))
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package routing
+
+import "github.com/rafael-sousa/stn-accounts/pkg/model/types"
+
+// decodeErr keeps the application errors raised while decoding a request body, such as a malformed types.Decimal,
+// and reports any other decoding failure as an invalid request body
+func decodeErr(err error) error {
+	if _, ok := err.(*types.Err); ok {
+		return err
+	}
+	return types.NewErr(types.ValidationErr, "invalid request body", err)
+}
@@ -2,41 +2,59 @@ package routing
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rs/zerolog/log"
 )
 
 type loginHandler struct {
-	accountSrv *service.Account
-	jwtHandler *jwt.Handler
+	accountSrv    *service.Account
+	sessionSrv    *service.Session
+	loginGuardSrv *service.LoginGuard
+	twoFactorSrv  *service.TwoFactor
+	secretSrv     *service.Secret
+	jwtHandler    *jwt.Handler
 }
 
-// Login exposes the route that grants user authentication
-func Login(accountSrv *service.Account, jwtHandler *jwt.Handler) func(chi.Router) {
+// Login exposes the routes that grant user authentication
+func Login(accountSrv *service.Account, sessionSrv *service.Session, loginGuardSrv *service.LoginGuard, twoFactorSrv *service.TwoFactor, secretSrv *service.Secret, jwtHandler *jwt.Handler) func(chi.Router) {
 	h := loginHandler{
-		accountSrv: accountSrv,
-		jwtHandler: jwtHandler,
+		accountSrv:    accountSrv,
+		sessionSrv:    sessionSrv,
+		loginGuardSrv: loginGuardSrv,
+		twoFactorSrv:  twoFactorSrv,
+		secretSrv:     secretSrv,
+		jwtHandler:    jwtHandler,
 	}
 	return func(r chi.Router) {
 		r.Post("/", h.post)
+		r.Post("/refresh", h.refresh)
+		r.Post("/reset", h.reset)
+		r.Post("/reset/confirmation", h.resetConfirmation)
 	}
 }
 
 // @ID post-login
 // @tags v1
-// @Summary Generates a new authorization token
+// @Summary Generates a new authorization token along with a refresh token
+// @Description Accounts that enabled the second factor must also send an authenticator or a recovery code.
+// @Description Every failed login delays the next attempt of the same CPF or client IP, and a CPF is locked out after too many of them.
 // @Accept  json
 // @Produce  json
 // @Param req body body.LoginRequest required "Login Request"
 // @Success 200 {object} body.LoginResponse
 // @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 429 {object} body.JSONError
 // @Failure 500 {object} body.JSONError
 // @Router /login [post]
 func (h *loginHandler) post(w http.ResponseWriter, r *http.Request) {
@@ -47,24 +65,148 @@ func (h *loginHandler) post(w http.ResponseWriter, r *http.Request) {
 		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
 		return
 	}
+	ip := clientIP(r)
+	if err = (*h.loginGuardSrv).Check(r.Context(), requestBody.CPF, ip); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
 	view, err := (*h.accountSrv).Login(r.Context(), requestBody.CPF, requestBody.Secret)
+	// A wrong second factor code is a failed attempt, whereas omitting it isn't
+	counted := err != nil || requestBody.TOTP != ""
+	if err == nil {
+		err = (*h.twoFactorSrv).Verify(r.Context(), view.ID, requestBody.TOTP)
+	}
+	// The failure is still reported as such if it can't be counted
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.AuthenticationErr && counted {
+		(*h.loginGuardSrv).Fail(r.Context(), requestBody.CPF, ip)
+	}
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	(*h.loginGuardSrv).Succeed(r.Context(), requestBody.CPF, ip)
+	refreshToken, err := (*h.sessionSrv).Start(r.Context(), view.ID)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	h.writeTokens(w, r, view, refreshToken)
+}
+
+// @ID post-login-refresh
+// @tags v1
+// @Summary Exchanges a refresh token for a new authorization token and a rotated refresh token
+// @Description Refresh tokens are single use. Presenting a used one revokes every refresh token issued since the login, along with every access token of the account issued so far.
+// @Accept  json
+// @Produce  json
+// @Param req body body.RefreshRequest required "Refresh Request"
+// @Success 200 {object} body.LoginResponse
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /login/refresh [post]
+func (h *loginHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	requestBody := body.RefreshRequest{}
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.RefreshRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	id, refreshToken, err := (*h.sessionSrv).Refresh(r.Context(), requestBody.RefreshToken)
 	if err != nil {
 		response.WriteErr(w, r, err)
 		return
 	}
-	token, claims, err := (*h.jwtHandler).Generate(view.ID)
+	// The role is read again so that the new access token reflects any role granted since the login
+	view, err := (*h.accountSrv).Get(r.Context(), dto.Requester{ID: id}, id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if view.Status == entity.StatusClosed {
+		response.WriteErr(w, r, types.NewErr(types.AuthenticationErr, "the account is closed", nil))
+		return
+	}
+	h.writeTokens(w, r, view, refreshToken)
+}
+
+// @ID post-login-reset
+// @tags v1
+// @Summary Sends a secret reset token to the holder of the account with the given CPF
+// @Description The response doesn't tell whether the account exists. Requesting a new token invalidates the previous ones.
+// @Accept  json
+// @Param req body body.SecretResetRequest required "Secret Reset Request"
+// @Success 202
+// @Failure 400 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /login/reset [post]
+func (h *loginHandler) reset(w http.ResponseWriter, r *http.Request) {
+	requestBody := body.SecretResetRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.SecretResetRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	if err := (*h.secretSrv).RequestReset(r.Context(), requestBody.CPF); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @ID post-login-reset-confirmation
+// @tags v1
+// @Summary Sets a new account secret through a reset token
+// @Description Reset tokens are single use and time-limited. Every session of the account is ended, along with the access tokens already issued.
+// @Accept  json
+// @Param req body body.SecretResetConfirmation required "Secret Reset Confirmation"
+// @Success 204
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /login/reset/confirmation [post]
+func (h *loginHandler) resetConfirmation(w http.ResponseWriter, r *http.Request) {
+	requestBody := body.SecretResetConfirmation{}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.SecretResetConfirmation")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	if err := (*h.secretSrv).Reset(r.Context(), requestBody.Token, requestBody.Secret); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err := response.WriteSuccess(w, r, nil, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the secret reset response")
+	}
+}
+
+// writeTokens generates an access token for the account and writes it into the response along with the refresh token
+func (h *loginHandler) writeTokens(w http.ResponseWriter, r *http.Request, view dto.AccountView, refreshToken string) {
+	token, claims, err := (*h.jwtHandler).Generate(view.ID, view.Role, jwt.RoleScopes(view.Role))
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("unable to generate the jwt token")
 		response.WriteErr(w, r, err)
 		return
 	}
 	responseBody := body.LoginResponse{
-		AccessToken: token,
-		TokenType:   "bearer",
-		ExpiresIn:   int(claims.ExpiresAt) - int(claims.IssuedAt),
+		AccessToken:  token,
+		TokenType:    "bearer",
+		ExpiresIn:    int(claims.ExpiresAt) - int(claims.IssuedAt),
+		RefreshToken: refreshToken,
 	}
 	if err = response.WriteSuccess(w, r, responseBody, nil); err != nil {
 		log.Error().Caller().Err(err).Msg("unable to encode body.LoginResponse into response")
 		response.WriteErr(w, r, err)
 	}
 }
+
+// clientIP returns the address the request came from, without its port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
@@ -93,7 +95,7 @@ func TestRoutingLoginCreate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service()
-			r.Route("/", routing.Login(&s, jwtHandler))
+			r.Route("/", routing.Login(&s, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, jwtHandler))
 
 			buffer, err := tc.reader()
 			if err != nil {
@@ -110,3 +112,335 @@ func TestRoutingLoginCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestRoutingLoginThrottle(t *testing.T) {
+	tt := []struct {
+		name   string
+		status int
+		check  error
+		login  error
+		calls  string
+	}{
+		{
+			name:   "post '/' successfully resets the failed logins",
+			status: http.StatusOK,
+			calls:  "check login succeed",
+		},
+		{
+			name:   "post '/' with wrong secret counts a failed login",
+			status: http.StatusUnauthorized,
+			login:  types.NewErr(types.AuthenticationErr, "the provided secret doesn't match the account's secret", nil),
+			calls:  "check login fail",
+		},
+		{
+			name:   "post '/' with invalid request data isn't counted",
+			status: http.StatusBadRequest,
+			login:  types.NewErr(types.ValidationErr, "field 'cpf' is required", nil),
+			calls:  "check login",
+		},
+		{
+			name:   "post '/' while locked out",
+			status: http.StatusTooManyRequests,
+			check:  types.NewErr(types.LockedErr, "too many failed logins, retry in 2 seconds", nil),
+			calls:  "check",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			assertClient := func(cpf string, ip string) {
+				testutil.AssertEq(t, "cpf", "00000000000", cpf)
+				testutil.AssertEq(t, "ip", "192.0.2.1", ip)
+			}
+			var guard service.LoginGuard = &testutil.LoginGuardServMock{
+				ExpectCheck: func(ctx context.Context, cpf string, ip string) error {
+					assertClient(cpf, ip)
+					calls = append(calls, "check")
+					return tc.check
+				},
+				ExpectFail: func(ctx context.Context, cpf string, ip string) error {
+					assertClient(cpf, ip)
+					calls = append(calls, "fail")
+					return nil
+				},
+				ExpectSucceed: func(ctx context.Context, cpf string, ip string) error {
+					assertClient(cpf, ip)
+					calls = append(calls, "succeed")
+					return nil
+				},
+			}
+			var s service.Account = &testutil.AccountServMock{
+				ExpectLogin: func(c context.Context, cpf string, secret string) (dto.AccountView, error) {
+					calls = append(calls, "login")
+					if tc.login != nil {
+						return dto.AccountView{}, tc.login
+					}
+					return testutil.NewAccountView(1, "Lucas", cpf, 0, time.Now()), nil
+				},
+			}
+			r := chi.NewRouter()
+			r.Route("/", routing.Login(&s, &sessionSrv, &guard, &twoFactorSrv, &secretSrv, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"cpf":"00000000000","secret":"pw"}`))
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+		})
+	}
+}
+
+func TestRoutingLoginTwoFactor(t *testing.T) {
+	tt := []struct {
+		name   string
+		status int
+		body   string
+		verify error
+		calls  string
+	}{
+		{
+			name:   "post '/' with a valid second factor code",
+			status: http.StatusOK,
+			body:   `{"cpf":"00000000000","secret":"pw","totp":"123456"}`,
+			calls:  "check login verify succeed",
+		},
+		{
+			name:   "post '/' without the required second factor code isn't counted",
+			status: http.StatusUnauthorized,
+			body:   `{"cpf":"00000000000","secret":"pw"}`,
+			verify: types.NewErr(types.AuthenticationErr, "the second factor code is required", nil),
+			calls:  "check login verify",
+		},
+		{
+			name:   "post '/' with wrong second factor code counts a failed login",
+			status: http.StatusUnauthorized,
+			body:   `{"cpf":"00000000000","secret":"pw","totp":"000000"}`,
+			verify: types.NewErr(types.AuthenticationErr, "invalid second factor code", nil),
+			calls:  "check login verify fail",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var guard service.LoginGuard = &testutil.LoginGuardServMock{
+				ExpectCheck: func(ctx context.Context, cpf string, ip string) error {
+					calls = append(calls, "check")
+					return nil
+				},
+				ExpectFail: func(ctx context.Context, cpf string, ip string) error {
+					calls = append(calls, "fail")
+					return nil
+				},
+				ExpectSucceed: func(ctx context.Context, cpf string, ip string) error {
+					calls = append(calls, "succeed")
+					return nil
+				},
+			}
+			var s service.Account = &testutil.AccountServMock{
+				ExpectLogin: func(c context.Context, cpf string, secret string) (dto.AccountView, error) {
+					calls = append(calls, "login")
+					return testutil.NewAccountView(1, "Lucas", cpf, 0, time.Now()), nil
+				},
+			}
+			var twoFactor service.TwoFactor = &testutil.TwoFactorServMock{
+				ExpectVerify: func(ctx context.Context, account int64, code string) error {
+					testutil.AssertEq(t, "account", int64(1), account)
+					calls = append(calls, "verify")
+					return tc.verify
+				},
+			}
+			r := chi.NewRouter()
+			r.Route("/", routing.Login(&s, &sessionSrv, &guard, &twoFactor, &secretSrv, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+		})
+	}
+}
+
+func TestRoutingLoginRefresh(t *testing.T) {
+	tt := []struct {
+		name          string
+		session       func() service.Session
+		accountStatus entity.Status
+		status        int
+		body          string
+		assertRes     func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "post '/refresh' successfully",
+			status: http.StatusOK,
+			body:   `{"refresh_token":"old"}`,
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRefresh: func(c context.Context, refreshToken string) (int64, string, error) {
+						testutil.AssertEq(t, "refresh token", "old", refreshToken)
+						return 1, "new", nil
+					},
+				}
+			},
+			assertRes: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var res body.LoginResponse
+				if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+					t.Fatalf("unable to decode the response body, %v", err)
+				}
+				testutil.AssertEq(t, "refresh token", "new", res.RefreshToken)
+				claims, err := jwtHandler.Parse(res.AccessToken)
+				if err != nil {
+					t.Fatalf("unable to parse the access token, %v", err)
+				}
+				testutil.AssertEq(t, "access token role", entity.RoleSupport, claims.Role)
+			},
+		},
+		{
+			name:   "post '/refresh' with a reused refresh token",
+			status: http.StatusUnauthorized,
+			body:   `{"refresh_token":"used"}`,
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRefresh: func(c context.Context, refreshToken string) (int64, string, error) {
+						return 0, "", types.NewErr(types.AuthenticationErr, "the refresh token was already used, the session has been revoked", nil)
+					},
+				}
+			},
+		},
+		{
+			name:          "post '/refresh' of a closed account",
+			status:        http.StatusUnauthorized,
+			body:          `{"refresh_token":"old"}`,
+			accountStatus: entity.StatusClosed,
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRefresh: func(c context.Context, refreshToken string) (int64, string, error) {
+						return 1, "new", nil
+					},
+				}
+			},
+		},
+		{
+			name:   "post '/refresh' with malformed body",
+			status: http.StatusBadRequest,
+			body:   `{"refresh_token":`,
+			session: func() service.Session {
+				return &testutil.SessionServMock{}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{
+				ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.AccountView, error) {
+					testutil.AssertEq(t, "id", int64(1), id)
+					view := testutil.NewAccountView(id, "Lia", "00000000000", 0, time.Now())
+					view.Role = entity.RoleSupport
+					if tc.accountStatus != "" {
+						view.Status = tc.accountStatus
+					}
+					return view, nil
+				},
+			}
+			session := tc.session()
+			r.Route("/", routing.Login(&s, &session, &loginGuardSrv, &twoFactorSrv, &secretSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPost, "/refresh", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.assertRes != nil {
+				tc.assertRes(t, res)
+			}
+		})
+	}
+}
+
+func TestRoutingLoginReset(t *testing.T) {
+	tt := []struct {
+		name   string
+		path   string
+		body   string
+		secret service.Secret
+		status int
+	}{
+		{
+			name: "post '/reset' successfully",
+			path: "/reset",
+			body: `{"cpf":"41112075020"}`,
+			secret: &testutil.SecretServMock{
+				ExpectRequestReset: func(ctx context.Context, cpf string) error {
+					testutil.AssertEq(t, "cpf", "41112075020", cpf)
+					return nil
+				},
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name: "post '/reset' with malformed cpf",
+			path: "/reset",
+			body: `{"cpf":"4111207502"}`,
+			secret: &testutil.SecretServMock{
+				ExpectRequestReset: func(ctx context.Context, cpf string) error {
+					return types.NewErr(types.ValidationErr, "field 'cpf' has an invalid format", nil)
+				},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "post '/reset' with malformed body",
+			path:   "/reset",
+			body:   `{"cpf":`,
+			secret: &testutil.SecretServMock{},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "post '/reset/confirmation' successfully",
+			path: "/reset/confirmation",
+			body: `{"token":"reset-token","secret":"new"}`,
+			secret: &testutil.SecretServMock{
+				ExpectReset: func(ctx context.Context, token string, secret string) error {
+					testutil.AssertEq(t, "token", "reset-token", token)
+					testutil.AssertEq(t, "secret", "new", secret)
+					return nil
+				},
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name: "post '/reset/confirmation' with expired token",
+			path: "/reset/confirmation",
+			body: `{"token":"reset-token","secret":"new"}`,
+			secret: &testutil.SecretServMock{
+				ExpectReset: func(ctx context.Context, token string, secret string) error {
+					return types.NewErr(types.AuthenticationErr, "expired secret reset token", nil)
+				},
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var s service.Account = &testutil.AccountServMock{}
+			r.Route("/", routing.Login(&s, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &tc.secret, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
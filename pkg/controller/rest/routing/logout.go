@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rs/zerolog/log"
+)
+
+type logoutHandler struct {
+	sessionSrv *service.Session
+}
+
+// Logout exposes the route that ends the current authenticated session
+func Logout(sessionSrv *service.Session, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := logoutHandler{sessionSrv: sessionSrv}
+	return func(r chi.Router) {
+		r.Use(middleware.NewAuthenticated(jwtHandler, sessionSrv))
+		r.Post("/", h.post)
+	}
+}
+
+// @ID post-logout
+// @tags v1
+// @Summary Revokes the current authorization token and, when given, the refresh tokens of its session
+// @Accept  json
+// @Produce  json
+// @Param req body body.LogoutRequest false "Logout Request"
+// @Success 204
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /logout [post]
+// @Security ApiKeyAuth
+func (h *logoutHandler) post(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	requestBody := body.LogoutRequest{}
+	// The body is optional, logging out without a refresh token only revokes the access token
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && err != io.EOF {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as body.LogoutRequest")
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "invalid request body", nil))
+		return
+	}
+	if err := (*h.sessionSrv).End(r.Context(), principal.AccountID, requestBody.RefreshToken, principal.TokenID, principal.ExpiresAt); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err := response.WriteSuccess(w, r, nil, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the logout response")
+	}
+}
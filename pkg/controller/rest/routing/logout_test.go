@@ -0,0 +1,134 @@
+package routing_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingLogout(t *testing.T) {
+	token, claims, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	tt := []struct {
+		name    string
+		session func() service.Session
+		status  int
+		body    io.Reader
+		headers map[string]string
+	}{
+		{
+			name:   "post '/' without auth header",
+			status: http.StatusUnauthorized,
+			session: func() service.Session {
+				return &testutil.SessionServMock{}
+			},
+		},
+		{
+			name:   "post '/' with refresh token successfully",
+			status: http.StatusNoContent,
+			body:   strings.NewReader(`{"refresh_token":"refresh"}`),
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRevoked: func(c context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+						return false, nil
+					},
+					ExpectEnd: func(c context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error {
+						testutil.AssertEq(t, "account", int64(1), account)
+						testutil.AssertEq(t, "refresh token", "refresh", refreshToken)
+						testutil.AssertEq(t, "jti", claims.Id, jti)
+						testutil.AssertEq(t, "expires at", claims.ExpiresAt, expiresAt.Unix())
+						return nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/' without body successfully",
+			status: http.StatusNoContent,
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRevoked: func(c context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+						return false, nil
+					},
+					ExpectEnd: func(c context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error {
+						testutil.AssertEq(t, "refresh token", "", refreshToken)
+						return nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/' with revoked access token",
+			status: http.StatusUnauthorized,
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRevoked: func(c context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+						return true, nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/' with refresh token of another account",
+			status: http.StatusUnauthorized,
+			body:   strings.NewReader(`{"refresh_token":"other"}`),
+			session: func() service.Session {
+				return &testutil.SessionServMock{
+					ExpectRevoked: func(c context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+						return false, nil
+					},
+					ExpectEnd: func(c context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error {
+						return types.NewErr(types.AuthenticationErr, "invalid refresh token", nil)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.session()
+			r.Route("/", routing.Logout(&s, jwtHandler))
+
+			body := tc.body
+			if body == nil {
+				body = http.NoBody
+			}
+			req, err := http.NewRequest(http.MethodPost, "/", body)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			for k, v := range tc.headers {
+				req.Header.Add(k, v)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
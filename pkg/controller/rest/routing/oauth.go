@@ -0,0 +1,119 @@
+package routing
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rs/zerolog/log"
+)
+
+type oauthHandler struct {
+	clientSrv  *service.Client
+	jwtHandler *jwt.Handler
+}
+
+// OAuth exposes the OAuth 2.0 token endpoint, which grants access tokens to the registered clients
+func OAuth(clientSrv *service.Client, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := oauthHandler{clientSrv: clientSrv, jwtHandler: jwtHandler}
+	return func(r chi.Router) {
+		r.Post("/token", h.postToken)
+	}
+}
+
+// @ID post-oauth-token
+// @tags v1
+// @Summary Grants an authorization token to a client through the OAuth 2.0 client credentials grant
+// @Description The client authenticates either with HTTP Basic or with the client_id and client_secret parameters.
+// @Description The token is granted the requested scopes, or every scope of the client when none is requested. It can't be refreshed.
+// @Accept  x-www-form-urlencoded
+// @Produce  json
+// @Param grant_type formData string true "Grant type" Enums(client_credentials)
+// @Param scope formData string false "Space delimited scopes"
+// @Param client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success 200 {object} body.TokenResponse
+// @Failure 400 {object} body.OAuthError
+// @Failure 401 {object} body.OAuthError
+// @Failure 500 {object} body.JSONError
+// @Router /oauth/token [post]
+func (h *oauthHandler) postToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		response.WriteOAuthErr(w, http.StatusBadRequest, "invalid_request", "unable to parse the request form")
+		return
+	}
+	if grantType := r.PostForm.Get("grant_type"); grantType != "client_credentials" {
+		if grantType == "" {
+			response.WriteOAuthErr(w, http.StatusBadRequest, "invalid_request", "parameter 'grant_type' is required")
+			return
+		}
+		response.WriteOAuthErr(w, http.StatusBadRequest, "unsupported_grant_type", "only the 'client_credentials' grant is supported")
+		return
+	}
+	id, secret, basic := r.BasicAuth()
+	if basic {
+		if r.PostForm.Get("client_id") != "" || r.PostForm.Get("client_secret") != "" {
+			response.WriteOAuthErr(w, http.StatusBadRequest, "invalid_request", "the client must authenticate with a single method")
+			return
+		}
+		// RFC 6749 form-encodes the credentials before encoding them as HTTP Basic
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	view, err := (*h.clientSrv).Authenticate(r.Context(), id, secret)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.AuthenticationErr {
+		if basic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		response.WriteOAuthErr(w, http.StatusUnauthorized, "invalid_client", customErr.Msg)
+		return
+	}
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	scopes := view.Scopes
+	if requested := strings.Fields(r.PostForm.Get("scope")); len(requested) > 0 {
+		for _, scope := range requested {
+			if !contains(view.Scopes, scope) {
+				response.WriteOAuthErr(w, http.StatusBadRequest, "invalid_scope", "the scope '"+scope+"' isn't granted to the client")
+				return
+			}
+		}
+		scopes = requested
+	}
+	token, claims, err := (*h.jwtHandler).GenerateClient(view.ID, scopes)
+	if err != nil {
+		log.Error().Caller().Err(err).Str("client_id", view.ID).Msg("unable to generate the jwt token")
+		response.WriteErr(w, r, err)
+		return
+	}
+	responseBody := body.TokenResponse{
+		AccessToken: token,
+		TokenType:   "bearer",
+		ExpiresIn:   int(claims.ExpiresAt) - int(claims.IssuedAt),
+		Scope:       strings.Join(scopes, " "),
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if err = response.WriteSuccess(w, r, responseBody, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode body.TokenResponse into response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package routing_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/body"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingOAuthToken(t *testing.T) {
+	var clientSrv service.Client = &testutil.ClientServMock{
+		ExpectAuthenticate: func(ctx context.Context, id string, secret string) (dto.ClientView, error) {
+			if id != "batch" || secret != "s3cr3t" {
+				return dto.ClientView{}, types.NewErr(types.AuthenticationErr, "invalid client credentials", nil)
+			}
+			return dto.ClientView{ID: id, Scopes: []string{entity.ScopeAccountsRead, entity.ScopeTransfersRead}}, nil
+		},
+	}
+	tt := []struct {
+		name      string
+		form      url.Values
+		basicAuth []string
+		status    int
+		errCode   string
+		scope     string
+	}{
+		{
+			name:   "post '/token' with body credentials successfully",
+			form:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"batch"}, "client_secret": {"s3cr3t"}},
+			status: http.StatusOK,
+			scope:  "accounts:read transfers:read",
+		},
+		{
+			name:      "post '/token' with basic credentials and narrowed scope successfully",
+			form:      url.Values{"grant_type": {"client_credentials"}, "scope": {"transfers:read"}},
+			basicAuth: []string{"batch", "s3cr3t"},
+			status:    http.StatusOK,
+			scope:     "transfers:read",
+		},
+		{
+			name:      "post '/token' with wrong secret",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: []string{"batch", "wrong"},
+			status:    http.StatusUnauthorized,
+			errCode:   "invalid_client",
+		},
+		{
+			name:      "post '/token' with both credential methods",
+			form:      url.Values{"grant_type": {"client_credentials"}, "client_id": {"batch"}},
+			basicAuth: []string{"batch", "s3cr3t"},
+			status:    http.StatusBadRequest,
+			errCode:   "invalid_request",
+		},
+		{
+			name:    "post '/token' without grant type",
+			form:    url.Values{"client_id": {"batch"}, "client_secret": {"s3cr3t"}},
+			status:  http.StatusBadRequest,
+			errCode: "invalid_request",
+		},
+		{
+			name:    "post '/token' with password grant",
+			form:    url.Values{"grant_type": {"password"}, "client_id": {"batch"}, "client_secret": {"s3cr3t"}},
+			status:  http.StatusBadRequest,
+			errCode: "unsupported_grant_type",
+		},
+		{
+			name:    "post '/token' with scope not granted to the client",
+			form:    url.Values{"grant_type": {"client_credentials"}, "client_id": {"batch"}, "client_secret": {"s3cr3t"}, "scope": {"transfers:write"}},
+			status:  http.StatusBadRequest,
+			errCode: "invalid_scope",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Route("/", routing.OAuth(&clientSrv, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.basicAuth != nil {
+				req.SetBasicAuth(tc.basicAuth[0], tc.basicAuth[1])
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "cache control", "no-store", res.Header().Get("Cache-Control"))
+			if tc.errCode != "" {
+				var oauthErr body.OAuthError
+				testutil.AssertNoErr(t, json.NewDecoder(res.Body).Decode(&oauthErr))
+				testutil.AssertEq(t, "error", tc.errCode, oauthErr.Error)
+				return
+			}
+			var tokenResponse body.TokenResponse
+			testutil.AssertNoErr(t, json.NewDecoder(res.Body).Decode(&tokenResponse))
+			testutil.AssertEq(t, "scope", tc.scope, tokenResponse.Scope)
+			claims, err := jwtHandler.Parse(tokenResponse.AccessToken)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "client id", "batch", claims.ClientID)
+			testutil.AssertEq(t, "token scopes", tc.scope, strings.Join(claims.Scopes, " "))
+		})
+	}
+}
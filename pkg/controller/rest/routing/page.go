@@ -0,0 +1,67 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// parsePage reads the pagination values from the limit, cursor and sort query params
+func parsePage(q url.Values) (p dto.PageRequest, err error) {
+	if v := q.Get("limit"); v != "" {
+		if p.Limit, err = strconv.Atoi(v); err != nil {
+			return p, types.NewErr(types.ValidationErr, "query param 'limit' must be an integer", err)
+		}
+	}
+	p.Cursor = q.Get("cursor")
+	p.Sort = q.Get("sort")
+	return p, nil
+}
+
+// parseTimeParam reads the query param n as either a RFC 3339 date-time or a full-date, such as "2021-01-31"
+func parseTimeParam(q url.Values, n string) (time.Time, error) {
+	v := q.Get(n)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return t, types.NewErr(types.ValidationErr, fmt.Sprintf("query param '%s' must be a RFC 3339 date-time or full-date", n), err)
+	}
+	return t, nil
+}
+
+// parseDecimalParam reads the query param n as a decimal number
+func parseDecimalParam(q url.Values, n string) (types.Decimal, error) {
+	v := q.Get(n)
+	if v == "" {
+		return "", nil
+	}
+	d, err := types.ParseDecimal(v)
+	if err != nil {
+		return d, types.NewErr(types.ValidationErr, fmt.Sprintf("query param '%s' must be a decimal number", n), err)
+	}
+	return d, nil
+}
+
+// nextLink returns the request URI pointing to the page at cursor and sets it as the Link header.
+// It returns an empty string when there's no next page
+func nextLink(w http.ResponseWriter, r *http.Request, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	q := r.URL.Query()
+	q.Set("cursor", cursor)
+	u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+	link := u.RequestURI()
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", link))
+	return link
+}
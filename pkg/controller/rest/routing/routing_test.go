@@ -1,19 +1,63 @@
 package routing_test
 
 import (
+	"context"
+	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
 
 var jwtHandler *jwt.Handler
+var statementSrv service.Statement = &testutil.StatementServMock{}
+var sessionSrv service.Session = &testutil.SessionServMock{
+	ExpectStart: func(ctx context.Context, account int64) (string, error) {
+		return "refresh-token", nil
+	},
+	ExpectRevoked: func(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+		return false, nil
+	},
+}
+var idempotencySrv service.Idempotency = &testutil.IdempotencyServMock{}
+var loginGuardSrv service.LoginGuard = &testutil.LoginGuardServMock{
+	ExpectCheck: func(ctx context.Context, cpf string, ip string) error {
+		return nil
+	},
+	ExpectFail: func(ctx context.Context, cpf string, ip string) error {
+		return nil
+	},
+	ExpectSucceed: func(ctx context.Context, cpf string, ip string) error {
+		return nil
+	},
+}
+var twoFactorSrv service.TwoFactor = &testutil.TwoFactorServMock{
+	ExpectVerify: func(ctx context.Context, account int64, code string) error {
+		return nil
+	},
+	ExpectStepUp: func(ctx context.Context, account int64, amount types.Decimal, code string) error {
+		return nil
+	},
+}
+var secretSrv service.Secret = &testutil.SecretServMock{}
+var cashSrv service.Cash = &testutil.CashServMock{}
+var scheduledTransferSrv service.ScheduledTransfer = &testutil.ScheduledTransferServMock{}
 
 func TestMain(m *testing.M) {
-	jwtHandler = jwt.NewHandler(&env.RestConfig{
+	var err error
+	jwtHandler, err = jwt.NewHandler(&env.RestConfig{
 		Secret:          []byte("secret"),
+		Issuer:          "stn-accounts",
+		Audience:        "stn-accounts",
 		TokenExpTimeout: 30,
 	})
+	if err != nil {
+		log.Fatal(err)
+	}
 	os.Exit(m.Run())
 }
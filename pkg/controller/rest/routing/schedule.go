@@ -0,0 +1,134 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rs/zerolog/log"
+)
+
+// schedule answers the transfers posted with an execution date, which are accepted to be executed later
+func (h *transferHandler) schedule(w http.ResponseWriter, r *http.Request, origin int64, transferCreation dto.TransferCreation) {
+	view, err := (*h.scheduledTransferSrv).Schedule(r.Context(), origin, transferCreation)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteLocated(w, http.StatusAccepted, view, fmt.Sprintf("/transfers/scheduled/%d", view.ID)); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the scheduled transfer into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID get-scheduled-transfer
+// @tags v1
+// @Summary Gets the transfers scheduled by the current authenticated user, sorted by execution date
+// @Produce json
+// @Param status query string false "Status of the scheduled transfers" Enums(pending, executed, failed, canceled)
+// @Success 200 {array} dto.ScheduledTransferView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /transfers/scheduled [get]
+// @Security ApiKeyAuth
+func (h *transferHandler) getScheduled(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	status := entity.ScheduleStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.Supported() {
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "query param 'status' must be one of 'pending', 'executed', 'failed', 'canceled'", nil))
+		return
+	}
+	views, err := (*h.scheduledTransferSrv).Fetch(r.Context(), principal.AccountID, status)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, views, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the scheduled transfers into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID get-scheduled-transfer-by-id
+// @tags v1
+// @Summary Gets a transfer scheduled by the current authenticated user
+// @Produce json
+// @Param id path int true "Scheduled Transfer ID"
+// @Success 200 {object} dto.ScheduledTransferView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /transfers/scheduled/{id} [get]
+// @Security ApiKeyAuth
+func (h *transferHandler) getScheduledByID(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.scheduledTransferSrv).Get(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the scheduled transfer into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID delete-scheduled-transfer
+// @tags v1
+// @Summary Cancels a pending transfer scheduled by the current authenticated user
+// @Produce json
+// @Param id path int true "Scheduled Transfer ID"
+// @Success 200 {object} dto.ScheduledTransferView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /transfers/scheduled/{id} [delete]
+// @Security ApiKeyAuth
+func (h *transferHandler) deleteScheduled(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.scheduledTransferSrv).Cancel(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the canceled scheduled transfer into the response")
+		response.WriteErr(w, r, err)
+	}
+}
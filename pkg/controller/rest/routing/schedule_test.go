@@ -0,0 +1,173 @@
+package routing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingScheduledTransfer(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
+	pending := func(id int64) dto.ScheduledTransferView {
+		return dto.ScheduledTransferView{ID: id, Origin: 1, Destination: 2, Amount: "10.50", Currency: types.BRL, Status: entity.SchedulePending}
+	}
+	tt := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    string
+		service  func() service.ScheduledTransfer
+		status   int
+		location string
+	}{
+		{
+			name:   "post '/' with execution date successfully",
+			method: http.MethodPost,
+			path:   "/",
+			body:   `{"account_destination_id":2,"amount":10.50,"execute_at":"2030-01-31T09:00:00Z"}`,
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectSchedule: func(c context.Context, origin int64, d dto.TransferCreation) (dto.ScheduledTransferView, error) {
+						testutil.AssertEq(t, "origin", int64(1), origin)
+						testutil.AssertEq(t, "execute at", time.Date(2030, 1, 31, 9, 0, 0, 0, time.UTC), d.ExecuteAt.UTC())
+						return pending(4), nil
+					},
+				}
+			},
+			status:   http.StatusAccepted,
+			location: "/transfers/scheduled/4",
+		},
+		{
+			name:   "post '/' with past execution date",
+			method: http.MethodPost,
+			path:   "/",
+			body:   `{"account_destination_id":2,"amount":10.50,"execute_at":"2020-01-31T09:00:00Z"}`,
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectSchedule: func(c context.Context, origin int64, d dto.TransferCreation) (dto.ScheduledTransferView, error) {
+						return dto.ScheduledTransferView{}, types.NewErr(types.ValidationErr, "field 'execute_at' must be in the future", nil)
+					},
+				}
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "get '/scheduled' successfully",
+			method: http.MethodGet,
+			path:   "/scheduled?status=pending",
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectFetch: func(c context.Context, origin int64, status entity.ScheduleStatus) ([]dto.ScheduledTransferView, error) {
+						testutil.AssertEq(t, "origin", int64(1), origin)
+						testutil.AssertEq(t, "status", entity.SchedulePending, status)
+						return []dto.ScheduledTransferView{pending(4)}, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get '/scheduled' with unsupported status",
+			method: http.MethodGet,
+			path:   "/scheduled?status=done",
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{}
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "get '/scheduled' with client token",
+			method: http.MethodGet,
+			path:   "/scheduled",
+			token:  clientToken,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{}
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "get '/scheduled/{id}' successfully",
+			method: http.MethodGet,
+			path:   "/scheduled/4",
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(4), id)
+						return pending(id), nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "delete '/scheduled/{id}' successfully",
+			method: http.MethodDelete,
+			path:   "/scheduled/4",
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectCancel: func(c context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error) {
+						view := pending(id)
+						view.Status = entity.ScheduleCanceled
+						return view, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "delete '/scheduled/{id}' already executed",
+			method: http.MethodDelete,
+			path:   "/scheduled/4",
+			token:  token,
+			service: func() service.ScheduledTransfer {
+				return &testutil.ScheduledTransferServMock{
+					ExpectCancel: func(c context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error) {
+						return dto.ScheduledTransferView{}, types.NewErr(types.ConflictErr, "scheduled transfer '4' is executed and can't be canceled", nil)
+					},
+				}
+			},
+			status: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := tc.service()
+			r.Route("/", routing.Transfers(&transferSrv, &s, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
+
+			req, err := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Add("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "location", tc.location, res.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rs/zerolog/log"
+)
+
+type standingOrderHandler struct {
+	standingOrderSrv *service.StandingOrder
+	twoFactorSrv     *service.TwoFactor
+}
+
+// StandingOrders handles the requests related to entity.StandingOrder
+func StandingOrders(standingOrderSrv *service.StandingOrder, idempotencySrv *service.Idempotency, sessionSrv *service.Session, twoFactorSrv *service.TwoFactor, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := standingOrderHandler{standingOrderSrv: standingOrderSrv, twoFactorSrv: twoFactorSrv}
+	return func(r chi.Router) {
+		r.Use(middleware.NewAuthenticated(jwtHandler, sessionSrv))
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/", h.get)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersWrite), middleware.NewIdempotent(idempotencySrv)).Post("/", h.post)
+		r.With(middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/{id:[\\d]+}", h.getByID)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersWrite)).Put("/{id:[\\d]+}", h.put)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersWrite)).Delete("/{id:[\\d]+}", h.delete)
+		r.With(middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/{id:[\\d]+}/executions", h.getExecutions)
+	}
+}
+
+// @ID get-standing-order
+// @tags v1
+// @Summary Gets the standing orders of the current authenticated user, sorted by creation
+// @Produce json
+// @Param status query string false "Status of the standing orders" Enums(active, finished, canceled)
+// @Success 200 {array} dto.StandingOrderView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders [get]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) get(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	status := entity.StandingOrderStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.Supported() {
+		response.WriteErr(w, r, types.NewErr(types.ValidationErr, "query param 'status' must be one of 'active', 'finished', 'canceled'", nil))
+		return
+	}
+	views, err := (*h.standingOrderSrv).Fetch(r.Context(), principal.AccountID, status)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, views, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the standing orders into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID post-standing-order
+// @tags v1
+// @Summary Creates a standing order, repeating a transfer weekly or monthly
+// @Accept  json
+// @Produce  json
+// @Param req body dto.StandingOrderCreation required "Standing Order Creation Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
+// @Param X-TOTP-Code header string false "Fresh authenticator code, required above the step-up amount"
+// @Header 201 {string} Location "/standing-orders/1"
+// @Success 201 {object} dto.StandingOrderView
+// @Failure 400 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 429 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders [post]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) post(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id := principal.AccountID
+	var standingOrderCreation dto.StandingOrderCreation
+	if err := json.NewDecoder(r.Body).Decode(&standingOrderCreation); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as standing order creation")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	if err := (*h.twoFactorSrv).StepUp(r.Context(), id, standingOrderCreation.Amount, r.Header.Get("X-TOTP-Code")); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.standingOrderSrv).Create(r.Context(), id, standingOrderCreation)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteLocated(w, http.StatusCreated, view, fmt.Sprintf("/standing-orders/%d", view.ID)); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the standing order into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID get-standing-order-by-id
+// @tags v1
+// @Summary Gets a standing order of the current authenticated user
+// @Produce json
+// @Param id path int true "Standing Order ID"
+// @Success 200 {object} dto.StandingOrderView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders/{id} [get]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.standingOrderSrv).Get(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the standing order into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID put-standing-order
+// @tags v1
+// @Summary Replaces the amount and the end of an active standing order of the current authenticated user
+// @Description The schedule itself doesn't change, the order has to be canceled and created again for that
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Standing Order ID"
+// @Param req body dto.StandingOrderUpdate required "Standing Order Update Request"
+// @Param X-TOTP-Code header string false "Fresh authenticator code, required above the step-up amount"
+// @Success 200 {object} dto.StandingOrderView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 429 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders/{id} [put]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) put(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	var standingOrderUpdate dto.StandingOrderUpdate
+	if err = json.NewDecoder(r.Body).Decode(&standingOrderUpdate); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as standing order update")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	if err = (*h.twoFactorSrv).StepUp(r.Context(), principal.AccountID, standingOrderUpdate.Amount, r.Header.Get("X-TOTP-Code")); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.standingOrderSrv).Update(r.Context(), principal.Requester(), id, standingOrderUpdate)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the updated standing order into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID delete-standing-order
+// @tags v1
+// @Summary Cancels an active standing order of the current authenticated user
+// @Produce json
+// @Param id path int true "Standing Order ID"
+// @Success 200 {object} dto.StandingOrderView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders/{id} [delete]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) delete(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.standingOrderSrv).Cancel(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the canceled standing order into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// @ID get-standing-order-executions
+// @tags v1
+// @Summary Gets the history of the executions of a standing order, sorted by their scheduled date
+// @Produce json
+// @Param id path int true "Standing Order ID"
+// @Success 200 {array} dto.StandingOrderExecutionView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /standing-orders/{id}/executions [get]
+// @Security ApiKeyAuth
+func (h *standingOrderHandler) getExecutions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	views, err := (*h.standingOrderSrv).FetchExecutions(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, views, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the standing order executions into the response")
+		response.WriteErr(w, r, err)
+	}
+}
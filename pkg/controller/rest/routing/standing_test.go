@@ -0,0 +1,242 @@
+package routing_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingStandingOrder(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	supportToken, _, _ := jwtHandler.Generate(5, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
+	// The dates sent are a year ahead, as the start of an order has to be in the future
+	startAt := time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second)
+	endAt := startAt.AddDate(0, 11, 0)
+	active := func(id int64) dto.StandingOrderView {
+		return dto.StandingOrderView{ID: id, Origin: 1, Destination: 2, Amount: "1500.00", Currency: types.BRL, Frequency: entity.FrequencyMonthly, Day: 5, Status: entity.StandingOrderActive}
+	}
+	tt := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    string
+		service  func() service.StandingOrder
+		status   int
+		location string
+	}{
+		{
+			name:   "post '/' successfully",
+			method: http.MethodPost,
+			path:   "/",
+			body:   fmt.Sprintf(`{"account_destination_id":2,"amount":1500.00,"frequency":"monthly","day_of_month":5,"start_at":"%s","count":12}`, startAt.Format(time.RFC3339)),
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectCreate: func(c context.Context, origin int64, d dto.StandingOrderCreation) (dto.StandingOrderView, error) {
+						testutil.AssertEq(t, "origin", int64(1), origin)
+						testutil.AssertEq(t, "frequency", entity.FrequencyMonthly, d.Frequency)
+						testutil.AssertEq(t, "day", 5, d.Day)
+						testutil.AssertEq(t, "count", 12, d.Count)
+						testutil.AssertEq(t, "start at", startAt, d.StartAt.UTC())
+						return active(4), nil
+					},
+				}
+			},
+			status:   http.StatusCreated,
+			location: "/standing-orders/4",
+		},
+		{
+			name:   "post '/' with unsupported frequency",
+			method: http.MethodPost,
+			path:   "/",
+			body:   fmt.Sprintf(`{"account_destination_id":2,"amount":1500.00,"frequency":"daily","start_at":"%s"}`, startAt.Format(time.RFC3339)),
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectCreate: func(c context.Context, origin int64, d dto.StandingOrderCreation) (dto.StandingOrderView, error) {
+						return dto.StandingOrderView{}, types.NewErr(types.ValidationErr, "field 'frequency' must be one of 'weekly', 'monthly'", nil)
+					},
+				}
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "post '/' with support token",
+			method: http.MethodPost,
+			path:   "/",
+			body:   fmt.Sprintf(`{"account_destination_id":2,"amount":1500.00,"frequency":"weekly","start_at":"%s"}`, startAt.Format(time.RFC3339)),
+			token:  supportToken,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{}
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "get '/' successfully",
+			method: http.MethodGet,
+			path:   "/?status=active",
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectFetch: func(c context.Context, origin int64, status entity.StandingOrderStatus) ([]dto.StandingOrderView, error) {
+						testutil.AssertEq(t, "origin", int64(1), origin)
+						testutil.AssertEq(t, "status", entity.StandingOrderActive, status)
+						return []dto.StandingOrderView{active(4)}, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get '/' with unsupported status",
+			method: http.MethodGet,
+			path:   "/?status=paused",
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{}
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "get '/' with client token",
+			method: http.MethodGet,
+			path:   "/",
+			token:  clientToken,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{}
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "get '/{id}' successfully",
+			method: http.MethodGet,
+			path:   "/4",
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(4), id)
+						return active(id), nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "put '/{id}' successfully",
+			method: http.MethodPut,
+			path:   "/4",
+			body:   fmt.Sprintf(`{"amount":1650.00,"end_at":"%s"}`, endAt.Format(time.RFC3339)),
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectUpdate: func(c context.Context, requester dto.Requester, id int64, d dto.StandingOrderUpdate) (dto.StandingOrderView, error) {
+						testutil.AssertEq(t, "id", int64(4), id)
+						testutil.AssertEq(t, "amount", types.Decimal("1650.00"), d.Amount)
+						testutil.AssertEq(t, "end at", endAt, d.EndAt.UTC())
+						view := active(id)
+						view.Amount = d.Amount
+						return view, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "put '/{id}' of a canceled standing order",
+			method: http.MethodPut,
+			path:   "/4",
+			body:   `{"amount":1650.00}`,
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectUpdate: func(c context.Context, requester dto.Requester, id int64, d dto.StandingOrderUpdate) (dto.StandingOrderView, error) {
+						return dto.StandingOrderView{}, types.NewErr(types.ConflictErr, "standing order '4' is canceled and can't be changed", nil)
+					},
+				}
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:   "delete '/{id}' successfully",
+			method: http.MethodDelete,
+			path:   "/4",
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectCancel: func(c context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error) {
+						view := active(id)
+						view.Status = entity.StandingOrderCanceled
+						return view, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get '/{id}/executions' successfully",
+			method: http.MethodGet,
+			path:   "/4/executions",
+			token:  supportToken,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectFetchExecutions: func(c context.Context, requester dto.Requester, id int64) ([]dto.StandingOrderExecutionView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 5, Role: entity.RoleSupport}, requester)
+						testutil.AssertEq(t, "id", int64(4), id)
+						return []dto.StandingOrderExecutionView{{ID: 7, Status: entity.ScheduleFailed, FailureReason: "the destination account is closed"}}, nil
+					},
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get '/{id}/executions' of another account",
+			method: http.MethodGet,
+			path:   "/4/executions",
+			token:  token,
+			service: func() service.StandingOrder {
+				return &testutil.StandingOrderServMock{
+					ExpectFetchExecutions: func(c context.Context, requester dto.Requester, id int64) ([]dto.StandingOrderExecutionView, error) {
+						return nil, types.NewErr(types.NotFoundErr, "standing order '4' was not found", nil)
+					},
+				}
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service()
+			r.Route("/", routing.StandingOrders(&s, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
+
+			req, err := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			req.Header.Add("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "location", tc.location, res.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/middleware"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rs/zerolog/log"
+)
+
+// @Summary Gets the statement of the current authenticated account
+// @Description The statement lists every ledger entry of the period along with the resulting balance.
+// @Description It's written as CSV when the Accept header prefers text/csv, otherwise as JSON.
+// @tags v1
+// @ID get-account-statement
+// @Accept  json
+// @Produce  json,text/csv
+// @Param id path int true "Account ID"
+// @Param from query string false "Inclusive period start, RFC 3339 date-time or full-date. Defaults to the start of the current month"
+// @Param to query string false "Exclusive period end, RFC 3339 date-time or full-date. Defaults to one month after the start"
+// @Success 200 {object} dto.StatementView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /accounts/{id}/statement [get]
+// @Security ApiKeyAuth
+func (h *accountHandler) getStatement(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	var period dto.StatementPeriod
+	q := r.URL.Query()
+	if period.From, err = parseTimeParam(q, "from"); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if period.To, err = parseTimeParam(q, "to"); err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.statementSrv).Get(r.Context(), principal.Requester(), id, period)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if response.Negotiate(r, response.JSON, response.CSV) == response.CSV {
+		filename := fmt.Sprintf("statement-%d-%s.csv", view.AccountID, view.From.Format("2006-01-02"))
+		err = response.WriteCSV(w, filename, statementRecords(view))
+	} else {
+		err = response.WriteSuccess(w, r, view, nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the account statement into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+// statementRecords lays the statement out as CSV records, the entries are enclosed by the opening and closing balances
+func statementRecords(view dto.StatementView) [][]string {
+	records := make([][]string, 0, len(view.Entries)+3)
+	records = append(records,
+		[]string{"created_at", "type", "description", "transfer_id", "counterparty_id", "amount", "balance", "currency"},
+		[]string{view.From.Format(time.RFC3339), "", "opening balance", "", "", "", string(view.OpeningBalance), string(view.Currency)},
+	)
+	optionalID := func(id *int64) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatInt(*id, 10)
+	}
+	for _, e := range view.Entries {
+		records = append(records, []string{
+			e.CreatedAt.Format(time.RFC3339),
+			string(e.Type),
+			e.Description,
+			optionalID(e.TransferID),
+			optionalID(e.Counterparty),
+			string(e.Amount),
+			string(e.Balance),
+			string(view.Currency),
+		})
+	}
+	return append(records, []string{view.To.Format(time.RFC3339), "", "closing balance", "", "", "", string(view.ClosingBalance), string(view.Currency)})
+}
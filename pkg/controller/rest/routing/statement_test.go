@@ -0,0 +1,144 @@
+package routing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingAccountGetStatement(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	transferID, counterparty := int64(7), int64(2)
+	view := dto.StatementView{
+		AccountID:      1,
+		Currency:       types.BRL,
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: "100.00",
+		ClosingBalance: "70.00",
+		Entries: []dto.StatementEntry{
+			{
+				CreatedAt:    from.Add(time.Hour),
+				Type:         entity.Debit,
+				Description:  "transfer to account 2",
+				TransferID:   &transferID,
+				Counterparty: &counterparty,
+				Amount:       "-30.00",
+				Balance:      "70.00",
+			},
+		},
+	}
+	tt := []struct {
+		name      string
+		service   func(t *testing.T) service.Statement
+		status    int
+		path      string
+		headers   map[string]string
+		assertRes func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "get '/{id}/statement' without auth header",
+			status: http.StatusUnauthorized,
+			path:   "/1/statement",
+			service: func(t *testing.T) service.Statement {
+				return &testutil.StatementServMock{}
+			},
+		},
+		{
+			name:   "get '/{id}/statement' as json successfully",
+			status: http.StatusOK,
+			path:   "/1/statement?from=2021-03-01&to=2021-04-01",
+			service: func(t *testing.T) service.Statement {
+				return &testutil.StatementServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64, p dto.StatementPeriod) (dto.StatementView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(1), id)
+						testutil.AssertEq(t, "from", from, p.From)
+						testutil.AssertEq(t, "to", from.AddDate(0, 1, 0), p.To)
+						return view, nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+			assertRes: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				testutil.AssertEq(t, "content type", "application/json", rec.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name:   "get '/{id}/statement' as csv successfully",
+			status: http.StatusOK,
+			path:   "/1/statement",
+			service: func(t *testing.T) service.Statement {
+				return &testutil.StatementServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64, p dto.StatementPeriod) (dto.StatementView, error) {
+						return view, nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+				"Accept":        "text/csv",
+			},
+			assertRes: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				testutil.AssertEq(t, "content type", "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+				expected := strings.Join([]string{
+					"created_at,type,description,transfer_id,counterparty_id,amount,balance,currency",
+					"2021-03-01T00:00:00Z,,opening balance,,,,100.00,BRL",
+					"2021-03-01T01:00:00Z,debit,transfer to account 2,7,2,-30.00,70.00,BRL",
+					"2021-04-01T00:00:00Z,,closing balance,,,,70.00,BRL",
+				}, "\n") + "\n"
+				testutil.AssertEq(t, "response body", expected, rec.Body.String())
+			},
+		},
+		{
+			name:   "get '/{id}/statement' with malformed period",
+			status: http.StatusBadRequest,
+			path:   "/1/statement?from=march",
+			service: func(t *testing.T) service.Statement {
+				return &testutil.StatementServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var accountSrv service.Account = &testutil.AccountServMock{}
+			s := tc.service(t)
+			r.Route("/", routing.Accounts(&accountSrv, &s, &idempotencySrv, &sessionSrv, &loginGuardSrv, &twoFactorSrv, &secretSrv, &cashSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			for k, v := range tc.headers {
+				req.Header.Add(k, v)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			if tc.assertRes != nil {
+				tc.assertRes(t, res)
+			}
+		})
+	}
+}
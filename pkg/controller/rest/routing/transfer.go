@@ -2,7 +2,11 @@ package routing
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/go-chi/chi"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
@@ -15,73 +19,150 @@ import (
 )
 
 type transferHandler struct {
-	transferSrv *service.Transfer
+	transferSrv          *service.Transfer
+	scheduledTransferSrv *service.ScheduledTransfer
+	twoFactorSrv         *service.TwoFactor
 }
 
-// Transfers handles the requests related to entity.Transfer
-func Transfers(transferSrv *service.Transfer, jwtHandler *jwt.Handler) func(chi.Router) {
-	h := transferHandler{transferSrv: transferSrv}
+// Transfers handles the requests related to entity.Transfer and entity.ScheduledTransfer
+func Transfers(transferSrv *service.Transfer, scheduledTransferSrv *service.ScheduledTransfer, idempotencySrv *service.Idempotency, sessionSrv *service.Session, twoFactorSrv *service.TwoFactor, jwtHandler *jwt.Handler) func(chi.Router) {
+	h := transferHandler{transferSrv: transferSrv, scheduledTransferSrv: scheduledTransferSrv, twoFactorSrv: twoFactorSrv}
 	return func(r chi.Router) {
-		r.Use(middleware.NewAuthenticated(jwtHandler))
-		r.Get("/", h.get)
-		r.Post("/", h.post)
+		r.Use(middleware.NewAuthenticated(jwtHandler, sessionSrv))
+		// Clients read transfers by id only, as they have no account of their own
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/", h.get)
+		r.With(middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/{id:[\\d]+}", h.getByID)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersWrite), middleware.NewIdempotent(idempotencySrv)).Post("/", h.post)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersReverse), middleware.NewIdempotent(idempotencySrv)).Post("/{id:[\\d]+}/reversal", h.postReversal)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/scheduled", h.getScheduled)
+		r.With(middleware.NewScoped(jwt.ScopeTransfersRead)).Get("/scheduled/{id:[\\d]+}", h.getScheduledByID)
+		r.With(middleware.NewAccountRestricted(), middleware.NewScoped(jwt.ScopeTransfersWrite)).Delete("/scheduled/{id:[\\d]+}", h.deleteScheduled)
 	}
 }
 
 // @ID get-transfer
 // @tags v1
-// @Summary Gets the list of tranfers for the current authenticated user
+// @Summary Gets a page of the transfers sent and received by the current authenticated user
 // @Accept json
 // @Produce json
-// @Success 200 {array} dto.TransferView
+// @Param limit query int false "Page size" minimum(1) maximum(200) default(50)
+// @Param cursor query string false "Cursor returned along the previous page"
+// @Param sort query string false "Sort column, prefixed by '-' when descending" Enums(created_at, -created_at) default(-created_at)
+// @Param direction query string false "Whether the transfers were received, sent or both" Enums(in, out, all) default(all)
+// @Param counterparty query int false "ID of the other account of the transfers"
+// @Param created_from query string false "Inclusive creation date lower bound, RFC 3339 date-time or full-date"
+// @Param created_to query string false "Exclusive creation date upper bound, RFC 3339 date-time or full-date"
+// @Param min_amount query number false "Inclusive lower bound of the amount debited or credited to the account"
+// @Param max_amount query number false "Inclusive upper bound of the amount debited or credited to the account"
+// @Header 200 {string} Link "Link to the next page, if there's any"
+// @Success 200 {object} dto.TransferPage
 // @Failure 400 {object} body.JSONError
 // @Failure 404 {object} body.JSONError
 // @Failure 500 {object} body.JSONError
 // @Router /transfers [get]
 // @Security ApiKeyAuth
 func (h *transferHandler) get(w http.ResponseWriter, r *http.Request) {
-	id, ok := r.Context().Value(middleware.CtxAccountID).(int64)
+	principal, ok := middleware.PrincipalFrom(r.Context())
 	if !ok {
-		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get account id from request context", nil))
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
 		return
 	}
-	transfers, err := (*h.transferSrv).Fetch(r.Context(), id)
+	id := principal.AccountID
+	transferFilter, err := parseTransferFilter(r.URL.Query())
 	if err != nil {
 		response.WriteErr(w, r, err)
 		return
 	}
-	if err = response.WriteSuccess(w, r, transfers, nil); err != nil {
+	page, err := (*h.transferSrv).Fetch(r.Context(), id, transferFilter)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	page.Next = nextLink(w, r, page.NextCursor)
+	if err = response.WriteSuccess(w, r, page, nil); err != nil {
 		log.Error().Caller().Err(err).Msg("unable to encode the transfers into the response")
 		response.WriteErr(w, r, err)
 	}
 }
 
+// @ID get-transfer-by-id
+// @tags v1
+// @Summary Gets a transfer sent or received by the current authenticated user
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} dto.TransferView
+// @Failure 400 {object} body.JSONError
+// @Failure 401 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /transfers/{id} [get]
+// @Security ApiKeyAuth
+func (h *transferHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	view, err := (*h.transferSrv).Get(r.Context(), principal.Requester(), id)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteSuccess(w, r, view, nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the transfer into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
 // @ID post-transfer
 // @tags v1
-// @Summary Creates a new transfer
+// @Summary Creates a new transfer, or schedules it when the execution date is set
 // @Accept  json
 // @Produce  json
 // @Param req body dto.TransferCreation required "Transfer Creation Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
+// @Param X-TOTP-Code header string false "Fresh authenticator code, required above the step-up amount"
 // @Header 201 {string} Location "/transfers/1"
+// @Header 202 {string} Location "/transfers/scheduled/1"
 // @Success 201 {object} dto.TransferView
+// @Success 202 {object} dto.ScheduledTransferView
 // @Failure 400 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
 // @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 429 {object} body.JSONError
 // @Failure 500 {object} body.JSONError
 // @Router /transfers [post]
 // @Security ApiKeyAuth
 func (h *transferHandler) post(w http.ResponseWriter, r *http.Request) {
-	id, ok := r.Context().Value(middleware.CtxAccountID).(int64)
+	principal, ok := middleware.PrincipalFrom(r.Context())
 	if !ok {
-		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get account id from request context", nil))
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
 		return
 	}
+	id := principal.AccountID
 	var transferCreation dto.TransferCreation
 	err := json.NewDecoder(r.Body).Decode(&transferCreation)
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("unable to decode request body as transfer creation")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	if err = (*h.twoFactorSrv).StepUp(r.Context(), id, transferCreation.Amount, r.Header.Get("X-TOTP-Code")); err != nil {
 		response.WriteErr(w, r, err)
 		return
 	}
+	if !transferCreation.ExecuteAt.IsZero() {
+		h.schedule(w, r, id, transferCreation)
+		return
+	}
 
 	view, err := (*h.transferSrv).Create(r.Context(), id, transferCreation)
 	if err != nil {
@@ -93,3 +174,74 @@ func (h *transferHandler) post(w http.ResponseWriter, r *http.Request) {
 		response.WriteErr(w, r, err)
 	}
 }
+
+// @ID post-transfer-reversal
+// @tags v1
+// @Summary Reverses a transfer, wholly or partially, through a compensating transfer
+// @Description Allowed to the destination account owner and to support staff. An empty amount reverses whatever wasn't reversed yet
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Transfer ID"
+// @Param req body dto.TransferReversal false "Transfer Reversal Request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the original response"
+// @Header 201 {string} Location "/transfers/2"
+// @Success 201 {object} dto.TransferView
+// @Failure 400 {object} body.JSONError
+// @Failure 403 {object} body.JSONError
+// @Failure 404 {object} body.JSONError
+// @Failure 409 {object} body.JSONError
+// @Failure 500 {object} body.JSONError
+// @Router /transfers/{id}/reversal [post]
+// @Security ApiKeyAuth
+func (h *transferHandler) postReversal(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFrom(r.Context())
+	if !ok {
+		response.WriteErr(w, r, types.NewErr(types.InternalErr, "unable to get the principal from request context", nil))
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to parse the id param from request URL")
+		response.WriteErr(w, r, err)
+		return
+	}
+	var transferReversal dto.TransferReversal
+	// The body is optional, its absence reversing whatever wasn't reversed yet
+	if err = json.NewDecoder(r.Body).Decode(&transferReversal); err != nil && err != io.EOF {
+		log.Error().Caller().Err(err).Msg("unable to decode request body as transfer reversal")
+		response.WriteErr(w, r, decodeErr(err))
+		return
+	}
+	view, err := (*h.transferSrv).Reverse(r.Context(), principal.Requester(), id, transferReversal)
+	if err != nil {
+		response.WriteErr(w, r, err)
+		return
+	}
+	if err = response.WriteLocated(w, http.StatusCreated, view, fmt.Sprintf("/transfers/%d", view.ID)); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to encode the transfer reversal into the response")
+		response.WriteErr(w, r, err)
+	}
+}
+
+func parseTransferFilter(q url.Values) (f dto.TransferFilter, err error) {
+	if f.PageRequest, err = parsePage(q); err != nil {
+		return f, err
+	}
+	f.Direction = q.Get("direction")
+	if v := q.Get("counterparty"); v != "" {
+		if f.Counterparty, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return f, types.NewErr(types.ValidationErr, "query param 'counterparty' must be an integer", err)
+		}
+	}
+	if f.CreatedFrom, err = parseTimeParam(q, "created_from"); err != nil {
+		return f, err
+	}
+	if f.CreatedTo, err = parseTimeParam(q, "created_to"); err != nil {
+		return f, err
+	}
+	if f.MinAmount, err = parseDecimalParam(q, "min_amount"); err != nil {
+		return f, err
+	}
+	f.MaxAmount, err = parseDecimalParam(q, "max_amount")
+	return f, err
+}
@@ -7,17 +7,23 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
 
 func TestRoutingTransferFetch(t *testing.T) {
-	token, _, _ := jwtHandler.Generate(1)
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
 	tt := []struct {
 		name    string
 		service func() service.Transfer
@@ -39,9 +45,9 @@ func TestRoutingTransferFetch(t *testing.T) {
 			path:   "/",
 			service: func() service.Transfer {
 				return &testutil.TransferServMock{
-					ExpectFetch: func(c context.Context, i int64) ([]dto.TransferView, error) {
+					ExpectFetch: func(c context.Context, i int64, f dto.TransferFilter) (dto.TransferPage, error) {
 						testutil.AssertEq(t, "id", int64(1), i)
-						return []dto.TransferView{}, nil
+						return dto.TransferPage{Data: []dto.TransferView{}}, nil
 					},
 				}
 			},
@@ -52,15 +58,22 @@ func TestRoutingTransferFetch(t *testing.T) {
 		{
 			name:   "get '/' with results successfully",
 			status: http.StatusOK,
-			path:   "/",
+			path:   "/?direction=in&counterparty=2&min_amount=5&max_amount=20.50&created_to=2021-02-01",
 			service: func() service.Transfer {
 				return &testutil.TransferServMock{
-					ExpectFetch: func(c context.Context, i int64) ([]dto.TransferView, error) {
+					ExpectFetch: func(c context.Context, i int64, f dto.TransferFilter) (dto.TransferPage, error) {
 						testutil.AssertEq(t, "id", int64(1), i)
-						return []dto.TransferView{
-							*testutil.NewTransferView(1, 2, 5),
-							*testutil.NewTransferView(2, 2, 10),
-							*testutil.NewTransferView(3, 2, 20),
+						testutil.AssertEq(t, "direction", "in", f.Direction)
+						testutil.AssertEq(t, "counterparty", int64(2), f.Counterparty)
+						testutil.AssertEq(t, "min amount", types.Decimal("5"), f.MinAmount)
+						testutil.AssertEq(t, "max amount", types.Decimal("20.50"), f.MaxAmount)
+						testutil.AssertEq(t, "created to", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), f.CreatedTo)
+						return dto.TransferPage{
+							Data: []dto.TransferView{
+								*testutil.NewTransferView(1, 2, 5),
+								*testutil.NewTransferView(2, 2, 10),
+								*testutil.NewTransferView(3, 2, 20),
+							},
 						}, nil
 					},
 				}
@@ -69,13 +82,46 @@ func TestRoutingTransferFetch(t *testing.T) {
 				"Authorization": "Bearer " + token,
 			},
 		},
+		{
+			name:   "get '/' with malformed amount filter",
+			status: http.StatusBadRequest,
+			path:   "/?min_amount=1,50",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "get '/' with client token",
+			status: http.StatusForbidden,
+			path:   "/",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + clientToken,
+			},
+		},
+		{
+			name:   "get '/' with malformed counterparty",
+			status: http.StatusBadRequest,
+			path:   "/?counterparty=two",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service()
-			r.Route("/", routing.Transfers(&s, jwtHandler))
+			r.Route("/", routing.Transfers(&s, &scheduledTransferSrv, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
 
 			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
 			if err != nil {
@@ -96,7 +142,8 @@ func TestRoutingTransferFetch(t *testing.T) {
 }
 
 func TestRoutingTransferCreate(t *testing.T) {
-	token, _, _ := jwtHandler.Generate(1)
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	supportToken, _, _ := jwtHandler.Generate(1, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
 	tt := []struct {
 		name      string
 		service   func() service.Transfer
@@ -115,8 +162,8 @@ func TestRoutingTransferCreate(t *testing.T) {
 					ExpectCreate: func(c context.Context, i int64, d dto.TransferCreation) (dto.TransferView, error) {
 						testutil.AssertEq(t, "id", int64(1), i)
 						testutil.AssertEq(t, "destination", int64(2), d.Destination)
-						testutil.AssertEq(t, "amount", float64(500), d.Amount)
-						return *testutil.NewTransferView(1, d.Destination, d.Amount), nil
+						testutil.AssertEq(t, "amount", types.Decimal("500.00"), d.Amount)
+						return *testutil.NewTransferView(1, d.Destination, 500), nil
 					},
 				}
 			},
@@ -132,13 +179,60 @@ func TestRoutingTransferCreate(t *testing.T) {
 
 			},
 		},
+		{
+			name:   "post '/' with amount as json string",
+			status: http.StatusCreated,
+			path:   "/",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectCreate: func(c context.Context, i int64, d dto.TransferCreation) (dto.TransferView, error) {
+						testutil.AssertEq(t, "amount", types.Decimal("0.29"), d.Amount)
+						return *testutil.NewTransferView(1, d.Destination, 0.29), nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+			reader: func() (io.Reader, error) {
+				return strings.NewReader(`{"account_destination_id":2,"amount":"0.29"}`), nil
+			},
+		},
+		{
+			name:   "post '/' with malformed amount",
+			status: http.StatusBadRequest,
+			path:   "/",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+			reader: func() (io.Reader, error) {
+				return strings.NewReader(`{"account_destination_id":2,"amount":"1,50"}`), nil
+			},
+		},
+		{
+			name:   "post '/' without the transfers write scope",
+			status: http.StatusForbidden,
+			path:   "/",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + supportToken,
+			},
+			reader: func() (io.Reader, error) {
+				return strings.NewReader(`{"account_destination_id":2,"amount":"1.50"}`), nil
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			r := chi.NewRouter()
 			s := tc.service()
-			r.Route("/", routing.Transfers(&s, jwtHandler))
+			r.Route("/", routing.Transfers(&s, &scheduledTransferSrv, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
 
 			buffer, err := tc.reader()
 			if err != nil {
@@ -161,3 +255,292 @@ func TestRoutingTransferCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestRoutingTransferGet(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
+	tt := []struct {
+		name    string
+		service func() service.Transfer
+		status  int
+		path    string
+		headers map[string]string
+	}{
+		{
+			name:   "get '/{id}' without auth header",
+			status: http.StatusUnauthorized,
+			path:   "/3",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+		},
+		{
+			name:   "get '/{id}' successfully",
+			status: http.StatusOK,
+			path:   "/3",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.TransferView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 1, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(3), id)
+						return *testutil.NewTransferView(id, 2, 10), nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "get '/{id}' with client token successfully",
+			status: http.StatusOK,
+			path:   "/3",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.TransferView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ClientID: "batch"}, requester)
+						return *testutil.NewTransferView(id, 2, 10), nil
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + clientToken,
+			},
+		},
+		{
+			name:   "get '/{id}' of another account",
+			status: http.StatusNotFound,
+			path:   "/4",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectGet: func(c context.Context, requester dto.Requester, id int64) (dto.TransferView, error) {
+						return dto.TransferView{}, types.NewErr(types.NotFoundErr, "transfer '4' was not found", nil)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service()
+			r.Route("/", routing.Transfers(&s, &scheduledTransferSrv, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			for k, v := range tc.headers {
+				req.Header.Add(k, v)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+		})
+	}
+}
+
+func TestRoutingTransferReversal(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(2, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	supportToken, _, _ := jwtHandler.Generate(5, entity.RoleSupport, jwt.RoleScopes(entity.RoleSupport))
+	readToken, _, _ := jwtHandler.Generate(2, entity.RoleCustomer, []string{jwt.ScopeAccountsRead, jwt.ScopeTransfersRead})
+	clientToken, _, _ := jwtHandler.GenerateClient("batch", entity.ClientScopes)
+	reversed := func(c context.Context, requester dto.Requester, id int64, d dto.TransferReversal) (dto.TransferView, error) {
+		view := *testutil.NewTransferView(7, 1, 10)
+		view.ReversalOf = &id
+		return view, nil
+	}
+	tt := []struct {
+		name     string
+		service  func() service.Transfer
+		status   int
+		body     string
+		location string
+		headers  map[string]string
+	}{
+		{
+			name:   "post '/{id}/reversal' without auth header",
+			status: http.StatusUnauthorized,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+		},
+		{
+			name:     "post '/{id}/reversal' without body successfully",
+			status:   http.StatusCreated,
+			location: "/transfers/7",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectReverse: func(c context.Context, requester dto.Requester, id int64, d dto.TransferReversal) (dto.TransferView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 2, Role: entity.RoleCustomer}, requester)
+						testutil.AssertEq(t, "id", int64(3), id)
+						testutil.AssertEq(t, "amount", types.Decimal(""), d.Amount)
+						return reversed(c, requester, id, d)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:     "post '/{id}/reversal' partially as support successfully",
+			status:   http.StatusCreated,
+			body:     `{"amount": 2.5}`,
+			location: "/transfers/7",
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectReverse: func(c context.Context, requester dto.Requester, id int64, d dto.TransferReversal) (dto.TransferView, error) {
+						testutil.AssertEq(t, "requester", dto.Requester{ID: 5, Role: entity.RoleSupport}, requester)
+						testutil.AssertEq(t, "amount", types.Decimal("2.5"), d.Amount)
+						return reversed(c, requester, id, d)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + supportToken,
+			},
+		},
+		{
+			name:   "post '/{id}/reversal' with invalid body",
+			status: http.StatusBadRequest,
+			body:   `{"amount": "2.5"`,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/{id}/reversal' without the destination funds",
+			status: http.StatusBadRequest,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectReverse: func(c context.Context, requester dto.Requester, id int64, d dto.TransferReversal) (dto.TransferView, error) {
+						return dto.TransferView{}, types.NewErr(types.ValidationErr, "the destination must have a balance greater than or equal to 10.00", nil)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/{id}/reversal' as the origin owner",
+			status: http.StatusForbidden,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{
+					ExpectReverse: func(c context.Context, requester dto.Requester, id int64, d dto.TransferReversal) (dto.TransferView, error) {
+						return dto.TransferView{}, types.NewErr(types.AuthorizationErr, "account '2' isn't allowed to reverse transfer '3'", nil)
+					},
+				}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + token,
+			},
+		},
+		{
+			name:   "post '/{id}/reversal' lacking the reversal scope",
+			status: http.StatusForbidden,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + readToken,
+			},
+		},
+		{
+			name:   "post '/{id}/reversal' with client token",
+			status: http.StatusForbidden,
+			service: func() service.Transfer {
+				return &testutil.TransferServMock{}
+			},
+			headers: map[string]string{
+				"Authorization": "Bearer " + clientToken,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			s := tc.service()
+			r.Route("/", routing.Transfers(&s, &scheduledTransferSrv, &idempotencySrv, &sessionSrv, &twoFactorSrv, jwtHandler))
+
+			req, err := http.NewRequest(http.MethodPost, "/3/reversal", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			for k, v := range tc.headers {
+				req.Header.Add(k, v)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "location", tc.location, res.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRoutingTransferStepUp(t *testing.T) {
+	token, _, _ := jwtHandler.Generate(1, entity.RoleCustomer, jwt.RoleScopes(entity.RoleCustomer))
+	tt := []struct {
+		name    string
+		status  int
+		code    string
+		stepUp  error
+		created bool
+	}{
+		{
+			name:    "post '/' with a fresh second factor code",
+			status:  http.StatusCreated,
+			code:    "123456",
+			created: true,
+		},
+		{
+			name:   "post '/' above the step-up amount without code",
+			status: http.StatusForbidden,
+			stepUp: types.NewErr(types.AuthorizationErr, "transfers above 1000 require a second factor code", nil),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			created := false
+			var s service.Transfer = &testutil.TransferServMock{
+				ExpectCreate: func(c context.Context, i int64, d dto.TransferCreation) (dto.TransferView, error) {
+					created = true
+					return *testutil.NewTransferView(1, d.Destination, 5000), nil
+				},
+			}
+			var twoFactor service.TwoFactor = &testutil.TwoFactorServMock{
+				ExpectStepUp: func(ctx context.Context, account int64, amount types.Decimal, code string) error {
+					testutil.AssertEq(t, "account", int64(1), account)
+					testutil.AssertEq(t, "amount", types.Decimal("5000"), amount)
+					testutil.AssertEq(t, "code", tc.code, code)
+					return tc.stepUp
+				},
+			}
+			r := chi.NewRouter()
+			r.Route("/", routing.Transfers(&s, &scheduledTransferSrv, &idempotencySrv, &sessionSrv, &twoFactor, jwtHandler))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"account_destination_id":2,"amount":"5000"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tc.code != "" {
+				req.Header.Set("X-TOTP-Code", tc.code)
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", tc.status, res.Code)
+			testutil.AssertEq(t, "created", tc.created, created)
+		})
+	}
+}
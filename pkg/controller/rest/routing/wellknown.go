@@ -0,0 +1,36 @@
+package routing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/response"
+	"github.com/rs/zerolog/log"
+)
+
+type wellKnownHandler struct {
+	jwtHandler *jwt.Handler
+}
+
+// WellKnown exposes the public metadata routes, such as the keys that verify the application tokens
+func WellKnown(jwtHandler *jwt.Handler) func(chi.Router) {
+	h := wellKnownHandler{jwtHandler: jwtHandler}
+	return func(r chi.Router) {
+		r.Get("/jwks.json", h.getJWKS)
+	}
+}
+
+// @ID get-jwks
+// @tags v1
+// @Summary Lists the public keys that verify the issued authorization tokens
+// @Produce  json
+// @Success 200 {object} jwt.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *wellKnownHandler) getJWKS(w http.ResponseWriter, r *http.Request) {
+	// Verifiers may cache the keys for a while, so a new key should be published before it starts signing
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := response.WriteSuccess(w, r, h.jwtHandler.JWKS(), nil); err != nil {
+		log.Error().Caller().Err(err).Msg("unable to write the jwks response")
+	}
+}
@@ -0,0 +1,86 @@
+package routing_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
+	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/routing"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRoutingWellKnownJWKS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "2021-01-01.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keysHandler, err := jwt.NewHandler(&env.RestConfig{Issuer: "stn-accounts", Audience: "stn-accounts", KeysDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name    string
+		handler *jwt.Handler
+		kids    string
+	}{
+		{
+			name:    "get '/jwks.json' with asymmetric keys",
+			handler: keysHandler,
+			kids:    "2021-01-01",
+		},
+		{
+			name:    "get '/jwks.json' with an hmac secret",
+			handler: jwtHandler,
+			kids:    "",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Route("/", routing.WellKnown(tc.handler))
+			req, err := http.NewRequest(http.MethodGet, "/jwks.json", nil)
+			if err != nil {
+				t.Fatalf("unable to create testcase request")
+			}
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			testutil.AssertEq(t, "status code", http.StatusOK, res.Code)
+			testutil.AssertEq(t, "cache control", "public, max-age=300", res.Header().Get("Cache-Control"))
+			var set jwt.JWKSet
+			if err = json.NewDecoder(res.Body).Decode(&set); err != nil {
+				t.Fatal(err)
+			}
+			kids := ""
+			for _, k := range set.Keys {
+				kids += k.Kid
+			}
+			testutil.AssertEq(t, "kids", tc.kids, kids)
+		})
+	}
+}
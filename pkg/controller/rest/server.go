@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/rafael-sousa/stn-accounts/pkg/controller/rest/jwt"
@@ -24,16 +25,36 @@ type Server interface {
 }
 
 type server struct {
-	accountSrv  *service.Account
-	transferSrv *service.Transfer
-	middlewares []func(http.Handler) http.Handler
+	accountSrv     *service.Account
+	transferSrv    *service.Transfer
+	scheduledSrv   *service.ScheduledTransfer
+	standingSrv    *service.StandingOrder
+	statementSrv   *service.Statement
+	idempotencySrv *service.Idempotency
+	sessionSrv     *service.Session
+	loginGuardSrv  *service.LoginGuard
+	twoFactorSrv   *service.TwoFactor
+	secretSrv      *service.Secret
+	clientSrv      *service.Client
+	cashSrv        *service.Cash
+	middlewares    []func(http.Handler) http.Handler
 }
 
 // NewServer constructs a server with its required dependencies
-func NewServer(accountSrv *service.Account, transferSrv *service.Transfer) Server {
+func NewServer(accountSrv *service.Account, transferSrv *service.Transfer, scheduledSrv *service.ScheduledTransfer, standingSrv *service.StandingOrder, statementSrv *service.Statement, idempotencySrv *service.Idempotency, sessionSrv *service.Session, loginGuardSrv *service.LoginGuard, twoFactorSrv *service.TwoFactor, secretSrv *service.Secret, clientSrv *service.Client, cashSrv *service.Cash) Server {
 	return &server{
-		accountSrv:  accountSrv,
-		transferSrv: transferSrv,
+		accountSrv:     accountSrv,
+		transferSrv:    transferSrv,
+		scheduledSrv:   scheduledSrv,
+		standingSrv:    standingSrv,
+		statementSrv:   statementSrv,
+		idempotencySrv: idempotencySrv,
+		sessionSrv:     sessionSrv,
+		loginGuardSrv:  loginGuardSrv,
+		twoFactorSrv:   twoFactorSrv,
+		secretSrv:      secretSrv,
+		clientSrv:      clientSrv,
+		cashSrv:        cashSrv,
 	}
 }
 
@@ -50,10 +71,22 @@ func (s *server) Start(cfg *env.RestConfig) {
 	for _, md := range s.middlewares {
 		router.Use(md)
 	}
-	jwtHandler := jwt.NewHandler(cfg)
-	router.Route("/accounts", routing.Accounts(s.accountSrv))
-	router.Route("/transfers", routing.Transfers(s.transferSrv, jwtHandler))
-	router.Route("/login", routing.Login(s.accountSrv, jwtHandler))
+	jwtHandler, err := jwt.NewHandler(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up the jwt handler")
+	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go jwtHandler.Watch(watchCtx, time.Duration(cfg.KeysReload)*time.Minute)
+
+	router.Route("/accounts", routing.Accounts(s.accountSrv, s.statementSrv, s.idempotencySrv, s.sessionSrv, s.loginGuardSrv, s.twoFactorSrv, s.secretSrv, s.cashSrv, jwtHandler))
+	router.Route("/transfers", routing.Transfers(s.transferSrv, s.scheduledSrv, s.idempotencySrv, s.sessionSrv, s.twoFactorSrv, jwtHandler))
+	router.Route("/standing-orders", routing.StandingOrders(s.standingSrv, s.idempotencySrv, s.sessionSrv, s.twoFactorSrv, jwtHandler))
+	router.Route("/login", routing.Login(s.accountSrv, s.sessionSrv, s.loginGuardSrv, s.twoFactorSrv, s.secretSrv, jwtHandler))
+	router.Route("/logout", routing.Logout(s.sessionSrv, jwtHandler))
+	router.Route("/oauth", routing.OAuth(s.clientSrv, jwtHandler))
+	router.Route("/clients", routing.Clients(s.clientSrv, s.sessionSrv, jwtHandler))
+	router.Route("/.well-known", routing.WellKnown(jwtHandler))
 	router.NotFound(routing.NotFound)
 	router.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("doc.json")))
 
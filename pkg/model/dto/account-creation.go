@@ -1,10 +1,13 @@
 // Package dto holds types meant to carry values for an specific task, or to limit the amount of info exposed to outer application layers
 package dto
 
+import "github.com/rafael-sousa/stn-accounts/pkg/model/types"
+
 // AccountCreation holds the values required for a entity.Account creation
 type AccountCreation struct {
-	Name    string  `json:"name" minLength:"1" maxLength:"255" example:"José da Silva" validate:"required"`
-	CPF     string  `json:"cpf" minLength:"11" maxLength:"11" example:"11881200000"`
-	Secret  string  `json:"secret" minLength:"1" maxLength:"50" example:"super_secret"`
-	Balance float64 `json:"balance" minimum:"0"`
+	Name     string             `json:"name" minLength:"1" maxLength:"255" example:"José da Silva" validate:"required"`
+	CPF      string             `json:"cpf" minLength:"11" maxLength:"11" example:"11881200000"`
+	Secret   string             `json:"secret" minLength:"1" maxLength:"50" example:"super_secret"`
+	Currency types.CurrencyCode `json:"currency" minLength:"3" maxLength:"3" example:"BRL" default:"BRL"`
+	Balance  types.Decimal      `json:"balance" swaggertype:"number" minimum:"0" example:"10.50"`
 }
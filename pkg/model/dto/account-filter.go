@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// AccountFilter holds the values that narrow and page an account listing.
+// CreatedFrom is inclusive while CreatedTo is exclusive
+type AccountFilter struct {
+	PageRequest
+	NamePrefix  string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// AccountPage is a page of an account listing. Next links to the following page, if there's any
+type AccountPage struct {
+	Data       []AccountView `json:"data"`
+	Next       string        `json:"next,omitempty" example:"/accounts?cursor=eyJpIjoxfQ&limit=50"`
+	NextCursor string        `json:"-"`
+}
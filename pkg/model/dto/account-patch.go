@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// AccountPatch holds the changes of a JSON Merge Patch (RFC 7396) to the mutable entity.Account fields.
+// A nil field is left untouched, whereas a field patched with null is cleared
+type AccountPatch struct {
+	Name *string `json:"name,omitempty" minLength:"1" maxLength:"255" example:"José da Silva"`
+}
+
+// UnmarshalJSON decodes the merge patch document, refusing the members that aren't mutable such as the cpf and the balance
+func (p *AccountPatch) UnmarshalJSON(b []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	for member, value := range members {
+		switch member {
+		case "name":
+			var name *string
+			if err := json.Unmarshal(value, &name); err != nil {
+				return err
+			}
+			if name == nil {
+				name = new(string)
+			}
+			p.Name = name
+		default:
+			return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' can't be changed", member), nil)
+		}
+	}
+	return nil
+}
@@ -4,15 +4,20 @@ import (
 	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
 
 // AccountView maintains the displayable entity.Account values
 type AccountView struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	CPF       string    `json:"cpf"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	CPF       string             `json:"cpf"`
+	Balance   types.Decimal      `json:"balance" swaggertype:"number"`
+	Currency  types.CurrencyCode `json:"currency"`
+	Role      entity.Role        `json:"role"`
+	Status    entity.Status      `json:"status"`
+	Version   int64              `json:"version"`
+	CreatedAt time.Time          `json:"created_at"`
 }
 
 // NewAccountView creates a view from the entity.Account stored at e
@@ -20,8 +25,12 @@ func NewAccountView(e entity.Account) AccountView {
 	return AccountView{
 		ID:        e.ID,
 		Name:      e.Name,
-		Balance:   e.Balance.Float64(),
+		Balance:   e.Currency.Decimal(e.Balance),
+		Currency:  e.Currency,
 		CPF:       e.CPF,
+		Role:      e.Role,
+		Status:    e.Status,
+		Version:   e.Version,
 		CreatedAt: e.CreatedAt,
 	}
 }
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// BalanceView exposes the current entity.Account balance along with its currency
+type BalanceView struct {
+	Balance  types.Decimal      `json:"balance" swaggertype:"number"`
+	Currency types.CurrencyCode `json:"currency"`
+}
+
+// NewBalanceView creates a view from the entity.Account stored at e
+func NewBalanceView(e entity.Account) BalanceView {
+	return BalanceView{
+		Balance:  e.Currency.Decimal(e.Balance),
+		Currency: e.Currency,
+	}
+}
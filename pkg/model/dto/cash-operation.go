@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// CashOperationCreation holds the values required for a deposit or a withdrawal.
+// The amount is expressed in the account currency and the reference identifies the operation at the external system
+type CashOperationCreation struct {
+	Amount    types.Decimal `json:"amount" validation:"required" swaggertype:"number" minimum:"0.01" example:"100.00"`
+	Reference string        `json:"reference" validation:"required" minLength:"1" maxLength:"64" example:"branch-0042-000981"`
+}
+
+// CashOperationView exposes the displayable entity.CashOperation values
+type CashOperationView struct {
+	ID        int64              `json:"id"`
+	AccountID int64              `json:"account_id"`
+	Type      entity.CashType    `json:"type"`
+	Amount    types.Decimal      `json:"amount" swaggertype:"number"`
+	Currency  types.CurrencyCode `json:"currency"`
+	Reference string             `json:"reference"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// NewCashOperationView creates a view from the entity.CashOperation stored at e
+func NewCashOperationView(e entity.CashOperation) CashOperationView {
+	return CashOperationView{
+		ID:        e.ID,
+		AccountID: e.AccountID,
+		Type:      e.Type,
+		Amount:    e.Currency.Decimal(e.Amount),
+		Currency:  e.Currency,
+		Reference: e.Reference,
+		CreatedAt: e.CreatedAt,
+	}
+}
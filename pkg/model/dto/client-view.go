@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// ClientCreation holds the values required for a entity.Client registration
+type ClientCreation struct {
+	Name   string   `json:"name" minLength:"1" maxLength:"255" example:"Reconciliation batch"`
+	Scopes []string `json:"scopes" example:"transfers:read"`
+}
+
+// ClientView maintains the displayable entity.Client values
+type ClientView struct {
+	ID        string    `json:"client_id" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes" example:"transfers:read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewClientView creates a view from the entity.Client stored at e
+func NewClientView(e entity.Client) ClientView {
+	return ClientView{
+		ID:        e.ID,
+		Name:      e.Name,
+		Scopes:    e.Scopes,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// ClientCredentials are the values of a newly registered entity.Client, the only time its secret is disclosed
+type ClientCredentials struct {
+	ClientView
+	Secret string `json:"client_secret" example:"2Qm0Yx8rTfJvN3bK5pLw7sHc9dGa1eUz4iOyXnRqVtE"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// PageRequest holds the cursor pagination values of a listing.
+// Cursor is the opaque value returned along the previous page and Sort names the ordering column, prefixed by '-' when descending
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   string
+}
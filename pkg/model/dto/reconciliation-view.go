@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// ReconciliationView compares the balance kept on the entity.Account with the one derived from its ledger entries
+type ReconciliationView struct {
+	Balance       types.Decimal      `json:"balance" swaggertype:"number"`
+	LedgerBalance types.Decimal      `json:"ledger_balance" swaggertype:"number"`
+	Currency      types.CurrencyCode `json:"currency"`
+	Reconciled    bool               `json:"reconciled"`
+}
+
+// NewReconciliationView creates a view from the entity.Account stored at e and the balance of its ledger entries
+func NewReconciliationView(e entity.Account, ledgerBalance types.Currency) ReconciliationView {
+	return ReconciliationView{
+		Balance:       e.Currency.Decimal(e.Balance),
+		LedgerBalance: e.Currency.Decimal(ledgerBalance),
+		Currency:      e.Currency,
+		Reconciled:    e.Balance == ledgerBalance,
+	}
+}
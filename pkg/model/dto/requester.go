@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+
+// Requester is the authenticated party on whose behalf an operation is performed, either an account or a client.
+// ClientID is only set for clients, which have neither an account ID nor a Role
+type Requester struct {
+	ID       int64
+	Role     entity.Role
+	ClientID string
+}
+
+// CanRead tells whether the requester is allowed to read the data of the given account.
+// Customers only read their own data whereas support and admin staff read the data of every account.
+// Clients read the data of every account as well, their scopes telling which kind of data
+func (r Requester) CanRead(account int64) bool {
+	if r.ClientID != "" {
+		return true
+	}
+	return r.ID == account || r.Role == entity.RoleSupport || r.Role == entity.RoleAdmin
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// ScheduledTransferView exposes the displayable entity.ScheduledTransfer values
+type ScheduledTransferView struct {
+	ID            int64                 `json:"id"`
+	Origin        int64                 `json:"account_origin_id"`
+	Destination   int64                 `json:"account_destination_id"`
+	Amount        types.Decimal         `json:"amount" swaggertype:"number"`
+	Currency      types.CurrencyCode    `json:"currency"`
+	Convert       bool                  `json:"convert"`
+	ExecuteAt     time.Time             `json:"execute_at"`
+	Status        entity.ScheduleStatus `json:"status" enums:"pending,executed,failed,canceled"`
+	TransferID    *int64                `json:"transfer_id,omitempty"`
+	FailureReason string                `json:"failure_reason,omitempty" example:"the origin must have a balance greater than or equal to 10.50"`
+	CreatedAt     time.Time             `json:"created_at"`
+	FinishedAt    *time.Time            `json:"finished_at,omitempty"`
+}
+
+// NewScheduledTransferView creates a view from the entity.ScheduledTransfer stored at e
+func NewScheduledTransferView(e entity.ScheduledTransfer) ScheduledTransferView {
+	return ScheduledTransferView{
+		ID:            e.ID,
+		Origin:        e.Origin,
+		Destination:   e.Destination,
+		Amount:        e.Currency.Decimal(e.Amount),
+		Currency:      e.Currency,
+		Convert:       e.Convert,
+		ExecuteAt:     e.ExecuteAt,
+		Status:        e.Status,
+		TransferID:    e.TransferID,
+		FailureReason: e.FailureReason,
+		CreatedAt:     e.CreatedAt,
+		FinishedAt:    e.FinishedAt,
+	}
+}
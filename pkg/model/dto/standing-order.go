@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// StandingOrderCreation holds the values required for a entity.StandingOrder creation.
+// The amount is expressed in the origin account currency, as on TransferCreation.
+// Monthly orders require the Day of the month, whereas weekly ones repeat on the StartAt weekday.
+// Either EndAt or Count may be set to end the order, which otherwise runs until canceled
+type StandingOrderCreation struct {
+	Destination int64            `json:"account_destination_id" validation:"required" minimum:"1"`
+	Amount      types.Decimal    `json:"amount" validation:"required" swaggertype:"number" minimum:"0.01" example:"1500.00"`
+	Convert     bool             `json:"convert" example:"false"`
+	Frequency   entity.Frequency `json:"frequency" validation:"required" enums:"weekly,monthly" example:"monthly"`
+	Day         int              `json:"day_of_month" minimum:"1" maximum:"31" example:"5"`
+	StartAt     time.Time        `json:"start_at" validation:"required" example:"2030-01-05T09:00:00Z"`
+	EndAt       *time.Time       `json:"end_at,omitempty" example:"2030-12-31T23:59:59Z"`
+	Count       int              `json:"count,omitempty" minimum:"1" example:"12"`
+}
+
+// StandingOrderUpdate holds the mutable values of an active entity.StandingOrder, replacing the current ones.
+// Leaving out both EndAt and Count makes the order run until canceled
+type StandingOrderUpdate struct {
+	Amount types.Decimal `json:"amount" validation:"required" swaggertype:"number" minimum:"0.01" example:"1650.00"`
+	EndAt  *time.Time    `json:"end_at,omitempty" example:"2030-12-31T23:59:59Z"`
+	Count  int           `json:"count,omitempty" minimum:"1" example:"12"`
+}
+
+// StandingOrderView exposes the displayable entity.StandingOrder values.
+// NextAt is only disclosed while the order is active
+type StandingOrderView struct {
+	ID          int64                      `json:"id"`
+	Origin      int64                      `json:"account_origin_id"`
+	Destination int64                      `json:"account_destination_id"`
+	Amount      types.Decimal              `json:"amount" swaggertype:"number"`
+	Currency    types.CurrencyCode         `json:"currency"`
+	Convert     bool                       `json:"convert"`
+	Frequency   entity.Frequency           `json:"frequency" enums:"weekly,monthly"`
+	Day         int                        `json:"day_of_month,omitempty"`
+	StartAt     time.Time                  `json:"start_at"`
+	EndAt       *time.Time                 `json:"end_at,omitempty"`
+	Count       int                        `json:"count,omitempty"`
+	Executions  int                        `json:"executions"`
+	NextAt      *time.Time                 `json:"next_at,omitempty"`
+	Status      entity.StandingOrderStatus `json:"status" enums:"active,finished,canceled"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	FinishedAt  *time.Time                 `json:"finished_at,omitempty"`
+}
+
+// NewStandingOrderView creates a view from the entity.StandingOrder stored at e
+func NewStandingOrderView(e entity.StandingOrder) StandingOrderView {
+	view := StandingOrderView{
+		ID:          e.ID,
+		Origin:      e.Origin,
+		Destination: e.Destination,
+		Amount:      e.Currency.Decimal(e.Amount),
+		Currency:    e.Currency,
+		Convert:     e.Convert,
+		Frequency:   e.Frequency,
+		Day:         e.Day,
+		StartAt:     e.StartAt,
+		EndAt:       e.EndAt,
+		Count:       e.Count,
+		Executions:  e.Executions,
+		Status:      e.Status,
+		CreatedAt:   e.CreatedAt,
+		FinishedAt:  e.FinishedAt,
+	}
+	if e.Status == entity.StandingOrderActive {
+		nextAt := e.NextAt
+		view.NextAt = &nextAt
+	}
+	return view
+}
+
+// StandingOrderExecutionView exposes the displayable entity.StandingOrderExecution values
+type StandingOrderExecutionView struct {
+	ID            int64                 `json:"id"`
+	ScheduledAt   time.Time             `json:"scheduled_at"`
+	Status        entity.ScheduleStatus `json:"status" enums:"executed,failed"`
+	TransferID    *int64                `json:"transfer_id,omitempty"`
+	FailureReason string                `json:"failure_reason,omitempty" example:"the origin must have a balance greater than or equal to 1500.00"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// NewStandingOrderExecutionView creates a view from the entity.StandingOrderExecution stored at e
+func NewStandingOrderExecutionView(e entity.StandingOrderExecution) StandingOrderExecutionView {
+	return StandingOrderExecutionView{
+		ID:            e.ID,
+		ScheduledAt:   e.ScheduledAt,
+		Status:        e.Status,
+		TransferID:    e.TransferID,
+		FailureReason: e.FailureReason,
+		CreatedAt:     e.CreatedAt,
+	}
+}
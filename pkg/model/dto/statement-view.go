@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// StatementPeriod is the time range covered by an account statement, from the inclusive From up to the exclusive To
+type StatementPeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// StatementView exposes the balance history of an entity.Account throughout a period
+type StatementView struct {
+	AccountID      int64              `json:"account_id"`
+	Currency       types.CurrencyCode `json:"currency"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	OpeningBalance types.Decimal      `json:"opening_balance" swaggertype:"number"`
+	ClosingBalance types.Decimal      `json:"closing_balance" swaggertype:"number"`
+	Entries        []StatementEntry   `json:"entries"`
+}
+
+// StatementEntry is a single movement of an account statement along with the balance it resulted in.
+// Debits have a negative amount and entries not originated by a transfer have neither TransferID nor Counterparty,
+// whereas deposits and withdrawals carry the CashOperationID
+type StatementEntry struct {
+	CreatedAt       time.Time        `json:"created_at"`
+	Type            entity.EntryType `json:"type"`
+	Description     string           `json:"description"`
+	TransferID      *int64           `json:"transfer_id,omitempty"`
+	CashOperationID *int64           `json:"cash_operation_id,omitempty"`
+	Counterparty    *int64           `json:"counterparty_id,omitempty"`
+	Amount          types.Decimal    `json:"amount" swaggertype:"number"`
+	Balance         types.Decimal    `json:"balance" swaggertype:"number"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// StatusChangeView maintains the displayable entity.StatusChange values
+type StatusChangeView struct {
+	From      entity.Status `json:"from" example:"active"`
+	To        entity.Status `json:"to" example:"blocked"`
+	Reason    string        `json:"reason" example:"fraud investigation"`
+	ChangedBy int64         `json:"changed_by"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// NewStatusChangeView creates a view from the entity.StatusChange stored at e
+func NewStatusChangeView(e entity.StatusChange) StatusChangeView {
+	return StatusChangeView{
+		From:      e.From,
+		To:        e.To,
+		Reason:    e.Reason,
+		ChangedBy: e.ChangedBy,
+		CreatedAt: e.CreatedAt,
+	}
+}
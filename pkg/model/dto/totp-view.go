@@ -0,0 +1,12 @@
+package dto
+
+// TOTPEnrollment holds the secret of a pending second factor enrollment, along with the key URI authenticator apps import
+type TOTPEnrollment struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/stn-accounts:1?algorithm=SHA1&digits=6&issuer=stn-accounts&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+// RecoveryCodes are the single use codes that replace the second factor, only disclosed when it's enabled
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes" example:"k3x9-a2mq"`
+}
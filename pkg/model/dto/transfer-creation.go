@@ -1,7 +1,18 @@
 package dto
 
-// TransferCreation holds the values required for a entity.Transfer creation
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// TransferCreation holds the values required for a entity.Transfer creation.
+// The amount is expressed in the origin account currency. Transfers to an account of another currency
+// must set Convert, acknowledging that the amount will be exchanged at the current rate.
+// Setting ExecuteAt schedules the transfer instead, exchanging the amount at the rate of the execution
 type TransferCreation struct {
-	Destination int64   `json:"account_destination_id" validation:"required" minimum:"1"`
-	Amount      float64 `json:"amount" validation:"required" minimum:"0.01"`
+	Destination int64         `json:"account_destination_id" validation:"required" minimum:"1"`
+	Amount      types.Decimal `json:"amount" validation:"required" swaggertype:"number" minimum:"0.01" example:"10.50"`
+	Convert     bool          `json:"convert" example:"false"`
+	ExecuteAt   time.Time     `json:"execute_at" example:"2030-01-31T09:00:00Z"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// TransferFilter holds the values that narrow and page the transfer history of an account.
+// Direction is one of "in", "out" or "all". Amounts are expressed in the account currency,
+// matching what was debited from or credited to the account. CreatedFrom is inclusive while CreatedTo is exclusive
+type TransferFilter struct {
+	PageRequest
+	Direction    string
+	Counterparty int64
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	MinAmount    types.Decimal
+	MaxAmount    types.Decimal
+}
+
+// TransferPage is a page of a transfer history. Next links to the following page, if there's any
+type TransferPage struct {
+	Data       []TransferView `json:"data"`
+	Next       string         `json:"next,omitempty" example:"/transfers?cursor=eyJpIjoxfQ&limit=50"`
+	NextCursor string         `json:"-"`
+}
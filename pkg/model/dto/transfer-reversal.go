@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/rafael-sousa/stn-accounts/pkg/model/types"
+
+// TransferReversal holds the values of a reversal of an entity.Transfer.
+// The amount is expressed in the destination currency of the original transfer,
+// an empty one reversing whatever wasn't reversed yet
+type TransferReversal struct {
+	Amount types.Decimal `json:"amount" swaggertype:"number" minimum:"0.01" example:"10.50"`
+}
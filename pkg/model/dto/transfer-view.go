@@ -4,22 +4,39 @@ import (
 	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
 
 // TransferView exposes the displayable entity.Transfer values
 type TransferView struct {
-	ID          int64     `json:"id"`
-	Destination int64     `json:"account_destination_id"`
-	Amount      float64   `json:"amount"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                  int64                 `json:"id"`
+	Origin              int64                 `json:"account_origin_id"`
+	Destination         int64                 `json:"account_destination_id"`
+	Amount              types.Decimal         `json:"amount" swaggertype:"number"`
+	Currency            types.CurrencyCode    `json:"currency"`
+	DestinationAmount   types.Decimal         `json:"destination_amount" swaggertype:"number"`
+	DestinationCurrency types.CurrencyCode    `json:"destination_currency"`
+	Rate                types.Decimal         `json:"rate" swaggertype:"number"`
+	ReversalOf          *int64                `json:"reversal_of,omitempty"`
+	ReversedAmount      types.Decimal         `json:"reversed_amount" swaggertype:"number"`
+	ReversalStatus      entity.ReversalStatus `json:"reversal_status" enums:"none,partial,full"`
+	CreatedAt           time.Time             `json:"created_at"`
 }
 
 // NewTransferView creates a view from the entity.Transfer stored at e
 func NewTransferView(e entity.Transfer) TransferView {
 	return TransferView{
-		ID:          e.ID,
-		Destination: e.Destination,
-		Amount:      e.Amount.Float64(),
-		CreatedAt:   e.CreatedAt,
+		ID:                  e.ID,
+		Origin:              e.Origin,
+		Destination:         e.Destination,
+		Amount:              e.Currency.Decimal(e.Amount),
+		Currency:            e.Currency,
+		DestinationAmount:   e.DestinationCurrency.Decimal(e.DestinationAmount),
+		DestinationCurrency: e.DestinationCurrency,
+		Rate:                e.Rate,
+		ReversalOf:          e.ReversalOf,
+		ReversedAmount:      e.DestinationCurrency.Decimal(e.ReversedAmount),
+		ReversalStatus:      e.ReversalStatus(),
+		CreatedAt:           e.CreatedAt,
 	}
 }
@@ -12,14 +12,60 @@ const (
 	AccountNameSize   int = 255
 	AccountCPFSize    int = 11
 	AccountSecretSize int = 50
+	StatusReasonSize  int = 255
 )
 
+// Role tells what an Account is allowed to do besides managing its own data
+type Role string
+
+// List of the supported account roles
+const (
+	RoleCustomer Role = "customer" // RoleCustomer only accesses its own account
+	RoleSupport  Role = "support"  // RoleSupport reads the data of every account
+	RoleAdmin    Role = "admin"    // RoleAdmin reads the data of every account and manages their roles
+)
+
+// Supported tells whether the role is one of the supported account roles
+func (r Role) Supported() bool {
+	return r == RoleCustomer || r == RoleSupport || r == RoleAdmin
+}
+
+// Status tells whether an Account is allowed to operate
+type Status string
+
+// List of the account statuses
+const (
+	StatusActive  Status = "active"  // StatusActive accounts operate normally
+	StatusBlocked Status = "blocked" // StatusBlocked accounts are frozen, neither sending nor receiving transfers
+	StatusClosed  Status = "closed"  // StatusClosed accounts are terminated, they can't log in nor be reopened
+)
+
+// Supported tells whether the status is one of the account statuses
+func (s Status) Supported() bool {
+	return s == StatusActive || s == StatusBlocked || s == StatusClosed
+}
+
 // Account models a financial account
 type Account struct {
 	ID        int64
 	Name      string
 	CPF       string
 	Secret    string
+	Currency  types.CurrencyCode
 	Balance   types.Currency
+	Role      Role
+	Status    Status
+	Version   int64 // Version is bumped on every profile update, guarding it against concurrent updates
+	CreatedAt time.Time
+}
+
+// StatusChange audits the change of an Account status, recording who changed it and why
+type StatusChange struct {
+	ID        int64
+	AccountID int64
+	From      Status
+	To        Status
+	Reason    string
+	ChangedBy int64
 	CreatedAt time.Time
 }
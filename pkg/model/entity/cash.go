@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// CashReferenceSize is the maximum size of the external reference of a CashOperation
+const CashReferenceSize int = 64
+
+// CashType tells whether a CashOperation puts money into or takes money out of an account
+type CashType string
+
+// List of the supported cash operation types
+const (
+	Deposit    CashType = "deposit"    // Deposit credits money coming from outside the application
+	Withdrawal CashType = "withdrawal" // Withdrawal debits money leaving the application
+)
+
+// CashOperation registers money moving between an account and the outside world, such as a cash-in at a branch.
+// The Reference identifies the operation at the external system, being unique per type, and ClientID is the
+// back-office client that reported it
+type CashOperation struct {
+	ID        int64
+	AccountID int64
+	Type      CashType
+	Amount    types.Currency
+	Currency  types.CurrencyCode
+	Reference string
+	ClientID  string
+	CreatedAt time.Time
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Scopes granted to the access tokens, each one allowing a kind of operation over a resource
+const (
+	ScopeAccountsRead     = "accounts:read"
+	ScopeTransfersRead    = "transfers:read"
+	ScopeTransfersWrite   = "transfers:write"
+	ScopeTransfersReverse = "transfers:reverse"
+	ScopeCashWrite        = "cash:write"
+)
+
+// ClientScopes lists the scopes a Client can be granted.
+// Clients act on behalf of no account, so they never transfer money, but the back-office ones report deposits and withdrawals
+var ClientScopes = []string{ScopeAccountsRead, ScopeTransfersRead, ScopeCashWrite}
+
+// Client is a service that authenticates with its own credentials rather than on behalf of an account,
+// such as a back-office batch job. Only the hash of its secret is stored, and a disabled client is no longer granted tokens
+type Client struct {
+	ID         string
+	Name       string
+	SecretHash string
+	Scopes     []string
+	CreatedAt  time.Time
+	DisabledAt *time.Time
+}
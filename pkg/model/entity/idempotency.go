@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// IdempotencyKey records the outcome of a request sent with an Idempotency-Key header so that its retries can be replayed.
+// A key is pending while StatusCode is 0, meaning the original request is still being processed.
+// Scope is the digest of the request method, path and principal, so that its length is fixed
+type IdempotencyKey struct {
+	Scope        string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	Location     string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// Completed reports whether the response of the original request has been stored
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// EntryType tells whether a LedgerEntry takes money out of or puts money into an account
+type EntryType string
+
+// List of the supported ledger entry types
+const (
+	Debit  EntryType = "debit"  // Debit decreases the account balance
+	Credit EntryType = "credit" // Credit increases the account balance
+)
+
+// LedgerEntry is a single posting of the double-entry book of record.
+// Every transfer yields one debit on the origin and one credit on the destination account,
+// a cash operation yields a lone entry referencing it, whereas an opening balance yields a lone credit referencing nothing
+type LedgerEntry struct {
+	ID              int64
+	AccountID       int64
+	TransferID      *int64
+	CashOperationID *int64
+	Type            EntryType
+	Amount          types.Currency
+	CreatedAt       time.Time
+}
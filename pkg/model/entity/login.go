@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// LoginAttempt counts the consecutive failed logins of a client, identified by Key as either 'cpf:<cpf>' or 'ip:<address>'.
+// LockedUntil is set once a CPF has failed too many times, refusing its logins until then
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// ScheduleFailureSize is the maximum length of the reason a scheduled transfer failed
+const ScheduleFailureSize int = 255
+
+// ScheduleStatus tells where a ScheduledTransfer stands
+type ScheduleStatus string
+
+// List of the scheduled transfer statuses
+const (
+	SchedulePending  ScheduleStatus = "pending"  // SchedulePending transfers wait for their execution date
+	ScheduleExecuted ScheduleStatus = "executed" // ScheduleExecuted transfers created the Transfer they were scheduled for
+	ScheduleFailed   ScheduleStatus = "failed"   // ScheduleFailed transfers were refused once executed, such as for insufficient funds
+	ScheduleCanceled ScheduleStatus = "canceled" // ScheduleCanceled transfers were canceled by the origin account before their execution
+)
+
+// Supported tells whether the status is one of the scheduled transfer statuses
+func (s ScheduleStatus) Supported() bool {
+	return s == SchedulePending || s == ScheduleExecuted || s == ScheduleFailed || s == ScheduleCanceled
+}
+
+// ScheduledTransfer is a Transfer to be created on ExecuteAt. Amount is expressed in the origin Currency and
+// Convert tells whether it may be exchanged into the destination currency.
+// TransferID links to the created Transfer once executed, whereas FailureReason tells why the execution was refused
+type ScheduledTransfer struct {
+	ID            int64
+	Origin        int64
+	Destination   int64
+	Amount        types.Currency
+	Currency      types.CurrencyCode
+	Convert       bool
+	ExecuteAt     time.Time
+	Status        ScheduleStatus
+	TransferID    *int64
+	FailureReason string
+	CreatedAt     time.Time
+	FinishedAt    *time.Time
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// Frequency tells how often a StandingOrder is executed
+type Frequency string
+
+// List of the standing order frequencies
+const (
+	FrequencyWeekly  Frequency = "weekly"  // FrequencyWeekly orders are executed every seven days from their start date
+	FrequencyMonthly Frequency = "monthly" // FrequencyMonthly orders are executed on a given day of every month
+)
+
+// Supported tells whether the frequency is one of the standing order frequencies
+func (f Frequency) Supported() bool {
+	return f == FrequencyWeekly || f == FrequencyMonthly
+}
+
+// StandingOrderStatus tells where a StandingOrder stands
+type StandingOrderStatus string
+
+// List of the standing order statuses
+const (
+	StandingOrderActive   StandingOrderStatus = "active"   // StandingOrderActive orders wait for their next execution
+	StandingOrderFinished StandingOrderStatus = "finished" // StandingOrderFinished orders reached either their end date or their count
+	StandingOrderCanceled StandingOrderStatus = "canceled" // StandingOrderCanceled orders were canceled by the origin account
+)
+
+// Supported tells whether the status is one of the standing order statuses
+func (s StandingOrderStatus) Supported() bool {
+	return s == StandingOrderActive || s == StandingOrderFinished || s == StandingOrderCanceled
+}
+
+// StandingOrder is a Transfer repeated on a schedule, starting at StartAt. Amount is expressed in the origin Currency and
+// Convert tells whether it may be exchanged into the destination currency.
+// Monthly orders run on Day, or on the last day of the months shorter than that, at the StartAt time of day.
+// The order ends after EndAt or once Count executions were made, whichever is set, and runs indefinitely otherwise.
+// Executions counts the occurrences already executed, either successfully or not, and NextAt is the upcoming one
+type StandingOrder struct {
+	ID          int64
+	Origin      int64
+	Destination int64
+	Amount      types.Currency
+	Currency    types.CurrencyCode
+	Convert     bool
+	Frequency   Frequency
+	Day         int
+	StartAt     time.Time
+	EndAt       *time.Time
+	Count       int
+	Executions  int
+	NextAt      time.Time
+	Status      StandingOrderStatus
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// Occurrence returns the date of the nth execution of the order, counting from zero
+func (o StandingOrder) Occurrence(n int) time.Time {
+	start := o.StartAt
+	if o.Frequency == FrequencyWeekly {
+		return start.AddDate(0, 0, 7*n)
+	}
+	// The first execution happens on the start month unless its day has already gone by
+	if monthDay(start.Year(), start.Month(), o.Day) < start.Day() {
+		n++
+	}
+	month := time.Date(start.Year(), start.Month()+time.Month(n), 1, 0, 0, 0, 0, start.Location())
+	return time.Date(month.Year(), month.Month(), monthDay(month.Year(), month.Month(), o.Day), start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
+// Exhausted tells whether the order has no execution left after the given number of executions
+func (o StandingOrder) Exhausted(executions int) bool {
+	if o.Count > 0 && executions >= o.Count {
+		return true
+	}
+	return o.EndAt != nil && o.Occurrence(executions).After(*o.EndAt)
+}
+
+// monthDay clamps day to the number of days of the given month
+func monthDay(year int, month time.Month, day int) int {
+	if last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); day > last {
+		return last
+	}
+	return day
+}
+
+// StandingOrderExecution records the outcome of an occurrence of a StandingOrder scheduled for ScheduledAt.
+// TransferID links to the created Transfer once executed, whereas FailureReason tells why the execution was refused
+type StandingOrderExecution struct {
+	ID            int64
+	StandingOrder int64
+	ScheduledAt   time.Time
+	Status        ScheduleStatus
+	TransferID    *int64
+	FailureReason string
+	CreatedAt     time.Time
+}
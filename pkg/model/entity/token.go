@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// RefreshToken is the server-side record of an opaque refresh token, of which only the hash is stored.
+// Every rotation issues a new token within the same Family, which is revoked altogether once a used token is presented again
+type RefreshToken struct {
+	Hash      string
+	Family    string
+	AccountID int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// RevokedToken blocklists the access token identified by JTI until it expires by itself
+type RevokedToken struct {
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// SecretResetToken is the server-side record of a single use token that allows setting a new account secret
+// without knowing the current one. Only its hash is stored, as it's delivered to the account holder by a notifier
+type SecretResetToken struct {
+	Hash      string
+	AccountID int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// TOTP is the second factor enrolled by an account, whose Secret generates time-based one-time codes.
+// It's only required once enabled, which takes a valid code. LastStep is the period of the last accepted code,
+// so that a code is never accepted twice
+type TOTP struct {
+	AccountID int64
+	Secret    string
+	CreatedAt time.Time
+	EnabledAt *time.Time
+	LastStep  int64
+}
+
+// RecoveryCode replaces the second factor once, when the account lost access to its authenticator. Only its hash is stored
+type RecoveryCode struct {
+	AccountID int64
+	Hash      string
+	UsedAt    *time.Time
+}
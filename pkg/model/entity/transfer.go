@@ -6,11 +6,43 @@ import (
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
 
-// Transfer registers a balance exchange between different accounts
+// ReversalStatus tells how much of a Transfer was given back to its origin
+type ReversalStatus string
+
+// List of the transfer reversal statuses
+const (
+	ReversalNone    ReversalStatus = "none"    // ReversalNone transfers weren't reversed at all
+	ReversalPartial ReversalStatus = "partial" // ReversalPartial transfers still have an amount left to reverse
+	ReversalFull    ReversalStatus = "full"    // ReversalFull transfers were given back entirely
+)
+
+// Transfer registers a balance exchange between different accounts.
+// Amount is debited in the origin Currency while DestinationAmount is credited in the DestinationCurrency,
+// both being related by the exchange Rate.
+// Reversals are compensating transfers linked to the original one through ReversalOf,
+// whose ReversedAmount sums what was given back, expressed in the original DestinationCurrency
 type Transfer struct {
-	ID          int64
-	Origin      int64
-	Destination int64
-	Amount      types.Currency
-	CreatedAt   time.Time
+	ID                  int64
+	Origin              int64
+	Destination         int64
+	Amount              types.Currency
+	Currency            types.CurrencyCode
+	DestinationAmount   types.Currency
+	DestinationCurrency types.CurrencyCode
+	Rate                types.Decimal
+	ReversalOf          *int64
+	ReversedAmount      types.Currency
+	CreatedAt           time.Time
+}
+
+// ReversalStatus derives the reversal status of the transfer from its ReversedAmount
+func (t Transfer) ReversalStatus() ReversalStatus {
+	switch {
+	case t.ReversedAmount <= 0:
+		return ReversalNone
+	case t.ReversedAmount < t.DestinationAmount:
+		return ReversalPartial
+	default:
+		return ReversalFull
+	}
 }
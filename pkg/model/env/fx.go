@@ -0,0 +1,27 @@
+package env
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sethvargo/go-envconfig"
+)
+
+// FXConfig maintains the settings of the exchange rate provider
+type FXConfig struct {
+	Provider  string `env:"FX_PROVIDER,default=static"`
+	RatesFile string `env:"FX_RATES_FILE"`
+	URL       string `env:"FX_URL"`
+	Timeout   int    `env:"FX_TIMEOUT,default=5"`
+}
+
+// NewFXConfig retrives the environment settings related to the exchange rate provider
+func NewFXConfig(ctx *context.Context) FXConfig {
+	var c FXConfig
+	if err := envconfig.Process(*ctx, &c); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to read the fx environment properties")
+	}
+	return c
+}
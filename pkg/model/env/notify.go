@@ -0,0 +1,25 @@
+package env
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sethvargo/go-envconfig"
+)
+
+// NotifyConfig maintains the settings of the channel that delivers messages to account holders
+type NotifyConfig struct {
+	Notifier string `env:"NOTIFIER,default=log"`
+	File     string `env:"NOTIFIER_FILE"`
+}
+
+// NewNotifyConfig retrives the environment settings related to the notifier
+func NewNotifyConfig(ctx *context.Context) NotifyConfig {
+	var c NotifyConfig
+	if err := envconfig.Process(*ctx, &c); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to read the notifier environment properties")
+	}
+	return c
+}
@@ -10,8 +10,24 @@ import (
 // RestConfig maintains the configuration for the Rest API
 type RestConfig struct {
 	Port            int    `env:"PORT,default=3000"`
-	Secret          []byte `env:"JWT_SECRET,default=rest-app@@secret"`
+	Secret          []byte `env:"JWT_SECRET"`
+	KeysDir         string `env:"JWT_KEYS_DIR"`
+	KeysReload      int    `env:"JWT_KEYS_RELOAD,default=10"`
+	Issuer          string `env:"JWT_ISSUER,default=stn-accounts"`
+	Audience        string `env:"JWT_AUDIENCE,default=stn-accounts"`
 	TokenExpTimeout int    `env:"JWT_EXP_TIMEOUT,default=30"`
+	IdempotencyTTL  int    `env:"IDEMPOTENCY_TTL,default=24"`
+	RefreshTokenTTL int    `env:"REFRESH_TOKEN_TTL,default=720"`
+	LoginAttempts   int    `env:"LOGIN_MAX_ATTEMPTS,default=5"`
+	LoginBackoff    int    `env:"LOGIN_BACKOFF,default=1"`
+	LoginLockout    int    `env:"LOGIN_LOCKOUT,default=15"`
+	TOTPIssuer      string `env:"TOTP_ISSUER,default=stn-accounts"`
+	StepUpAmount    string `env:"STEP_UP_AMOUNT"`
+	SecretResetTTL  int    `env:"SECRET_RESET_TTL,default=30"`
+	SecretMinLength int    `env:"SECRET_MIN_LENGTH,default=8"`
+	SecretClasses   int    `env:"SECRET_MIN_CLASSES,default=2"`
+	SecretCommon    bool   `env:"SECRET_REJECT_COMMON,default=true"`
+	BcryptCost      int    `env:"BCRYPT_COST,default=10"`
 }
 
 // NewRestConfig retrives the environment settings related to the Rest API
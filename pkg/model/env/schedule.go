@@ -0,0 +1,24 @@
+package env
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sethvargo/go-envconfig"
+)
+
+// ScheduleConfig maintains the settings of the executors of scheduled transfers and standing orders
+type ScheduleConfig struct {
+	Interval int `env:"SCHEDULE_INTERVAL,default=30"`
+}
+
+// NewScheduleConfig retrives the environment settings related to the schedule executors
+func NewScheduleConfig(ctx *context.Context) ScheduleConfig {
+	var c ScheduleConfig
+	if err := envconfig.Process(*ctx, &c); err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to read the schedule environment properties")
+	}
+	return c
+}
@@ -1,15 +1,95 @@
 // Package types models the a variety of custom application types
 package types
 
-// Currency is a model that stores an amount as cents
+import (
+	"fmt"
+	"math"
+)
+
+// Currency is a model that stores an amount in the minor unit of its currency, such as cents
 type Currency int64
 
-// NewCurrency creates a Currecy value from a float64
+// NewCurrency creates a Currecy value from a float64, rounding it to the nearest hundredth of the unit.
+// Amounts coming from outside the application must go through Decimal instead
 func NewCurrency(v float64) Currency {
-	return Currency(v * 100)
+	return Currency(math.Round(v * 100))
+}
+
+// CurrencyCode is an ISO 4217 alphabetic currency code
+type CurrencyCode string
+
+// List of the currencies supported by the application
+const (
+	BRL CurrencyCode = "BRL" // BRL is the brazilian real, also the default account currency
+	USD CurrencyCode = "USD" // USD is the united states dollar
+	EUR CurrencyCode = "EUR" // EUR is the euro
+	GBP CurrencyCode = "GBP" // GBP is the pound sterling
+	JPY CurrencyCode = "JPY" // JPY is the japanese yen, which has no minor unit
+)
+
+// DefaultCurrencyCode is assigned to accounts that don't specify a currency
+const DefaultCurrencyCode = BRL
+
+var _exponents = map[CurrencyCode]int{
+	BRL: 2,
+	USD: 2,
+	EUR: 2,
+	GBP: 2,
+	JPY: 0,
+}
+
+// Supported tells whether the application knows the minor unit of the currency
+func (c CurrencyCode) Supported() bool {
+	_, ok := _exponents[c]
+	return ok
+}
+
+// Exponent returns the number of fraction digits of the currency minor unit, or a InternalErr when the currency isn't supported
+func (c CurrencyCode) Exponent() (int, error) {
+	if exp, ok := _exponents[c]; ok {
+		return exp, nil
+	}
+	return 0, unsupportedErr(c)
+}
+
+// Decimal formats an amount stored in the currency minor unit.
+// Codes are verified when read from requests and from the database, so formatting the amount of an unsupported one
+// is a programmer error that panics with the InternalErr reported by Exponent
+func (c CurrencyCode) Decimal(v Currency) Decimal {
+	exp, err := c.Exponent()
+	if err != nil {
+		panic(err)
+	}
+	return NewDecimal(v, exp)
+}
+
+// Currency converts a decimal amount into the currency minor unit, see Decimal#Currency
+func (c CurrencyCode) Currency(d Decimal) (Currency, error) {
+	exp, err := c.Exponent()
+	if err != nil {
+		return 0, err
+	}
+	return d.Currency(exp)
+}
+
+// Scan implements sql.Scanner, refusing the codes the application doesn't support with a InternalErr
+func (c *CurrencyCode) Scan(src interface{}) error {
+	var code CurrencyCode
+	switch v := src.(type) {
+	case []byte:
+		code = CurrencyCode(v)
+	case string:
+		code = CurrencyCode(v)
+	default:
+		return NewErr(InternalErr, fmt.Sprintf("unable to scan the currency code from '%v'", src), nil)
+	}
+	if !code.Supported() {
+		return unsupportedErr(code)
+	}
+	*c = code
+	return nil
 }
 
-// Float64 converts the cents to the monetary unit
-func (c Currency) Float64() float64 {
-	return float64(c) / 100
+func unsupportedErr(c CurrencyCode) error {
+	return NewErr(InternalErr, fmt.Sprintf("unsupported currency code '%s'", string(c)), nil)
 }
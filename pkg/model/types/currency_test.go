@@ -0,0 +1,99 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestCurrencyCodeExponent(t *testing.T) {
+	tt := []struct {
+		name      string
+		code      types.CurrencyCode
+		expected  int
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "exponent of currency with minor unit",
+			code:      types.BRL,
+			expected:  2,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "exponent of currency without minor unit",
+			code:      types.JPY,
+			expected:  0,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "exponent of unsupported currency",
+			code: "XYZ",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unsupported currency code 'XYZ'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			exp, err := tc.code.Exponent()
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "exponent", tc.expected, exp)
+		})
+	}
+}
+
+func TestCurrencyCodeConversion(t *testing.T) {
+	_, err := types.CurrencyCode("XYZ").Currency("10.00")
+	testutil.AssertCustomErr(t, types.InternalErr, err, "unsupported currency code 'XYZ'")
+	testutil.AssertEq(t, "decimal", types.Decimal("10.00"), types.USD.Decimal(1000))
+	defer func() {
+		err, _ := recover().(error)
+		testutil.AssertCustomErr(t, types.InternalErr, err, "unsupported currency code 'XYZ'")
+	}()
+	types.CurrencyCode("XYZ").Decimal(1000)
+}
+
+func TestCurrencyCodeScan(t *testing.T) {
+	tt := []struct {
+		name      string
+		src       interface{}
+		expected  types.CurrencyCode
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "scan currency code from bytes",
+			src:       []byte("USD"),
+			expected:  types.USD,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "scan currency code from string",
+			src:       "JPY",
+			expected:  types.JPY,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "scan unsupported currency code",
+			src:  []byte("XYZ"),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unsupported currency code 'XYZ'")
+			},
+		},
+		{
+			name: "scan null currency code",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unable to scan the currency code from '<nil>'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var code types.CurrencyCode
+			tc.assertErr(t, code.Scan(tc.src))
+			testutil.AssertEq(t, "code", tc.expected, code)
+		})
+	}
+}
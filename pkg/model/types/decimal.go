@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var _isDecimal = regexp.MustCompile(`^-?\d+(\.\d+)?$`).MatchString
+
+// Decimal is the exact textual representation of a monetary amount, such as "10.25".
+// It carries amounts across the application boundaries so that they never go through a float64
+type Decimal string
+
+// ParseDecimal validates the format of the value stored at s and returns it as a Decimal.
+// Exponent notation isn't supported and leading zeros are removed from the integer part
+func ParseDecimal(s string) (Decimal, error) {
+	if !_isDecimal(s) {
+		return "", NewErr(ValidationErr, fmt.Sprintf("invalid decimal value '%s'", s), nil)
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction := splitDecimal(s)
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	if fraction != "" {
+		return Decimal(sign + integer + "." + fraction), nil
+	}
+	return Decimal(sign + integer), nil
+}
+
+// NewDecimal formats the minor units stored at c using exp fraction digits
+func NewDecimal(c Currency, exp int) Decimal {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	digits := strconv.FormatInt(int64(c), 10)
+	if exp <= 0 {
+		return Decimal(sign + digits)
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return Decimal(sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:])
+}
+
+// Trim removes the insignificant trailing zeros of the fraction part, such as in "5.250000" or "1.000"
+func (d Decimal) Trim() Decimal {
+	integer, fraction := splitDecimal(string(d))
+	if fraction = strings.TrimRight(fraction, "0"); fraction == "" {
+		return Decimal(integer)
+	}
+	return Decimal(integer + "." + fraction)
+}
+
+// Scale returns the number of significant fraction digits, trailing zeros aren't taken into account
+func (d Decimal) Scale() int {
+	_, fraction := splitDecimal(string(d))
+	return len(strings.TrimRight(fraction, "0"))
+}
+
+// Currency converts the decimal into minor units given the currency exponent.
+// No rounding takes place, so it fails when d has more significant fraction digits than exp.
+// An empty decimal converts to zero
+func (d Decimal) Currency(exp int) (Currency, error) {
+	if d == "" {
+		return 0, nil
+	}
+	if d.Scale() > exp {
+		return 0, NewErr(ValidationErr, fmt.Sprintf("decimal value '%s' exceeds %d fraction digits", d, exp), nil)
+	}
+	integer, fraction := splitDecimal(string(d))
+	fraction = strings.TrimRight(fraction, "0")
+	fraction += strings.Repeat("0", exp-len(fraction))
+	v, err := strconv.ParseInt(integer+fraction, 10, 64)
+	if err != nil {
+		return 0, NewErr(ValidationErr, fmt.Sprintf("decimal value '%s' is out of range", d), err)
+	}
+	return Currency(v), nil
+}
+
+// UnmarshalJSON accepts either a JSON string or a JSON number as the decimal value
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return NewErr(ValidationErr, "invalid decimal string", err)
+		}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON writes the decimal as a JSON number, keeping its exact digits
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("0"), nil
+	}
+	return []byte(d), nil
+}
+
+func splitDecimal(s string) (string, string) {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
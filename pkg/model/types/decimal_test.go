@@ -0,0 +1,167 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tt := []struct {
+		name      string
+		input     string
+		expected  types.Decimal
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "unmarshal json number",
+			input:     `0.29`,
+			expected:  "0.29",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "unmarshal json string",
+			input:     `"1.15"`,
+			expected:  "1.15",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "unmarshal json number with leading zeros",
+			input:     `"-007.10"`,
+			expected:  "-7.10",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "unmarshal json null",
+			input:     `null`,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:  "unmarshal json number with exponent",
+			input: `1e2`,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "invalid decimal value '1e2'")
+			},
+		},
+		{
+			name:  "unmarshal json string with letters",
+			input: `"ten"`,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "invalid decimal value 'ten'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var d types.Decimal
+			err := json.Unmarshal([]byte(tc.input), &d)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "decimal", tc.expected, d)
+		})
+	}
+}
+
+func TestDecimalMarshalJSON(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    types.Currency
+		exp      int
+		expected string
+	}{
+		{name: "marshal cents", input: 29, exp: 2, expected: "0.29"},
+		{name: "marshal negative cents", input: -115, exp: 2, expected: "-1.15"},
+		{name: "marshal whole amount", input: 1000, exp: 2, expected: "10.00"},
+		{name: "marshal amount without minor unit", input: 1000, exp: 0, expected: "1000"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(types.NewDecimal(tc.input, tc.exp))
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "json", tc.expected, string(b))
+		})
+	}
+}
+
+func TestDecimalTrim(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    types.Decimal
+		expected types.Decimal
+	}{
+		{name: "trim trailing zeros", input: "5.250000", expected: "5.25"},
+		{name: "trim whole fraction", input: "1.000000000000", expected: "1"},
+		{name: "trim nothing", input: "0.1875", expected: "0.1875"},
+		{name: "trim integer", input: "10", expected: "10"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.AssertEq(t, "decimal", tc.expected, tc.input.Trim())
+		})
+	}
+}
+
+func TestDecimalCurrency(t *testing.T) {
+	tt := []struct {
+		name      string
+		input     types.Decimal
+		exp       int
+		expected  types.Currency
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "convert amount that floats can't represent",
+			input:     "0.29",
+			exp:       2,
+			expected:  29,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert amount with trailing zeros",
+			input:     "1.1500",
+			exp:       2,
+			expected:  115,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert amount with fewer fraction digits",
+			input:     "-3.5",
+			exp:       2,
+			expected:  -350,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert empty amount",
+			exp:       2,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:  "convert amount with sub-cent precision",
+			input: "0.001",
+			exp:   2,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "decimal value '0.001' exceeds 2 fraction digits")
+			},
+		},
+		{
+			name:  "convert amount out of range",
+			input: "99999999999999999999",
+			exp:   2,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "decimal value '99999999999999999999' is out of range")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := tc.input.Currency(tc.exp)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "currency", tc.expected, c)
+		})
+	}
+}
@@ -21,6 +21,10 @@ const (
 	NotFoundErr       ErrCode = "0070" // NotFoundErr occurs when accessing a nonexisting resource
 	AuthenticationErr ErrCode = "0080" // AuthenticationErr occurs when the authentication process completes unsuccessfully
 	ConflictErr       ErrCode = "0090" // ConflictErr occurs an operation could not complete due to a conflict with the current state of the resource
+	AuthorizationErr  ErrCode = "0100" // AuthorizationErr occurs when the authenticated requester isn't allowed to access the resource
+	LockedErr         ErrCode = "0110" // LockedErr occurs when logins are refused for a while after too many failed attempts
+	PreconditionErr   ErrCode = "0120" // PreconditionErr occurs when the resource was modified since the version the request is based on
+	NoPreconditionErr ErrCode = "0130" // NoPreconditionErr occurs when a request that must be conditional doesn't tell the version it's based on
 )
 
 // Err represents an error acknowledged by the application business
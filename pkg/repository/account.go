@@ -4,17 +4,61 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
 
-// Account exposes database operations related to account domain
+// Account exposes database operations related to account domain.
+// UpdateProfile bumps the account version, affecting no row unless e.Version is the current one
 type Account interface {
-	Fetch(ctx context.Context) ([]entity.Account, error)
+	Fetch(ctx context.Context, q AccountQuery) ([]entity.Account, error)
 	Create(ctx context.Context, e entity.Account) (int64, error)
 	GetBalance(ctx context.Context, id int64) (types.Currency, error)
 	FindBy(ctx context.Context, cpf string) (entity.Account, error)
+	Get(ctx context.Context, id int64) (entity.Account, error)
 	Exists(ctx context.Context, id int64) (bool, error)
 	UpdateBalance(ctx context.Context, id int64, b types.Currency) error
+	UpdateRole(ctx context.Context, id int64, role entity.Role) error
+	UpdateSecret(ctx context.Context, id int64, secret string) error
+	UpdateStatus(ctx context.Context, id int64, status entity.Status) error
+	UpdateProfile(ctx context.Context, e entity.Account) error
+	Lock(ctx context.Context, ids ...int64) error
+}
+
+// StatusChange exposes database operations related to the audit trail of the account statuses.
+// Fetch returns the changes of the account from the oldest to the newest
+type StatusChange interface {
+	Create(ctx context.Context, e entity.StatusChange) (int64, error)
+	Fetch(ctx context.Context, account int64) ([]entity.StatusChange, error)
+}
+
+// AccountSort enumerates the columns an account listing can be sorted by
+type AccountSort string
+
+// Available account sorting columns
+const (
+	AccountSortCreatedAt AccountSort = "created_at"
+	AccountSortName      AccountSort = "name"
+)
+
+// AccountQuery narrows and pages the accounts returned by Account#Fetch.
+// Zero values disable the respective filter and a Limit of 0 returns every matching row
+type AccountQuery struct {
+	Limit       int
+	Sort        AccountSort
+	Desc        bool
+	After       *AccountKey
+	NamePrefix  string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// AccountKey is the position of an account within a sorted listing, ties on the sort column are broken by ID.
+// Listings resume right after the key
+type AccountKey struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// CashOperation exposes database operations related to the deposits and withdrawals.
+// Create fails with a types.ConflictErr when the reference was already reported for the same type of operation,
+// and Fetch returns the account operations from the inclusive from up to the exclusive to
+type CashOperation interface {
+	Create(ctx context.Context, e entity.CashOperation) (int64, error)
+	Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.CashOperation, error)
+}
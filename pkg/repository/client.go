@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// Client exposes database operations related to the service client domain.
+// Disable must fail with a types.NoRowAffectedErr when the client is unknown or was already disabled
+type Client interface {
+	Create(ctx context.Context, e entity.Client) error
+	Get(ctx context.Context, id string) (entity.Client, error)
+	Disable(ctx context.Context, id string, at time.Time) error
+}
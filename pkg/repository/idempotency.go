@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// Idempotency exposes database operations related to the idempotency key domain.
+// Create must fail with a types.ConflictErr when the scope already holds the key
+type Idempotency interface {
+	Create(ctx context.Context, e entity.IdempotencyKey) error
+	Get(ctx context.Context, scope string, key string) (entity.IdempotencyKey, error)
+	Update(ctx context.Context, e entity.IdempotencyKey) error
+	Delete(ctx context.Context, scope string, key string) error
+}
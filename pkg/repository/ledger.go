@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// Ledger exposes database operations related to the ledger entry domain
+type Ledger interface {
+	Create(ctx context.Context, e entity.LedgerEntry) (int64, error)
+	GetBalance(ctx context.Context, account int64) (types.Currency, error)
+	GetBalanceBefore(ctx context.Context, account int64, t time.Time) (types.Currency, error)
+	Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// LoginAttempt exposes database operations related to the failed login counters.
+// Increment counts a failure at the given time, discarding the previous ones when the last of them happened before since
+type LoginAttempt interface {
+	Get(ctx context.Context, key string) (entity.LoginAttempt, error)
+	Increment(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
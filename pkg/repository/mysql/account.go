@@ -4,6 +4,8 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"sort"
+	"strings"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
@@ -21,8 +23,30 @@ func NewAccount(txr *repository.Transactioner) repository.Account {
 	return &account{txr: txr}
 }
 
-func (r *account) Fetch(ctx context.Context) ([]entity.Account, error) {
-	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, "SELECT id, name, cpf, secret, balance, created_at FROM account")
+func (r *account) Fetch(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+	var sel query
+	if q.NamePrefix != "" {
+		sel.where("name LIKE ?", prefixPattern(q.NamePrefix))
+	}
+	if !q.CreatedFrom.IsZero() {
+		sel.where("created_at >= ?", q.CreatedFrom)
+	}
+	if !q.CreatedTo.IsZero() {
+		sel.where("created_at < ?", q.CreatedTo)
+	}
+	col := "created_at"
+	if q.Sort == repository.AccountSortName {
+		col = "name"
+	}
+	if q.After != nil {
+		var v interface{} = q.After.CreatedAt
+		if q.Sort == repository.AccountSortName {
+			v = q.After.Name
+		}
+		sel.after(col, q.Desc, v, q.After.ID)
+	}
+	stmt, args := sel.build("SELECT id, name, cpf, secret, currency, balance, role, status, version, created_at FROM account", col, q.Desc, q.Limit)
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, types.NewErr(types.SelectStmtErr, "fetching accounts", err)
 	}
@@ -30,7 +54,7 @@ func (r *account) Fetch(ctx context.Context) ([]entity.Account, error) {
 	accs := make([]entity.Account, 0)
 	for rows.Next() {
 		acc := entity.Account{}
-		if err = rows.Scan(&acc.ID, &acc.Name, &acc.CPF, &acc.Secret, &acc.Balance, &acc.CreatedAt); err != nil {
+		if err = rows.Scan(&acc.ID, &acc.Name, &acc.CPF, &acc.Secret, &acc.Currency, &acc.Balance, &acc.Role, &acc.Status, &acc.Version, &acc.CreatedAt); err != nil {
 			return nil, types.NewErr(types.SelectStmtErr, "scanning account row", err)
 		}
 		accs = append(accs, acc)
@@ -43,12 +67,12 @@ func (r *account) Fetch(ctx context.Context) ([]entity.Account, error) {
 }
 
 func (r *account) Create(ctx context.Context, e entity.Account) (insertedID int64, err error) {
-	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO account(name, cpf, secret, balance, created_at) VALUES (?,?,?,?,?)")
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO account(name, cpf, secret, currency, balance, role, status, version, created_at) VALUES (?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return insertedID, types.NewErr(types.InsertStmtErr, "preparing account insert stmt", err)
 	}
 	defer stmt.Close()
-	result, err := stmt.ExecContext(ctx, e.Name, e.CPF, e.Secret, e.Balance, e.CreatedAt)
+	result, err := stmt.ExecContext(ctx, e.Name, e.CPF, e.Secret, e.Currency, e.Balance, e.Role, e.Status, e.Version, e.CreatedAt)
 	if err != nil {
 		return insertedID, types.NewErr(types.InsertStmtErr, "exec account insert stmt", err)
 	}
@@ -70,8 +94,8 @@ func (r *account) GetBalance(ctx context.Context, id int64) (types.Currency, err
 }
 
 func (r *account) FindBy(ctx context.Context, cpf string) (acc entity.Account, err error) {
-	q := "SELECT id, name, cpf, secret, balance, created_at FROM account WHERE cpf=?"
-	err = (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, cpf).Scan(&acc.ID, &acc.Name, &acc.CPF, &acc.Secret, &acc.Balance, &acc.CreatedAt)
+	q := "SELECT id, name, cpf, secret, currency, balance, role, status, version, created_at FROM account WHERE cpf=?"
+	err = (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, cpf).Scan(&acc.ID, &acc.Name, &acc.CPF, &acc.Secret, &acc.Currency, &acc.Balance, &acc.Role, &acc.Status, &acc.Version, &acc.CreatedAt)
 	if err == sql.ErrNoRows {
 		return acc, types.NewErr(types.EmptyResultErr, "no result finding account by cpf", err)
 	}
@@ -81,6 +105,18 @@ func (r *account) FindBy(ctx context.Context, cpf string) (acc entity.Account, e
 	return acc, nil
 }
 
+func (r *account) Get(ctx context.Context, id int64) (acc entity.Account, err error) {
+	q := "SELECT id, name, cpf, secret, currency, balance, role, status, version, created_at FROM account WHERE id=?"
+	err = (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id).Scan(&acc.ID, &acc.Name, &acc.CPF, &acc.Secret, &acc.Currency, &acc.Balance, &acc.Role, &acc.Status, &acc.Version, &acc.CreatedAt)
+	if err == sql.ErrNoRows {
+		return acc, types.NewErr(types.EmptyResultErr, "no result getting account by id", err)
+	}
+	if err != nil {
+		return acc, types.NewErr(types.SelectStmtErr, "getting account by id", err)
+	}
+	return acc, nil
+}
+
 func (r *account) UpdateBalance(ctx context.Context, id int64, balance types.Currency) error {
 	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE account SET balance=? WHERE id=?")
 	if err != nil {
@@ -101,6 +137,86 @@ func (r *account) UpdateBalance(ctx context.Context, id int64, balance types.Cur
 	return nil
 }
 
+func (r *account) UpdateRole(ctx context.Context, id int64, role entity.Role) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE account SET role=? WHERE id=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing update account role stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, role, id)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update account role stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update role stmt", nil)
+	}
+	return nil
+}
+
+func (r *account) UpdateSecret(ctx context.Context, id int64, secret string) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE account SET secret=? WHERE id=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing update account secret stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, secret, id)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update account secret stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update secret stmt", nil)
+	}
+	return nil
+}
+
+func (r *account) UpdateStatus(ctx context.Context, id int64, status entity.Status) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE account SET status=? WHERE id=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing update account status stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, status, id)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update account status stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update status stmt", nil)
+	}
+	return nil
+}
+
+func (r *account) UpdateProfile(ctx context.Context, e entity.Account) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE account SET name=?, version=version+1 WHERE id=? AND version=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing update account profile stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.Name, e.ID, e.Version)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update account profile stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update profile stmt", nil)
+	}
+	return nil
+}
+
 func (r *account) Exists(ctx context.Context, id int64) (bool, error) {
 	var exists bool
 	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, "SELECT EXISTS(SELECT id FROM account WHERE id=?)", id).Scan(&exists)
@@ -109,3 +225,31 @@ func (r *account) Exists(ctx context.Context, id int64) (bool, error) {
 	}
 	return exists, nil
 }
+
+// Lock acquires an exclusive row lock over the accounts stored at ids until the current tx ends.
+// Rows are locked in ascending id order so that concurrent callers can't deadlock each other.
+// It must run within repository.Transactioner#WithTx, otherwise the locks are released right away
+func (r *account) Lock(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	sorted := make([]int64, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	args := make([]interface{}, 0, len(sorted))
+	for _, id := range sorted {
+		args = append(args, id)
+	}
+	q := "SELECT id FROM account WHERE id IN (?" + strings.Repeat(",?", len(args)-1) + ") ORDER BY id FOR UPDATE"
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q, args...)
+	if err != nil {
+		return types.NewErr(types.SelectStmtErr, "locking account rows", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err = rows.Err(); err != nil {
+		return types.NewErr(types.SelectStmtErr, "iterating over the locked account rows", err)
+	}
+	return nil
+}
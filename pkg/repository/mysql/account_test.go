@@ -3,10 +3,13 @@ package mysql_test
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
@@ -35,7 +38,7 @@ func TestAccountRepositoryFetch(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			entities := persistTestAccountEntity(t, tc.input)
 
-			if accs, err := repo.Fetch(context.Background()); err == nil {
+			if accs, err := repo.Fetch(context.Background(), repository.AccountQuery{}); err == nil {
 				testutil.AssertEq(t, "result size", len(tc.input), len(accs))
 				for _, acc := range accs {
 					if expected, ok := entities[acc.ID]; ok {
@@ -54,6 +57,62 @@ func TestAccountRepositoryFetch(t *testing.T) {
 	}
 }
 
+func TestAccountRepositoryFetchPage(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	base := time.Now().UTC().Truncate(time.Second)
+	input := []entity.Account{
+		testutil.NewEntityAccount(0, "Bob", "00000000010", "S010", 1),
+		testutil.NewEntityAccount(0, "Bea", "00000000011", "S011", 1),
+		testutil.NewEntityAccount(0, "Al_", "00000000012", "S012", 1),
+		testutil.NewEntityAccount(0, "Ana", "00000000013", "S013", 1),
+	}
+	for i := range input {
+		input[i].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+	}
+	persistTestAccountEntity(t, input)
+
+	names := func(accs []entity.Account) string {
+		result := make([]string, 0, len(accs))
+		for _, acc := range accs {
+			result = append(result, acc.Name)
+		}
+		return strings.Join(result, ",")
+	}
+	tt := []struct {
+		name     string
+		query    repository.AccountQuery
+		expected string
+	}{
+		{
+			name:     "fetch accounts sorted by creation date",
+			query:    repository.AccountQuery{Limit: 2, Sort: repository.AccountSortCreatedAt},
+			expected: "Bob,Bea",
+		},
+		{
+			name:     "fetch accounts sorted by name descending after key",
+			query:    repository.AccountQuery{Sort: repository.AccountSortName, Desc: true, After: &repository.AccountKey{Name: "Bea"}},
+			expected: "Ana,Al_",
+		},
+		{
+			name:     "fetch accounts by name prefix with wildcard",
+			query:    repository.AccountQuery{Sort: repository.AccountSortName, NamePrefix: "Al_"},
+			expected: "Al_",
+		},
+		{
+			name:     "fetch accounts by creation date range",
+			query:    repository.AccountQuery{Sort: repository.AccountSortCreatedAt, CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(3 * time.Hour)},
+			expected: "Bea,Al_",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			accs, err := repo.Fetch(context.Background(), tc.query)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "names", tc.expected, names(accs))
+		})
+	}
+}
+
 func TestAccountRepositoryCreate(t *testing.T) {
 	repo := mysql.NewAccount(&txr)
 	tt := []struct {
@@ -78,8 +137,8 @@ func TestAccountRepositoryCreate(t *testing.T) {
 			for _, e := range tc.input {
 				if id, err := repo.Create(context.Background(), e); err == nil {
 					current := entity.Account{}
-					row := db.QueryRow("SELECT name, cpf, secret, balance, created_at FROM account WHERE id=?", id)
-					if err = row.Scan(&current.Name, &current.CPF, &current.Secret, &current.Balance, &current.CreatedAt); err == nil {
+					row := db.QueryRow("SELECT name, cpf, secret, currency, balance, created_at FROM account WHERE id=?", id)
+					if err = row.Scan(&current.Name, &current.CPF, &current.Secret, &current.Currency, &current.Balance, &current.CreatedAt); err == nil {
 						if !reflect.DeepEqual(e, current) {
 							t.Errorf("expected new account equal to '%v' but got '%v'", e, current)
 						}
@@ -177,6 +236,47 @@ func TestAccountRepositoryFindBy(t *testing.T) {
 	}
 }
 
+func TestAccountRepositoryGet(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	tt := []struct {
+		name   string
+		input  map[int64]entity.Account
+		assert func(*testing.T, error)
+	}{
+		{
+			name: "get existing account by id",
+			input: persistTestAccountEntity(t, []entity.Account{
+				testutil.NewEntityAccount(0, "Lara", "55555555561", "S510", 510),
+			}),
+			assert: func(t *testing.T, err error) {
+				t.Error(err)
+			},
+		},
+		{
+			name:  "get nonexisting account by id",
+			input: map[int64]entity.Account{0: testutil.NewEntityAccount(0, "Lena", "55555555562", "S511", 511)},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting account by id")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for id, acc := range tc.input {
+				if e, err := repo.Get(context.Background(), id); err == nil {
+					acc.ID = id
+					if !reflect.DeepEqual(acc, e) {
+						t.Errorf("expected account equal to '%v' but got '%v'", acc, e)
+					}
+				} else {
+					tc.assert(t, err)
+				}
+			}
+		})
+	}
+}
+
 func TestAccountRepositoryUpdateBalance(t *testing.T) {
 	getCurrentAccBalance := func(id int64) (types.Currency, error) {
 		var balance types.Currency
@@ -230,6 +330,122 @@ func TestAccountRepositoryUpdateBalance(t *testing.T) {
 	}
 }
 
+func TestAccountRepositoryUpdateRole(t *testing.T) {
+	getCurrentAccRole := func(id int64) (entity.Role, error) {
+		var role entity.Role
+		err := db.QueryRow("SELECT role FROM account WHERE id=?", id).Scan(&role)
+		return role, err
+	}
+
+	repo := mysql.NewAccount(&txr)
+	tt := []struct {
+		name    string
+		input   map[int64]entity.Account
+		assert  func(*testing.T, error)
+		newRole entity.Role
+	}{
+		{
+			name: "update role from existing account",
+			input: persistTestAccountEntity(t, []entity.Account{
+				testutil.NewEntityAccount(0, "Ozzy", "88888888881", "S800", 800),
+			}),
+			assert: func(t *testing.T, err error) {
+				t.Error(err)
+			},
+			newRole: entity.RoleSupport,
+		},
+		{
+			name:  "update role from nonexisting account",
+			input: map[int64]entity.Account{0: testutil.NewEntityAccount(0, "Lizzy", "88888888882", "S802", 802)},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update role stmt")
+			},
+			newRole: entity.RoleAdmin,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for id := range tc.input {
+				if err := repo.UpdateRole(context.Background(), id, tc.newRole); err == nil {
+					if role, err := getCurrentAccRole(id); err == nil {
+						testutil.AssertEq(t, "new role", tc.newRole, role)
+					} else {
+						t.Error(err)
+					}
+				} else {
+					tc.assert(t, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountRepositoryUpdateSecret(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	ctx := context.Background()
+	var id int64
+	for k := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Bruce", "88888888883", "S803", 803),
+	}) {
+		id = k
+	}
+
+	testutil.AssertNoErr(t, repo.UpdateSecret(ctx, id, "S804"))
+	var secret string
+	testutil.AssertNoErr(t, db.QueryRow("SELECT secret FROM account WHERE id=?", id).Scan(&secret))
+	testutil.AssertEq(t, "new secret", "S804", secret)
+
+	err := repo.UpdateSecret(ctx, id+1, "S805")
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update secret stmt")
+}
+
+func TestAccountRepositoryUpdateStatus(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	ctx := context.Background()
+	var id int64
+	for k := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Selina", "88888888884", "S806", 806),
+	}) {
+		id = k
+	}
+
+	acc, err := repo.Get(ctx, id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "default status", entity.StatusActive, acc.Status)
+
+	testutil.AssertNoErr(t, repo.UpdateStatus(ctx, id, entity.StatusBlocked))
+	acc, err = repo.Get(ctx, id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "new status", entity.StatusBlocked, acc.Status)
+
+	err = repo.UpdateStatus(ctx, id+1, entity.StatusClosed)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update status stmt")
+}
+
+func TestAccountRepositoryUpdateProfile(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	ctx := context.Background()
+	var acc entity.Account
+	for k, v := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Selena", "88888888885", "S807", 807),
+	}) {
+		acc = v
+		acc.ID = k
+	}
+
+	acc.Name = "Selina"
+	testutil.AssertNoErr(t, repo.UpdateProfile(ctx, acc))
+	updated, err := repo.Get(ctx, acc.ID)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "new name", "Selina", updated.Name)
+	testutil.AssertEq(t, "new version", acc.Version+1, updated.Version)
+
+	acc.Name = "Celina"
+	err = repo.UpdateProfile(ctx, acc)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update profile stmt")
+}
+
 func TestAccountRepositoryExists(t *testing.T) {
 	repo := mysql.NewAccount(&txr)
 	tt := []struct {
@@ -263,3 +479,36 @@ func TestAccountRepositoryExists(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountRepositoryLock(t *testing.T) {
+	repo := mysql.NewAccount(&txr)
+	tt := []struct {
+		name  string
+		input map[int64]entity.Account
+	}{
+		{
+			name: "lock existing accounts",
+			input: persistTestAccountEntity(t, []entity.Account{
+				testutil.NewEntityAccount(0, "Anna", "88888888801", "S801", 801),
+				testutil.NewEntityAccount(0, "Bella", "88888888802", "S802", 802),
+			}),
+		},
+		{
+			name:  "lock nonexisting accounts",
+			input: map[int64]entity.Account{0: testutil.NewEntityAccount(0, "Carl", "88888888803", "S803", 803)},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]int64, 0, len(tc.input))
+			for id := range tc.input {
+				ids = append(ids, id)
+			}
+			err := txr.WithTx(context.Background(), func(ctx context.Context) error {
+				return repo.Lock(ctx, ids...)
+			})
+			testutil.AssertNoErr(t, err)
+		})
+	}
+}
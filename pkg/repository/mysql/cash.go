@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type cashOperation struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.CashOperation = (*cashOperation)(nil)
+
+// NewCashOperation creates a value that satisfies the repository.CashOperation interface
+func NewCashOperation(txr *repository.Transactioner) repository.CashOperation {
+	return &cashOperation{txr: txr}
+}
+
+func (r *cashOperation) Create(ctx context.Context, e entity.CashOperation) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO cash_operation(account_id, operation_type, amount, currency, reference, client_id, created_at) VALUES (?,?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing cash operation insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.AccountID, e.Type, e.Amount, e.Currency, e.Reference, e.ClientID, e.CreatedAt)
+	if err != nil {
+		if mysqlErr, ok := err.(*driver.MySQLError); ok && mysqlErr.Number == erDupEntry {
+			return insertedID, types.NewErr(types.ConflictErr, "cash operation reference already exists", err)
+		}
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec cash operation insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted cash operation id", err)
+	}
+	return insertedID, nil
+}
+
+func (r *cashOperation) Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.CashOperation, error) {
+	q := "SELECT id, account_id, operation_type, amount, currency, reference, client_id, created_at FROM cash_operation WHERE account_id=? AND created_at >= ? AND created_at < ? ORDER BY created_at, id"
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q, account, from, to)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "querying the account cash operations", err)
+	}
+	defer rows.Close()
+	operations := make([]entity.CashOperation, 0)
+	for rows.Next() {
+		var e entity.CashOperation
+		if err = rows.Scan(&e.ID, &e.AccountID, &e.Type, &e.Amount, &e.Currency, &e.Reference, &e.ClientID, &e.CreatedAt); err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning the cash operation row", err)
+		}
+		operations = append(operations, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the cash operation rows", err)
+	}
+	return operations, nil
+}
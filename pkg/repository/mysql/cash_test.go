@@ -0,0 +1,52 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestCashOperationRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewCashOperation(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Lois", "17171717171", "S170", 0),
+	}) {
+		account = id
+	}
+	operations := []entity.CashOperation{
+		{AccountID: account, Type: entity.Deposit, Amount: types.NewCurrency(50), Currency: "BRL", Reference: "atm-1", ClientID: "branch", CreatedAt: now},
+		{AccountID: account, Type: entity.Withdrawal, Amount: types.NewCurrency(20), Currency: "BRL", Reference: "atm-1", ClientID: "branch", CreatedAt: now},
+	}
+	for _, e := range operations {
+		id, err := repo.Create(ctx, e)
+		testutil.AssertNoErr(t, err)
+		if id <= 0 {
+			t.Errorf("expected a positive cash operation id, got %d", id)
+		}
+	}
+
+	_, err := repo.Create(ctx, operations[0])
+	testutil.AssertCustomErr(t, types.ConflictErr, err, "cash operation reference already exists")
+
+	stored, err := repo.Fetch(ctx, account, now.Add(-time.Hour), now.Add(time.Hour))
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "cash operations", len(operations), len(stored))
+	for i, e := range stored {
+		testutil.AssertEq(t, "type", operations[i].Type, e.Type)
+		testutil.AssertEq(t, "amount", operations[i].Amount, e.Amount)
+		testutil.AssertEq(t, "reference", operations[i].Reference, e.Reference)
+		testutil.AssertEq(t, "client id", operations[i].ClientID, e.ClientID)
+	}
+
+	stored, err = repo.Fetch(ctx, account, now.Add(time.Hour), now.Add(2*time.Hour))
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "cash operations out of the period", 0, len(stored))
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type client struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.Client = (*client)(nil)
+
+// NewClient creates a value that satisfies the repository.Client interface
+func NewClient(txr *repository.Transactioner) repository.Client {
+	return &client{txr: txr}
+}
+
+// Create inserts the client, whose scopes are stored space delimited
+func (r *client) Create(ctx context.Context, e entity.Client) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO client(client_id, name, secret_hash, scopes, created_at) VALUES (?,?,?,?,?)")
+	if err != nil {
+		return types.NewErr(types.InsertStmtErr, "preparing client insert stmt", err)
+	}
+	defer stmt.Close()
+	if _, err = stmt.ExecContext(ctx, e.ID, e.Name, e.SecretHash, strings.Join(e.Scopes, " "), e.CreatedAt); err != nil {
+		return types.NewErr(types.InsertStmtErr, "exec client insert stmt", err)
+	}
+	return nil
+}
+
+func (r *client) Get(ctx context.Context, id string) (entity.Client, error) {
+	var e entity.Client
+	var scopes string
+	q := "SELECT client_id, name, secret_hash, scopes, created_at, disabled_at FROM client WHERE client_id=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id).
+		Scan(&e.ID, &e.Name, &e.SecretHash, &scopes, &e.CreatedAt, &e.DisabledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting client", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting client", err)
+	}
+	e.Scopes = strings.Fields(scopes)
+	return e, nil
+}
+
+func (r *client) Disable(ctx context.Context, id string, at time.Time) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE client SET disabled_at=? WHERE client_id=? AND disabled_at IS NULL", at, id)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the disable client stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the disable client stmt", nil)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestClientRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewClient(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	e := entity.Client{
+		ID:         "c1",
+		Name:       "Batch",
+		SecretHash: "h1",
+		Scopes:     []string{entity.ScopeAccountsRead, entity.ScopeTransfersRead},
+		CreatedAt:  now,
+	}
+	testutil.AssertNoErr(t, repo.Create(ctx, e))
+
+	stored, err := repo.Get(ctx, e.ID)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "name", e.Name, stored.Name)
+	testutil.AssertEq(t, "secret hash", e.SecretHash, stored.SecretHash)
+	testutil.AssertEq(t, "scopes", strings.Join(e.Scopes, " "), strings.Join(stored.Scopes, " "))
+	if stored.DisabledAt != nil {
+		t.Errorf("expected the client to be enabled")
+	}
+
+	testutil.AssertNoErr(t, repo.Disable(ctx, e.ID, now))
+	err = repo.Disable(ctx, e.ID, now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the disable client stmt")
+	disabled, err := repo.Get(ctx, e.ID)
+	testutil.AssertNoErr(t, err)
+	if disabled.DisabledAt == nil {
+		t.Errorf("expected the client to be disabled")
+	}
+
+	_, err = repo.Get(ctx, "unknown")
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting client")
+}
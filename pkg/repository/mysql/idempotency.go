@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+// erDupEntry is the mysql error number of unique constraint violations
+const erDupEntry uint16 = 1062
+
+type idempotency struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.Idempotency = (*idempotency)(nil)
+
+// NewIdempotency creates a value that satisfies the repository.Idempotency interface
+func NewIdempotency(txr *repository.Transactioner) repository.Idempotency {
+	return &idempotency{txr: txr}
+}
+
+func (r *idempotency) Create(ctx context.Context, e entity.IdempotencyKey) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO idempotency_key(scope, idem_key, request_hash, created_at, expires_at) VALUES (?,?,?,?,?)")
+	if err != nil {
+		return types.NewErr(types.InsertStmtErr, "preparing idempotency key insert stmt", err)
+	}
+	defer stmt.Close()
+	if _, err = stmt.ExecContext(ctx, e.Scope, e.Key, e.RequestHash, e.CreatedAt, e.ExpiresAt); err != nil {
+		if mysqlErr, ok := err.(*driver.MySQLError); ok && mysqlErr.Number == erDupEntry {
+			return types.NewErr(types.ConflictErr, "idempotency key already exists", err)
+		}
+		return types.NewErr(types.InsertStmtErr, "exec idempotency key insert stmt", err)
+	}
+	return nil
+}
+
+func (r *idempotency) Get(ctx context.Context, scope string, key string) (entity.IdempotencyKey, error) {
+	var e entity.IdempotencyKey
+	q := "SELECT scope, idem_key, request_hash, status_code, location, response_body, created_at, expires_at FROM idempotency_key WHERE scope=? AND idem_key=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, scope, key).
+		Scan(&e.Scope, &e.Key, &e.RequestHash, &e.StatusCode, &e.Location, &e.ResponseBody, &e.CreatedAt, &e.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting idempotency key", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting idempotency key", err)
+	}
+	return e, nil
+}
+
+func (r *idempotency) Update(ctx context.Context, e entity.IdempotencyKey) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE idempotency_key SET status_code=?, location=?, response_body=? WHERE scope=? AND idem_key=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing update idempotency key stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.StatusCode, e.Location, e.ResponseBody, e.Scope, e.Key)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update idempotency key stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update idempotency key stmt", nil)
+	}
+	return nil
+}
+
+func (r *idempotency) Delete(ctx context.Context, scope string, key string) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "DELETE FROM idempotency_key WHERE scope=? AND idem_key=?", scope, key); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the delete idempotency key stmt", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestIdempotencyRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewIdempotency(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	e := entity.IdempotencyKey{
+		Scope:       "POST /transfers 1",
+		Key:         "k1",
+		RequestHash: "5d41402abc4b2a76b9719d911017c592",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	testutil.AssertNoErr(t, repo.Create(ctx, e))
+	err := repo.Create(ctx, e)
+	testutil.AssertCustomErr(t, types.ConflictErr, err, "idempotency key already exists")
+
+	pending, err := repo.Get(ctx, e.Scope, e.Key)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "request hash", e.RequestHash, pending.RequestHash)
+	testutil.AssertEq(t, "completed", false, pending.Completed())
+
+	e.StatusCode = 201
+	e.Location = "/transfers/1"
+	e.ResponseBody = []byte(`{"id":1}`)
+	testutil.AssertNoErr(t, repo.Update(ctx, e))
+	completed, err := repo.Get(ctx, e.Scope, e.Key)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "status code", e.StatusCode, completed.StatusCode)
+	testutil.AssertEq(t, "location", e.Location, completed.Location)
+	testutil.AssertEq(t, "response body", string(e.ResponseBody), string(completed.ResponseBody))
+
+	testutil.AssertNoErr(t, repo.Delete(ctx, e.Scope, e.Key))
+	_, err = repo.Get(ctx, e.Scope, e.Key)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting idempotency key")
+}
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type ledger struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.Ledger = (*ledger)(nil)
+
+// NewLedger creates a value that satisfies the repository.Ledger interface
+func NewLedger(txr *repository.Transactioner) repository.Ledger {
+	return &ledger{txr: txr}
+}
+
+func (r *ledger) Create(ctx context.Context, e entity.LedgerEntry) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO ledger_entry(account_id, transfer_id, cash_operation_id, entry_type, amount, created_at) VALUES (?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing ledger entry insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.AccountID, e.TransferID, e.CashOperationID, e.Type, e.Amount, e.CreatedAt)
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec ledger entry insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted ledger entry id", err)
+	}
+	return insertedID, nil
+}
+
+// GetBalance derives the account balance by adding up its credits and subtracting its debits
+func (r *ledger) GetBalance(ctx context.Context, account int64) (types.Currency, error) {
+	var balance types.Currency
+	q := "SELECT COALESCE(SUM(IF(entry_type='credit', amount, -amount)), 0) FROM ledger_entry WHERE account_id=?"
+	if err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, account).Scan(&balance); err != nil {
+		return 0, types.NewErr(types.SelectStmtErr, "summing the account ledger entries", err)
+	}
+	return balance, nil
+}
+
+// GetBalanceBefore derives the account balance out of the entries posted before t
+func (r *ledger) GetBalanceBefore(ctx context.Context, account int64, t time.Time) (types.Currency, error) {
+	var balance types.Currency
+	q := "SELECT COALESCE(SUM(IF(entry_type='credit', amount, -amount)), 0) FROM ledger_entry WHERE account_id=? AND created_at < ?"
+	if err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, account, t).Scan(&balance); err != nil {
+		return 0, types.NewErr(types.SelectStmtErr, "summing the account ledger entries before a date", err)
+	}
+	return balance, nil
+}
+
+// Fetch returns the account entries posted from the inclusive from up to the exclusive to, in posting order
+func (r *ledger) Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error) {
+	q := "SELECT id, account_id, transfer_id, cash_operation_id, entry_type, amount, created_at FROM ledger_entry WHERE account_id=? AND created_at >= ? AND created_at < ? ORDER BY created_at, id"
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q, account, from, to)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "querying the account ledger entries", err)
+	}
+	defer rows.Close()
+	entries := make([]entity.LedgerEntry, 0)
+	for rows.Next() {
+		var e entity.LedgerEntry
+		if err = rows.Scan(&e.ID, &e.AccountID, &e.TransferID, &e.CashOperationID, &e.Type, &e.Amount, &e.CreatedAt); err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning the ledger entry row", err)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the ledger entry rows", err)
+	}
+	return entries, nil
+}
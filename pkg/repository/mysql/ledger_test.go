@@ -0,0 +1,134 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestLedgerRepositoryCreate(t *testing.T) {
+	repo := mysql.NewLedger(&txr)
+	tt := []struct {
+		name    string
+		prepare func(*testing.T) entity.LedgerEntry
+	}{
+		{
+			name: "create opening ledger entry successfully",
+			prepare: func(t *testing.T) entity.LedgerEntry {
+				accounts := persistTestAccountEntity(t, []entity.Account{
+					testutil.NewEntityAccount(0, "Otto", "13131313131", "S130", 130),
+				})
+				for id, acc := range accounts {
+					return entity.LedgerEntry{
+						AccountID: id,
+						Type:      entity.Credit,
+						Amount:    acc.Balance,
+						CreatedAt: acc.CreatedAt,
+					}
+				}
+				return entity.LedgerEntry{}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			e := tc.prepare(t)
+			if id, err := repo.Create(context.Background(), e); err == nil {
+				current := entity.LedgerEntry{ID: id}
+				row := db.QueryRow("SELECT account_id, transfer_id, entry_type, amount, created_at FROM ledger_entry WHERE id=?", id)
+				if err = row.Scan(&current.AccountID, &current.TransferID, &current.Type, &current.Amount, &current.CreatedAt); err == nil {
+					e.ID = id
+					testutil.AssertEq(t, "account id", e.AccountID, current.AccountID)
+					testutil.AssertEq(t, "entry type", e.Type, current.Type)
+					testutil.AssertEq(t, "amount", e.Amount, current.Amount)
+					if current.TransferID != nil {
+						t.Errorf("expected no transfer id but got '%d'", *current.TransferID)
+					}
+				} else {
+					t.Error(err)
+				}
+			} else {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestLedgerRepositoryGetBalance(t *testing.T) {
+	repo := mysql.NewLedger(&txr)
+	tt := []struct {
+		name     string
+		entries  []entity.EntryType
+		expected types.Currency
+	}{
+		{
+			name:     "get balance with no entries",
+			expected: 0,
+		},
+		{
+			name:     "get balance with credits and debits",
+			entries:  []entity.EntryType{entity.Credit, entity.Credit, entity.Debit},
+			expected: types.NewCurrency(10),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			accounts := persistTestAccountEntity(t, []entity.Account{
+				testutil.NewEntityAccount(0, "Paul", "14141414141", "S140", 0),
+			})
+			for id := range accounts {
+				for _, entryType := range tc.entries {
+					_, err := db.Exec("INSERT INTO ledger_entry(account_id, entry_type, amount, created_at) VALUES (?,?,?,?)", id, entryType, types.NewCurrency(10), time.Now())
+					logFatal(err, "unable to exec ledger entry insert stmt")
+				}
+				if balance, err := repo.GetBalance(context.Background(), id); err == nil {
+					testutil.AssertEq(t, "balance", tc.expected, balance)
+				} else {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestLedgerRepositoryStatement(t *testing.T) {
+	repo := mysql.NewLedger(&txr)
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Rui", "15151515151", "S150", 0),
+	})
+	base := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	for id := range accounts {
+		for _, e := range []struct {
+			entryType entity.EntryType
+			amount    float64
+			createdAt time.Time
+		}{
+			{entity.Credit, 100, base.AddDate(0, 0, -1)},
+			{entity.Debit, 30, base},
+			{entity.Credit, 5, base.AddDate(0, 0, 10)},
+			{entity.Debit, 1, base.AddDate(0, 1, 0)},
+		} {
+			_, err := db.Exec("INSERT INTO ledger_entry(account_id, entry_type, amount, created_at) VALUES (?,?,?,?)", id, e.entryType, types.NewCurrency(e.amount), e.createdAt)
+			logFatal(err, "unable to exec ledger entry insert stmt")
+		}
+
+		opening, err := repo.GetBalanceBefore(context.Background(), id, base)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEq(t, "opening balance", types.NewCurrency(100), opening)
+
+		entries, err := repo.Fetch(context.Background(), id, base, base.AddDate(0, 1, 0))
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEq(t, "entries size", 2, len(entries))
+		if len(entries) == 2 {
+			testutil.AssertEq(t, "first entry type", entity.Debit, entries[0].Type)
+			testutil.AssertEq(t, "second entry amount", types.NewCurrency(5), entries[1].Amount)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type loginAttempt struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.LoginAttempt = (*loginAttempt)(nil)
+
+// NewLoginAttempt creates a value that satisfies the repository.LoginAttempt interface
+func NewLoginAttempt(txr *repository.Transactioner) repository.LoginAttempt {
+	return &loginAttempt{txr: txr}
+}
+
+func (r *loginAttempt) Get(ctx context.Context, key string) (entity.LoginAttempt, error) {
+	var e entity.LoginAttempt
+	q := "SELECT attempt_key, failures, last_failure_at, locked_until FROM login_attempt WHERE attempt_key=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, key).Scan(&e.Key, &e.Failures, &e.LastFailureAt, &e.LockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting login attempt", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting login attempt", err)
+	}
+	return e, nil
+}
+
+// Increment upserts the counter so that concurrent failures of the same key are all counted
+func (r *loginAttempt) Increment(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error) {
+	// failures is assigned first so that it still compares against the previous last_failure_at
+	q := `INSERT INTO login_attempt(attempt_key, failures, last_failure_at) VALUES (?,1,?)
+		ON DUPLICATE KEY UPDATE failures=IF(last_failure_at < ?, 1, failures+1), last_failure_at=VALUES(last_failure_at)`
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, q, key, at, since); err != nil {
+		return entity.LoginAttempt{}, types.NewErr(types.InsertStmtErr, "exec login attempt upsert stmt", err)
+	}
+	return r.Get(ctx, key)
+}
+
+// Lock refuses the logins of the key until the given time, restarting its counter
+func (r *loginAttempt) Lock(ctx context.Context, key string, until time.Time) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE login_attempt SET failures=0, locked_until=? WHERE attempt_key=?", until, key)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the lock login attempt stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the lock login attempt stmt", nil)
+	}
+	return nil
+}
+
+func (r *loginAttempt) Delete(ctx context.Context, key string) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "DELETE FROM login_attempt WHERE attempt_key=?", key); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the delete login attempt stmt", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestLoginAttemptRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewLoginAttempt(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	key := "cpf:17171717171"
+
+	_, err := repo.Get(ctx, key)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting login attempt")
+	err = repo.Lock(ctx, key, now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the lock login attempt stmt")
+
+	attempt, err := repo.Increment(ctx, key, now.Add(-time.Hour), now.Add(-2*time.Hour))
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "failures", 1, attempt.Failures)
+	attempt, err = repo.Increment(ctx, key, now.Add(-time.Hour), now.Add(-2*time.Hour))
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "failures", 2, attempt.Failures)
+	attempt, err = repo.Increment(ctx, key, now, now.Add(-time.Minute))
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "failures after the window", 1, attempt.Failures)
+
+	testutil.AssertNoErr(t, repo.Lock(ctx, key, now.Add(time.Minute)))
+	locked, err := repo.Get(ctx, key)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "failures", 0, locked.Failures)
+	if locked.LockedUntil == nil || !locked.LockedUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the login attempt to be locked until '%v' but got '%v'", now.Add(time.Minute), locked.LockedUntil)
+	}
+
+	testutil.AssertNoErr(t, repo.Delete(ctx, key))
+	_, err = repo.Get(ctx, key)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting login attempt")
+}
@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN role VARCHAR(16) CHARACTER SET ascii NOT NULL DEFAULT 'customer';
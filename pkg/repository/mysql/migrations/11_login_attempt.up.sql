@@ -0,0 +1,6 @@
+CREATE TABLE login_attempt(
+    attempt_key VARCHAR(64) CHARACTER SET ascii NOT NULL PRIMARY KEY,
+    failures INT NOT NULL,
+    last_failure_at DATETIME NOT NULL,
+    locked_until DATETIME NULL
+);
@@ -0,0 +1,14 @@
+CREATE TABLE account_totp(
+    account_id INT NOT NULL PRIMARY KEY REFERENCES account(id),
+    secret VARCHAR(64) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL,
+    enabled_at DATETIME NULL,
+    last_step BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE recovery_code(
+    account_id INT NOT NULL REFERENCES account(id),
+    code_hash CHAR(64) CHARACTER SET ascii NOT NULL,
+    used_at DATETIME NULL,
+    PRIMARY KEY (account_id, code_hash)
+);
@@ -0,0 +1,3 @@
+DROP INDEX refresh_token_account_idx ON refresh_token;
+
+DROP TABLE secret_reset_token;
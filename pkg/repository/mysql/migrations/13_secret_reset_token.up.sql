@@ -0,0 +1,10 @@
+CREATE TABLE secret_reset_token(
+    token_hash CHAR(64) CHARACTER SET ascii NOT NULL PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES account(id),
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    INDEX secret_reset_token_account_idx (account_id)
+);
+
+CREATE INDEX refresh_token_account_idx ON refresh_token (account_id);
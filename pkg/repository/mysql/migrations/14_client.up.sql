@@ -0,0 +1,8 @@
+CREATE TABLE client(
+    client_id CHAR(32) CHARACTER SET ascii NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    secret_hash CHAR(64) CHARACTER SET ascii NOT NULL,
+    scopes VARCHAR(255) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL,
+    disabled_at DATETIME NULL
+);
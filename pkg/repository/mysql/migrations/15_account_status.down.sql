@@ -0,0 +1,3 @@
+DROP TABLE account_status_change;
+
+ALTER TABLE account DROP COLUMN status;
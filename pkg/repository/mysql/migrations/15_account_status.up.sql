@@ -0,0 +1,12 @@
+ALTER TABLE account ADD COLUMN status VARCHAR(16) CHARACTER SET ascii NOT NULL DEFAULT 'active';
+
+CREATE TABLE account_status_change(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES account(id),
+    from_status VARCHAR(16) CHARACTER SET ascii NOT NULL,
+    to_status VARCHAR(16) CHARACTER SET ascii NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    changed_by INT NOT NULL REFERENCES account(id),
+    created_at DATETIME NOT NULL,
+    INDEX account_status_change_account_idx (account_id, id)
+);
@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1;
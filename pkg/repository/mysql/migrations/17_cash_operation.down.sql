@@ -0,0 +1,3 @@
+ALTER TABLE ledger_entry DROP COLUMN cash_operation_id;
+
+DROP TABLE cash_operation;
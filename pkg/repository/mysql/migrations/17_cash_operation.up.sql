@@ -0,0 +1,14 @@
+CREATE TABLE cash_operation(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES account(id),
+    operation_type ENUM('deposit', 'withdrawal') NOT NULL,
+    amount BIGINT NOT NULL CHECK(amount > 0),
+    currency CHAR(3) CHARACTER SET ascii NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    client_id CHAR(32) CHARACTER SET ascii NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE INDEX cash_operation_reference_idx (operation_type, reference),
+    INDEX cash_operation_account_idx (account_id, created_at)
+);
+
+ALTER TABLE ledger_entry ADD COLUMN cash_operation_id INT NULL REFERENCES cash_operation(id);
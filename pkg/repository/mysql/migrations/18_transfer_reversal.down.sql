@@ -0,0 +1,4 @@
+DROP INDEX transfer_reversal_of_idx ON transfer;
+
+ALTER TABLE transfer DROP COLUMN reversed_amount;
+ALTER TABLE transfer DROP COLUMN reversal_of;
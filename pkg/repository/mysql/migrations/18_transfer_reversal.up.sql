@@ -0,0 +1,4 @@
+ALTER TABLE transfer ADD COLUMN reversal_of INT NULL REFERENCES transfer(id);
+ALTER TABLE transfer ADD COLUMN reversed_amount BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX transfer_reversal_of_idx ON transfer (reversal_of);
@@ -0,0 +1,16 @@
+CREATE TABLE scheduled_transfer(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_origin_id INT NOT NULL REFERENCES account(id),
+    account_destination_id INT NOT NULL REFERENCES account(id),
+    amount BIGINT NOT NULL CHECK(amount > 0),
+    currency CHAR(3) CHARACTER SET ascii NOT NULL,
+    conversion BOOLEAN NOT NULL DEFAULT FALSE,
+    execute_at DATETIME NOT NULL,
+    status ENUM('pending', 'executed', 'failed', 'canceled') NOT NULL DEFAULT 'pending',
+    transfer_id INT NULL REFERENCES transfer(id),
+    failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    INDEX scheduled_transfer_due_idx (status, execute_at, id),
+    INDEX scheduled_transfer_origin_idx (account_origin_id, execute_at, id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE standing_order_execution;
+DROP TABLE standing_order;
@@ -0,0 +1,31 @@
+CREATE TABLE standing_order(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_origin_id INT NOT NULL REFERENCES account(id),
+    account_destination_id INT NOT NULL REFERENCES account(id),
+    amount BIGINT NOT NULL CHECK(amount > 0),
+    currency CHAR(3) CHARACTER SET ascii NOT NULL,
+    conversion BOOLEAN NOT NULL DEFAULT FALSE,
+    frequency ENUM('weekly', 'monthly') NOT NULL,
+    day_of_month TINYINT NOT NULL DEFAULT 0,
+    start_at DATETIME NOT NULL,
+    end_at DATETIME NULL,
+    max_executions INT NOT NULL DEFAULT 0,
+    executions INT NOT NULL DEFAULT 0,
+    next_at DATETIME NOT NULL,
+    status ENUM('active', 'finished', 'canceled') NOT NULL DEFAULT 'active',
+    created_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    INDEX standing_order_due_idx (status, next_at, id),
+    INDEX standing_order_origin_idx (account_origin_id, id)
+);
+
+CREATE TABLE standing_order_execution(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    standing_order_id INT NOT NULL REFERENCES standing_order(id),
+    scheduled_at DATETIME NOT NULL,
+    status ENUM('executed', 'failed') NOT NULL,
+    transfer_id INT NULL REFERENCES transfer(id),
+    failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    UNIQUE INDEX standing_order_execution_occurrence_idx (standing_order_id, scheduled_at)
+);
@@ -0,0 +1,4 @@
+CREATE TABLE revoked_account(
+    account_id INT NOT NULL PRIMARY KEY REFERENCES account(id),
+    revoked_at DATETIME NOT NULL
+);
@@ -0,0 +1 @@
+DROP TABLE ledger_entry;
\ No newline at end of file
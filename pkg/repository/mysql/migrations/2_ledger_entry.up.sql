@@ -0,0 +1,21 @@
+CREATE TABLE ledger_entry(
+    id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES account(id),
+    transfer_id INT NULL REFERENCES transfer(id),
+    entry_type ENUM('debit', 'credit') NOT NULL,
+    amount BIGINT NOT NULL CHECK(amount > 0),
+    created_at DATETIME NOT NULL,
+    INDEX ledger_entry_account_idx (account_id)
+);
+
+INSERT INTO ledger_entry(account_id, transfer_id, entry_type, amount, created_at)
+SELECT account_origin_id, id, 'debit', amount, created_at FROM transfer;
+
+INSERT INTO ledger_entry(account_id, transfer_id, entry_type, amount, created_at)
+SELECT account_destination_id, id, 'credit', amount, created_at FROM transfer;
+
+INSERT INTO ledger_entry(account_id, transfer_id, entry_type, amount, created_at)
+SELECT a.id, NULL, 'credit', a.balance - COALESCE(SUM(IF(l.entry_type = 'credit', l.amount, -l.amount)), 0), a.created_at
+FROM account a LEFT JOIN ledger_entry l ON l.account_id = a.id
+GROUP BY a.id, a.balance, a.created_at
+HAVING a.balance - COALESCE(SUM(IF(l.entry_type = 'credit', l.amount, -l.amount)), 0) > 0;
@@ -0,0 +1,3 @@
+ALTER TABLE transfer DROP COLUMN currency;
+
+ALTER TABLE account DROP COLUMN currency;
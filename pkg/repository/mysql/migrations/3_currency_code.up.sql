@@ -0,0 +1,3 @@
+ALTER TABLE account ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL' AFTER secret;
+
+ALTER TABLE transfer ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL' AFTER amount;
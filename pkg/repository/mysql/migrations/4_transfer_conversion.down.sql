@@ -0,0 +1,3 @@
+ALTER TABLE transfer DROP COLUMN rate;
+ALTER TABLE transfer DROP COLUMN destination_currency;
+ALTER TABLE transfer DROP COLUMN destination_amount;
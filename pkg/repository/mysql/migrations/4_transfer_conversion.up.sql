@@ -0,0 +1,5 @@
+ALTER TABLE transfer ADD COLUMN destination_amount BIGINT NOT NULL DEFAULT 0 AFTER currency;
+ALTER TABLE transfer ADD COLUMN destination_currency CHAR(3) NOT NULL DEFAULT 'BRL' AFTER destination_amount;
+ALTER TABLE transfer ADD COLUMN rate DECIMAL(24,12) NOT NULL DEFAULT 1 AFTER destination_currency;
+
+UPDATE transfer SET destination_amount = amount, destination_currency = currency, rate = 1;
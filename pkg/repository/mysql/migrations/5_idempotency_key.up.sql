@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_key(
+    scope VARCHAR(64) CHARACTER SET ascii NOT NULL,
+    idem_key VARCHAR(128) CHARACTER SET ascii NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    response_body BLOB NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (scope, idem_key)
+);
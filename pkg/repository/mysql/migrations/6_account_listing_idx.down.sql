@@ -0,0 +1,2 @@
+DROP INDEX account_name_idx ON account;
+DROP INDEX account_created_at_idx ON account;
@@ -0,0 +1,2 @@
+CREATE INDEX account_created_at_idx ON account (created_at, id);
+CREATE INDEX account_name_idx ON account (name, id);
@@ -0,0 +1,2 @@
+DROP INDEX transfer_destination_created_at_idx ON transfer;
+DROP INDEX transfer_origin_created_at_idx ON transfer;
@@ -0,0 +1,2 @@
+CREATE INDEX transfer_origin_created_at_idx ON transfer (account_origin_id, created_at, id);
+CREATE INDEX transfer_destination_created_at_idx ON transfer (account_destination_id, created_at, id);
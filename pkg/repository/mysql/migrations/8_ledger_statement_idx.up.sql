@@ -0,0 +1,2 @@
+CREATE INDEX ledger_entry_account_created_at_idx ON ledger_entry (account_id, created_at, id);
+DROP INDEX ledger_entry_account_idx ON ledger_entry;
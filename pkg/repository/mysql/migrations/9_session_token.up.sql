@@ -0,0 +1,16 @@
+CREATE TABLE refresh_token(
+    token_hash CHAR(64) CHARACTER SET ascii NOT NULL PRIMARY KEY,
+    family CHAR(32) CHARACTER SET ascii NOT NULL,
+    account_id INT NOT NULL REFERENCES account(id),
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    INDEX refresh_token_family_idx (family)
+);
+
+CREATE TABLE revoked_token(
+    jti VARCHAR(64) CHARACTER SET ascii NOT NULL PRIMARY KEY,
+    expires_at DATETIME NOT NULL,
+    INDEX revoked_token_expires_at_idx (expires_at)
+);
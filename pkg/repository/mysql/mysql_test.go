@@ -59,7 +59,52 @@ func runMigrations(pool *dockertest.Pool) {
 }
 
 func dbWipe() {
-	_, err := db.Exec("DELETE FROM transfer")
+	_, err := db.Exec("DELETE FROM client")
+	logFatal(err, "unable to clean the client table")
+
+	_, err = db.Exec("DELETE FROM account_status_change")
+	logFatal(err, "unable to clean the account_status_change table")
+
+	_, err = db.Exec("DELETE FROM secret_reset_token")
+	logFatal(err, "unable to clean the secret_reset_token table")
+
+	_, err = db.Exec("DELETE FROM recovery_code")
+	logFatal(err, "unable to clean the recovery_code table")
+
+	_, err = db.Exec("DELETE FROM account_totp")
+	logFatal(err, "unable to clean the account_totp table")
+
+	_, err = db.Exec("DELETE FROM login_attempt")
+	logFatal(err, "unable to clean the login_attempt table")
+
+	_, err = db.Exec("DELETE FROM idempotency_key")
+	logFatal(err, "unable to clean the idempotency_key table")
+
+	_, err = db.Exec("DELETE FROM revoked_token")
+	logFatal(err, "unable to clean the revoked_token table")
+
+	_, err = db.Exec("DELETE FROM revoked_account")
+	logFatal(err, "unable to clean the revoked_account table")
+
+	_, err = db.Exec("DELETE FROM refresh_token")
+	logFatal(err, "unable to clean the refresh_token table")
+
+	_, err = db.Exec("DELETE FROM ledger_entry")
+	logFatal(err, "unable to clean the ledger_entry table")
+
+	_, err = db.Exec("DELETE FROM standing_order_execution")
+	logFatal(err, "unable to clean the standing_order_execution table")
+
+	_, err = db.Exec("DELETE FROM standing_order")
+	logFatal(err, "unable to clean the standing_order table")
+
+	_, err = db.Exec("DELETE FROM scheduled_transfer")
+	logFatal(err, "unable to clean the scheduled_transfer table")
+
+	_, err = db.Exec("DELETE FROM cash_operation")
+	logFatal(err, "unable to clean the cash_operation table")
+
+	_, err = db.Exec("DELETE FROM transfer")
 	logFatal(err, "unable to clean the transfer table")
 
 	_, err = db.Exec("DELETE FROM account")
@@ -78,11 +123,11 @@ func persistTestAccountEntity(t *testing.T, input []entity.Account) map[int64]en
 		return entities
 	}
 	t.Cleanup(dbWipe)
-	stmt, err := db.Prepare("INSERT INTO account(name,cpf,secret,balance,created_at) VALUES (?,?,?,?,?)")
+	stmt, err := db.Prepare("INSERT INTO account(name,cpf,secret,currency,balance,created_at) VALUES (?,?,?,?,?,?)")
 	logFatal(err, "unable to prepare account insert stmt")
 	defer stmt.Close()
 	for _, e := range input {
-		result, _ := stmt.Exec(e.Name, e.CPF, e.Secret, e.Balance, e.CreatedAt)
+		result, _ := stmt.Exec(e.Name, e.CPF, e.Secret, e.Currency, e.Balance, e.CreatedAt)
 		logFatal(err, "unable to exec account insert stmt")
 		id, _ := result.LastInsertId()
 		logFatal(err, "unable to retrieve inserted account id")
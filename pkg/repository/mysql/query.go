@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+)
+
+var _likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// query assembles a SELECT statement out of optional conditions
+type query struct {
+	conds []string
+	args  []interface{}
+}
+
+func (q *query) where(cond string, args ...interface{}) {
+	q.conds = append(q.conds, cond)
+	q.args = append(q.args, args...)
+}
+
+// after restricts the rows to those that come after the (col, id) key in the given direction
+func (q *query) after(col string, desc bool, v interface{}, id int64) {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	q.where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", col, op, col, op), v, v, id)
+}
+
+// build returns the statement made of the base SELECT, the conditions, the ordering and the limit, if any
+func (q *query) build(base string, col string, desc bool, limit int) (string, []interface{}) {
+	var b strings.Builder
+	b.WriteString(base)
+	if len(q.conds) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(q.conds, " AND "))
+	}
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	fmt.Fprintf(&b, " ORDER BY %s %s, id %s", col, dir, dir)
+	args := q.args
+	if limit > 0 {
+		b.WriteString(" LIMIT ?")
+		args = append(args, limit)
+	}
+	return b.String(), args
+}
+
+// prefixPattern returns a LIKE pattern matching the values that start with p
+func prefixPattern(p string) string {
+	return _likeEscaper.Replace(p) + "%"
+}
@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+const scheduledTransferColumns = "id, account_origin_id, account_destination_id, amount, currency, conversion, execute_at, status, transfer_id, failure_reason, created_at, finished_at"
+
+type scheduledTransfer struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.ScheduledTransfer = (*scheduledTransfer)(nil)
+
+// NewScheduledTransfer creates a value that satisfies the repository.ScheduledTransfer interface
+func NewScheduledTransfer(txr *repository.Transactioner) repository.ScheduledTransfer {
+	return &scheduledTransfer{txr: txr}
+}
+
+func (r *scheduledTransfer) Create(ctx context.Context, e entity.ScheduledTransfer) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO scheduled_transfer(account_origin_id, account_destination_id, amount, currency, conversion, execute_at, status, created_at) VALUES (?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing scheduled transfer insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.Origin, e.Destination, e.Amount, e.Currency, e.Convert, e.ExecuteAt, e.Status, e.CreatedAt)
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec scheduled transfer insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted scheduled transfer id", err)
+	}
+	return insertedID, nil
+}
+
+func (r *scheduledTransfer) Get(ctx context.Context, id int64) (entity.ScheduledTransfer, error) {
+	q := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfer WHERE id=?"
+	e, err := scanScheduledTransfer((*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id))
+	if err == sql.ErrNoRows {
+		return e, types.NewErr(types.EmptyResultErr, "no result getting scheduled transfer by id", err)
+	}
+	if err != nil {
+		return e, types.NewErr(types.SelectStmtErr, "getting scheduled transfer by id", err)
+	}
+	return e, nil
+}
+
+func (r *scheduledTransfer) Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]entity.ScheduledTransfer, error) {
+	q := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfer WHERE account_origin_id=?"
+	args := []interface{}{origin}
+	if status != "" {
+		q += " AND status=?"
+		args = append(args, status)
+	}
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q+" ORDER BY execute_at, id", args...)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "querying the account scheduled transfers", err)
+	}
+	defer rows.Close()
+	transfers := make([]entity.ScheduledTransfer, 0)
+	for rows.Next() {
+		e, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning the scheduled transfer row", err)
+		}
+		transfers = append(transfers, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the scheduled transfer rows", err)
+	}
+	return transfers, nil
+}
+
+func (r *scheduledTransfer) ClaimDue(ctx context.Context, now time.Time) (entity.ScheduledTransfer, error) {
+	// Executors running elsewhere wait on the locked row and move on to the next one once it's no longer pending
+	q := "SELECT " + scheduledTransferColumns + " FROM scheduled_transfer WHERE status=? AND execute_at <= ? ORDER BY execute_at, id LIMIT 1 FOR UPDATE"
+	e, err := scanScheduledTransfer((*r.txr).GetConn(ctx).QueryRowContext(ctx, q, entity.SchedulePending, now))
+	if err == sql.ErrNoRows {
+		return e, types.NewErr(types.EmptyResultErr, "no scheduled transfer is due", err)
+	}
+	if err != nil {
+		return e, types.NewErr(types.SelectStmtErr, "claiming the due scheduled transfer", err)
+	}
+	return e, nil
+}
+
+func (r *scheduledTransfer) Finish(ctx context.Context, e entity.ScheduledTransfer) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE scheduled_transfer SET status=?, transfer_id=?, failure_reason=?, finished_at=? WHERE id=? AND status=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing the finish scheduled transfer stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.Status, e.TransferID, e.FailureReason, e.FinishedAt, e.ID, entity.SchedulePending)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the finish scheduled transfer stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the finish scheduled transfer stmt", nil)
+	}
+	return nil
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanScheduledTransfer(row scanner) (e entity.ScheduledTransfer, err error) {
+	err = row.Scan(&e.ID, &e.Origin, &e.Destination, &e.Amount, &e.Currency, &e.Convert, &e.ExecuteAt, &e.Status, &e.TransferID, &e.FailureReason, &e.CreatedAt, &e.FinishedAt)
+	return e, err
+}
@@ -0,0 +1,78 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestScheduledTransferRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewScheduledTransfer(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "A", "73000000001", "S731", 100),
+		testutil.NewEntityAccount(0, "B", "73000000002", "S732", 100),
+	})
+	ids := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	schedule := func(executeAt time.Time) int64 {
+		id, err := repo.Create(ctx, entity.ScheduledTransfer{
+			Origin:      ids[0],
+			Destination: ids[1],
+			Amount:      types.NewCurrency(10),
+			Currency:    types.BRL,
+			ExecuteAt:   executeAt,
+			Status:      entity.SchedulePending,
+			CreatedAt:   now,
+		})
+		testutil.AssertNoErr(t, err)
+		return id
+	}
+	due := schedule(now.Add(-time.Minute))
+	later := schedule(now.Add(time.Hour))
+
+	err := txr.WithTx(ctx, func(txCtx context.Context) error {
+		e, err := repo.ClaimDue(txCtx, now)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEq(t, "claimed id", due, e.ID)
+		e.Status = entity.ScheduleFailed
+		e.FailureReason = "the origin account is blocked"
+		e.FinishedAt = &now
+		return repo.Finish(txCtx, e)
+	})
+	testutil.AssertNoErr(t, err)
+
+	err = txr.WithTx(ctx, func(txCtx context.Context) error {
+		_, err := repo.ClaimDue(txCtx, now)
+		return err
+	})
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no scheduled transfer is due")
+
+	stored, err := repo.Get(ctx, due)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "status", entity.ScheduleFailed, stored.Status)
+	testutil.AssertEq(t, "failure reason", "the origin account is blocked", stored.FailureReason)
+
+	err = repo.Finish(ctx, stored)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the finish scheduled transfer stmt")
+
+	pending, err := repo.Fetch(ctx, ids[0], entity.SchedulePending)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "pending transfers", 1, len(pending))
+	testutil.AssertEq(t, "pending id", later, pending[0].ID)
+
+	all, err := repo.Fetch(ctx, ids[0], "")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "scheduled transfers", 2, len(all))
+
+	_, err = repo.Get(ctx, later+1)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting scheduled transfer by id")
+}
@@ -0,0 +1,180 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+const standingOrderColumns = "id, account_origin_id, account_destination_id, amount, currency, conversion, frequency, day_of_month, start_at, end_at, max_executions, executions, next_at, status, created_at, finished_at"
+
+type standingOrder struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.StandingOrder = (*standingOrder)(nil)
+
+// NewStandingOrder creates a value that satisfies the repository.StandingOrder interface
+func NewStandingOrder(txr *repository.Transactioner) repository.StandingOrder {
+	return &standingOrder{txr: txr}
+}
+
+func (r *standingOrder) Create(ctx context.Context, e entity.StandingOrder) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO standing_order(account_origin_id, account_destination_id, amount, currency, conversion, frequency, day_of_month, start_at, end_at, max_executions, executions, next_at, status, created_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing standing order insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.Origin, e.Destination, e.Amount, e.Currency, e.Convert, e.Frequency, e.Day, e.StartAt, e.EndAt, e.Count, e.Executions, e.NextAt, e.Status, e.CreatedAt)
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec standing order insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted standing order id", err)
+	}
+	return insertedID, nil
+}
+
+func (r *standingOrder) Get(ctx context.Context, id int64) (entity.StandingOrder, error) {
+	q := "SELECT " + standingOrderColumns + " FROM standing_order WHERE id=?"
+	e, err := scanStandingOrder((*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id))
+	if err == sql.ErrNoRows {
+		return e, types.NewErr(types.EmptyResultErr, "no result getting standing order by id", err)
+	}
+	if err != nil {
+		return e, types.NewErr(types.SelectStmtErr, "getting standing order by id", err)
+	}
+	return e, nil
+}
+
+func (r *standingOrder) Lock(ctx context.Context, id int64) (entity.StandingOrder, error) {
+	q := "SELECT " + standingOrderColumns + " FROM standing_order WHERE id=? FOR UPDATE"
+	e, err := scanStandingOrder((*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id))
+	if err == sql.ErrNoRows {
+		return e, types.NewErr(types.EmptyResultErr, "no result locking standing order by id", err)
+	}
+	if err != nil {
+		return e, types.NewErr(types.SelectStmtErr, "locking standing order by id", err)
+	}
+	return e, nil
+}
+
+func (r *standingOrder) Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]entity.StandingOrder, error) {
+	q := "SELECT " + standingOrderColumns + " FROM standing_order WHERE account_origin_id=?"
+	args := []interface{}{origin}
+	if status != "" {
+		q += " AND status=?"
+		args = append(args, status)
+	}
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q+" ORDER BY id", args...)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "querying the account standing orders", err)
+	}
+	defer rows.Close()
+	orders := make([]entity.StandingOrder, 0)
+	for rows.Next() {
+		e, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning the standing order row", err)
+		}
+		orders = append(orders, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the standing order rows", err)
+	}
+	return orders, nil
+}
+
+func (r *standingOrder) ClaimDue(ctx context.Context, now time.Time) (entity.StandingOrder, error) {
+	// Executors running elsewhere wait on the locked row and move on once its next execution is no longer due
+	q := "SELECT " + standingOrderColumns + " FROM standing_order WHERE status=? AND next_at <= ? ORDER BY next_at, id LIMIT 1 FOR UPDATE"
+	e, err := scanStandingOrder((*r.txr).GetConn(ctx).QueryRowContext(ctx, q, entity.StandingOrderActive, now))
+	if err == sql.ErrNoRows {
+		return e, types.NewErr(types.EmptyResultErr, "no standing order is due", err)
+	}
+	if err != nil {
+		return e, types.NewErr(types.SelectStmtErr, "claiming the due standing order", err)
+	}
+	return e, nil
+}
+
+func (r *standingOrder) Update(ctx context.Context, e entity.StandingOrder) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE standing_order SET amount=?, end_at=?, max_executions=?, executions=?, next_at=?, status=?, finished_at=? WHERE id=? AND status=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing the update standing order stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.Amount, e.EndAt, e.Count, e.Executions, e.NextAt, e.Status, e.FinishedAt, e.ID, entity.StandingOrderActive)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update standing order stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update standing order stmt", nil)
+	}
+	return nil
+}
+
+func scanStandingOrder(row scanner) (e entity.StandingOrder, err error) {
+	err = row.Scan(&e.ID, &e.Origin, &e.Destination, &e.Amount, &e.Currency, &e.Convert, &e.Frequency, &e.Day, &e.StartAt, &e.EndAt, &e.Count, &e.Executions, &e.NextAt, &e.Status, &e.CreatedAt, &e.FinishedAt)
+	return e, err
+}
+
+type standingOrderExecution struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.StandingOrderExecution = (*standingOrderExecution)(nil)
+
+// NewStandingOrderExecution creates a value that satisfies the repository.StandingOrderExecution interface
+func NewStandingOrderExecution(txr *repository.Transactioner) repository.StandingOrderExecution {
+	return &standingOrderExecution{txr: txr}
+}
+
+func (r *standingOrderExecution) Create(ctx context.Context, e entity.StandingOrderExecution) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO standing_order_execution(standing_order_id, scheduled_at, status, transfer_id, failure_reason, created_at) VALUES (?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing standing order execution insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.StandingOrder, e.ScheduledAt, e.Status, e.TransferID, e.FailureReason, e.CreatedAt)
+	if err != nil {
+		if mysqlErr, ok := err.(*driver.MySQLError); ok && mysqlErr.Number == erDupEntry {
+			return insertedID, types.NewErr(types.ConflictErr, "standing order occurrence already executed", err)
+		}
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec standing order execution insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted standing order execution id", err)
+	}
+	return insertedID, nil
+}
+
+func (r *standingOrderExecution) Fetch(ctx context.Context, standingOrder int64) ([]entity.StandingOrderExecution, error) {
+	q := "SELECT id, standing_order_id, scheduled_at, status, transfer_id, failure_reason, created_at FROM standing_order_execution WHERE standing_order_id=? ORDER BY scheduled_at, id"
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q, standingOrder)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "querying the standing order executions", err)
+	}
+	defer rows.Close()
+	executions := make([]entity.StandingOrderExecution, 0)
+	for rows.Next() {
+		var e entity.StandingOrderExecution
+		if err = rows.Scan(&e.ID, &e.StandingOrder, &e.ScheduledAt, &e.Status, &e.TransferID, &e.FailureReason, &e.CreatedAt); err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning the standing order execution row", err)
+		}
+		executions = append(executions, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the standing order execution rows", err)
+	}
+	return executions, nil
+}
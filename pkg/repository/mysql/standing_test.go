@@ -0,0 +1,106 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStandingOrderRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewStandingOrder(&txr)
+	executionRepo := mysql.NewStandingOrderExecution(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "A", "74000000001", "S741", 100),
+		testutil.NewEntityAccount(0, "B", "74000000002", "S742", 100),
+	})
+	ids := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	create := func(nextAt time.Time) int64 {
+		id, err := repo.Create(ctx, entity.StandingOrder{
+			Origin:      ids[0],
+			Destination: ids[1],
+			Amount:      types.NewCurrency(10),
+			Currency:    types.BRL,
+			Frequency:   entity.FrequencyWeekly,
+			StartAt:     nextAt,
+			Count:       2,
+			NextAt:      nextAt,
+			Status:      entity.StandingOrderActive,
+			CreatedAt:   now,
+		})
+		testutil.AssertNoErr(t, err)
+		return id
+	}
+	due := create(now.Add(-time.Minute))
+	later := create(now.Add(time.Hour))
+
+	err := txr.WithTx(ctx, func(txCtx context.Context) error {
+		e, err := repo.ClaimDue(txCtx, now)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEq(t, "claimed id", due, e.ID)
+		if _, err = executionRepo.Create(txCtx, entity.StandingOrderExecution{
+			StandingOrder: e.ID,
+			ScheduledAt:   e.NextAt,
+			Status:        entity.ScheduleFailed,
+			FailureReason: "the origin account is blocked",
+			CreatedAt:     now,
+		}); err != nil {
+			return err
+		}
+		e.Executions++
+		e.NextAt = e.Occurrence(e.Executions)
+		return repo.Update(txCtx, e)
+	})
+	testutil.AssertNoErr(t, err)
+
+	err = txr.WithTx(ctx, func(txCtx context.Context) error {
+		_, err := repo.ClaimDue(txCtx, now)
+		return err
+	})
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no standing order is due")
+
+	stored, err := repo.Get(ctx, due)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "executions", 1, stored.Executions)
+	testutil.AssertEq(t, "next at", now.Add(-time.Minute).AddDate(0, 0, 7).Unix(), stored.NextAt.Unix())
+
+	_, err = executionRepo.Create(ctx, entity.StandingOrderExecution{
+		StandingOrder: due,
+		ScheduledAt:   now.Add(-time.Minute),
+		Status:        entity.ScheduleFailed,
+		CreatedAt:     now,
+	})
+	testutil.AssertCustomErr(t, types.ConflictErr, err, "standing order occurrence already executed")
+
+	executions, err := executionRepo.Fetch(ctx, due)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "executions", 1, len(executions))
+	testutil.AssertEq(t, "failure reason", "the origin account is blocked", executions[0].FailureReason)
+
+	stored.Status = entity.StandingOrderCanceled
+	stored.FinishedAt = &now
+	testutil.AssertNoErr(t, repo.Update(ctx, stored))
+	err = repo.Update(ctx, stored)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update standing order stmt")
+
+	active, err := repo.Fetch(ctx, ids[0], entity.StandingOrderActive)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "active orders", 1, len(active))
+	testutil.AssertEq(t, "active id", later, active[0].ID)
+
+	all, err := repo.Fetch(ctx, ids[0], "")
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "standing orders", 2, len(all))
+
+	_, err = repo.Get(ctx, later+1)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting standing order by id")
+}
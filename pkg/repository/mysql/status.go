@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type statusChange struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.StatusChange = (*statusChange)(nil)
+
+// NewStatusChange creates a value that satisfies the repository.StatusChange interface
+func NewStatusChange(txr *repository.Transactioner) repository.StatusChange {
+	return &statusChange{txr: txr}
+}
+
+func (r *statusChange) Create(ctx context.Context, e entity.StatusChange) (insertedID int64, err error) {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO account_status_change(account_id, from_status, to_status, reason, changed_by, created_at) VALUES (?,?,?,?,?,?)")
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "preparing account status change insert stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, e.AccountID, e.From, e.To, e.Reason, e.ChangedBy, e.CreatedAt)
+	if err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "exec account status change insert stmt", err)
+	}
+	if insertedID, err = result.LastInsertId(); err != nil {
+		return insertedID, types.NewErr(types.InsertStmtErr, "getting the inserted account status change id", err)
+	}
+	return insertedID, nil
+}
+
+func (r *statusChange) Fetch(ctx context.Context, account int64) ([]entity.StatusChange, error) {
+	q := "SELECT id, account_id, from_status, to_status, reason, changed_by, created_at FROM account_status_change WHERE account_id=? ORDER BY id"
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, q, account)
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "fetching account status changes", err)
+	}
+	defer rows.Close()
+	changes := make([]entity.StatusChange, 0)
+	for rows.Next() {
+		var e entity.StatusChange
+		if err = rows.Scan(&e.ID, &e.AccountID, &e.From, &e.To, &e.Reason, &e.ChangedBy, &e.CreatedAt); err != nil {
+			return nil, types.NewErr(types.SelectStmtErr, "scanning account status change row", err)
+		}
+		changes = append(changes, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "iterating over the account status change rows", err)
+	}
+	return changes, nil
+}
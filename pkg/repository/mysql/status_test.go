@@ -0,0 +1,47 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStatusChangeRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewStatusChange(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Lois", "17171717171", "S170", 0),
+	}) {
+		account = id
+	}
+	changes := []entity.StatusChange{
+		{AccountID: account, From: entity.StatusActive, To: entity.StatusBlocked, Reason: "fraud investigation", ChangedBy: account, CreatedAt: now},
+		{AccountID: account, From: entity.StatusBlocked, To: entity.StatusActive, Reason: "cleared", ChangedBy: account, CreatedAt: now},
+	}
+	for _, e := range changes {
+		id, err := repo.Create(ctx, e)
+		testutil.AssertNoErr(t, err)
+		if id <= 0 {
+			t.Errorf("expected a positive status change id, got %d", id)
+		}
+	}
+
+	stored, err := repo.Fetch(ctx, account)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "status changes", len(changes), len(stored))
+	for i, e := range stored {
+		testutil.AssertEq(t, "from", changes[i].From, e.From)
+		testutil.AssertEq(t, "to", changes[i].To, e.To)
+		testutil.AssertEq(t, "reason", changes[i].Reason, e.Reason)
+	}
+
+	stored, err = repo.Fetch(ctx, account+1)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "status changes of another account", 0, len(stored))
+}
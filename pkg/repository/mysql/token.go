@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type refreshToken struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.RefreshToken = (*refreshToken)(nil)
+
+// NewRefreshToken creates a value that satisfies the repository.RefreshToken interface
+func NewRefreshToken(txr *repository.Transactioner) repository.RefreshToken {
+	return &refreshToken{txr: txr}
+}
+
+func (r *refreshToken) Create(ctx context.Context, e entity.RefreshToken) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO refresh_token(token_hash, family, account_id, created_at, expires_at) VALUES (?,?,?,?,?)")
+	if err != nil {
+		return types.NewErr(types.InsertStmtErr, "preparing refresh token insert stmt", err)
+	}
+	defer stmt.Close()
+	if _, err = stmt.ExecContext(ctx, e.Hash, e.Family, e.AccountID, e.CreatedAt, e.ExpiresAt); err != nil {
+		return types.NewErr(types.InsertStmtErr, "exec refresh token insert stmt", err)
+	}
+	return nil
+}
+
+func (r *refreshToken) Get(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	var e entity.RefreshToken
+	q := "SELECT token_hash, family, account_id, created_at, expires_at, used_at, revoked_at FROM refresh_token WHERE token_hash=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, hash).
+		Scan(&e.Hash, &e.Family, &e.AccountID, &e.CreatedAt, &e.ExpiresAt, &e.UsedAt, &e.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting refresh token", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting refresh token", err)
+	}
+	return e, nil
+}
+
+// MarkUsed flags the token as rotated. It yields a types.NoRowAffectedErr when the token was already used or revoked
+func (r *refreshToken) MarkUsed(ctx context.Context, hash string, at time.Time) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE refresh_token SET used_at=? WHERE token_hash=? AND used_at IS NULL AND revoked_at IS NULL", at, hash)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the mark refresh token as used stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the mark refresh token as used stmt", nil)
+	}
+	return nil
+}
+
+func (r *refreshToken) RevokeFamily(ctx context.Context, family string, at time.Time) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE refresh_token SET revoked_at=? WHERE family=? AND revoked_at IS NULL", at, family); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the revoke refresh token family stmt", err)
+	}
+	return nil
+}
+
+// RevokeAccount revokes every refresh token family of the account, ending all of its sessions
+func (r *refreshToken) RevokeAccount(ctx context.Context, account int64, at time.Time) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE refresh_token SET revoked_at=? WHERE account_id=? AND revoked_at IS NULL", at, account); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the revoke account refresh tokens stmt", err)
+	}
+	return nil
+}
+
+type revokedToken struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.RevokedToken = (*revokedToken)(nil)
+
+// NewRevokedToken creates a value that satisfies the repository.RevokedToken interface
+func NewRevokedToken(txr *repository.Transactioner) repository.RevokedToken {
+	return &revokedToken{txr: txr}
+}
+
+// Create blocklists the access token, blocklisting it again yields a types.ConflictErr
+func (r *revokedToken) Create(ctx context.Context, e entity.RevokedToken) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "INSERT INTO revoked_token(jti, expires_at) VALUES (?,?)", e.JTI, e.ExpiresAt); err != nil {
+		if mysqlErr, ok := err.(*driver.MySQLError); ok && mysqlErr.Number == erDupEntry {
+			return types.NewErr(types.ConflictErr, "access token already revoked", err)
+		}
+		return types.NewErr(types.InsertStmtErr, "exec revoked token insert stmt", err)
+	}
+	return nil
+}
+
+func (r *revokedToken) Exists(ctx context.Context, jti string) (bool, error) {
+	var exists bool
+	if err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM revoked_token WHERE jti=?)", jti).Scan(&exists); err != nil {
+		return false, types.NewErr(types.SelectStmtErr, "checking the revoked token", err)
+	}
+	return exists, nil
+}
+
+// DeleteExpired drops the entries of the access tokens expired by at, as they're rejected regardless of the blocklist
+func (r *revokedToken) DeleteExpired(ctx context.Context, at time.Time) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "DELETE FROM revoked_token WHERE expires_at < ?", at); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the delete expired revoked tokens stmt", err)
+	}
+	return nil
+}
+
+// RevokeAccount revokes the access tokens of the account issued up to at, keeping the latest time when revoked again
+func (r *revokedToken) RevokeAccount(ctx context.Context, account int64, at time.Time) error {
+	q := "INSERT INTO revoked_account(account_id, revoked_at) VALUES (?,?) ON DUPLICATE KEY UPDATE revoked_at=GREATEST(revoked_at, VALUES(revoked_at))"
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, q, account, at); err != nil {
+		return types.NewErr(types.InsertStmtErr, "exec revoked account upsert stmt", err)
+	}
+	return nil
+}
+
+// AccountRevokedAt returns the time up to which the access tokens of the account are revoked, which is nil when they never were
+func (r *revokedToken) AccountRevokedAt(ctx context.Context, account int64) (*time.Time, error) {
+	var revokedAt time.Time
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, "SELECT revoked_at FROM revoked_account WHERE account_id=?", account).Scan(&revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewErr(types.SelectStmtErr, "getting the revoked account", err)
+	}
+	return &revokedAt, nil
+}
+
+type secretResetToken struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.SecretResetToken = (*secretResetToken)(nil)
+
+// NewSecretResetToken creates a value that satisfies the repository.SecretResetToken interface
+func NewSecretResetToken(txr *repository.Transactioner) repository.SecretResetToken {
+	return &secretResetToken{txr: txr}
+}
+
+func (r *secretResetToken) Create(ctx context.Context, e entity.SecretResetToken) error {
+	q := "INSERT INTO secret_reset_token(token_hash, account_id, created_at, expires_at) VALUES (?,?,?,?)"
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, q, e.Hash, e.AccountID, e.CreatedAt, e.ExpiresAt); err != nil {
+		return types.NewErr(types.InsertStmtErr, "exec secret reset token insert stmt", err)
+	}
+	return nil
+}
+
+func (r *secretResetToken) Get(ctx context.Context, hash string) (entity.SecretResetToken, error) {
+	var e entity.SecretResetToken
+	q := "SELECT token_hash, account_id, created_at, expires_at, used_at FROM secret_reset_token WHERE token_hash=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, hash).
+		Scan(&e.Hash, &e.AccountID, &e.CreatedAt, &e.ExpiresAt, &e.UsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting secret reset token", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting secret reset token", err)
+	}
+	return e, nil
+}
+
+// MarkUsed flags the token as used. It yields a types.NoRowAffectedErr when the token was already used
+func (r *secretResetToken) MarkUsed(ctx context.Context, hash string, at time.Time) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE secret_reset_token SET used_at=? WHERE token_hash=? AND used_at IS NULL", at, hash)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the mark secret reset token as used stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the mark secret reset token as used stmt", nil)
+	}
+	return nil
+}
+
+// Invalidate flags every unused token of the account as used, so that only the latest one issued remains valid
+func (r *secretResetToken) Invalidate(ctx context.Context, account int64, at time.Time) error {
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE secret_reset_token SET used_at=? WHERE account_id=? AND used_at IS NULL", at, account); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the invalidate secret reset tokens stmt", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestRefreshTokenRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewRefreshToken(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Ivo", "16161616161", "S160", 0),
+	}) {
+		account = id
+	}
+	first := entity.RefreshToken{Hash: "a1", Family: "f1", AccountID: account, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	second := entity.RefreshToken{Hash: "a2", Family: "f1", AccountID: account, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	testutil.AssertNoErr(t, repo.Create(ctx, first))
+	testutil.AssertNoErr(t, repo.Create(ctx, second))
+
+	testutil.AssertNoErr(t, repo.MarkUsed(ctx, first.Hash, now))
+	err := repo.MarkUsed(ctx, first.Hash, now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the mark refresh token as used stmt")
+
+	used, err := repo.Get(ctx, first.Hash)
+	testutil.AssertNoErr(t, err)
+	if used.UsedAt == nil {
+		t.Errorf("expected the refresh token to be used")
+	}
+
+	testutil.AssertNoErr(t, repo.RevokeFamily(ctx, "f1", now))
+	revoked, err := repo.Get(ctx, second.Hash)
+	testutil.AssertNoErr(t, err)
+	if revoked.RevokedAt == nil {
+		t.Errorf("expected the refresh token family to be revoked")
+	}
+
+	third := entity.RefreshToken{Hash: "a3", Family: "f2", AccountID: account, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	testutil.AssertNoErr(t, repo.Create(ctx, third))
+	testutil.AssertNoErr(t, repo.RevokeAccount(ctx, account, now))
+	revoked, err = repo.Get(ctx, third.Hash)
+	testutil.AssertNoErr(t, err)
+	if revoked.RevokedAt == nil {
+		t.Errorf("expected every refresh token of the account to be revoked")
+	}
+
+	_, err = repo.Get(ctx, "unknown")
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting refresh token")
+}
+
+func TestRevokedTokenRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewRevokedToken(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testutil.AssertNoErr(t, repo.Create(ctx, entity.RevokedToken{JTI: "expired", ExpiresAt: now.Add(-time.Minute)}))
+	testutil.AssertNoErr(t, repo.Create(ctx, entity.RevokedToken{JTI: "active", ExpiresAt: now.Add(time.Minute)}))
+	err := repo.Create(ctx, entity.RevokedToken{JTI: "active", ExpiresAt: now.Add(time.Minute)})
+	testutil.AssertCustomErr(t, types.ConflictErr, err, "access token already revoked")
+
+	testutil.AssertNoErr(t, repo.DeleteExpired(ctx, now))
+	for jti, expected := range map[string]bool{"expired": false, "active": true, "unknown": false} {
+		exists, err := repo.Exists(ctx, jti)
+		testutil.AssertNoErr(t, err)
+		testutil.AssertEq(t, "exists "+jti, expected, exists)
+	}
+
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Ana", "18181818181", "S180", 0),
+	}) {
+		account = id
+	}
+	revokedAt, err := repo.AccountRevokedAt(ctx, account)
+	testutil.AssertNoErr(t, err)
+	if revokedAt != nil {
+		t.Errorf("expected the account to have no revoked access tokens")
+	}
+	testutil.AssertNoErr(t, repo.RevokeAccount(ctx, account, now))
+	testutil.AssertNoErr(t, repo.RevokeAccount(ctx, account, now.Add(-time.Hour)))
+	revokedAt, err = repo.AccountRevokedAt(ctx, account)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "revoked at", now, revokedAt.UTC())
+}
+
+func TestSecretResetTokenRepositoryLifecycle(t *testing.T) {
+	repo := mysql.NewSecretResetToken(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Ana", "17171717171", "S170", 0),
+	}) {
+		account = id
+	}
+	first := entity.SecretResetToken{Hash: "r1", AccountID: account, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	second := entity.SecretResetToken{Hash: "r2", AccountID: account, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	testutil.AssertNoErr(t, repo.Create(ctx, first))
+	testutil.AssertNoErr(t, repo.Create(ctx, second))
+
+	testutil.AssertNoErr(t, repo.MarkUsed(ctx, first.Hash, now))
+	err := repo.MarkUsed(ctx, first.Hash, now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the mark secret reset token as used stmt")
+
+	testutil.AssertNoErr(t, repo.Invalidate(ctx, account, now))
+	invalidated, err := repo.Get(ctx, second.Hash)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "account id", account, invalidated.AccountID)
+	if invalidated.UsedAt == nil {
+		t.Errorf("expected the secret reset token to be invalidated")
+	}
+
+	_, err = repo.Get(ctx, "unknown")
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting secret reset token")
+}
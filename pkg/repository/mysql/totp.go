@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+type totp struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.TOTP = (*totp)(nil)
+
+// NewTOTP creates a value that satisfies the repository.TOTP interface
+func NewTOTP(txr *repository.Transactioner) repository.TOTP {
+	return &totp{txr: txr}
+}
+
+func (r *totp) Get(ctx context.Context, account int64) (entity.TOTP, error) {
+	var e entity.TOTP
+	q := "SELECT account_id, secret, created_at, enabled_at, last_step FROM account_totp WHERE account_id=?"
+	err := (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, account).Scan(&e.AccountID, &e.Secret, &e.CreatedAt, &e.EnabledAt, &e.LastStep)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return e, types.NewErr(types.EmptyResultErr, "no result getting totp", err)
+		}
+		return e, types.NewErr(types.SelectStmtErr, "getting totp", err)
+	}
+	return e, nil
+}
+
+// Save upserts the enrollment, leaving it disabled until a code is verified
+func (r *totp) Save(ctx context.Context, e entity.TOTP) error {
+	q := `INSERT INTO account_totp(account_id, secret, created_at) VALUES (?,?,?)
+		ON DUPLICATE KEY UPDATE secret=VALUES(secret), created_at=VALUES(created_at), enabled_at=NULL, last_step=0`
+	if _, err := (*r.txr).GetConn(ctx).ExecContext(ctx, q, e.AccountID, e.Secret, e.CreatedAt); err != nil {
+		return types.NewErr(types.InsertStmtErr, "exec totp upsert stmt", err)
+	}
+	return nil
+}
+
+// Enable turns the second factor on, accepting the code of step. It yields a types.NoRowAffectedErr when it's already enabled
+func (r *totp) Enable(ctx context.Context, account int64, step int64, at time.Time) error {
+	return r.update(ctx, "UPDATE account_totp SET enabled_at=?, last_step=? WHERE account_id=? AND enabled_at IS NULL", "enable totp", at, step, account)
+}
+
+func (r *totp) Accept(ctx context.Context, account int64, step int64) error {
+	return r.update(ctx, "UPDATE account_totp SET last_step=? WHERE account_id=? AND last_step < ?", "accept totp", step, account, step)
+}
+
+// update execs the stmt, yielding a types.NoRowAffectedErr when no row is affected
+func (r *totp) update(ctx context.Context, q string, name string, args ...interface{}) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, q, args...)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the "+name+" stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the "+name+" stmt", nil)
+	}
+	return nil
+}
+
+type recoveryCode struct {
+	txr *repository.Transactioner
+}
+
+var _ repository.RecoveryCode = (*recoveryCode)(nil)
+
+// NewRecoveryCode creates a value that satisfies the repository.RecoveryCode interface
+func NewRecoveryCode(txr *repository.Transactioner) repository.RecoveryCode {
+	return &recoveryCode{txr: txr}
+}
+
+// Replace discards the previous codes of the account, it's expected to run within a transaction
+func (r *recoveryCode) Replace(ctx context.Context, account int64, hashes []string) error {
+	conn := (*r.txr).GetConn(ctx)
+	if _, err := conn.ExecContext(ctx, "DELETE FROM recovery_code WHERE account_id=?", account); err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the delete recovery codes stmt", err)
+	}
+	stmt, err := conn.PrepareContext(ctx, "INSERT INTO recovery_code(account_id, code_hash) VALUES (?,?)")
+	if err != nil {
+		return types.NewErr(types.InsertStmtErr, "preparing recovery code insert stmt", err)
+	}
+	defer stmt.Close()
+	for _, hash := range hashes {
+		if _, err = stmt.ExecContext(ctx, account, hash); err != nil {
+			return types.NewErr(types.InsertStmtErr, "exec recovery code insert stmt", err)
+		}
+	}
+	return nil
+}
+
+func (r *recoveryCode) Use(ctx context.Context, account int64, hash string, at time.Time) error {
+	result, err := (*r.txr).GetConn(ctx).ExecContext(ctx, "UPDATE recovery_code SET used_at=? WHERE account_id=? AND code_hash=? AND used_at IS NULL", at, account, hash)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the use recovery code stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the use recovery code stmt", nil)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestTOTPRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewTOTP(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Ari", "18181818181", "S180", 0),
+	}) {
+		account = id
+	}
+
+	_, err := repo.Get(ctx, account)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting totp")
+
+	testutil.AssertNoErr(t, repo.Save(ctx, entity.TOTP{AccountID: account, Secret: "first", CreatedAt: now}))
+	testutil.AssertNoErr(t, repo.Save(ctx, entity.TOTP{AccountID: account, Secret: "second", CreatedAt: now}))
+	e, err := repo.Get(ctx, account)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "secret", "second", e.Secret)
+	if e.EnabledAt != nil {
+		t.Errorf("expected the totp to be disabled")
+	}
+
+	testutil.AssertNoErr(t, repo.Enable(ctx, account, 10, now))
+	err = repo.Enable(ctx, account, 11, now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the enable totp stmt")
+
+	err = repo.Accept(ctx, account, 10)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the accept totp stmt")
+	testutil.AssertNoErr(t, repo.Accept(ctx, account, 11))
+	e, err = repo.Get(ctx, account)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "last step", int64(11), e.LastStep)
+}
+
+func TestRecoveryCodeRepositoryLifecycle(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewRecoveryCode(&txr)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	var account int64
+	for id := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Ana", "19191919191", "S190", 0),
+	}) {
+		account = id
+	}
+
+	testutil.AssertNoErr(t, repo.Replace(ctx, account, []string{"h1", "h2"}))
+	testutil.AssertNoErr(t, repo.Use(ctx, account, "h1", now))
+	err := repo.Use(ctx, account, "h1", now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the use recovery code stmt")
+
+	testutil.AssertNoErr(t, repo.Replace(ctx, account, []string{"h3"}))
+	err = repo.Use(ctx, account, "h2", now)
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the use recovery code stmt")
+	testutil.AssertNoErr(t, repo.Use(ctx, account, "h3", now))
+}
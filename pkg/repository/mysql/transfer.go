@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
@@ -19,8 +20,36 @@ func NewTransfer(txr *repository.Transactioner) repository.Transfer {
 	return &transfer{txr: txr}
 }
 
-func (r *transfer) Fetch(ctx context.Context, origin int64) ([]entity.Transfer, error) {
-	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, "SELECT id, account_origin_id, account_destination_id, amount, created_at FROM transfer WHERE account_origin_id=?", origin)
+func (r *transfer) Fetch(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+	var sel query
+	switch q.Direction {
+	case repository.TransferOut:
+		sel.where("account_origin_id = ?", q.Account)
+	case repository.TransferIn:
+		sel.where("account_destination_id = ?", q.Account)
+	default:
+		sel.where("(account_origin_id = ? OR account_destination_id = ?)", q.Account, q.Account)
+	}
+	if q.Counterparty != 0 {
+		sel.where("IF(account_origin_id = ?, account_destination_id, account_origin_id) = ?", q.Account, q.Counterparty)
+	}
+	if !q.CreatedFrom.IsZero() {
+		sel.where("created_at >= ?", q.CreatedFrom)
+	}
+	if !q.CreatedTo.IsZero() {
+		sel.where("created_at < ?", q.CreatedTo)
+	}
+	if q.MinAmount != nil {
+		sel.where("IF(account_origin_id = ?, amount, destination_amount) >= ?", q.Account, *q.MinAmount)
+	}
+	if q.MaxAmount != nil {
+		sel.where("IF(account_origin_id = ?, amount, destination_amount) <= ?", q.Account, *q.MaxAmount)
+	}
+	if q.After != nil {
+		sel.after("created_at", q.Desc, q.After.CreatedAt, q.After.ID)
+	}
+	stmt, args := sel.build("SELECT id, account_origin_id, account_destination_id, amount, currency, destination_amount, destination_currency, rate, reversal_of, reversed_amount, created_at FROM transfer", "created_at", q.Desc, q.Limit)
+	rows, err := (*r.txr).GetConn(ctx).QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, types.NewErr(types.SelectStmtErr, "querying transfers by id", err)
 	}
@@ -28,10 +57,11 @@ func (r *transfer) Fetch(ctx context.Context, origin int64) ([]entity.Transfer,
 	transfers := make([]entity.Transfer, 0)
 	for rows.Next() {
 		transfer := entity.Transfer{}
-		err = rows.Scan(&transfer.ID, &transfer.Origin, &transfer.Destination, &transfer.Amount, &transfer.CreatedAt)
+		err = rows.Scan(&transfer.ID, &transfer.Origin, &transfer.Destination, &transfer.Amount, &transfer.Currency, &transfer.DestinationAmount, &transfer.DestinationCurrency, &transfer.Rate, &transfer.ReversalOf, &transfer.ReversedAmount, &transfer.CreatedAt)
 		if err != nil {
 			return nil, types.NewErr(types.SelectStmtErr, "scanning the transfer row", err)
 		}
+		transfer.Rate = transfer.Rate.Trim()
 		transfers = append(transfers, transfer)
 	}
 	if err = rows.Err(); err != nil {
@@ -40,13 +70,27 @@ func (r *transfer) Fetch(ctx context.Context, origin int64) ([]entity.Transfer,
 	return transfers, nil
 
 }
+
+func (r *transfer) Get(ctx context.Context, id int64) (transfer entity.Transfer, err error) {
+	q := "SELECT id, account_origin_id, account_destination_id, amount, currency, destination_amount, destination_currency, rate, reversal_of, reversed_amount, created_at FROM transfer WHERE id=?"
+	err = (*r.txr).GetConn(ctx).QueryRowContext(ctx, q, id).Scan(&transfer.ID, &transfer.Origin, &transfer.Destination, &transfer.Amount, &transfer.Currency, &transfer.DestinationAmount, &transfer.DestinationCurrency, &transfer.Rate, &transfer.ReversalOf, &transfer.ReversedAmount, &transfer.CreatedAt)
+	if err == sql.ErrNoRows {
+		return transfer, types.NewErr(types.EmptyResultErr, "no result getting transfer by id", err)
+	}
+	if err != nil {
+		return transfer, types.NewErr(types.SelectStmtErr, "getting transfer by id", err)
+	}
+	transfer.Rate = transfer.Rate.Trim()
+	return transfer, nil
+}
+
 func (r *transfer) Create(ctx context.Context, transfer entity.Transfer) (insertedID int64, err error) {
-	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO transfer(account_origin_id, account_destination_id, amount, created_at) VALUES (?,?,?,?)")
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "INSERT INTO transfer(account_origin_id, account_destination_id, amount, currency, destination_amount, destination_currency, rate, reversal_of, created_at) VALUES (?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		return insertedID, types.NewErr(types.SelectStmtErr, "preparing transfer insert stmt", err)
 	}
 	defer stmt.Close()
-	result, err := stmt.ExecContext(ctx, transfer.Origin, transfer.Destination, transfer.Amount, transfer.CreatedAt)
+	result, err := stmt.ExecContext(ctx, transfer.Origin, transfer.Destination, transfer.Amount, transfer.Currency, transfer.DestinationAmount, transfer.DestinationCurrency, string(transfer.Rate), transfer.ReversalOf, transfer.CreatedAt)
 	if err != nil {
 		return insertedID, types.NewErr(types.SelectStmtErr, "exec transfer insert stmt", err)
 	}
@@ -55,3 +99,23 @@ func (r *transfer) Create(ctx context.Context, transfer entity.Transfer) (insert
 	}
 	return insertedID, nil
 }
+
+func (r *transfer) UpdateReversedAmount(ctx context.Context, id int64, reversed types.Currency) error {
+	stmt, err := (*r.txr).GetConn(ctx).PrepareContext(ctx, "UPDATE transfer SET reversed_amount=? WHERE id=?")
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "preparing the update transfer reversed amount stmt", err)
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, reversed, id)
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "exec the update transfer reversed amount stmt", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return types.NewErr(types.UpdateStmtErr, "getting the affected rows number", err)
+	}
+	if rowsAffected == 0 {
+		return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update transfer reversed amount stmt", nil)
+	}
+	return nil
+}
@@ -2,67 +2,141 @@ package mysql_test
 
 import (
 	"context"
+	"math"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository/mysql"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
 
 func TestTransferRepositoryFetch(t *testing.T) {
+	t.Cleanup(dbWipe)
 	repo := mysql.NewTransfer(&txr)
+	ids := make(map[string]int64)
+	for id, acc := range persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "A", "70000000001", "S701", 100),
+		testutil.NewEntityAccount(0, "B", "70000000002", "S702", 100),
+		testutil.NewEntityAccount(0, "C", "70000000003", "S703", 100),
+	}) {
+		ids[acc.Name] = id
+	}
+	base := time.Now().UTC().Truncate(time.Second)
+	stmt, err := db.Prepare("INSERT INTO transfer(account_origin_id, account_destination_id, amount, destination_amount, created_at) VALUES (?,?,?,?,?)")
+	logFatal(err, "unable to prepare insert stmt")
+	defer stmt.Close()
+	for i, tr := range []struct {
+		origin, destination string
+		amount              float64
+	}{
+		{"A", "B", 5},
+		{"B", "A", 10},
+		{"C", "A", 20},
+		{"A", "C", 1},
+		{"B", "C", 7},
+	} {
+		_, err = stmt.Exec(ids[tr.origin], ids[tr.destination], types.NewCurrency(tr.amount), types.NewCurrency(tr.amount), base.Add(time.Duration(i)*time.Hour))
+		logFatal(err, "unable to exec insert stmt")
+	}
+	amount := func(v float64) *types.Currency {
+		c := types.NewCurrency(v)
+		return &c
+	}
+	amounts := func(transfers []entity.Transfer) string {
+		result := make([]string, 0, len(transfers))
+		for _, tr := range transfers {
+			result = append(result, string(types.BRL.Decimal(tr.Amount)))
+		}
+		return strings.Join(result, ",")
+	}
+
 	tt := []struct {
-		name         string
-		expectedSize int
-		prepare      func(*testing.T) (int64, int64, int64)
+		name     string
+		query    repository.TransferQuery
+		expected string
 	}{
 		{
-			name:         "fetch transfers with result successfully",
-			expectedSize: 1,
-			prepare: func(t *testing.T) (int64, int64, int64) {
-				t.Cleanup(dbWipe)
-
-				result, err := db.Exec("INSERT INTO account(name,cpf,secret,balance,created_at) VALUES ('John','99999999999','pw',100,'2100-12-31')")
-				logFatal(err, "unable to prepare insert stmt")
-
-				origin, err := result.LastInsertId()
-				logFatal(err, "unable to retrieve inserted id")
-
-				result, err = db.Exec("INSERT INTO account(name,cpf,secret,balance,created_at) VALUES ('Doe','88888888888','pw',500,'2100-12-31')")
-				logFatal(err, "unable to exec insert stmt")
-
-				destination, err := result.LastInsertId()
-				logFatal(err, "unable to retrieve inserted id")
-
-				stmt, err := db.Prepare("INSERT INTO transfer(account_origin_id, account_destination_id, amount, created_at) VALUES (?,?,?,?)")
-				logFatal(err, "unable to prepare insert stmt")
-
-				result, err = stmt.Exec(origin, destination, 500, time.Now())
-				logFatal(err, "unable to exec insert stmt")
-
-				id, err := result.LastInsertId()
-				logFatal(err, "unable to retrieve inserted id")
-				return origin, destination, id
-			},
+			name:     "fetch all transfers of account",
+			query:    repository.TransferQuery{Account: ids["A"]},
+			expected: "5.00,10.00,20.00,1.00",
+		},
+		{
+			name:     "fetch outgoing transfers of account newest first",
+			query:    repository.TransferQuery{Account: ids["A"], Direction: repository.TransferOut, Desc: true},
+			expected: "1.00,5.00",
+		},
+		{
+			name:     "fetch incoming transfers of account",
+			query:    repository.TransferQuery{Account: ids["A"], Direction: repository.TransferIn},
+			expected: "10.00,20.00",
+		},
+		{
+			name:     "fetch transfers of account by counterparty",
+			query:    repository.TransferQuery{Account: ids["A"], Counterparty: ids["C"]},
+			expected: "20.00,1.00",
+		},
+		{
+			name:     "fetch transfers of account by amount range",
+			query:    repository.TransferQuery{Account: ids["A"], MinAmount: amount(5), MaxAmount: amount(10)},
+			expected: "5.00,10.00",
+		},
+		{
+			name:     "fetch transfers of account by creation date range",
+			query:    repository.TransferQuery{Account: ids["A"], CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(3 * time.Hour)},
+			expected: "10.00,20.00",
+		},
+		{
+			name:     "fetch transfers of account after key",
+			query:    repository.TransferQuery{Account: ids["A"], Limit: 2, After: &repository.TransferKey{ID: math.MaxInt32, CreatedAt: base}},
+			expected: "10.00,20.00",
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			origin, _, _ := tc.prepare(t)
-			if transfers, err := repo.Fetch(context.Background(), origin); err == nil {
-				testutil.AssertEq(t, "return size", tc.expectedSize, len(transfers))
-			} else {
-				t.Error(err)
-			}
-
+			transfers, err := repo.Fetch(context.Background(), tc.query)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "amounts", tc.expected, amounts(transfers))
 		})
 	}
 }
 
+func TestTransferRepositoryGet(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewTransfer(&txr)
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "A", "71000000001", "S711", 100),
+		testutil.NewEntityAccount(0, "B", "71000000002", "S712", 100),
+	})
+	ids := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	result, err := db.Exec("INSERT INTO transfer(account_origin_id, account_destination_id, amount, destination_amount, created_at) VALUES (?,?,?,?,?)", ids[0], ids[1], types.NewCurrency(3), types.NewCurrency(3), time.Now())
+	logFatal(err, "unable to prepare testcase")
+	id, err := result.LastInsertId()
+	logFatal(err, "unable to retrieve inserted id")
+
+	transfer, err := repo.Get(context.Background(), id)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "origin", ids[0], transfer.Origin)
+	testutil.AssertEq(t, "destination", ids[1], transfer.Destination)
+	testutil.AssertEq(t, "amount", types.NewCurrency(3), transfer.Amount)
+	testutil.AssertEq(t, "rate", types.Decimal("1"), transfer.Rate)
+
+	_, err = repo.Get(context.Background(), id+1)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting transfer by id")
+}
+
 func TestTransferRepositoryCreate(t *testing.T) {
 	repo := mysql.NewTransfer(&txr)
 	tt := []struct {
@@ -115,3 +189,117 @@ func TestTransferRepositoryCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestTransferRepositoryReversal(t *testing.T) {
+	t.Cleanup(dbWipe)
+	repo := mysql.NewTransfer(&txr)
+	ctx := context.Background()
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "A", "72000000001", "S721", 100),
+		testutil.NewEntityAccount(0, "B", "72000000002", "S722", 100),
+	})
+	ids := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	originalID, err := repo.Create(ctx, testutil.NewEntityTransfer(0, ids[0], ids[1], 10))
+	testutil.AssertNoErr(t, err)
+	reversal := testutil.NewEntityTransfer(0, ids[1], ids[0], 4)
+	reversal.ReversalOf = &originalID
+	reversalID, err := repo.Create(ctx, reversal)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertNoErr(t, repo.UpdateReversedAmount(ctx, originalID, types.NewCurrency(4)))
+
+	original, err := repo.Get(ctx, originalID)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "reversed amount", types.NewCurrency(4), original.ReversedAmount)
+	testutil.AssertEq(t, "reversal status", entity.ReversalPartial, original.ReversalStatus())
+	if original.ReversalOf != nil {
+		t.Errorf("expected the original transfer not to be a reversal, got '%d'", *original.ReversalOf)
+	}
+
+	stored, err := repo.Get(ctx, reversalID)
+	testutil.AssertNoErr(t, err)
+	if stored.ReversalOf == nil || *stored.ReversalOf != originalID {
+		t.Errorf("expected the reversal of transfer '%d', got '%v'", originalID, stored.ReversalOf)
+	}
+
+	err = repo.UpdateReversedAmount(ctx, reversalID+1, types.NewCurrency(4))
+	testutil.AssertCustomErr(t, types.NoRowAffectedErr, err, "no rows affected by the update transfer reversed amount stmt")
+}
+
+func TestTransferConcurrency(t *testing.T) {
+	t.Cleanup(dbWipe)
+	accountRepo := mysql.NewAccount(&txr)
+	transferRepo := mysql.NewTransfer(&txr)
+	ledgerRepo := mysql.NewLedger(&txr)
+	rateProvider, err := fx.NewStatic(map[string]types.Decimal{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := service.NewTransfer(&txr, &transferRepo, &accountRepo, &ledgerRepo, &rateProvider)
+
+	accounts := persistTestAccountEntity(t, []entity.Account{
+		testutil.NewEntityAccount(0, "Hub", "12121212121", "S900", 100),
+		testutil.NewEntityAccount(0, "Spoke A", "12121212122", "S901", 0),
+		testutil.NewEntityAccount(0, "Spoke B", "12121212123", "S902", 0),
+	})
+	var hub int64
+	var spokes []int64
+	var total types.Currency
+	for id, acc := range accounts {
+		total += acc.Balance
+		if acc.Name == "Hub" {
+			hub = id
+		} else {
+			spokes = append(spokes, id)
+		}
+	}
+
+	// The hub can't afford every outgoing transfer while the other goroutines move money back into it
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(2)
+		go func(dest int64) {
+			defer wg.Done()
+			srv.Create(context.Background(), hub, dto.TransferCreation{Destination: dest, Amount: "5"})
+		}(spokes[i%len(spokes)])
+		go func(origin int64) {
+			defer wg.Done()
+			srv.Create(context.Background(), origin, dto.TransferCreation{Destination: hub, Amount: "1"})
+		}(spokes[i%len(spokes)])
+	}
+	wg.Wait()
+
+	var current types.Currency
+	rows, err := db.Query("SELECT id, balance FROM account")
+	logFatal(err, "unable to query account balances")
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var b types.Currency
+		logFatal(rows.Scan(&id, &b), "unable to scan account balance")
+		if b < 0 {
+			t.Errorf("expected non negative balance but got '%d'", b)
+		}
+		current += b
+		if posted, err := ledgerRepo.GetBalance(context.Background(), id); err == nil {
+			// The opening balances weren't posted by persistTestAccountEntity, only the transfers
+			testutil.AssertEq(t, "ledger balance", b-accounts[id].Balance, posted)
+		} else {
+			t.Error(err)
+		}
+	}
+	testutil.AssertEq(t, "total balance", total, current)
+
+	var transferred types.Currency
+	err = db.QueryRow("SELECT COALESCE(SUM(amount), 0) FROM transfer WHERE account_origin_id=?", hub).Scan(&transferred)
+	logFatal(err, "unable to sum the hub transfers")
+	var received types.Currency
+	err = db.QueryRow("SELECT COALESCE(SUM(amount), 0) FROM transfer WHERE account_destination_id=?", hub).Scan(&received)
+	logFatal(err, "unable to sum the hub transfers")
+	var hubBalance types.Currency
+	err = db.QueryRow("SELECT balance FROM account WHERE id=?", hub).Scan(&hubBalance)
+	logFatal(err, "unable to get the hub balance")
+	testutil.AssertEq(t, "hub balance", accounts[hub].Balance-transferred+received, hubBalance)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// ScheduledTransfer exposes database operations related to the transfers scheduled for a future date.
+// Fetch lists the transfers scheduled by an origin account, narrowed by status unless it's empty.
+// ClaimDue returns the earliest pending transfer due at now, failing with a types.EmptyResultErr when there's none.
+// It must run within a tx, as the row stays locked until the tx ends so that a single executor claims it.
+// Finish stores the outcome of a pending transfer, failing with a types.NoRowAffectedErr when it's no longer pending
+type ScheduledTransfer interface {
+	Create(ctx context.Context, e entity.ScheduledTransfer) (int64, error)
+	Get(ctx context.Context, id int64) (entity.ScheduledTransfer, error)
+	Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]entity.ScheduledTransfer, error)
+	ClaimDue(ctx context.Context, now time.Time) (entity.ScheduledTransfer, error)
+	Finish(ctx context.Context, e entity.ScheduledTransfer) error
+}
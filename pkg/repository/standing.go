@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// StandingOrder exposes database operations related to the transfers repeated on a schedule.
+// Fetch lists the orders of an origin account, narrowed by status unless it's empty.
+// ClaimDue returns the active order whose next execution is the earliest due at now, failing with a types.EmptyResultErr when there's none.
+// It must run within a tx, as the row stays locked until the tx ends so that a single executor claims it.
+// Lock returns the order stored at id like Get, holding its row lock until the tx ends.
+// Update stores the mutable values of an active order, failing with a types.NoRowAffectedErr when it's no longer active
+type StandingOrder interface {
+	Create(ctx context.Context, e entity.StandingOrder) (int64, error)
+	Get(ctx context.Context, id int64) (entity.StandingOrder, error)
+	Lock(ctx context.Context, id int64) (entity.StandingOrder, error)
+	Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]entity.StandingOrder, error)
+	ClaimDue(ctx context.Context, now time.Time) (entity.StandingOrder, error)
+	Update(ctx context.Context, e entity.StandingOrder) error
+}
+
+// StandingOrderExecution exposes database operations related to the history of the standing order executions.
+// Create must fail with a types.ConflictErr when the occurrence was already recorded
+type StandingOrderExecution interface {
+	Create(ctx context.Context, e entity.StandingOrderExecution) (int64, error)
+	Fetch(ctx context.Context, standingOrder int64) ([]entity.StandingOrderExecution, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// RefreshToken exposes database operations related to the refresh token domain
+type RefreshToken interface {
+	Create(ctx context.Context, e entity.RefreshToken) error
+	Get(ctx context.Context, hash string) (entity.RefreshToken, error)
+	MarkUsed(ctx context.Context, hash string, at time.Time) error
+	RevokeFamily(ctx context.Context, family string, at time.Time) error
+	RevokeAccount(ctx context.Context, account int64, at time.Time) error
+}
+
+// RevokedToken exposes database operations related to the access token blocklist.
+// Besides single tokens, every access token of an account issued up to a given time can be revoked at once
+type RevokedToken interface {
+	Create(ctx context.Context, e entity.RevokedToken) error
+	Exists(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context, at time.Time) error
+	RevokeAccount(ctx context.Context, account int64, at time.Time) error
+	AccountRevokedAt(ctx context.Context, account int64) (*time.Time, error)
+}
+
+// SecretResetToken exposes database operations related to the secret reset domain
+type SecretResetToken interface {
+	Create(ctx context.Context, e entity.SecretResetToken) error
+	Get(ctx context.Context, hash string) (entity.SecretResetToken, error)
+	MarkUsed(ctx context.Context, hash string, at time.Time) error
+	Invalidate(ctx context.Context, account int64, at time.Time) error
+}
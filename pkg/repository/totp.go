@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// TOTP exposes database operations related to the second factor domain.
+// Save replaces the enrollment of the account, whereas Accept must fail with a types.NoRowAffectedErr
+// unless step comes after the last accepted one
+type TOTP interface {
+	Get(ctx context.Context, account int64) (entity.TOTP, error)
+	Save(ctx context.Context, e entity.TOTP) error
+	Enable(ctx context.Context, account int64, step int64, at time.Time) error
+	Accept(ctx context.Context, account int64, step int64) error
+}
+
+// RecoveryCode exposes database operations related to the second factor recovery codes.
+// Use must fail with a types.NoRowAffectedErr when the code is unknown or was already used
+type RecoveryCode interface {
+	Replace(ctx context.Context, account int64, hashes []string) error
+	Use(ctx context.Context, account int64, hash string, at time.Time) error
+}
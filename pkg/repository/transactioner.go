@@ -30,27 +30,22 @@ func NewTxr(db *sql.DB) Transactioner {
 
 // WithTx starts a db transaction and stores it on the specified context.
 // It runs the function stored at fn with the transactional context.
-// If f function yields no error, the transaction is committed otherwise is rolled back
+// If f function yields no error, the transaction is committed otherwise is rolled back.
+// A context already holding a tx joins it, leaving the commit or rollback to whoever started it
 func (txr *transactioner) WithTx(ctx context.Context, fn func(context.Context) error) (err error) {
-	ctxTx := ctx.Value(CtxTxKey)
-
-	if ctxTx == nil {
-		ctxTx, err = txr.db.BeginTx(ctx, nil)
-		if err != nil {
-			return types.NewErr(types.InternalErr, "unable to begin tx", err)
-		}
-		ctx = context.WithValue(ctx, CtxTxKey, ctxTx)
+	if ctx.Value(CtxTxKey) != nil {
+		return fn(ctx)
+	}
+	tx, err := txr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.NewErr(types.InternalErr, "unable to begin tx", err)
 	}
 
-	err = fn(ctx)
-
-	if tx, ok := ctxTx.(*sql.Tx); ok {
-		if err == nil {
-			return tx.Commit()
-		}
-		if e := tx.Rollback(); e != nil {
-			log.Error().Caller().Err(e).Msg("unable to rollback tx")
-		}
+	if err = fn(context.WithValue(ctx, CtxTxKey, tx)); err == nil {
+		return tx.Commit()
+	}
+	if e := tx.Rollback(); e != nil {
+		log.Error().Caller().Err(e).Msg("unable to rollback tx")
 	}
 	return err
 }
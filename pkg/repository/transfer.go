@@ -2,12 +2,50 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
 
-// Transfer exposes database operations related to transfer domain
+// Transfer exposes database operations related to transfer domain.
+// UpdateReversedAmount sets the total amount given back by the reversals of a transfer
 type Transfer interface {
-	Fetch(ctx context.Context, origin int64) ([]entity.Transfer, error)
+	Fetch(ctx context.Context, q TransferQuery) ([]entity.Transfer, error)
+	Get(ctx context.Context, id int64) (entity.Transfer, error)
 	Create(ctx context.Context, e entity.Transfer) (int64, error)
+	UpdateReversedAmount(ctx context.Context, id int64, reversed types.Currency) error
+}
+
+// TransferDirection tells whether the listed transfers were sent or received by the account
+type TransferDirection string
+
+// Available transfer directions
+const (
+	TransferIn  TransferDirection = "in"
+	TransferOut TransferDirection = "out"
+	TransferAll TransferDirection = "all"
+)
+
+// TransferQuery narrows and pages the transfers of an Account returned by Transfer#Fetch, sorted by creation date.
+// Amounts are compared from the account perspective, i.e. the debited amount of outgoing transfers and the credited amount of incoming ones.
+// Zero values and nil pointers disable the respective filter and a Limit of 0 returns every matching row
+type TransferQuery struct {
+	Account      int64
+	Direction    TransferDirection
+	Counterparty int64
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	MinAmount    *types.Currency
+	MaxAmount    *types.Currency
+	Limit        int
+	Desc         bool
+	After        *TransferKey
+}
+
+// TransferKey is the position of a transfer within a listing sorted by creation date, ties are broken by ID.
+// Listings resume right after the key
+type TransferKey struct {
+	ID        int64
+	CreatedAt time.Time
 }
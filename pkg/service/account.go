@@ -4,6 +4,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
@@ -17,79 +18,210 @@ import (
 
 // Account exposes the business operations available to entity.Account type
 type Account interface {
-	Fetch(ctx context.Context) ([]dto.AccountView, error)
-	GetBalance(ctx context.Context, id int64) (float64, error)
+	Fetch(ctx context.Context, f dto.AccountFilter) (dto.AccountPage, error)
+	Get(ctx context.Context, requester dto.Requester, id int64) (dto.AccountView, error)
+	GetBalance(ctx context.Context, requester dto.Requester, id int64) (dto.BalanceView, error)
+	Reconcile(ctx context.Context, id int64) (dto.ReconciliationView, error)
 	Create(ctx context.Context, d dto.AccountCreation) (dto.AccountView, error)
 	Login(ctx context.Context, cpf string, secret string) (dto.AccountView, error)
+	UpdateRole(ctx context.Context, id int64, role entity.Role) (dto.AccountView, error)
+	UpdateStatus(ctx context.Context, requester dto.Requester, id int64, status entity.Status, reason string) (dto.AccountView, error)
+	FetchStatusChanges(ctx context.Context, id int64) ([]dto.StatusChangeView, error)
+	Patch(ctx context.Context, requester dto.Requester, id int64, version int64, patch dto.AccountPatch) (dto.AccountView, error)
 }
 
 type account struct {
-	accountRepository *repository.Account
-	accountValidator  *validation.Account
-	txr               *repository.Transactioner
+	accountRepository      *repository.Account
+	ledgerRepository       *repository.Ledger
+	statusChangeRepository *repository.StatusChange
+	accountValidator       *validation.Account
+	txr                    *repository.Transactioner
+	secretPolicy           SecretPolicy
 }
 
 var _ Account = (*account)(nil)
 
-// NewAccount returns a value responsible for managing entity.Account actions and integrity
-func NewAccount(txr *repository.Transactioner, accountRepository *repository.Account) Account {
+// NewAccount returns a value responsible for managing entity.Account actions and integrity.
+// The secrets of new accounts must comply with the given policy
+func NewAccount(txr *repository.Transactioner, accountRepository *repository.Account, ledgerRepository *repository.Ledger, statusChangeRepository *repository.StatusChange, secretPolicy SecretPolicy) Account {
 	return &account{
-		accountRepository: accountRepository,
-		txr:               txr,
+		accountRepository:      accountRepository,
+		ledgerRepository:       ledgerRepository,
+		statusChangeRepository: statusChangeRepository,
+		txr:                    txr,
+		secretPolicy:           secretPolicy,
 		accountValidator: &validation.Account{
 			AccountRepository: accountRepository,
+			SecretStrength:    secretPolicy.Strength,
 		},
 	}
 }
 
-// Fetch returns a list of dto.AccountView
-func (srv *account) Fetch(ctx context.Context) ([]dto.AccountView, error) {
-	(*srv.accountRepository).Fetch(ctx)
-	accounts, err := (*srv.accountRepository).Fetch(ctx)
+// accountCursor is the position encoded into the cursor of an account listing
+type accountCursor struct {
+	Sort      string    `json:"s"`
+	ID        int64     `json:"i"`
+	Name      string    `json:"n"`
+	CreatedAt time.Time `json:"c"`
+}
+
+// Fetch returns a page of dto.AccountView narrowed by the given filter.
+// Accounts are sorted by creation date unless the filter specifies otherwise
+func (srv *account) Fetch(ctx context.Context, accountFilter dto.AccountFilter) (page dto.AccountPage, err error) {
+	if accountFilter.Limit == 0 {
+		accountFilter.Limit = defaultPageLimit
+	}
+	if err = srv.accountValidator.Filter(accountFilter); err != nil {
+		return page, err
+	}
+	sort, desc := parseSort(accountFilter.Sort, string(repository.AccountSortCreatedAt))
+	q := repository.AccountQuery{
+		Limit:       accountFilter.Limit + 1,
+		Sort:        repository.AccountSort(sort),
+		Desc:        desc,
+		NamePrefix:  accountFilter.NamePrefix,
+		CreatedFrom: accountFilter.CreatedFrom,
+		CreatedTo:   accountFilter.CreatedTo,
+	}
+	if accountFilter.Cursor != "" {
+		var c accountCursor
+		if err = decodeCursor(accountFilter.Cursor, &c); err != nil {
+			return page, err
+		}
+		if c.Sort != accountFilter.Sort {
+			return page, types.NewErr(types.ValidationErr, "field 'cursor' doesn't belong to the requested sort", nil)
+		}
+		q.After = &repository.AccountKey{ID: c.ID, Name: c.Name, CreatedAt: c.CreatedAt}
+	}
+	accounts, err := (*srv.accountRepository).Fetch(ctx, q)
 	if err != nil {
 		log.Error().Caller().Err(err).Msg("unable to fetch accounts")
-		return nil, err
+		return page, err
 	}
-	views := make([]dto.AccountView, 0, len(accounts))
+	// The extra row fetched beyond the limit only tells there's a next page
+	if len(accounts) > accountFilter.Limit {
+		accounts = accounts[:accountFilter.Limit]
+		last := accounts[len(accounts)-1]
+		page.NextCursor = encodeCursor(accountCursor{Sort: accountFilter.Sort, ID: last.ID, Name: last.Name, CreatedAt: last.CreatedAt})
+	}
+	page.Data = make([]dto.AccountView, 0, len(accounts))
 	for _, account := range accounts {
-		views = append(views, dto.NewAccountView(account))
+		page.Data = append(page.Data, dto.NewAccountView(account))
 	}
-	return views, nil
+	return page, nil
 }
 
-// GetBalance returns the given account balance
-func (srv *account) GetBalance(ctx context.Context, id int64) (float64, error) {
-	balance, err := (*srv.accountRepository).GetBalance(ctx, id)
+// Get returns the details of the entity.Account stored at id, which are only disclosed to the account owner and to the support staff
+func (srv *account) Get(ctx context.Context, requester dto.Requester, id int64) (view dto.AccountView, err error) {
+	if !requester.CanRead(id) {
+		return view, accountAccessErr(requester, id)
+	}
+	account, err := (*srv.accountRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get account")
+		return view, err
+	}
+	return dto.NewAccountView(account), nil
+}
+
+// accountAccessErr tells that the requester tried to access another account. As on transfers, the accounts the requester
+// isn't allowed to read are reported as not found, so that their existence isn't disclosed
+func accountAccessErr(requester dto.Requester, id int64) error {
+	if !requester.CanRead(id) {
+		return types.NewErr(types.NotFoundErr, fmt.Sprintf("account '%d' was not found", id), nil)
+	}
+	return types.NewErr(types.AuthorizationErr, fmt.Sprintf("account '%d' isn't allowed to access account '%d'", requester.ID, id), nil)
+}
+
+// GetBalance returns the given account balance, which is only disclosed to the account owner and to the support staff
+func (srv *account) GetBalance(ctx context.Context, requester dto.Requester, id int64) (view dto.BalanceView, err error) {
+	if !requester.CanRead(id) {
+		return view, accountAccessErr(requester, id)
+	}
+	account, err := (*srv.accountRepository).Get(ctx, id)
 	if err != nil {
 		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get account balance")
-		return 0, err
+		return view, err
 	}
-	return balance.Float64(), nil
+	return dto.NewBalanceView(account), nil
+}
+
+// Reconcile compares the account balance with the one derived from its ledger entries, which must always match.
+// The account is locked meanwhile, so that a concurrent transfer can't be seen halfway through
+func (srv *account) Reconcile(ctx context.Context, id int64) (view dto.ReconciliationView, err error) {
+	var account entity.Account
+	var ledgerBalance types.Currency
+	err = (*srv.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*srv.accountRepository).Lock(txCtx, id); err != nil {
+			return err
+		}
+		if account, err = (*srv.accountRepository).Get(txCtx, id); err != nil {
+			return err
+		}
+		ledgerBalance, err = (*srv.ledgerRepository).GetBalance(txCtx, id)
+		return err
+	})
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to reconcile the account balance")
+		return view, err
+	}
+	view = dto.NewReconciliationView(account, ledgerBalance)
+	if !view.Reconciled {
+		log.Error().Caller().
+			Int64("id", id).
+			Int64("balance", int64(account.Balance)).
+			Int64("ledger_balance", int64(ledgerBalance)).
+			Msg("account balance doesn't match its ledger")
+	}
+	return view, nil
 }
 
 // Create validates and persists the given e entity.Account
 func (srv *account) Create(ctx context.Context, accountCreation dto.AccountCreation) (view dto.AccountView, err error) {
 	var account entity.Account
+	if accountCreation.Currency == "" {
+		accountCreation.Currency = types.DefaultCurrencyCode
+	}
 	err = (*srv.txr).WithTx(ctx, func(txCtx context.Context) error {
 		err := srv.accountValidator.Creation(txCtx, accountCreation)
 		if err != nil {
 			return err
 		}
 
-		hash, err := bcrypt.GenerateFromPassword([]byte(accountCreation.Secret), bcrypt.DefaultCost)
+		balance, err := accountCreation.Currency.Currency(accountCreation.Balance)
+		if err != nil {
+			return err
+		}
+		hash, err := srv.secretPolicy.hash(accountCreation.Secret)
 		if err != nil {
-			log.Info().Caller().Err(err).Msg("unable to create the account secret hash")
 			return err
 		}
 		account = entity.Account{
 			Name:      accountCreation.Name,
 			CPF:       accountCreation.CPF,
-			Balance:   types.NewCurrency(accountCreation.Balance),
+			Currency:  accountCreation.Currency,
+			Balance:   balance,
+			Role:      entity.RoleCustomer,
+			Status:    entity.StatusActive,
+			Version:   1,
 			CreatedAt: time.Now(),
-			Secret:    string(hash),
+			Secret:    hash,
 		}
 		id, err := (*srv.accountRepository).Create(txCtx, account)
+		if err != nil {
+			return err
+		}
 		account.ID = id
+		if account.Balance == 0 {
+			return nil
+		}
+		// The opening balance is the only credit that isn't backed by a transfer
+		_, err = (*srv.ledgerRepository).Create(txCtx, entity.LedgerEntry{
+			AccountID: account.ID,
+			Type:      entity.Credit,
+			Amount:    account.Balance,
+			CreatedAt: account.CreatedAt,
+		})
 		return err
 	})
 
@@ -122,5 +254,129 @@ func (srv *account) Login(ctx context.Context, cpf string, secret string) (view
 	if err != nil {
 		return view, types.NewErr(types.AuthenticationErr, "the provided secret doesn't match the account's secret", err)
 	}
+	// The status is only disclosed to whoever knows the secret
+	if account.Status == entity.StatusClosed {
+		return view, types.NewErr(types.AuthenticationErr, "the account is closed", nil)
+	}
+	return dto.NewAccountView(account), nil
+}
+
+// UpdateRole grants the given role to the entity.Account stored at id
+func (srv *account) UpdateRole(ctx context.Context, id int64, role entity.Role) (view dto.AccountView, err error) {
+	if !role.Supported() {
+		return view, types.NewErr(types.ValidationErr, fmt.Sprintf("field 'role' must be one of '%s', '%s' or '%s'", entity.RoleCustomer, entity.RoleSupport, entity.RoleAdmin), nil)
+	}
+	var account entity.Account
+	err = (*srv.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if account, err = (*srv.accountRepository).Get(txCtx, id); err != nil {
+			return err
+		}
+		// Granting the current role again doesn't affect any row
+		if account.Role == role {
+			return nil
+		}
+		account.Role = role
+		return (*srv.accountRepository).UpdateRole(txCtx, id, role)
+	})
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Str("role", string(role)).Msg("unable to update the account role")
+		return view, err
+	}
 	return dto.NewAccountView(account), nil
 }
+
+// UpdateStatus moves the entity.Account stored at id to the given status, auditing the change along with the requester and the reason.
+// The account row stays locked meanwhile, so that a concurrent transfer can't change the balance of an account being closed
+func (srv *account) UpdateStatus(ctx context.Context, requester dto.Requester, id int64, status entity.Status, reason string) (view dto.AccountView, err error) {
+	var account entity.Account
+	err = (*srv.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*srv.accountRepository).Lock(txCtx, id); err != nil {
+			return err
+		}
+		if account, err = (*srv.accountRepository).Get(txCtx, id); err != nil {
+			return err
+		}
+		if err = srv.accountValidator.StatusChange(account, status, reason); err != nil {
+			return err
+		}
+		if err = (*srv.accountRepository).UpdateStatus(txCtx, id, status); err != nil {
+			return err
+		}
+		_, err = (*srv.statusChangeRepository).Create(txCtx, entity.StatusChange{
+			AccountID: id,
+			From:      account.Status,
+			To:        status,
+			Reason:    reason,
+			ChangedBy: requester.ID,
+			CreatedAt: time.Now(),
+		})
+		account.Status = status
+		return err
+	})
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Str("status", string(status)).Msg("unable to update the account status")
+		return view, err
+	}
+	log.Info().Int64("id", id).Int64("changed_by", requester.ID).Str("status", string(status)).Str("reason", reason).Msg("account status changed")
+	return dto.NewAccountView(account), nil
+}
+
+// Patch applies the changes to the profile of the entity.Account stored at id, which is only allowed to the account owner and to the admin staff.
+// The changes are based on the given version, failing if the account was modified meanwhile
+func (srv *account) Patch(ctx context.Context, requester dto.Requester, id int64, version int64, patch dto.AccountPatch) (view dto.AccountView, err error) {
+	if requester.ID != id && requester.Role != entity.RoleAdmin {
+		return view, accountAccessErr(requester, id)
+	}
+	if err = srv.accountValidator.Patch(patch); err != nil {
+		return view, err
+	}
+	account, err := (*srv.accountRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the account to patch")
+		return view, err
+	}
+	if account.Version != version {
+		return view, modifiedAccountErr(id, version)
+	}
+	if patch.Name != nil {
+		account.Name = *patch.Name
+	}
+	err = (*srv.accountRepository).UpdateProfile(ctx, account)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return view, modifiedAccountErr(id, version)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to update the account profile")
+		return view, err
+	}
+	account.Version++
+	log.Info().Int64("id", id).Int64("changed_by", requester.ID).Int64("version", account.Version).Msg("account profile changed")
+	return dto.NewAccountView(account), nil
+}
+
+// modifiedAccountErr tells that the account was modified since the version a change is based on
+func modifiedAccountErr(id int64, version int64) error {
+	return types.NewErr(types.PreconditionErr, fmt.Sprintf("account '%d' was modified since version %d", id, version), nil)
+}
+
+// FetchStatusChanges returns the audit trail of the entity.Account stored at id, from the oldest status change to the newest
+func (srv *account) FetchStatusChanges(ctx context.Context, id int64) ([]dto.StatusChangeView, error) {
+	exists, err := (*srv.accountRepository).Exists(ctx, id)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to verify the account existence")
+		return nil, err
+	}
+	if !exists {
+		return nil, types.NewErr(types.EmptyResultErr, fmt.Sprintf("account '%d' was not found", id), nil)
+	}
+	changes, err := (*srv.statusChangeRepository).Fetch(ctx, id)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to fetch the account status changes")
+		return nil, err
+	}
+	views := make([]dto.StatusChangeView, 0, len(changes))
+	for _, change := range changes {
+		views = append(views, dto.NewStatusChangeView(change))
+	}
+	return views, nil
+}
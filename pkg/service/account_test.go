@@ -14,10 +14,11 @@ import (
 
 func TestAccountServiceCreate(t *testing.T) {
 	tt := []struct {
-		name      string
-		d         dto.AccountCreation
-		repo      func(dto.AccountCreation) repository.Account
-		assertErr func(*testing.T, error)
+		name       string
+		d          dto.AccountCreation
+		repo       func(dto.AccountCreation) repository.Account
+		ledgerRepo func(dto.AccountCreation) repository.Ledger
+		assertErr  func(*testing.T, error)
 	}{
 		{
 			name: "create account successfully",
@@ -29,15 +30,70 @@ func TestAccountServiceCreate(t *testing.T) {
 					},
 					ExpectCreate: func(ctx context.Context, e entity.Account) (int64, error) {
 						testutil.AssertEq(t, "name", d.Name, e.Name)
-						testutil.AssertEq(t, "balance", d.Balance, e.Balance.Float64())
+						testutil.AssertEq(t, "balance", d.Balance, types.BRL.Decimal(e.Balance))
 						testutil.AssertEq(t, "cpf", d.CPF, e.CPF)
 						return int64(1), nil
 					},
 				}
 			},
+			ledgerRepo: func(d dto.AccountCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						testutil.AssertEq(t, "account id", int64(1), e.AccountID)
+						testutil.AssertEq(t, "entry type", entity.Credit, e.Type)
+						testutil.AssertEq(t, "amount", d.Balance, types.BRL.Decimal(e.Amount))
+						if e.TransferID != nil {
+							t.Errorf("expected opening entry with no transfer but got '%d'", *e.TransferID)
+						}
+						return int64(1), nil
+					},
+				}
+			},
 			d:         testutil.NewAccountCreation("John", "62202136029", "pw", 100),
 			assertErr: testutil.AssertNoErr,
 		},
+		{
+			name: "create account with no opening balance",
+			repo: func(d dto.AccountCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectFindBy: func(ctx context.Context, cpf string) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "EmptyResultErr", nil)
+					},
+					ExpectCreate: func(ctx context.Context, e entity.Account) (int64, error) {
+						return int64(2), nil
+					},
+				}
+			},
+			ledgerRepo: func(d dto.AccountCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			d:         testutil.NewAccountCreation("Jane", "62202136029", "pw", 0),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "create account with ledger repository error",
+			repo: func(d dto.AccountCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectFindBy: func(ctx context.Context, cpf string) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "EmptyResultErr", nil)
+					},
+					ExpectCreate: func(ctx context.Context, e entity.Account) (int64, error) {
+						return int64(3), nil
+					},
+				}
+			},
+			ledgerRepo: func(d dto.AccountCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						return 0, types.NewErr(types.InsertStmtErr, "exec ledger entry insert stmt", nil)
+					},
+				}
+			},
+			d: testutil.NewAccountCreation("Joan", "62202136029", "pw", 10),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InsertStmtErr, err, "exec ledger entry insert stmt")
+			},
+		},
 		{
 			name: "create account with validation err",
 			repo: func(d dto.AccountCreation) repository.Account {
@@ -70,7 +126,11 @@ func TestAccountServiceCreate(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := tc.repo(tc.d)
-			s := service.NewAccount(&txr, &repo)
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			if tc.ledgerRepo != nil {
+				ledgerRepo = tc.ledgerRepo(tc.d)
+			}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
 			acc, err := s.Create(context.Background(), tc.d)
 			if err == nil && tc.assertErr == nil {
 				testutil.AssertNotDefault(t, "id", acc.ID)
@@ -86,9 +146,16 @@ func TestAccountServiceCreate(t *testing.T) {
 }
 
 func TestAccountServiceFetch(t *testing.T) {
+	accounts := []entity.Account{
+		testutil.NewEntityAccount(1, "Jose", "00123456789", "PW001", 100),
+		testutil.NewEntityAccount(2, "Maria", "98765432100", "PW002", 200),
+		testutil.NewEntityAccount(3, "Silva", "98745632100", "PW003", 300),
+	}
 	tt := []struct {
 		name         string
 		expectedSize int
+		filter       dto.AccountFilter
+		hasNext      bool
 		repo         func() repository.Account
 		assertErr    func(*testing.T, error)
 	}{
@@ -97,7 +164,10 @@ func TestAccountServiceFetch(t *testing.T) {
 			expectedSize: 0,
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectFetch: func(ctx context.Context) ([]entity.Account, error) {
+					ExpectFetch: func(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+						testutil.AssertEq(t, "limit", 51, q.Limit)
+						testutil.AssertEq(t, "sort", repository.AccountSortCreatedAt, q.Sort)
+						testutil.AssertEq(t, "desc", false, q.Desc)
 						return []entity.Account{}, nil
 					},
 				}
@@ -106,23 +176,60 @@ func TestAccountServiceFetch(t *testing.T) {
 		{
 			name:         "fetch account with results",
 			expectedSize: 3,
+			filter: dto.AccountFilter{
+				PageRequest: dto.PageRequest{Limit: 3, Sort: "-name"},
+				NamePrefix:  "J",
+			},
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectFetch: func(ctx context.Context) ([]entity.Account, error) {
-						return []entity.Account{
-							testutil.NewEntityAccount(1, "Jose", "00123456789", "PW001", 100),
-							testutil.NewEntityAccount(2, "Maria", "98765432100", "PW002", 200),
-							testutil.NewEntityAccount(3, "Silva", "98745632100", "PW003", 300),
-						}, nil
+					ExpectFetch: func(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+						testutil.AssertEq(t, "limit", 4, q.Limit)
+						testutil.AssertEq(t, "sort", repository.AccountSortName, q.Sort)
+						testutil.AssertEq(t, "desc", true, q.Desc)
+						testutil.AssertEq(t, "name prefix", "J", q.NamePrefix)
+						return accounts, nil
 					},
 				}
 			},
 		},
+		{
+			name:         "fetch account with next page",
+			expectedSize: 2,
+			filter:       dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 2}},
+			hasNext:      true,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectFetch: func(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+						return accounts, nil
+					},
+				}
+			},
+		},
+		{
+			name:   "fetch account with invalid cursor",
+			filter: dto.AccountFilter{PageRequest: dto.PageRequest{Cursor: "not a cursor"}},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'cursor' has an invalid format")
+			},
+		},
+		{
+			name:   "fetch account with limit out of range",
+			filter: dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 201}},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'limit' must be between 1 and 200")
+			},
+		},
 		{
 			name: "fetch account repository error",
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectFetch: func(ctx context.Context) ([]entity.Account, error) {
+					ExpectFetch: func(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
 						return nil, types.NewErr(types.InternalErr, "internal error", nil)
 					},
 				}
@@ -136,11 +243,13 @@ func TestAccountServiceFetch(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := tc.repo()
-			s := service.NewAccount(&txr, &repo)
-			accs, err := s.Fetch(context.Background())
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			page, err := s.Fetch(context.Background(), tc.filter)
 			if err == nil && tc.assertErr == nil {
-				testutil.AssertEq(t, "accs size", len(accs), tc.expectedSize)
-				for _, acc := range accs {
+				testutil.AssertEq(t, "accs size", tc.expectedSize, len(page.Data))
+				testutil.AssertEq(t, "has next", tc.hasNext, page.NextCursor != "")
+				for _, acc := range page.Data {
 					testutil.AssertNotDefault(t, "name", acc.Name)
 					testutil.AssertNotDefault(t, "cpf", acc.CPF)
 					testutil.AssertNotDefault(t, "balance", acc.Balance)
@@ -153,39 +262,176 @@ func TestAccountServiceFetch(t *testing.T) {
 	}
 }
 
+func TestAccountServiceFetchCursor(t *testing.T) {
+	accounts := []entity.Account{
+		testutil.NewEntityAccount(1, "Ana", "00123456789", "PW001", 100),
+		testutil.NewEntityAccount(2, "Bia", "98765432100", "PW002", 200),
+	}
+	var after *repository.AccountKey
+	repo := repository.Account(&testutil.AccountRepoMock{
+		ExpectFetch: func(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+			after = q.After
+			return accounts, nil
+		},
+	})
+	var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+	s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+
+	first, err := s.Fetch(context.Background(), dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 1, Sort: "name"}})
+	testutil.AssertNoErr(t, err)
+	_, err = s.Fetch(context.Background(), dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 1, Sort: "name", Cursor: first.NextCursor}})
+	testutil.AssertNoErr(t, err)
+	if after == nil {
+		t.Fatal("expected the second page to resume after the first one")
+	}
+	testutil.AssertEq(t, "after id", int64(1), after.ID)
+	testutil.AssertEq(t, "after name", "Ana", after.Name)
+
+	_, err = s.Fetch(context.Background(), dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 1, Cursor: first.NextCursor}})
+	testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'cursor' doesn't belong to the requested sort")
+}
+
+func TestAccountServiceGet(t *testing.T) {
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		id        int64
+		repo      func() repository.Account
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "get own account successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						testutil.AssertEq(t, "id", int64(1), id)
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get account of another owner",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        2,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "account '2' was not found")
+			},
+		},
+		{
+			name:      "get account of another owner as support",
+			requester: dto.Requester{ID: 1, Role: entity.RoleSupport},
+			id:        2,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get account with repository error",
+			requester: dto.Requester{ID: 3, Role: entity.RoleCustomer},
+			id:        3,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account by id", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting account by id")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := tc.repo()
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			view, err := s.Get(context.Background(), tc.requester, tc.id)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "id", tc.id, view.ID)
+			}
+		})
+	}
+}
+
 func TestAccountServiceGetBalance(t *testing.T) {
 	tt := []struct {
 		name      string
-		expected  float64
-		repo      func(int64, float64) repository.Account
+		expected  dto.BalanceView
+		repo      func(int64, dto.BalanceView) repository.Account
 		assertErr func(*testing.T, error)
+		requester dto.Requester
 		id        int64
 	}{
 		{
 			name: "get account balance successfully",
-			repo: func(id int64, balance float64) repository.Account {
+			repo: func(id int64, view dto.BalanceView) repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(ctx context.Context, currentID int64) (types.Currency, error) {
+					ExpectGet: func(ctx context.Context, currentID int64) (entity.Account, error) {
 						testutil.AssertEq(t, "id", id, currentID)
-						return types.NewCurrency(balance), nil
+						balance, err := view.Currency.Currency(view.Balance)
+						return entity.Account{ID: id, Currency: view.Currency, Balance: balance}, err
 					},
 				}
 			},
-			expected:  500,
+			expected:  dto.BalanceView{Balance: "500.00", Currency: types.USD},
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
 			id:        1,
 			assertErr: testutil.AssertNoErr,
 		},
+		{
+			name: "get account balance of another owner",
+			repo: func(id int64, view dto.BalanceView) repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			expected:  dto.BalanceView{},
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        2,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "account '2' was not found")
+			},
+		},
+		{
+			name: "get account balance of another owner as admin",
+			repo: func(id int64, view dto.BalanceView) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, currentID int64) (entity.Account, error) {
+						balance, err := view.Currency.Currency(view.Balance)
+						return entity.Account{ID: id, Currency: view.Currency, Balance: balance}, err
+					},
+				}
+			},
+			expected:  dto.BalanceView{Balance: "1.00", Currency: types.BRL},
+			requester: dto.Requester{ID: 1, Role: entity.RoleAdmin},
+			id:        2,
+			assertErr: testutil.AssertNoErr,
+		},
 		{
 			name: "get account balance with repository error",
-			repo: func(id int64, balance float64) repository.Account {
+			repo: func(id int64, view dto.BalanceView) repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(ctx context.Context, currentID int64) (types.Currency, error) {
-						return types.NewCurrency(0), types.NewErr(types.EmptyResultErr, "no sql rows", nil)
+					ExpectGet: func(ctx context.Context, currentID int64) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "no sql rows", nil)
 					},
 				}
 			},
-			expected: 0,
-			id:       2,
+			expected:  dto.BalanceView{},
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			id:        2,
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no sql rows")
 			},
@@ -195,12 +441,71 @@ func TestAccountServiceGetBalance(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := tc.repo(tc.id, tc.expected)
-			s := service.NewAccount(&txr, &repo)
-			balance, err := s.GetBalance(context.Background(), tc.id)
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			balance, err := s.GetBalance(context.Background(), tc.requester, tc.id)
 			testutil.AssertEq(t, "balance", tc.expected, balance)
-			if err != nil {
-				tc.assertErr(t, err)
+			tc.assertErr(t, err)
+		})
+	}
+}
+
+func TestAccountServiceReconcile(t *testing.T) {
+	tt := []struct {
+		name      string
+		balance   types.Currency
+		ledger    types.Currency
+		getErr    error
+		expected  dto.ReconciliationView
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "reconcile account balance matching its ledger",
+			balance:   50000,
+			ledger:    50000,
+			expected:  dto.ReconciliationView{Balance: "500.00", LedgerBalance: "500.00", Currency: types.BRL, Reconciled: true},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "reconcile account balance drifted from its ledger",
+			balance:   50000,
+			ledger:    49000,
+			expected:  dto.ReconciliationView{Balance: "500.00", LedgerBalance: "490.00", Currency: types.BRL},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "reconcile nonexistent account",
+			getErr: types.NewErr(types.EmptyResultErr, "no sql rows", nil),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no sql rows")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var locked int64
+			var repo repository.Account = &testutil.AccountRepoMock{
+				ExpectLock: func(ctx context.Context, ids ...int64) error {
+					testutil.AssertEq(t, "locked ids", 1, len(ids))
+					locked = ids[0]
+					return nil
+				},
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					testutil.AssertEq(t, "locked", int64(2), locked)
+					return entity.Account{ID: id, Currency: types.BRL, Balance: tc.balance}, tc.getErr
+				},
+			}
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{
+				ExpectGetBalance: func(ctx context.Context, account int64) (types.Currency, error) {
+					testutil.AssertEq(t, "account", int64(2), account)
+					return tc.ledger, nil
+				},
 			}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			view, err := s.Reconcile(context.Background(), 2)
+			testutil.AssertEq(t, "reconciliation", tc.expected, view)
+			tc.assertErr(t, err)
 		})
 	}
 }
@@ -245,6 +550,23 @@ func TestAccountServiceLogin(t *testing.T) {
 				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the provided secret doesn't match the account's secret")
 			},
 		},
+		{
+			name:     "login with closed account",
+			expected: testutil.NewEntityAccount(3, "Sousa", "41112075020", "$2a$10$c3GzxvPAAMS9pDqB9XIYi.kT/PN7CxfRev.BsRLvAJqVcZnFiW05i", 0),
+			secret:   "...",
+			cpf:      "41112075020",
+			repo: func(exp entity.Account) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectFindBy: func(ctx context.Context, cpf string) (entity.Account, error) {
+						exp.Status = entity.StatusClosed
+						return exp, nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the account is closed")
+			},
+		},
 		{
 			name:   "login with repository error",
 			secret: "...",
@@ -300,7 +622,8 @@ func TestAccountServiceLogin(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := tc.repo(tc.expected)
-			s := service.NewAccount(&txr, &repo)
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
 			view, err := s.Login(context.Background(), tc.cpf, tc.secret)
 			if err != nil {
 				tc.assertErr(t, err)
@@ -308,9 +631,340 @@ func TestAccountServiceLogin(t *testing.T) {
 				testutil.AssertEq(t, "id", tc.expected.ID, view.ID)
 				testutil.AssertEq(t, "name", tc.expected.Name, view.Name)
 				testutil.AssertEq(t, "cpf", tc.expected.CPF, view.CPF)
-				testutil.AssertEq(t, "balance", tc.expected.Balance.Float64(), view.Balance)
+				testutil.AssertEq(t, "balance", types.BRL.Decimal(tc.expected.Balance), view.Balance)
 				testutil.AssertNotDefault(t, "created_at", view.CreatedAt)
 			}
 		})
 	}
 }
+
+func TestAccountServiceUpdateRole(t *testing.T) {
+	tt := []struct {
+		name      string
+		id        int64
+		role      entity.Role
+		repo      func() repository.Account
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "update account role successfully",
+			id:   1,
+			role: entity.RoleSupport,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+					ExpectUpdateRole: func(ctx context.Context, id int64, role entity.Role) error {
+						testutil.AssertEq(t, "id", int64(1), id)
+						testutil.AssertEq(t, "role", entity.RoleSupport, role)
+						return nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "update account role to its current role",
+			id:   1,
+			role: entity.RoleCustomer,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "update account role to an unsupported role",
+			id:   1,
+			role: "root",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'role' must be one of 'customer', 'support' or 'admin'")
+			},
+		},
+		{
+			name: "update role of nonexistent account",
+			id:   9,
+			role: entity.RoleAdmin,
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account by id", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting account by id")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := tc.repo()
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			view, err := s.UpdateRole(context.Background(), tc.id, tc.role)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "role", tc.role, view.Role)
+			}
+		})
+	}
+}
+
+func TestAccountServiceUpdateStatus(t *testing.T) {
+	tt := []struct {
+		name             string
+		id               int64
+		status           entity.Status
+		reason           string
+		repo             func() repository.Account
+		statusChangeRepo func() repository.StatusChange
+		assertErr        func(*testing.T, error)
+	}{
+		{
+			name:   "update account status successfully",
+			id:     1,
+			status: entity.StatusBlocked,
+			reason: "fraud investigation",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: func(ctx context.Context, ids ...int64) error {
+						return nil
+					},
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+					ExpectUpdateStatus: func(ctx context.Context, id int64, status entity.Status) error {
+						testutil.AssertEq(t, "id", int64(1), id)
+						testutil.AssertEq(t, "status", entity.StatusBlocked, status)
+						return nil
+					},
+				}
+			},
+			statusChangeRepo: func() repository.StatusChange {
+				return &testutil.StatusChangeRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.StatusChange) (int64, error) {
+						testutil.AssertEq(t, "account_id", int64(1), e.AccountID)
+						testutil.AssertEq(t, "from", entity.StatusActive, e.From)
+						testutil.AssertEq(t, "to", entity.StatusBlocked, e.To)
+						testutil.AssertEq(t, "reason", "fraud investigation", e.Reason)
+						testutil.AssertEq(t, "changed_by", int64(7), e.ChangedBy)
+						return 1, nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "update account status to its current status",
+			id:     1,
+			status: entity.StatusActive,
+			reason: "no reason",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: func(ctx context.Context, ids ...int64) error {
+						return nil
+					},
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+				}
+			},
+			statusChangeRepo: func() repository.StatusChange {
+				return &testutil.StatusChangeRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "account '1' is already active")
+			},
+		},
+		{
+			name:   "update status of nonexistent account",
+			id:     9,
+			status: entity.StatusBlocked,
+			reason: "fraud investigation",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: func(ctx context.Context, ids ...int64) error {
+						return types.NewErr(types.EmptyResultErr, "no result locking account", nil)
+					},
+				}
+			},
+			statusChangeRepo: func() repository.StatusChange {
+				return &testutil.StatusChangeRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result locking account")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := tc.repo()
+			statusChangeRepo := tc.statusChangeRepo()
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			view, err := s.UpdateStatus(context.Background(), dto.Requester{ID: 7, Role: entity.RoleAdmin}, tc.id, tc.status, tc.reason)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "status", tc.status, view.Status)
+			}
+		})
+	}
+}
+
+func TestAccountServicePatch(t *testing.T) {
+	name := func(n string) *string { return &n }
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		id        int64
+		version   int64
+		patch     dto.AccountPatch
+		repo      func() repository.Account
+		expected  string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "patch account name successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("Ana Sousa")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana Suosa", "71453945024", "pw", 10), nil
+					},
+					ExpectUpdateProfile: func(ctx context.Context, e entity.Account) error {
+						testutil.AssertEq(t, "id", int64(1), e.ID)
+						testutil.AssertEq(t, "name", "Ana Sousa", e.Name)
+						testutil.AssertEq(t, "version", int64(1), e.Version)
+						return nil
+					},
+				}
+			},
+			expected:  "Ana Sousa",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "patch another account as admin",
+			requester: dto.Requester{ID: 7, Role: entity.RoleAdmin},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+					ExpectUpdateProfile: func(ctx context.Context, e entity.Account) error {
+						return nil
+					},
+				}
+			},
+			expected:  "Ana",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "patch another account as customer",
+			requester: dto.Requester{ID: 7, Role: entity.RoleCustomer},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("Ana Sousa")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "account '1' was not found")
+			},
+		},
+		{
+			name:      "patch another account as support",
+			requester: dto.Requester{ID: 7, Role: entity.RoleSupport},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("Ana Sousa")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '7' isn't allowed to access account '1'")
+			},
+		},
+		{
+			name:      "patch account with invalid name",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'name' is required")
+			},
+		},
+		{
+			name:      "patch account based on an outdated version",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("Ana Sousa")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10)
+						acc.Version = 2
+						return acc, nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.PreconditionErr, err, "account '1' was modified since version 1")
+			},
+		},
+		{
+			name:      "patch account modified concurrently",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			version:   1,
+			patch:     dto.AccountPatch{Name: name("Ana Sousa")},
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 10), nil
+					},
+					ExpectUpdateProfile: func(ctx context.Context, e entity.Account) error {
+						return types.NewErr(types.NoRowAffectedErr, "no rows affected by the update profile stmt", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.PreconditionErr, err, "account '1' was modified since version 1")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := tc.repo()
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			s := service.NewAccount(&txr, &repo, &ledgerRepo, &statusChangeRepo, secretPolicy)
+			view, err := s.Patch(context.Background(), tc.requester, tc.id, tc.version, tc.patch)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "name", tc.expected, view.Name)
+				testutil.AssertEq(t, "version", tc.version+1, view.Version)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// Cash exposes the deposits and withdrawals, which move money between an entity.Account and the outside world
+type Cash interface {
+	Deposit(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error)
+	Withdraw(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error)
+}
+
+type cash struct {
+	cashOperationRepository *repository.CashOperation
+	accountRepository       *repository.Account
+	ledgerRepository        *repository.Ledger
+	txr                     *repository.Transactioner
+	cashValidator           *validation.Cash
+}
+
+var _ Cash = (*cash)(nil)
+
+// NewCash returns a value responsible for managing entity.CashOperation integrity
+func NewCash(txr *repository.Transactioner, cashOperationRepository *repository.CashOperation, accountRepository *repository.Account, ledgerRepository *repository.Ledger) Cash {
+	return &cash{
+		cashOperationRepository: cashOperationRepository,
+		accountRepository:       accountRepository,
+		ledgerRepository:        ledgerRepository,
+		txr:                     txr,
+		cashValidator: &validation.Cash{
+			AccountRepository: accountRepository,
+		},
+	}
+}
+
+// Deposit credits the account stored at account with the amount reported by the requester
+func (s *cash) Deposit(ctx context.Context, requester dto.Requester, account int64, cashCreation dto.CashOperationCreation) (dto.CashOperationView, error) {
+	return s.create(ctx, requester, entity.Deposit, account, cashCreation)
+}
+
+// Withdraw debits the account stored at account with the amount reported by the requester
+func (s *cash) Withdraw(ctx context.Context, requester dto.Requester, account int64, cashCreation dto.CashOperationCreation) (dto.CashOperationView, error) {
+	return s.create(ctx, requester, entity.Withdrawal, account, cashCreation)
+}
+
+// create validates and persists a cash operation of type t, updating the account balance and posting the ledger entry within the same tx
+func (s *cash) create(ctx context.Context, requester dto.Requester, t entity.CashType, account int64, cashCreation dto.CashOperationCreation) (view dto.CashOperationView, err error) {
+	var operation entity.CashOperation
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		// The row stays locked until the tx ends, so the balance read below can't be changed by a concurrent transfer
+		if err := (*s.accountRepository).Lock(txCtx, account); err != nil {
+			return err
+		}
+		if err := s.cashValidator.Creation(txCtx, t, account, cashCreation); err != nil {
+			return err
+		}
+		acc, err := (*s.accountRepository).Get(txCtx, account)
+		if err != nil {
+			return err
+		}
+		amount, err := acc.Currency.Currency(cashCreation.Amount)
+		if err != nil {
+			return err
+		}
+		entry := entity.LedgerEntry{AccountID: account, Type: entity.Credit, Amount: amount}
+		balance := acc.Balance + amount
+		if t == entity.Withdrawal {
+			entry.Type = entity.Debit
+			balance = acc.Balance - amount
+		}
+		if err = (*s.accountRepository).UpdateBalance(txCtx, account, balance); err != nil {
+			return err
+		}
+		operation = entity.CashOperation{
+			AccountID: account,
+			Type:      t,
+			Amount:    amount,
+			Currency:  acc.Currency,
+			Reference: cashCreation.Reference,
+			ClientID:  requester.ClientID,
+			CreatedAt: time.Now(),
+		}
+		if operation.ID, err = (*s.cashOperationRepository).Create(txCtx, operation); err != nil {
+			return err
+		}
+		entry.CashOperationID = &operation.ID
+		entry.CreatedAt = operation.CreatedAt
+		_, err = (*s.ledgerRepository).Create(txCtx, entry)
+		return err
+	})
+	if err != nil {
+		log.Info().Caller().Err(err).
+			Int64("account_id", account).
+			Str("type", string(t)).
+			Str("amount", string(cashCreation.Amount)).
+			Str("reference", cashCreation.Reference).
+			Msg("unable to create the cash operation")
+		return view, err
+	}
+	log.Info().
+		Int64("id", operation.ID).
+		Int64("account_id", account).
+		Str("type", string(t)).
+		Str("client_id", requester.ClientID).
+		Msg("cash operation created")
+	return dto.NewCashOperationView(operation), nil
+}
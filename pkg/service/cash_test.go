@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestCashServiceCreate(t *testing.T) {
+	requester := dto.Requester{ClientID: "branch"}
+	cashCreation := dto.CashOperationCreation{Amount: "100.00", Reference: "branch-0042-000981"}
+	tt := []struct {
+		name       string
+		cashType   entity.CashType
+		balance    float64
+		status     entity.Status
+		cashRepo   func() repository.CashOperation
+		ledgerRepo func() repository.Ledger
+		expected   types.Currency
+		assertErr  func(*testing.T, error)
+	}{
+		{
+			name:     "deposit successfully",
+			cashType: entity.Deposit,
+			balance:  50,
+			cashRepo: func() repository.CashOperation {
+				return &testutil.CashOperationRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.CashOperation) (int64, error) {
+						testutil.AssertEq(t, "type", entity.Deposit, e.Type)
+						testutil.AssertEq(t, "amount", types.NewCurrency(100), e.Amount)
+						testutil.AssertEq(t, "reference", "branch-0042-000981", e.Reference)
+						testutil.AssertEq(t, "client id", "branch", e.ClientID)
+						return 3, nil
+					},
+				}
+			},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						testutil.AssertEq(t, "entry type", entity.Credit, e.Type)
+						testutil.AssertEq(t, "cash operation id", int64(3), *e.CashOperationID)
+						testutil.AssertEq(t, "transfer id", (*int64)(nil), e.TransferID)
+						return 1, nil
+					},
+				}
+			},
+			expected:  types.NewCurrency(150),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:     "withdraw successfully",
+			cashType: entity.Withdrawal,
+			balance:  150,
+			cashRepo: func() repository.CashOperation {
+				return &testutil.CashOperationRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.CashOperation) (int64, error) {
+						testutil.AssertEq(t, "type", entity.Withdrawal, e.Type)
+						return 4, nil
+					},
+				}
+			},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						testutil.AssertEq(t, "entry type", entity.Debit, e.Type)
+						testutil.AssertEq(t, "amount", types.NewCurrency(100), e.Amount)
+						return 1, nil
+					},
+				}
+			},
+			expected:  types.NewCurrency(50),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:     "withdraw more than the balance",
+			cashType: entity.Withdrawal,
+			balance:  50,
+			cashRepo: func() repository.CashOperation {
+				return &testutil.CashOperationRepoMock{}
+			},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the account must have a balance greater than or equal to 100.00")
+			},
+		},
+		{
+			name:     "deposit into a blocked account",
+			cashType: entity.Deposit,
+			balance:  50,
+			status:   entity.StatusBlocked,
+			cashRepo: func() repository.CashOperation {
+				return &testutil.CashOperationRepoMock{}
+			},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the deposit account is blocked")
+			},
+		},
+		{
+			name:     "deposit with a reported reference",
+			cashType: entity.Deposit,
+			balance:  50,
+			cashRepo: func() repository.CashOperation {
+				return &testutil.CashOperationRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.CashOperation) (int64, error) {
+						return 0, types.NewErr(types.ConflictErr, "cash operation reference already exists", nil)
+					},
+				}
+			},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			expected: types.NewCurrency(150),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "cash operation reference already exists")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var accountRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectLock: func(ctx context.Context, ids ...int64) error {
+					return nil
+				},
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					acc := testutil.NewEntityAccount(id, "Bia", "71453945024", "pw", tc.balance)
+					if tc.status != "" {
+						acc.Status = tc.status
+					}
+					return acc, nil
+				},
+				ExpectUpdateBalance: func(ctx context.Context, id int64, balance types.Currency) error {
+					testutil.AssertEq(t, "balance", tc.expected, balance)
+					return nil
+				},
+			}
+			cashRepo := tc.cashRepo()
+			ledgerRepo := tc.ledgerRepo()
+			s := service.NewCash(&txr, &cashRepo, &accountRepo, &ledgerRepo)
+			operate := s.Deposit
+			if tc.cashType == entity.Withdrawal {
+				operate = s.Withdraw
+			}
+			view, err := operate(context.Background(), requester, 2, cashCreation)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "account id", int64(2), view.AccountID)
+				testutil.AssertEq(t, "type", tc.cashType, view.Type)
+				testutil.AssertEq(t, "amount", types.Decimal("100.00"), view.Amount)
+			}
+		})
+	}
+}
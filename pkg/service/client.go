@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// Client exposes the business operations related to the service clients, which authenticate with their own credentials
+// instead of on behalf of an account
+type Client interface {
+	Create(ctx context.Context, clientCreation dto.ClientCreation) (dto.ClientCredentials, error)
+	Authenticate(ctx context.Context, id string, secret string) (dto.ClientView, error)
+	Disable(ctx context.Context, id string) error
+}
+
+type client struct {
+	clientRepository *repository.Client
+	clientValidator  *validation.Client
+}
+
+var _ Client = (*client)(nil)
+
+// NewClient returns a value responsible for managing entity.Client integrity
+func NewClient(clientRepository *repository.Client) Client {
+	return &client{
+		clientRepository: clientRepository,
+		clientValidator:  &validation.Client{},
+	}
+}
+
+// Create registers a new client with random credentials. Only the hash of its secret is stored,
+// so the returned credentials are the only place it's disclosed
+func (srv *client) Create(ctx context.Context, clientCreation dto.ClientCreation) (credentials dto.ClientCredentials, err error) {
+	if err = srv.clientValidator.Creation(clientCreation); err != nil {
+		return credentials, err
+	}
+	id, err := randomBytes(16)
+	if err != nil {
+		return credentials, err
+	}
+	b, err := randomBytes(32)
+	if err != nil {
+		return credentials, err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	e := entity.Client{
+		ID:         hex.EncodeToString(id),
+		Name:       clientCreation.Name,
+		SecretHash: hashToken(secret),
+		Scopes:     uniqScopes(clientCreation.Scopes),
+		CreatedAt:  time.Now(),
+	}
+	if err = (*srv.clientRepository).Create(ctx, e); err != nil {
+		log.Error().Caller().Err(err).Str("name", e.Name).Msg("unable to create the client")
+		return credentials, err
+	}
+	return dto.ClientCredentials{ClientView: dto.NewClientView(e), Secret: secret}, nil
+}
+
+// Authenticate returns the client identified by the given credentials. Unknown and disabled clients
+// are refused with the same error as a wrong secret, so that client ids can't be probed
+func (srv *client) Authenticate(ctx context.Context, id string, secret string) (view dto.ClientView, err error) {
+	invalidErr := types.NewErr(types.AuthenticationErr, "invalid client credentials", nil)
+	if id == "" || secret == "" {
+		return view, invalidErr
+	}
+	e, err := (*srv.clientRepository).Get(ctx, id)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return view, invalidErr
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Str("client_id", id).Msg("unable to get the client")
+		return view, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(e.SecretHash)) != 1 || e.DisabledAt != nil {
+		log.Info().Str("client_id", id).Msg("client authentication failed")
+		return view, invalidErr
+	}
+	return dto.NewClientView(e), nil
+}
+
+// Disable stops granting tokens to the client. The ones already granted remain valid until they expire
+func (srv *client) Disable(ctx context.Context, id string) error {
+	err := (*srv.clientRepository).Disable(ctx, id, time.Now())
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return types.NewErr(types.EmptyResultErr, fmt.Sprintf("no enabled client with id '%s'", id), nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Str("client_id", id).Msg("unable to disable the client")
+		return err
+	}
+	return nil
+}
+
+// uniqScopes returns the given scopes without repetitions, keeping their order
+func uniqScopes(scopes []string) []string {
+	uniq := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		if !seen[s] {
+			seen[s] = true
+			uniq = append(uniq, s)
+		}
+	}
+	return uniq
+}
@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// clientRepo returns a repository mock storing clients in memory
+func clientRepo(clients map[string]entity.Client) repository.Client {
+	return &testutil.ClientRepoMock{
+		ExpectCreate: func(ctx context.Context, e entity.Client) error {
+			clients[e.ID] = e
+			return nil
+		},
+		ExpectGet: func(ctx context.Context, id string) (entity.Client, error) {
+			e, ok := clients[id]
+			if !ok {
+				return e, types.NewErr(types.EmptyResultErr, "no result getting client", nil)
+			}
+			return e, nil
+		},
+		ExpectDisable: func(ctx context.Context, id string, at time.Time) error {
+			e, ok := clients[id]
+			if !ok || e.DisabledAt != nil {
+				return types.NewErr(types.NoRowAffectedErr, "no rows affected by the disable client stmt", nil)
+			}
+			e.DisabledAt = &at
+			clients[id] = e
+			return nil
+		},
+	}
+}
+
+func TestClientServiceCreate(t *testing.T) {
+	tt := []struct {
+		name           string
+		clientCreation dto.ClientCreation
+		scopes         string
+		assertErr      func(*testing.T, error)
+	}{
+		{
+			name:           "create client successfully",
+			clientCreation: dto.ClientCreation{Name: "Batch", Scopes: []string{entity.ScopeTransfersRead, entity.ScopeAccountsRead, entity.ScopeTransfersRead}},
+			scopes:         "transfers:read accounts:read",
+			assertErr:      testutil.AssertNoErr,
+		},
+		{
+			name:           "create client without scopes",
+			clientCreation: dto.ClientCreation{Name: "Batch"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'scopes' is required")
+			},
+		},
+		{
+			name:           "create client with a transfer write scope",
+			clientCreation: dto.ClientCreation{Name: "Batch", Scopes: []string{entity.ScopeTransfersWrite}},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'scopes' must be one of 'accounts:read', 'transfers:read', 'cash:write'")
+			},
+		},
+		{
+			name:           "create client without name",
+			clientCreation: dto.ClientCreation{Scopes: []string{entity.ScopeAccountsRead}},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'name' is required")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clients := map[string]entity.Client{}
+			repo := clientRepo(clients)
+			srv := service.NewClient(&repo)
+			credentials, err := srv.Create(context.Background(), tc.clientCreation)
+			tc.assertErr(t, err)
+			if err != nil {
+				testutil.AssertEq(t, "stored clients", 0, len(clients))
+				return
+			}
+			testutil.AssertEq(t, "client id length", 32, len(credentials.ID))
+			testutil.AssertEq(t, "scopes", tc.scopes, strings.Join(credentials.Scopes, " "))
+			if clients[credentials.ID].SecretHash == credentials.Secret {
+				t.Errorf("expected the client secret to be stored hashed")
+			}
+			view, err := srv.Authenticate(context.Background(), credentials.ID, credentials.Secret)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "authenticated client", credentials.ID, view.ID)
+		})
+	}
+}
+
+func TestClientServiceAuthenticate(t *testing.T) {
+	clients := map[string]entity.Client{}
+	repo := clientRepo(clients)
+	srv := service.NewClient(&repo)
+	enabled, err := srv.Create(context.Background(), dto.ClientCreation{Name: "Batch", Scopes: []string{entity.ScopeAccountsRead}})
+	testutil.AssertNoErr(t, err)
+	disabled, err := srv.Create(context.Background(), dto.ClientCreation{Name: "Legacy", Scopes: []string{entity.ScopeAccountsRead}})
+	testutil.AssertNoErr(t, err)
+	testutil.AssertNoErr(t, srv.Disable(context.Background(), disabled.ID))
+
+	tt := []struct {
+		name      string
+		id        string
+		secret    string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "authenticate client successfully",
+			id:        enabled.ID,
+			secret:    enabled.Secret,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "authenticate client with wrong secret",
+			id:     enabled.ID,
+			secret: disabled.Secret,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid client credentials")
+			},
+		},
+		{
+			name:   "authenticate unknown client",
+			id:     "unknown",
+			secret: enabled.Secret,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid client credentials")
+			},
+		},
+		{
+			name:   "authenticate disabled client",
+			id:     disabled.ID,
+			secret: disabled.Secret,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid client credentials")
+			},
+		},
+		{
+			name: "authenticate client without credentials",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid client credentials")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.Authenticate(context.Background(), tc.id, tc.secret)
+			tc.assertErr(t, err)
+		})
+	}
+
+	err = srv.Disable(context.Background(), disabled.ID)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no enabled client with id '"+disabled.ID+"'")
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// defaultPageLimit is the number of items of a listing page when the request doesn't specify one
+const defaultPageLimit int = 50
+
+// encodeCursor turns the listing position stored at v into an opaque url-safe value
+func encodeCursor(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor restores into v the listing position encoded by encodeCursor
+func decodeCursor(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		return types.NewErr(types.ValidationErr, "field 'cursor' has an invalid format", err)
+	}
+	return nil
+}
+
+// parseSort splits a sort value such as "-name" into its column and whether it's descending
+func parseSort(sort string, fallback string) (string, bool) {
+	if sort == "" {
+		return fallback, false
+	}
+	return strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+}
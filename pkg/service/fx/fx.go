@@ -0,0 +1,120 @@
+// Package fx handles foreign exchange rates and the conversion of amounts between currencies
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// MaxRateScale is the number of fraction digits a rate can be stored with
+const MaxRateScale int = 12
+
+// Identity is the rate applied to transfers between accounts of the same currency
+const Identity types.Decimal = "1"
+
+// RateProvider exposes the exchange rates used by transfers between accounts of different currencies.
+// A rate tells how many units of the currency to are bought by one unit of the currency from
+type RateProvider interface {
+	Rate(ctx context.Context, from types.CurrencyCode, to types.CurrencyCode) (types.Decimal, error)
+}
+
+// Convert exchanges the amount held in the minor unit of from into the minor unit of to.
+// The result is rounded half to even to the nearest minor unit of to
+func Convert(amount types.Currency, from types.CurrencyCode, to types.CurrencyCode, rate types.Decimal) (types.Currency, error) {
+	r, ok := new(big.Rat).SetString(string(rate))
+	if !ok || r.Sign() <= 0 {
+		return 0, types.NewErr(types.ValidationErr, fmt.Sprintf("invalid exchange rate '%s'", rate), nil)
+	}
+	fromExp, err := from.Exponent()
+	if err != nil {
+		return 0, err
+	}
+	toExp, err := to.Exponent()
+	if err != nil {
+		return 0, err
+	}
+	v := new(big.Rat).SetInt64(int64(amount))
+	v.Mul(v, r)
+	// Scale the result from the minor unit of from to the minor unit of to
+	if shift := toExp - fromExp; shift >= 0 {
+		v.Mul(v, new(big.Rat).SetInt(pow10(shift)))
+	} else {
+		v.Quo(v, new(big.Rat).SetInt(pow10(-shift)))
+	}
+	result := roundHalfEven(v)
+	if !result.IsInt64() {
+		return 0, types.NewErr(types.ValidationErr, "the converted amount is out of range", nil)
+	}
+	return types.Currency(result.Int64()), nil
+}
+
+// Prorate returns the share of whole matching the ratio between part and total, rounded half to even
+func Prorate(whole types.Currency, part types.Currency, total types.Currency) types.Currency {
+	v := new(big.Rat).SetFrac(big.NewInt(int64(whole)), big.NewInt(int64(total)))
+	v.Mul(v, new(big.Rat).SetInt64(int64(part)))
+	return types.Currency(roundHalfEven(v).Int64())
+}
+
+// Inverse returns the rate of the opposite exchange, rounded to MaxRateScale fraction digits
+func Inverse(rate types.Decimal) (types.Decimal, error) {
+	r, ok := new(big.Rat).SetString(string(rate))
+	if !ok || r.Sign() <= 0 {
+		return "", types.NewErr(types.ValidationErr, fmt.Sprintf("invalid exchange rate '%s'", rate), nil)
+	}
+	return types.Decimal(r.Inv(r).FloatString(MaxRateScale)).Trim(), nil
+}
+
+func verifyRate(from types.CurrencyCode, to types.CurrencyCode, rate types.Decimal) error {
+	if _, err := types.ParseDecimal(string(rate)); err != nil {
+		return types.NewErr(types.InternalErr, fmt.Sprintf("invalid %s/%s exchange rate '%s'", from, to, rate), err)
+	}
+	if rate.Scale() > MaxRateScale {
+		return types.NewErr(types.InternalErr, fmt.Sprintf("the %s/%s exchange rate '%s' exceeds %d fraction digits", from, to, rate, MaxRateScale), nil)
+	}
+	if r, _ := new(big.Rat).SetString(string(rate)); r.Sign() <= 0 {
+		return types.NewErr(types.InternalErr, fmt.Sprintf("the %s/%s exchange rate '%s' must be positive", from, to, rate), nil)
+	}
+	return nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func roundHalfEven(v *big.Rat) *big.Int {
+	q, r := new(big.Int).QuoRem(v.Num(), v.Denom(), new(big.Int))
+	// Compare twice the remainder against the denominator to find out which side of the half it lies
+	cmp := new(big.Int).Abs(new(big.Int).Mul(r, big.NewInt(2))).Cmp(v.Denom())
+	if cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+		if v.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// New creates the RateProvider described by the given config
+func New(cfg *env.FXConfig) (RateProvider, error) {
+	switch cfg.Provider {
+	case "static":
+		if cfg.RatesFile == "" {
+			return NewStatic(map[string]types.Decimal{})
+		}
+		return NewStaticFromFile(cfg.RatesFile)
+	case "http":
+		if cfg.URL == "" {
+			return nil, types.NewErr(types.InternalErr, "the http rate provider requires an url", nil)
+		}
+		return NewHTTP(&http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}, cfg.URL), nil
+	default:
+		return nil, types.NewErr(types.InternalErr, fmt.Sprintf("unknown rate provider '%s'", cfg.Provider), nil)
+	}
+}
@@ -0,0 +1,151 @@
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestConvert(t *testing.T) {
+	tt := []struct {
+		name      string
+		amount    types.Currency
+		from      types.CurrencyCode
+		to        types.CurrencyCode
+		rate      types.Decimal
+		expected  types.Currency
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "convert amount between currencies of same exponent",
+			amount:    10000,
+			from:      types.BRL,
+			to:        types.USD,
+			rate:      "0.1875",
+			expected:  1875,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert amount into currency without minor unit",
+			amount:    1050,
+			from:      types.USD,
+			to:        types.JPY,
+			rate:      "149.37",
+			expected:  1568,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert amount from currency without minor unit",
+			amount:    1000,
+			from:      types.JPY,
+			to:        types.EUR,
+			rate:      "0.006123",
+			expected:  612,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "convert amount into unsupported currency",
+			amount: 10000,
+			from:   types.BRL,
+			to:     "XYZ",
+			rate:   "0.1875",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unsupported currency code 'XYZ'")
+			},
+		},
+		{
+			name:      "convert amount rounding half to even downwards",
+			amount:    10000,
+			from:      types.BRL,
+			to:        types.USD,
+			rate:      "0.19325",
+			expected:  1932,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "convert amount rounding half to even upwards",
+			amount:    10000,
+			from:      types.BRL,
+			to:        types.USD,
+			rate:      "0.19335",
+			expected:  1934,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "convert amount with zero rate",
+			amount: 10000,
+			from:   types.BRL,
+			to:     types.USD,
+			rate:   "0",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "invalid exchange rate '0'")
+			},
+		},
+		{
+			name:   "convert amount out of range",
+			amount: 1 << 62,
+			from:   types.BRL,
+			to:     types.JPY,
+			rate:   "1000",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the converted amount is out of range")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := fx.Convert(tc.amount, tc.from, tc.to, tc.rate)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "converted amount", tc.expected, v)
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tt := []struct {
+		name     string
+		whole    types.Currency
+		part     types.Currency
+		total    types.Currency
+		expected types.Currency
+	}{
+		{name: "prorate the whole amount", whole: 10000, part: 1875, total: 1875, expected: 10000},
+		{name: "prorate half of the amount", whole: 10000, part: 1000, total: 2000, expected: 5000},
+		{name: "prorate rounding half to even", whole: 5, part: 1, total: 2, expected: 2},
+		{name: "prorate nothing", whole: 10000, part: 0, total: 1875, expected: 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.AssertEq(t, "prorated amount", tc.expected, fx.Prorate(tc.whole, tc.part, tc.total))
+		})
+	}
+}
+
+func TestInverse(t *testing.T) {
+	tt := []struct {
+		name      string
+		rate      types.Decimal
+		expected  types.Decimal
+		assertErr func(*testing.T, error)
+	}{
+		{name: "inverse identity rate", rate: fx.Identity, expected: fx.Identity, assertErr: testutil.AssertNoErr},
+		{name: "inverse exact rate", rate: "0.25", expected: "4", assertErr: testutil.AssertNoErr},
+		{name: "inverse rate rounded to the max scale", rate: "3", expected: "0.333333333333", assertErr: testutil.AssertNoErr},
+		{
+			name: "inverse zero rate",
+			rate: "0",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "invalid exchange rate '0'")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := fx.Inverse(tc.rate)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "inverse rate", tc.expected, v)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+type rateResponse struct {
+	Rate types.Decimal `json:"rate"`
+}
+
+type httpProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+var _ RateProvider = (*httpProvider)(nil)
+
+// NewHTTP creates a RateProvider that queries a remote service with GET {baseURL}?from=USD&to=BRL.
+// The service must reply with a json body such as {"rate": "5.4321"}
+func NewHTTP(client *http.Client, baseURL string) RateProvider {
+	return &httpProvider{client: client, baseURL: baseURL}
+}
+
+// Rate requests the current rate of the given pair from the remote service
+func (p *httpProvider) Rate(ctx context.Context, from types.CurrencyCode, to types.CurrencyCode) (types.Decimal, error) {
+	if from == to {
+		return Identity, nil
+	}
+	u, err := url.Parse(p.baseURL)
+	if err != nil {
+		return "", types.NewErr(types.InternalErr, "invalid rate provider url", err)
+	}
+	q := u.Query()
+	q.Set("from", string(from))
+	q.Set("to", string(to))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", types.NewErr(types.InternalErr, "unable to create the rate request", err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", types.NewErr(types.InternalErr, "unable to reach the rate provider", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return "", types.NewErr(types.ValidationErr, fmt.Sprintf("no exchange rate available from '%s' to '%s'", from, to), nil)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", types.NewErr(types.InternalErr, fmt.Sprintf("unexpected rate provider status %d", res.StatusCode), nil)
+	}
+	var body rateResponse
+	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", types.NewErr(types.InternalErr, "unable to decode the rate provider response", err)
+	}
+	if err = verifyRate(from, to, body.Rate); err != nil {
+		return "", err
+	}
+	return body.Rate, nil
+}
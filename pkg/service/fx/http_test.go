@@ -0,0 +1,69 @@
+package fx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestHTTPRate(t *testing.T) {
+	tt := []struct {
+		name      string
+		handler   http.HandlerFunc
+		expected  types.Decimal
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "get rate successfully",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				testutil.AssertEq(t, "from", "BRL", r.URL.Query().Get("from"))
+				testutil.AssertEq(t, "to", "USD", r.URL.Query().Get("to"))
+				w.Write([]byte(`{"rate": "0.1875"}`))
+			},
+			expected:  "0.1875",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "get rate of unknown pair",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "no exchange rate available from 'BRL' to 'USD'")
+			},
+		},
+		{
+			name: "get rate with provider failure",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unexpected rate provider status 502")
+			},
+		},
+		{
+			name: "get rate with invalid body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"rate": "1e3"}`))
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unable to decode the rate provider response")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+			p := fx.NewHTTP(srv.Client(), srv.URL)
+			rate, err := p.Rate(context.Background(), types.BRL, types.USD)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "rate", tc.expected, rate)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+type static struct {
+	rates map[string]types.Decimal
+}
+
+var _ RateProvider = (*static)(nil)
+
+// NewStatic creates a RateProvider backed by a fixed set of rates keyed by pair, such as "USD/BRL"
+func NewStatic(rates map[string]types.Decimal) (RateProvider, error) {
+	for pair, rate := range rates {
+		var from, to types.CurrencyCode
+		if _, err := fmt.Sscanf(pair, "%3s/%3s", &from, &to); err != nil || pairKey(from, to) != pair {
+			return nil, types.NewErr(types.InternalErr, fmt.Sprintf("invalid currency pair '%s'", pair), err)
+		}
+		if err := verifyRate(from, to, rate); err != nil {
+			return nil, err
+		}
+	}
+	return &static{rates: rates}, nil
+}
+
+// NewStaticFromFile creates a static RateProvider from a json file such as {"USD/BRL": "5.4321"}
+func NewStaticFromFile(path string) (RateProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, types.NewErr(types.InternalErr, fmt.Sprintf("unable to open the rates file '%s'", path), err)
+	}
+	defer f.Close()
+	rates := make(map[string]types.Decimal)
+	if err = json.NewDecoder(f).Decode(&rates); err != nil {
+		return nil, types.NewErr(types.InternalErr, fmt.Sprintf("unable to decode the rates file '%s'", path), err)
+	}
+	return NewStatic(rates)
+}
+
+// Rate returns the configured rate of the given pair
+func (p *static) Rate(ctx context.Context, from types.CurrencyCode, to types.CurrencyCode) (types.Decimal, error) {
+	if from == to {
+		return Identity, nil
+	}
+	rate, ok := p.rates[pairKey(from, to)]
+	if !ok {
+		return "", types.NewErr(types.ValidationErr, fmt.Sprintf("no exchange rate available from '%s' to '%s'", from, to), nil)
+	}
+	return rate, nil
+}
+
+func pairKey(from types.CurrencyCode, to types.CurrencyCode) string {
+	return string(from) + "/" + string(to)
+}
@@ -0,0 +1,112 @@
+package fx_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStaticRate(t *testing.T) {
+	p, err := fx.NewStatic(map[string]types.Decimal{"BRL/USD": "0.1875"})
+	testutil.AssertNoErr(t, err)
+	tt := []struct {
+		name      string
+		from      types.CurrencyCode
+		to        types.CurrencyCode
+		expected  types.Decimal
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "get configured rate",
+			from:      types.BRL,
+			to:        types.USD,
+			expected:  "0.1875",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get rate of same currency",
+			from:      types.EUR,
+			to:        types.EUR,
+			expected:  fx.Identity,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "get rate of missing pair",
+			from: types.USD,
+			to:   types.BRL,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "no exchange rate available from 'USD' to 'BRL'")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := p.Rate(context.Background(), tc.from, tc.to)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "rate", tc.expected, rate)
+		})
+	}
+}
+
+func TestNewStatic(t *testing.T) {
+	tt := []struct {
+		name      string
+		rates     map[string]types.Decimal
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "create static provider successfully",
+			rates:     map[string]types.Decimal{"BRL/USD": "0.1875", "USD/JPY": "149.37"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:  "create static provider with invalid pair",
+			rates: map[string]types.Decimal{"BRLUSD": "0.1875"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "invalid currency pair 'BRLUSD'")
+			},
+		},
+		{
+			name:  "create static provider with negative rate",
+			rates: map[string]types.Decimal{"BRL/USD": "-0.1875"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "the BRL/USD exchange rate '-0.1875' must be positive")
+			},
+		},
+		{
+			name:  "create static provider with too precise rate",
+			rates: map[string]types.Decimal{"BRL/USD": "0.1234567890123"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "the BRL/USD exchange rate '0.1234567890123' exceeds 12 fraction digits")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fx.NewStatic(tc.rates)
+			tc.assertErr(t, err)
+		})
+	}
+}
+
+func TestNewStaticFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := ioutil.WriteFile(path, []byte(`{"USD/BRL": "5.3312", "EUR/USD": 1.0842}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := fx.NewStaticFromFile(path)
+	testutil.AssertNoErr(t, err)
+	rate, err := p.Rate(context.Background(), types.EUR, types.USD)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "rate", types.Decimal("1.0842"), rate)
+
+	_, err = fx.NewStaticFromFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil {
+		t.Error("expected error opening a missing rates file")
+	}
+}
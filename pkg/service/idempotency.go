@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// Idempotency exposes the business operations available to entity.IdempotencyKey type
+type Idempotency interface {
+	Begin(ctx context.Context, scope string, key string, requestHash string) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, e entity.IdempotencyKey) error
+	Release(ctx context.Context, scope string, key string) error
+}
+
+type idempotency struct {
+	idempotencyRepository *repository.Idempotency
+	idempotencyValidator  *validation.Idempotency
+	ttl                   time.Duration
+}
+
+var _ Idempotency = (*idempotency)(nil)
+
+// NewIdempotency returns a value responsible for managing entity.IdempotencyKey integrity.
+// Keys expire after ttl, from then on they can be reused for a different request
+func NewIdempotency(idempotencyRepository *repository.Idempotency, ttl time.Duration) Idempotency {
+	return &idempotency{
+		idempotencyRepository: idempotencyRepository,
+		idempotencyValidator:  &validation.Idempotency{},
+		ttl:                   ttl,
+	}
+}
+
+// Begin reserves the key within the scope for the request identified by requestHash.
+// It returns nil when the request must be processed, or the stored entity.IdempotencyKey when the request is a retry
+// whose response must be replayed. Reusing a key with a different request or while it's pending yields a types.ConflictErr
+func (s *idempotency) Begin(ctx context.Context, scope string, key string, requestHash string) (*entity.IdempotencyKey, error) {
+	if err := s.idempotencyValidator.Key(key); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	e := entity.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+	err := (*s.idempotencyRepository).Create(ctx, e)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.ConflictErr {
+		return s.resume(ctx, e)
+	}
+	if err != nil {
+		log.Info().Caller().Err(err).Str("scope", scope).Str("key", key).Msg("unable to reserve the idempotency key")
+		return nil, err
+	}
+	return nil, nil
+}
+
+// resume handles a key that was already reserved, taking it over once it has expired
+func (s *idempotency) resume(ctx context.Context, e entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	stored, err := (*s.idempotencyRepository).Get(ctx, e.Scope, e.Key)
+	if err != nil {
+		log.Info().Caller().Err(err).Str("scope", e.Scope).Str("key", e.Key).Msg("unable to get the idempotency key")
+		return nil, err
+	}
+	if stored.ExpiresAt.Before(e.CreatedAt) {
+		if err = (*s.idempotencyRepository).Delete(ctx, e.Scope, e.Key); err != nil {
+			log.Info().Caller().Err(err).Str("scope", e.Scope).Str("key", e.Key).Msg("unable to delete the expired idempotency key")
+			return nil, err
+		}
+		if err = (*s.idempotencyRepository).Create(ctx, e); err != nil {
+			log.Info().Caller().Err(err).Str("scope", e.Scope).Str("key", e.Key).Msg("unable to reserve the idempotency key")
+			return nil, err
+		}
+		return nil, nil
+	}
+	if stored.RequestHash != e.RequestHash {
+		return nil, types.NewErr(types.ConflictErr, fmt.Sprintf("idempotency key '%s' was already used with a different request", e.Key), nil)
+	}
+	if !stored.Completed() {
+		return nil, types.NewErr(types.ConflictErr, fmt.Sprintf("a request with idempotency key '%s' is still being processed", e.Key), nil)
+	}
+	return &stored, nil
+}
+
+// Complete stores the response of the request that reserved the key
+func (s *idempotency) Complete(ctx context.Context, e entity.IdempotencyKey) error {
+	if err := (*s.idempotencyRepository).Update(ctx, e); err != nil {
+		log.Error().Caller().Err(err).Str("scope", e.Scope).Str("key", e.Key).Int("status", e.StatusCode).Msg("unable to store the idempotent response")
+		return err
+	}
+	return nil
+}
+
+// Release drops a pending key so that the request can be retried, as when it failed unexpectedly
+func (s *idempotency) Release(ctx context.Context, scope string, key string) error {
+	if err := (*s.idempotencyRepository).Delete(ctx, scope, key); err != nil {
+		log.Error().Caller().Err(err).Str("scope", scope).Str("key", key).Msg("unable to release the idempotency key")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestIdempotencyServiceBegin(t *testing.T) {
+	conflict := func(c context.Context, e entity.IdempotencyKey) error {
+		return types.NewErr(types.ConflictErr, "idempotency key already exists", nil)
+	}
+	stored := func(hash string, status int, expiresAt time.Time) func(context.Context, string, string) (entity.IdempotencyKey, error) {
+		return func(c context.Context, scope, key string) (entity.IdempotencyKey, error) {
+			return entity.IdempotencyKey{Scope: scope, Key: key, RequestHash: hash, StatusCode: status, ExpiresAt: expiresAt}, nil
+		}
+	}
+	tt := []struct {
+		name      string
+		key       string
+		repo      func() repository.Idempotency
+		replayed  bool
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "begin request with new key",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: func(c context.Context, e entity.IdempotencyKey) error {
+						testutil.AssertEq(t, "scope", "POST /transfers 1", e.Scope)
+						testutil.AssertEq(t, "request hash", "h1", e.RequestHash)
+						testutil.AssertEq(t, "ttl", time.Hour, e.ExpiresAt.Sub(e.CreatedAt))
+						return nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "begin retried request of completed key",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: conflict,
+					ExpectGet:    stored("h1", 201, time.Now().Add(time.Minute)),
+				}
+			},
+			replayed:  true,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "begin request reusing key with different payload",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: conflict,
+					ExpectGet:    stored("h2", 201, time.Now().Add(time.Minute)),
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "idempotency key 'k1' was already used with a different request")
+			},
+		},
+		{
+			name: "begin request of pending key",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: conflict,
+					ExpectGet:    stored("h1", 0, time.Now().Add(time.Minute)),
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "a request with idempotency key 'k1' is still being processed")
+			},
+		},
+		{
+			name: "begin request of expired key",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				created := false
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: func(c context.Context, e entity.IdempotencyKey) error {
+						if !created {
+							created = true
+							return conflict(c, e)
+						}
+						return nil
+					},
+					ExpectGet: stored("h2", 201, time.Now().Add(-time.Minute)),
+					ExpectDelete: func(c context.Context, scope, key string) error {
+						testutil.AssertEq(t, "key", "k1", key)
+						return nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "begin request with too long key",
+			key:  strings.Repeat("k", 129),
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'Idempotency-Key' must have at most 128 characters")
+			},
+		},
+		{
+			name: "begin request with repository error",
+			key:  "k1",
+			repo: func() repository.Idempotency {
+				return &testutil.IdempotencyRepoMock{
+					ExpectCreate: func(c context.Context, e entity.IdempotencyKey) error {
+						return types.NewErr(types.InsertStmtErr, "exec idempotency key insert stmt", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InsertStmtErr, err, "exec idempotency key insert stmt")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := tc.repo()
+			s := service.NewIdempotency(&repo, time.Hour)
+			e, err := s.Begin(context.Background(), "POST /transfers 1", tc.key, "h1")
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "replayed", tc.replayed, e != nil)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// LoginPolicy configures how failed logins are throttled
+type LoginPolicy struct {
+	MaxAttempts int           // MaxAttempts is the number of consecutive failures that locks a CPF out
+	Backoff     time.Duration // Backoff is the delay imposed after the first failure, doubled at every following one
+	Lockout     time.Duration // Lockout is how long a CPF stays locked out, which also caps the backoff
+}
+
+// LoginGuard exposes the business operations that protect the login from brute-force attacks.
+// Failures are counted per CPF and per client IP, each one delaying the next attempt exponentially,
+// and a CPF that fails too many times in a row is locked out for a while
+type LoginGuard interface {
+	Check(ctx context.Context, cpf string, ip string) error
+	Fail(ctx context.Context, cpf string, ip string) error
+	Succeed(ctx context.Context, cpf string, ip string) error
+	Unlock(ctx context.Context, account int64) error
+}
+
+type loginGuard struct {
+	loginAttemptRepository *repository.LoginAttempt
+	accountRepository      *repository.Account
+	policy                 LoginPolicy
+}
+
+var _ LoginGuard = (*loginGuard)(nil)
+
+// NewLoginGuard returns a value responsible for managing entity.LoginAttempt integrity according to the given policy
+func NewLoginGuard(loginAttemptRepository *repository.LoginAttempt, accountRepository *repository.Account, policy LoginPolicy) LoginGuard {
+	return &loginGuard{
+		loginAttemptRepository: loginAttemptRepository,
+		accountRepository:      accountRepository,
+		policy:                 policy,
+	}
+}
+
+// cpfKey and ipKey identify the counters of each kind of client, whereas stepUpKey identifies the counter
+// of the second factor codes presented by an account to transfer amounts above the step-up amount
+func cpfKey(cpf string) string       { return "cpf:" + cpf }
+func ipKey(ip string) string         { return "ip:" + ip }
+func stepUpKey(account int64) string { return "step-up:" + strconv.FormatInt(account, 10) }
+
+// keys returns the counters the login attempt is accounted to, the ip is ignored when unknown
+func keys(cpf string, ip string) []string {
+	if ip == "" {
+		return []string{cpfKey(cpf)}
+	}
+	return []string{cpfKey(cpf), ipKey(ip)}
+}
+
+// Check returns a types.LockedErr when the CPF is locked out or when either the CPF or the IP must still wait before trying again
+func (g *loginGuard) Check(ctx context.Context, cpf string, ip string) error {
+	now := time.Now()
+	for _, key := range keys(cpf, ip) {
+		attempt, err := (*g.loginAttemptRepository).Get(ctx, key)
+		if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+			continue
+		}
+		if err != nil {
+			log.Error().Caller().Err(err).Str("key", key).Msg("unable to get the login attempt")
+			return err
+		}
+		if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+			return types.NewErr(types.LockedErr, fmt.Sprintf("too many failed logins, locked out until %s", attempt.LockedUntil.UTC().Format(time.RFC3339)), nil)
+		}
+		if attempt.Failures == 0 {
+			continue
+		}
+		if wait := attempt.LastFailureAt.Add(g.backoff(attempt.Failures)).Sub(now); wait > 0 {
+			return types.NewErr(types.LockedErr, fmt.Sprintf("too many failed logins, retry in %d seconds", int64(math.Ceil(wait.Seconds()))), nil)
+		}
+	}
+	return nil
+}
+
+// backoff returns the delay imposed after the given number of consecutive failures
+func (g *loginGuard) backoff(failures int) time.Duration {
+	d := g.policy.Backoff
+	for i := 1; i < failures && d < g.policy.Lockout; i++ {
+		d *= 2
+	}
+	if d > g.policy.Lockout {
+		return g.policy.Lockout
+	}
+	return d
+}
+
+// Fail counts a failed login, locking the CPF out once it reaches the policy max attempts.
+// Failures older than the lockout duration are forgotten
+func (g *loginGuard) Fail(ctx context.Context, cpf string, ip string) error {
+	now := time.Now()
+	for _, key := range keys(cpf, ip) {
+		attempt, err := (*g.loginAttemptRepository).Increment(ctx, key, now, now.Add(-g.policy.Lockout))
+		if err != nil {
+			log.Error().Caller().Err(err).Str("key", key).Msg("unable to count the failed login")
+			return err
+		}
+		// Only the CPF gets locked out, as an IP may be shared by several clients
+		if key != cpfKey(cpf) || attempt.Failures < g.policy.MaxAttempts {
+			continue
+		}
+		if err = (*g.loginAttemptRepository).Lock(ctx, key, now.Add(g.policy.Lockout)); err != nil {
+			log.Error().Caller().Err(err).Str("key", key).Msg("unable to lock the cpf out")
+			return err
+		}
+		log.Warn().Str("cpf", cpf).Str("ip", ip).Int("failures", attempt.Failures).Msg("cpf locked out after too many failed logins")
+	}
+	return nil
+}
+
+// Succeed resets the counters of the CPF and the IP
+func (g *loginGuard) Succeed(ctx context.Context, cpf string, ip string) error {
+	for _, key := range keys(cpf, ip) {
+		if err := (*g.loginAttemptRepository).Delete(ctx, key); err != nil {
+			log.Error().Caller().Err(err).Str("key", key).Msg("unable to reset the login attempts")
+			return err
+		}
+	}
+	return nil
+}
+
+// Unlock lifts the lockout of the account stored at id and resets its counters, including the step-up one
+func (g *loginGuard) Unlock(ctx context.Context, account int64) error {
+	e, err := (*g.accountRepository).Get(ctx, account)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", account).Msg("unable to get the account to unlock")
+		return err
+	}
+	for _, key := range []string{cpfKey(e.CPF), stepUpKey(account)} {
+		if err = (*g.loginAttemptRepository).Delete(ctx, key); err != nil {
+			log.Error().Caller().Err(err).Int64("id", account).Str("key", key).Msg("unable to unlock the account")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+var loginPolicy = service.LoginPolicy{MaxAttempts: 3, Backoff: time.Second, Lockout: 15 * time.Minute}
+
+func TestLoginGuardServiceCheck(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+	tt := []struct {
+		name      string
+		attempts  map[string]entity.LoginAttempt
+		ip        string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "check login with no previous failures",
+			ip:        "10.0.0.1",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "check login after the backoff",
+			ip:   "10.0.0.1",
+			attempts: map[string]entity.LoginAttempt{
+				"cpf:41112075020": {Failures: 2, LastFailureAt: time.Now().Add(-3 * time.Second)},
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "check login within the cpf backoff",
+			ip:   "10.0.0.1",
+			attempts: map[string]entity.LoginAttempt{
+				"cpf:41112075020": {Failures: 3, LastFailureAt: time.Now()},
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.LockedErr, err, "too many failed logins, retry in 4 seconds")
+			},
+		},
+		{
+			name: "check login within the ip backoff",
+			ip:   "10.0.0.1",
+			attempts: map[string]entity.LoginAttempt{
+				"ip:10.0.0.1": {Failures: 30, LastFailureAt: time.Now()},
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.LockedErr, err, "too many failed logins, retry in 900 seconds")
+			},
+		},
+		{
+			name: "check login of a locked out cpf",
+			attempts: map[string]entity.LoginAttempt{
+				"cpf:41112075020": {LockedUntil: &lockedUntil},
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.LockedErr, err, "too many failed logins, locked out until "+lockedUntil.UTC().Format(time.RFC3339))
+			},
+		},
+		{
+			name: "check login of an expired lockout",
+			attempts: map[string]entity.LoginAttempt{
+				"cpf:41112075020": {LockedUntil: &time.Time{}},
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{
+				ExpectGet: func(ctx context.Context, key string) (entity.LoginAttempt, error) {
+					if attempt, ok := tc.attempts[key]; ok {
+						return attempt, nil
+					}
+					return entity.LoginAttempt{}, types.NewErr(types.EmptyResultErr, "no result getting login attempt", nil)
+				},
+			}
+			var accountRepo repository.Account = &testutil.AccountRepoMock{}
+			g := service.NewLoginGuard(&repo, &accountRepo, loginPolicy)
+			tc.assertErr(t, g.Check(context.Background(), "41112075020", tc.ip))
+		})
+	}
+}
+
+func TestLoginGuardServiceFail(t *testing.T) {
+	tt := []struct {
+		name     string
+		failures map[string]int
+		locked   string
+	}{
+		{
+			name:     "fail login below the max attempts",
+			failures: map[string]int{"cpf:41112075020": 2, "ip:10.0.0.1": 2},
+		},
+		{
+			name:     "fail login reaching the max attempts",
+			failures: map[string]int{"cpf:41112075020": 3, "ip:10.0.0.1": 3},
+			locked:   "cpf:41112075020",
+		},
+		{
+			name:     "fail login of an ip beyond the max attempts",
+			failures: map[string]int{"cpf:41112075020": 1, "ip:10.0.0.1": 10},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var incremented, locked []string
+			var repo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{
+				ExpectIncrement: func(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error) {
+					testutil.AssertEq(t, "window", loginPolicy.Lockout, at.Sub(since))
+					incremented = append(incremented, key)
+					return entity.LoginAttempt{Key: key, Failures: tc.failures[key], LastFailureAt: at}, nil
+				},
+				ExpectLock: func(ctx context.Context, key string, until time.Time) error {
+					locked = append(locked, key)
+					return nil
+				},
+			}
+			var accountRepo repository.Account = &testutil.AccountRepoMock{}
+			g := service.NewLoginGuard(&repo, &accountRepo, loginPolicy)
+			testutil.AssertNoErr(t, g.Fail(context.Background(), "41112075020", "10.0.0.1"))
+			testutil.AssertEq(t, "incremented", "cpf:41112075020 ip:10.0.0.1", strings.Join(incremented, " "))
+			testutil.AssertEq(t, "locked", tc.locked, strings.Join(locked, " "))
+		})
+	}
+}
+
+func TestLoginGuardServiceReset(t *testing.T) {
+	var deleted []string
+	var repo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{
+		ExpectDelete: func(ctx context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		},
+	}
+	var accountRepo repository.Account = &testutil.AccountRepoMock{
+		ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+			if id != 1 {
+				return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account", nil)
+			}
+			return testutil.NewEntityAccount(id, "Sousa", "41112075020", "pw", 0), nil
+		},
+	}
+	g := service.NewLoginGuard(&repo, &accountRepo, loginPolicy)
+
+	testutil.AssertNoErr(t, g.Succeed(context.Background(), "41112075020", "10.0.0.1"))
+	testutil.AssertEq(t, "deleted on success", "cpf:41112075020 ip:10.0.0.1", strings.Join(deleted, " "))
+
+	deleted = nil
+	testutil.AssertNoErr(t, g.Unlock(context.Background(), 1))
+	testutil.AssertEq(t, "deleted on unlock", "cpf:41112075020 step-up:1", strings.Join(deleted, " "))
+
+	err := g.Unlock(context.Background(), 2)
+	testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting account")
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+type file struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ Notifier = (*file)(nil)
+
+// NewFile creates a Notifier that appends the messages to the file at path, one json object per line
+func NewFile(path string) Notifier {
+	return &file{path: path}
+}
+
+// Notify appends the message to the file, creating it when missing
+func (n *file) Notify(ctx context.Context, m Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return types.NewErr(types.InternalErr, "unable to encode the notification", err)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return types.NewErr(types.InternalErr, fmt.Sprintf("unable to open the notification file '%s'", n.path), err)
+	}
+	defer f.Close()
+	if _, err = f.Write(append(b, '\n')); err != nil {
+		return types.NewErr(types.InternalErr, fmt.Sprintf("unable to write to the notification file '%s'", n.path), err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+type logNotifier struct{}
+
+var _ Notifier = (*logNotifier)(nil)
+
+// NewLog creates a Notifier that writes the messages to the application log, meant for local use only
+func NewLog() Notifier {
+	return &logNotifier{}
+}
+
+// Notify logs the whole message, text included
+func (n *logNotifier) Notify(ctx context.Context, m Message) error {
+	log.Info().
+		Int64("account_id", m.AccountID).
+		Str("cpf", m.CPF).
+		Str("subject", m.Subject).
+		Str("text", m.Text).
+		Msg("notification")
+	return nil
+}
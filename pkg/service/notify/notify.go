@@ -0,0 +1,40 @@
+// Package notify delivers messages addressed to account holders, such as secret reset tokens
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// Message is the content delivered to the holder of an account
+type Message struct {
+	AccountID int64     `json:"account_id"`
+	CPF       string    `json:"cpf"`
+	Subject   string    `json:"subject"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Notifier delivers messages to account holders through a channel of its own
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// New creates the Notifier described by the given config
+func New(cfg *env.NotifyConfig) (Notifier, error) {
+	switch cfg.Notifier {
+	case "log":
+		return NewLog(), nil
+	case "file":
+		if cfg.File == "" {
+			return nil, types.NewErr(types.InternalErr, "the file notifier requires a file path", nil)
+		}
+		return NewFile(cfg.File), nil
+	default:
+		return nil, types.NewErr(types.InternalErr, fmt.Sprintf("unknown notifier '%s'", cfg.Notifier), nil)
+	}
+}
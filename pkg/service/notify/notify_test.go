@@ -0,0 +1,77 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/env"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/notify"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestNew(t *testing.T) {
+	tt := []struct {
+		name      string
+		cfg       env.NotifyConfig
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "create log notifier",
+			cfg:       env.NotifyConfig{Notifier: "log"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "create file notifier",
+			cfg:       env.NotifyConfig{Notifier: "file", File: "notifications.jsonl"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "create file notifier without path",
+			cfg:  env.NotifyConfig{Notifier: "file"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "the file notifier requires a file path")
+			},
+		},
+		{
+			name: "create unknown notifier",
+			cfg:  env.NotifyConfig{Notifier: "sms"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unknown notifier 'sms'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := notify.New(&tc.cfg)
+			tc.assertErr(t, err)
+		})
+	}
+}
+
+func TestFileNotify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify")
+	testutil.AssertNoErr(t, err)
+	path := filepath.Join(dir, "notifications.jsonl")
+	n := notify.NewFile(path)
+
+	for _, text := range []string{"first", "second"} {
+		err = n.Notify(context.Background(), notify.Message{AccountID: 1, CPF: "41112075020", Subject: "secret reset", Text: text, CreatedAt: time.Now()})
+		testutil.AssertNoErr(t, err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	testutil.AssertNoErr(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	testutil.AssertEq(t, "lines", 2, len(lines))
+	var m notify.Message
+	testutil.AssertNoErr(t, json.Unmarshal([]byte(lines[1]), &m))
+	testutil.AssertEq(t, "account id", int64(1), m.AccountID)
+	testutil.AssertEq(t, "text", "second", m.Text)
+}
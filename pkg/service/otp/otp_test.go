@@ -0,0 +1,68 @@
+package otp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/service/otp"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// secret is the base32 encoding of the RFC 6238 SHA1 test key, "12345678901234567890"
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	tt := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.code, func(t *testing.T) {
+			code, err := otp.Code(secret, otp.Step(time.Unix(tc.unix, 0)))
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "code", tc.code, code)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	tt := []struct {
+		name string
+		code string
+		at   time.Time
+		ok   bool
+		step int64
+	}{
+		{name: "validate current code", code: "081804", at: now, ok: true, step: otp.Step(now)},
+		{name: "validate previous code", code: "081804", at: now.Add(otp.Period), ok: true, step: otp.Step(now)},
+		{name: "validate expired code", code: "081804", at: now.Add(2 * otp.Period)},
+		{name: "validate wrong code", code: "000000", at: now},
+		{name: "validate malformed code", code: "81804", at: now},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok, err := otp.Validate(secret, tc.code, tc.at)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "ok", tc.ok, ok)
+			testutil.AssertEq(t, "step", tc.step, step)
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	s, err := otp.NewSecret()
+	testutil.AssertNoErr(t, err)
+	testutil.AssertEq(t, "secret length", 32, len(s))
+	_, err = otp.Code(s, 1)
+	testutil.AssertNoErr(t, err)
+}
+
+func TestURI(t *testing.T) {
+	testutil.AssertEq(t, "uri", "otpauth://totp/stn-accounts:1?algorithm=SHA1&digits=6&issuer=stn-accounts&period=30&secret="+secret, otp.URI("stn-accounts", "1", secret))
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// ScheduledTransfer represents the business operations available to entity.ScheduledTransfer type
+type ScheduledTransfer interface {
+	Schedule(ctx context.Context, origin int64, d dto.TransferCreation) (dto.ScheduledTransferView, error)
+	Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]dto.ScheduledTransferView, error)
+	Get(ctx context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error)
+	Cancel(ctx context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error)
+	ExecuteDue(ctx context.Context) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type scheduledTransfer struct {
+	txr                         *repository.Transactioner
+	scheduledTransferRepository *repository.ScheduledTransfer
+	accountRepository           *repository.Account
+	transferService             *Transfer
+	transferValidator           *validation.Transfer
+}
+
+var _ ScheduledTransfer = (*scheduledTransfer)(nil)
+
+// NewScheduledTransfer returns a value responsible for scheduling transfers and executing them once due.
+// Scheduled transfers are executed through the given Transfer service, within the tx that claims them
+func NewScheduledTransfer(txr *repository.Transactioner, scheduledTransferRepository *repository.ScheduledTransfer, accountRepository *repository.Account, transferService *Transfer) ScheduledTransfer {
+	return &scheduledTransfer{
+		txr:                         txr,
+		scheduledTransferRepository: scheduledTransferRepository,
+		accountRepository:           accountRepository,
+		transferService:             transferService,
+		transferValidator: &validation.Transfer{
+			AccountRepository: accountRepository,
+		},
+	}
+}
+
+// Schedule validates and persists a pending entity.ScheduledTransfer from the origin account to be executed at d.ExecuteAt
+func (s *scheduledTransfer) Schedule(ctx context.Context, origin int64, transferCreation dto.TransferCreation) (view dto.ScheduledTransferView, err error) {
+	now := time.Now()
+	if err = s.transferValidator.Scheduling(ctx, origin, transferCreation, now); err != nil {
+		return view, err
+	}
+	originAccount, err := (*s.accountRepository).Get(ctx, origin)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("account_origin_id", origin).Msg("unable to get the origin account")
+		return view, err
+	}
+	amount, err := originAccount.Currency.Currency(transferCreation.Amount)
+	if err != nil {
+		return view, err
+	}
+	e := entity.ScheduledTransfer{
+		Origin:      origin,
+		Destination: transferCreation.Destination,
+		Amount:      amount,
+		Currency:    originAccount.Currency,
+		Convert:     transferCreation.Convert,
+		ExecuteAt:   transferCreation.ExecuteAt.UTC().Truncate(time.Second),
+		Status:      entity.SchedulePending,
+		CreatedAt:   now,
+	}
+	if e.ID, err = (*s.scheduledTransferRepository).Create(ctx, e); err != nil {
+		log.Error().Caller().Err(err).
+			Int64("account_origin_id", origin).
+			Int64("account_destination_id", transferCreation.Destination).
+			Msg("unable to schedule the transfer")
+		return view, err
+	}
+	return dto.NewScheduledTransferView(e), nil
+}
+
+// Fetch returns the transfers scheduled by the origin account sorted by execution date, narrowed by status unless it's empty
+func (s *scheduledTransfer) Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]dto.ScheduledTransferView, error) {
+	transfers, err := (*s.scheduledTransferRepository).Fetch(ctx, origin, status)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_origin_id", origin).Msg("unable to fetch the scheduled transfers")
+		return nil, err
+	}
+	views := make([]dto.ScheduledTransferView, 0, len(transfers))
+	for _, e := range transfers {
+		views = append(views, dto.NewScheduledTransferView(e))
+	}
+	return views, nil
+}
+
+// Get returns the entity.ScheduledTransfer stored at id as long as the requester is allowed to read its origin account.
+// Transfers scheduled by other accounts are reported as not found so that their existence isn't disclosed
+func (s *scheduledTransfer) Get(ctx context.Context, requester dto.Requester, id int64) (view dto.ScheduledTransferView, err error) {
+	e, err := s.get(ctx, requester, id)
+	if err != nil {
+		return view, err
+	}
+	return dto.NewScheduledTransferView(e), nil
+}
+
+// Cancel cancels the pending entity.ScheduledTransfer stored at id, which only its origin account owner is allowed to.
+// Transfers no longer pending, including the ones being executed at the same time, can't be canceled
+func (s *scheduledTransfer) Cancel(ctx context.Context, requester dto.Requester, id int64) (view dto.ScheduledTransferView, err error) {
+	e, err := s.get(ctx, requester, id)
+	if err != nil {
+		return view, err
+	}
+	if requester.ID != e.Origin {
+		return view, types.NewErr(types.AuthorizationErr, fmt.Sprintf("account '%d' isn't allowed to cancel scheduled transfer '%d'", requester.ID, id), nil)
+	}
+	if e.Status != entity.SchedulePending {
+		return view, types.NewErr(types.ConflictErr, fmt.Sprintf("scheduled transfer '%d' is %s and can't be canceled", id, e.Status), nil)
+	}
+	now := time.Now()
+	e.Status = entity.ScheduleCanceled
+	e.FinishedAt = &now
+	if err = (*s.scheduledTransferRepository).Finish(ctx, e); err != nil {
+		if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+			return view, types.NewErr(types.ConflictErr, fmt.Sprintf("scheduled transfer '%d' is no longer pending", id), err)
+		}
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to cancel the scheduled transfer")
+		return view, err
+	}
+	return dto.NewScheduledTransferView(e), nil
+}
+
+// ExecuteDue executes the pending transfers due by now one at a time, returning how many of them were either executed or failed.
+// It stops at the first error that isn't a refusal of the transfer, leaving the transfer pending to be retried
+func (s *scheduledTransfer) ExecuteDue(ctx context.Context) (processed int, err error) {
+	for ctx.Err() == nil {
+		claimed, err := s.executeNext(ctx, time.Now())
+		if err != nil || !claimed {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, ctx.Err()
+}
+
+// Run executes the due transfers every interval until ctx is done
+func (s *scheduledTransfer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := s.ExecuteDue(ctx)
+			if err != nil {
+				log.Error().Caller().Err(err).Int("processed", processed).Msg("unable to execute the due scheduled transfers")
+			} else if processed > 0 {
+				log.Info().Int("processed", processed).Msg("due scheduled transfers executed")
+			}
+		}
+	}
+}
+
+// executeNext claims the earliest transfer due by now and executes it within the same tx, so that the claim is released
+// along with the tx and the transfer is never executed twice, whether by this or another replica.
+// It tells whether there was a transfer due
+func (s *scheduledTransfer) executeNext(ctx context.Context, now time.Time) (claimed bool, err error) {
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		e, err := (*s.scheduledTransferRepository).ClaimDue(txCtx, now)
+		if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		claimed = true
+		view, err := (*s.transferService).Create(txCtx, e.Origin, dto.TransferCreation{
+			Destination: e.Destination,
+			Amount:      e.Currency.Decimal(e.Amount),
+			Convert:     e.Convert,
+		})
+		if err == nil {
+			e.Status = entity.ScheduleExecuted
+			e.TransferID = &view.ID
+		} else if reason, refused := failureReason(err); refused {
+			e.Status = entity.ScheduleFailed
+			e.FailureReason = reason
+		} else {
+			return err
+		}
+		finishedAt := time.Now()
+		e.FinishedAt = &finishedAt
+		if err = (*s.scheduledTransferRepository).Finish(txCtx, e); err != nil {
+			return err
+		}
+		log.Info().
+			Int64("id", e.ID).
+			Int64("account_origin_id", e.Origin).
+			Str("status", string(e.Status)).
+			Str("failure_reason", e.FailureReason).
+			Msg("scheduled transfer executed")
+		return nil
+	})
+	return claimed, err
+}
+
+// failureReason tells whether err refused the execution of a scheduled transfer, such as for insufficient funds or a closed account,
+// as opposed to an error worth retrying. The reason is the error message, truncated to entity.ScheduleFailureSize
+func failureReason(err error) (string, bool) {
+	customErr, ok := err.(*types.Err)
+	if !ok {
+		return "", false
+	}
+	switch customErr.Code {
+	case types.ValidationErr, types.EmptyResultErr, types.NotFoundErr, types.ConflictErr:
+		reason := []rune(customErr.Msg)
+		if len(reason) > entity.ScheduleFailureSize {
+			reason = reason[:entity.ScheduleFailureSize]
+		}
+		return string(reason), true
+	}
+	return "", false
+}
+
+func (s *scheduledTransfer) get(ctx context.Context, requester dto.Requester, id int64) (entity.ScheduledTransfer, error) {
+	e, err := (*s.scheduledTransferRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the scheduled transfer")
+		return e, err
+	}
+	if !requester.CanRead(e.Origin) {
+		return e, types.NewErr(types.NotFoundErr, fmt.Sprintf("scheduled transfer '%d' was not found", id), nil)
+	}
+	return e, nil
+}
@@ -0,0 +1,258 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// newScheduledTransfer returns a pending transfer of 10.00 from account 1 to account 2 stored at id
+func newScheduledTransfer(id int64) entity.ScheduledTransfer {
+	return entity.ScheduledTransfer{
+		ID:          id,
+		Origin:      1,
+		Destination: 2,
+		Amount:      types.NewCurrency(10),
+		Currency:    types.BRL,
+		ExecuteAt:   time.Now().Add(-time.Minute).UTC().Truncate(time.Second),
+		Status:      entity.SchedulePending,
+		CreatedAt:   time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+	}
+}
+
+func TestScheduledTransferServiceSchedule(t *testing.T) {
+	tt := []struct {
+		name      string
+		d         dto.TransferCreation
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "schedule transfer successfully",
+			d:         dto.TransferCreation{Destination: 2, Amount: "10.50", ExecuteAt: time.Now().Add(24 * time.Hour)},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "schedule transfer in the past",
+			d:    dto.TransferCreation{Destination: 2, Amount: "10.50", ExecuteAt: time.Now().Add(-time.Minute)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'execute_at' must be in the future")
+			},
+		},
+		{
+			name: "schedule transfer to the origin",
+			d:    dto.TransferCreation{Destination: 1, Amount: "10.50", ExecuteAt: time.Now().Add(time.Hour)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "fields 'origin id' and 'destination id' can't be the same")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.ScheduledTransfer = &testutil.ScheduledTransferRepoMock{
+				ExpectCreate: func(ctx context.Context, e entity.ScheduledTransfer) (int64, error) {
+					testutil.AssertEq(t, "origin", int64(1), e.Origin)
+					testutil.AssertEq(t, "amount", types.Currency(1050), e.Amount)
+					testutil.AssertEq(t, "status", entity.SchedulePending, e.Status)
+					testutil.AssertEq(t, "execute at", tc.d.ExecuteAt.UTC().Truncate(time.Second), e.ExecuteAt)
+					return 4, nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					// The origin balance isn't verified until the transfer is executed
+					return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 0), nil
+				},
+			}
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := service.NewScheduledTransfer(&txr, &repo, &accRepo, &transferSrv)
+			view, err := s.Schedule(context.Background(), 1, tc.d)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "id", int64(4), view.ID)
+				testutil.AssertEq(t, "amount", types.Decimal("10.50"), view.Amount)
+			}
+		})
+	}
+}
+
+func TestScheduledTransferServiceCancel(t *testing.T) {
+	executed := newScheduledTransfer(3)
+	executed.Status = entity.ScheduleExecuted
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		stored    entity.ScheduledTransfer
+		finishErr error
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "cancel scheduled transfer successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    newScheduledTransfer(3),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "cancel scheduled transfer of another account",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			stored:    newScheduledTransfer(3),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "scheduled transfer '3' was not found")
+			},
+		},
+		{
+			name:      "cancel scheduled transfer of another account as support",
+			requester: dto.Requester{ID: 5, Role: entity.RoleSupport},
+			stored:    newScheduledTransfer(3),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '5' isn't allowed to cancel scheduled transfer '3'")
+			},
+		},
+		{
+			name:      "cancel executed scheduled transfer",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    executed,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "scheduled transfer '3' is executed and can't be canceled")
+			},
+		},
+		{
+			name:      "cancel scheduled transfer while it's executed",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    newScheduledTransfer(3),
+			finishErr: types.NewErr(types.NoRowAffectedErr, "no rows affected by the finish scheduled transfer stmt", nil),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "scheduled transfer '3' is no longer pending")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.ScheduledTransfer = &testutil.ScheduledTransferRepoMock{
+				ExpectGet: func(ctx context.Context, id int64) (entity.ScheduledTransfer, error) {
+					return tc.stored, nil
+				},
+				ExpectFinish: func(ctx context.Context, e entity.ScheduledTransfer) error {
+					testutil.AssertEq(t, "status", entity.ScheduleCanceled, e.Status)
+					testutil.AssertNotDefault(t, "finished at", e.FinishedAt)
+					return tc.finishErr
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := service.NewScheduledTransfer(&txr, &repo, &accRepo, &transferSrv)
+			view, err := s.Cancel(context.Background(), tc.requester, 3)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "status", entity.ScheduleCanceled, view.Status)
+			}
+		})
+	}
+}
+
+func TestScheduledTransferServiceExecuteDue(t *testing.T) {
+	tt := []struct {
+		name      string
+		due       []entity.ScheduledTransfer
+		create    func(int64) (dto.TransferView, error)
+		expected  int
+		outcome   entity.ScheduleStatus
+		reason    string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "execute due transfers successfully",
+			due:  []entity.ScheduledTransfer{newScheduledTransfer(3), newScheduledTransfer(4)},
+			create: func(origin int64) (dto.TransferView, error) {
+				return *testutil.NewTransferView(9, 2, 10), nil
+			},
+			expected:  2,
+			outcome:   entity.ScheduleExecuted,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due transfer without funds",
+			due:  []entity.ScheduledTransfer{newScheduledTransfer(3)},
+			create: func(origin int64) (dto.TransferView, error) {
+				return dto.TransferView{}, types.NewErr(types.ValidationErr, "the origin must have a balance greater than or equal to 10.00", nil)
+			},
+			expected:  1,
+			outcome:   entity.ScheduleFailed,
+			reason:    "the origin must have a balance greater than or equal to 10.00",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due transfer to a closed account",
+			due:  []entity.ScheduledTransfer{newScheduledTransfer(3)},
+			create: func(origin int64) (dto.TransferView, error) {
+				return dto.TransferView{}, types.NewErr(types.ValidationErr, "the destination account is closed", nil)
+			},
+			expected:  1,
+			outcome:   entity.ScheduleFailed,
+			reason:    "the destination account is closed",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due transfer while the rate provider is unavailable",
+			due:  []entity.ScheduledTransfer{newScheduledTransfer(3), newScheduledTransfer(4)},
+			create: func(origin int64) (dto.TransferView, error) {
+				return dto.TransferView{}, types.NewErr(types.InternalErr, "unable to get the exchange rate", nil)
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unable to get the exchange rate")
+			},
+		},
+		{
+			name:      "execute without due transfers",
+			assertErr: testutil.AssertNoErr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			due := tc.due
+			finished := 0
+			var repo repository.ScheduledTransfer = &testutil.ScheduledTransferRepoMock{
+				ExpectClaimDue: func(ctx context.Context, now time.Time) (entity.ScheduledTransfer, error) {
+					if len(due) == 0 {
+						return entity.ScheduledTransfer{}, types.NewErr(types.EmptyResultErr, "no scheduled transfer is due", nil)
+					}
+					e := due[0]
+					due = due[1:]
+					return e, nil
+				},
+				ExpectFinish: func(ctx context.Context, e entity.ScheduledTransfer) error {
+					finished++
+					testutil.AssertEq(t, "status", tc.outcome, e.Status)
+					testutil.AssertEq(t, "failure reason", tc.reason, e.FailureReason)
+					testutil.AssertNotDefault(t, "finished at", e.FinishedAt)
+					if tc.outcome == entity.ScheduleExecuted {
+						testutil.AssertEq(t, "transfer id", int64(9), *e.TransferID)
+					}
+					return nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var transferSrv service.Transfer = &testutil.TransferServMock{
+				ExpectCreate: func(ctx context.Context, origin int64, d dto.TransferCreation) (dto.TransferView, error) {
+					testutil.AssertEq(t, "destination", int64(2), d.Destination)
+					testutil.AssertEq(t, "amount", types.Decimal("10.00"), d.Amount)
+					return tc.create(origin)
+				},
+			}
+			s := service.NewScheduledTransfer(&txr, &repo, &accRepo, &transferSrv)
+			processed, err := s.ExecuteDue(context.Background())
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "processed", tc.expected, processed)
+			testutil.AssertEq(t, "finished", tc.expected, finished)
+		})
+	}
+}
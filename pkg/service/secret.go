@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/notify"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SecretPolicy configures the strength required from new account secrets and how they're hashed
+type SecretPolicy struct {
+	Strength validation.SecretStrength // Strength is checked whenever a secret is set
+	Cost     int                       // Cost is the bcrypt cost, bcrypt.DefaultCost when zero
+}
+
+// hash returns the bcrypt hash of the secret
+func (p SecretPolicy) hash(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), p.Cost)
+	if err != nil {
+		log.Info().Caller().Err(err).Msg("unable to create the account secret hash")
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Secret exposes the business operations that replace the secret of an entity.Account, either by its owner
+// or through a single use reset token delivered to the account holder. Both end every session of the account
+type Secret interface {
+	Change(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error
+	RequestReset(ctx context.Context, cpf string) error
+	Reset(ctx context.Context, token string, secret string) error
+}
+
+type secret struct {
+	accountRepository          *repository.Account
+	secretResetTokenRepository *repository.SecretResetToken
+	refreshTokenRepository     *repository.RefreshToken
+	revokedTokenRepository     *repository.RevokedToken
+	notifier                   *notify.Notifier
+	accountValidator           *validation.Account
+	txr                        *repository.Transactioner
+	policy                     SecretPolicy
+	ttl                        time.Duration
+}
+
+var _ Secret = (*secret)(nil)
+
+// NewSecret returns a value responsible for managing the account secrets and entity.SecretResetToken integrity.
+// New secrets must comply with the given policy, and reset tokens are delivered by the given notifier and expire after ttl
+func NewSecret(txr *repository.Transactioner, accountRepository *repository.Account, secretResetTokenRepository *repository.SecretResetToken, refreshTokenRepository *repository.RefreshToken, revokedTokenRepository *repository.RevokedToken, notifier *notify.Notifier, policy SecretPolicy, ttl time.Duration) Secret {
+	return &secret{
+		accountRepository:          accountRepository,
+		secretResetTokenRepository: secretResetTokenRepository,
+		refreshTokenRepository:     refreshTokenRepository,
+		revokedTokenRepository:     revokedTokenRepository,
+		notifier:                   notifier,
+		txr:                        txr,
+		policy:                     policy,
+		ttl:                        ttl,
+		accountValidator: &validation.Account{
+			AccountRepository: accountRepository,
+			SecretStrength:    policy.Strength,
+		},
+	}
+}
+
+// Change replaces the secret of the account stored at id, which is only allowed to the account owner knowing the current secret
+func (s *secret) Change(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error {
+	if requester.ID != id {
+		return accountAccessErr(requester, id)
+	}
+	account, err := (*s.accountRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the account to change its secret")
+		return err
+	}
+	if err = s.accountValidator.SecretChange(current, secret, account); err != nil {
+		return err
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(account.Secret), []byte(current)); err != nil {
+		return types.NewErr(types.ValidationErr, "field 'current_secret' doesn't match the account's secret", nil)
+	}
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		return s.replace(txCtx, id, secret, time.Now())
+	})
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to change the account secret")
+		return err
+	}
+	return nil
+}
+
+// RequestReset issues a reset token to the account of the given cpf and delivers it to the account holder.
+// It tells nothing about whether the account exists, and issuing a new token invalidates the previous ones
+func (s *secret) RequestReset(ctx context.Context, cpf string) error {
+	if err := s.accountValidator.ResetRequest(cpf); err != nil {
+		return err
+	}
+	account, err := (*s.accountRepository).FindBy(ctx, cpf)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		log.Info().Str("cpf", cpf).Msg("secret reset requested for a nonexistent account")
+		return nil
+	}
+	if err != nil {
+		log.Info().Caller().Err(err).Str("cpf", cpf).Msg("unable to find the account entity via cpf")
+		return err
+	}
+	b, err := randomBytes(32)
+	if err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	now := time.Now()
+	e := entity.SecretResetToken{
+		Hash:      hashToken(token),
+		AccountID: account.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*s.secretResetTokenRepository).Invalidate(txCtx, account.ID, now); err != nil {
+			return err
+		}
+		if err := (*s.secretResetTokenRepository).Create(txCtx, e); err != nil {
+			return err
+		}
+		// Delivering within the transaction discards the token when the holder can't be notified
+		return (*s.notifier).Notify(txCtx, notify.Message{
+			AccountID: account.ID,
+			CPF:       account.CPF,
+			Subject:   "Account secret reset",
+			Text:      fmt.Sprintf("Use the token %s to set a new secret until %s", token, e.ExpiresAt.UTC().Format(time.RFC3339)),
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account.ID).Msg("unable to issue the secret reset token")
+		return err
+	}
+	return nil
+}
+
+// Reset replaces the secret of the account the given reset token was issued to, which is used up in the process
+func (s *secret) Reset(ctx context.Context, token string, secret string) error {
+	if token == "" {
+		return types.NewErr(types.ValidationErr, "field 'token' is required", nil)
+	}
+	current, err := (*s.secretResetTokenRepository).Get(ctx, hashToken(token))
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return types.NewErr(types.AuthenticationErr, "invalid secret reset token", nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to get the secret reset token")
+		return err
+	}
+	now := time.Now()
+	if current.UsedAt != nil {
+		return types.NewErr(types.AuthenticationErr, "the secret reset token was already used", nil)
+	}
+	if !current.ExpiresAt.After(now) {
+		return types.NewErr(types.AuthenticationErr, "expired secret reset token", nil)
+	}
+	account, err := (*s.accountRepository).Get(ctx, current.AccountID)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", current.AccountID).Msg("unable to get the account to reset its secret")
+		return err
+	}
+	if err = s.accountValidator.SecretReset(secret, account); err != nil {
+		return err
+	}
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*s.secretResetTokenRepository).MarkUsed(txCtx, current.Hash, now); err != nil {
+			return err
+		}
+		return s.replace(txCtx, current.AccountID, secret, now)
+	})
+	// A concurrent reset has just used the same token
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return types.NewErr(types.AuthenticationErr, "the secret reset token was already used", nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", current.AccountID).Msg("unable to reset the account secret")
+		return err
+	}
+	return nil
+}
+
+// replace stores the hash of the new secret, revokes every session of the account along with the access tokens
+// issued so far and invalidates its pending reset tokens
+func (s *secret) replace(ctx context.Context, account int64, secret string, now time.Time) error {
+	hash, err := s.policy.hash(secret)
+	if err != nil {
+		return err
+	}
+	if err = (*s.accountRepository).UpdateSecret(ctx, account, hash); err != nil {
+		return err
+	}
+	if err = (*s.refreshTokenRepository).RevokeAccount(ctx, account, now); err != nil {
+		return err
+	}
+	if err = revokeAccess(ctx, s.revokedTokenRepository, account, now); err != nil {
+		return err
+	}
+	return (*s.secretResetTokenRepository).Invalidate(ctx, account, now)
+}
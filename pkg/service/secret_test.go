@@ -0,0 +1,281 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/notify"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// secretRepos returns repository mocks that record the replacement of the account secret as calls
+func secretRepos(t *testing.T, account entity.Account, calls *[]string) (repository.Account, repository.RefreshToken, repository.RevokedToken) {
+	var accountRepo repository.Account = &testutil.AccountRepoMock{
+		ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+			if id != account.ID {
+				return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account", nil)
+			}
+			return account, nil
+		},
+		ExpectFindBy: func(ctx context.Context, cpf string) (entity.Account, error) {
+			if cpf != account.CPF {
+				return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result finding account", nil)
+			}
+			return account, nil
+		},
+		ExpectUpdateSecret: func(ctx context.Context, id int64, secret string) error {
+			testutil.AssertEq(t, "account", account.ID, id)
+			testutil.AssertNoErr(t, bcrypt.CompareHashAndPassword([]byte(secret), []byte("new-secret")))
+			*calls = append(*calls, "update")
+			return nil
+		},
+	}
+	var refreshRepo repository.RefreshToken = &testutil.RefreshTokenRepoMock{
+		ExpectRevokeAccount: func(ctx context.Context, id int64, at time.Time) error {
+			testutil.AssertEq(t, "account", account.ID, id)
+			*calls = append(*calls, "revoke")
+			return nil
+		},
+	}
+	var revokedRepo repository.RevokedToken = &testutil.RevokedTokenRepoMock{
+		ExpectRevokeAccount: func(ctx context.Context, id int64, at time.Time) error {
+			testutil.AssertEq(t, "account", account.ID, id)
+			*calls = append(*calls, "revoke-access")
+			return nil
+		},
+	}
+	return accountRepo, refreshRepo, revokedRepo
+}
+
+func TestSecretServiceChange(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("current-secret"), bcrypt.MinCost)
+	testutil.AssertNoErr(t, err)
+	account := testutil.NewEntityAccount(1, "Sousa", "41112075020", string(hash), 0)
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		current   string
+		secret    string
+		calls     string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "change secret successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			current:   "current-secret",
+			secret:    "new-secret",
+			calls:     "update revoke revoke-access invalidate",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "change secret with wrong current secret",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			current:   "wrong-secret",
+			secret:    "new-secret",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'current_secret' doesn't match the account's secret")
+			},
+		},
+		{
+			name:      "change secret without current secret",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			secret:    "new-secret",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'current_secret' is required")
+			},
+		},
+		{
+			name:      "change secret without new secret",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			current:   "current-secret",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' is required")
+			},
+		},
+		{
+			name:      "change secret of another account as customer",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			current:   "current-secret",
+			secret:    "new-secret",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "account '1' was not found")
+			},
+		},
+		{
+			name:      "change secret of another account as admin",
+			requester: dto.Requester{ID: 2, Role: entity.RoleAdmin},
+			current:   "current-secret",
+			secret:    "new-secret",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '2' isn't allowed to access account '1'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			accountRepo, refreshRepo, revokedRepo := secretRepos(t, account, &calls)
+			var resetRepo repository.SecretResetToken = &testutil.SecretResetTokenRepoMock{
+				ExpectInvalidate: func(ctx context.Context, id int64, at time.Time) error {
+					calls = append(calls, "invalidate")
+					return nil
+				},
+			}
+			var notifier notify.Notifier = &testutil.NotifierMock{}
+			s := service.NewSecret(&txr, &accountRepo, &resetRepo, &refreshRepo, &revokedRepo, &notifier, secretPolicy, time.Hour)
+			tc.assertErr(t, s.Change(context.Background(), tc.requester, 1, tc.current, tc.secret))
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+		})
+	}
+}
+
+func TestSecretServiceRequestReset(t *testing.T) {
+	account := testutil.NewEntityAccount(1, "Sousa", "41112075020", "hash", 0)
+	tt := []struct {
+		name      string
+		cpf       string
+		calls     string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "request reset successfully",
+			cpf:       "41112075020",
+			calls:     "invalidate create notify",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "request reset of a nonexistent account",
+			cpf:       "52998224725",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "request reset with malformed cpf",
+			cpf:  "4111207502",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'cpf' has an invalid format")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var created entity.SecretResetToken
+			accountRepo, refreshRepo, revokedRepo := secretRepos(t, account, &calls)
+			var resetRepo repository.SecretResetToken = &testutil.SecretResetTokenRepoMock{
+				ExpectInvalidate: func(ctx context.Context, id int64, at time.Time) error {
+					calls = append(calls, "invalidate")
+					return nil
+				},
+				ExpectCreate: func(ctx context.Context, e entity.SecretResetToken) error {
+					created = e
+					calls = append(calls, "create")
+					return nil
+				},
+			}
+			var notifier notify.Notifier = &testutil.NotifierMock{
+				ExpectNotify: func(ctx context.Context, m notify.Message) error {
+					testutil.AssertEq(t, "account", int64(1), m.AccountID)
+					token := strings.Fields(m.Text)[3]
+					if created.Hash == token || len(created.Hash) != 64 {
+						t.Errorf("expected the reset token to be stored hashed")
+					}
+					calls = append(calls, "notify")
+					return nil
+				},
+			}
+			s := service.NewSecret(&txr, &accountRepo, &resetRepo, &refreshRepo, &revokedRepo, &notifier, secretPolicy, time.Hour)
+			tc.assertErr(t, s.RequestReset(context.Background(), tc.cpf))
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+			if tc.calls != "" {
+				testutil.AssertEq(t, "ttl", time.Hour, created.ExpiresAt.Sub(created.CreatedAt))
+			}
+		})
+	}
+}
+
+func TestSecretServiceReset(t *testing.T) {
+	account := testutil.NewEntityAccount(1, "Sousa", "41112075020", "hash", 0)
+	usedAt := time.Now().Add(-time.Minute)
+	tt := []struct {
+		name      string
+		token     *entity.SecretResetToken
+		markUsed  error
+		calls     string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "reset secret successfully",
+			token:     &entity.SecretResetToken{AccountID: 1, ExpiresAt: time.Now().Add(time.Minute)},
+			calls:     "use update revoke revoke-access invalidate",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "reset secret with unknown token",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid secret reset token")
+			},
+		},
+		{
+			name:  "reset secret with expired token",
+			token: &entity.SecretResetToken{AccountID: 1, ExpiresAt: time.Now().Add(-time.Minute)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "expired secret reset token")
+			},
+		},
+		{
+			name:  "reset secret with used token",
+			token: &entity.SecretResetToken{AccountID: 1, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the secret reset token was already used")
+			},
+		},
+		{
+			name:     "reset secret with token used concurrently",
+			token:    &entity.SecretResetToken{AccountID: 1, ExpiresAt: time.Now().Add(time.Minute)},
+			markUsed: types.NewErr(types.NoRowAffectedErr, "no rows affected by the mark secret reset token as used stmt", nil),
+			calls:    "use",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the secret reset token was already used")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			accountRepo, refreshRepo, revokedRepo := secretRepos(t, account, &calls)
+			var resetRepo repository.SecretResetToken = &testutil.SecretResetTokenRepoMock{
+				ExpectGet: func(ctx context.Context, hash string) (entity.SecretResetToken, error) {
+					if tc.token == nil {
+						return entity.SecretResetToken{}, types.NewErr(types.EmptyResultErr, "no result getting secret reset token", nil)
+					}
+					e := *tc.token
+					e.Hash = hash
+					return e, nil
+				},
+				ExpectMarkUsed: func(ctx context.Context, hash string, at time.Time) error {
+					calls = append(calls, "use")
+					return tc.markUsed
+				},
+				ExpectInvalidate: func(ctx context.Context, id int64, at time.Time) error {
+					calls = append(calls, "invalidate")
+					return nil
+				},
+			}
+			var notifier notify.Notifier = &testutil.NotifierMock{}
+			s := service.NewSecret(&txr, &accountRepo, &resetRepo, &refreshRepo, &revokedRepo, &notifier, secretPolicy, time.Hour)
+			tc.assertErr(t, s.Reset(context.Background(), "reset-token", "new-secret"))
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+		})
+	}
+}
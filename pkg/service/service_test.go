@@ -5,11 +5,19 @@ import (
 	"testing"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var txr repository.Transactioner
 
+// statusChangeRepo serves the account service tests that never change an account status
+var statusChangeRepo repository.StatusChange = &testutil.StatusChangeRepoMock{}
+
+// secretPolicy keeps the tests from paying for the default bcrypt cost
+var secretPolicy = service.SecretPolicy{Cost: bcrypt.MinCost}
+
 func TestMain(m *testing.M) {
 	txr = &testutil.TransactionerMock{}
 	os.Exit(m.Run())
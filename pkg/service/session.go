@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Session exposes the business operations related to the lifecycle of authenticated sessions,
+// which are kept alive by rotating refresh tokens and ended by revoking them along with the access token
+type Session interface {
+	Start(ctx context.Context, account int64) (string, error)
+	Refresh(ctx context.Context, refreshToken string) (int64, string, error)
+	End(ctx context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error
+	Revoked(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error)
+}
+
+type session struct {
+	refreshTokenRepository *repository.RefreshToken
+	revokedTokenRepository *repository.RevokedToken
+	txr                    *repository.Transactioner
+	ttl                    time.Duration
+}
+
+var _ Session = (*session)(nil)
+
+// NewSession returns a value responsible for managing entity.RefreshToken and entity.RevokedToken integrity.
+// Refresh tokens expire after ttl
+func NewSession(txr *repository.Transactioner, refreshTokenRepository *repository.RefreshToken, revokedTokenRepository *repository.RevokedToken, ttl time.Duration) Session {
+	return &session{
+		refreshTokenRepository: refreshTokenRepository,
+		revokedTokenRepository: revokedTokenRepository,
+		txr:                    txr,
+		ttl:                    ttl,
+	}
+}
+
+// Start issues the refresh token of a new session of the account
+func (s *session) Start(ctx context.Context, account int64) (string, error) {
+	family, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	token, err := s.issue(ctx, account, hex.EncodeToString(family), time.Now())
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to start the session")
+		return "", err
+	}
+	return token, nil
+}
+
+// Refresh rotates the given refresh token, returning the account it belongs to along with its replacement.
+// Presenting a token that was already rotated is taken as a theft, so the whole token family gets revoked
+// along with every access token of the account issued so far
+func (s *session) Refresh(ctx context.Context, refreshToken string) (account int64, rotated string, err error) {
+	current, err := s.get(ctx, refreshToken)
+	if err != nil {
+		return 0, "", err
+	}
+	now := time.Now()
+	if current.RevokedAt != nil {
+		return 0, "", types.NewErr(types.AuthenticationErr, "the refresh token was revoked", nil)
+	}
+	if current.UsedAt != nil {
+		return 0, "", s.revokeReused(ctx, current, now)
+	}
+	if !current.ExpiresAt.After(now) {
+		return 0, "", types.NewErr(types.AuthenticationErr, "expired refresh token", nil)
+	}
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*s.refreshTokenRepository).MarkUsed(txCtx, current.Hash, now); err != nil {
+			return err
+		}
+		rotated, err = s.issue(txCtx, current.AccountID, current.Family, now)
+		return err
+	})
+	// A concurrent refresh has just used the same token
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return 0, "", s.revokeReused(ctx, current, now)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", current.AccountID).Msg("unable to rotate the refresh token")
+		return 0, "", err
+	}
+	return current.AccountID, rotated, nil
+}
+
+// End revokes the refresh token family of the account session, when a refresh token is given,
+// and blocklists the access token identified by jti until it expires
+func (s *session) End(ctx context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error {
+	now := time.Now()
+	if refreshToken != "" {
+		current, err := s.get(ctx, refreshToken)
+		if err != nil {
+			return err
+		}
+		if current.AccountID != account {
+			return types.NewErr(types.AuthenticationErr, "invalid refresh token", nil)
+		}
+		if err = (*s.refreshTokenRepository).RevokeFamily(ctx, current.Family, now); err != nil {
+			log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to revoke the refresh token family")
+			return err
+		}
+	}
+	err := (*s.revokedTokenRepository).Create(ctx, entity.RevokedToken{JTI: jti, ExpiresAt: expiresAt})
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.ConflictErr {
+		return nil
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Str("jti", jti).Msg("unable to revoke the access token")
+		return err
+	}
+	// The blocklist only needs to outlive the access tokens it holds
+	if err = (*s.revokedTokenRepository).DeleteExpired(ctx, now); err != nil {
+		log.Warn().Caller().Err(err).Msg("unable to delete the expired revoked tokens")
+	}
+	return nil
+}
+
+// Revoked tells whether the access token identified by jti was revoked, either by itself or along with
+// every token of the account issued up to then. The account is 0 for the tokens issued to clients
+func (s *session) Revoked(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+	revoked, err := (*s.revokedTokenRepository).Exists(ctx, jti)
+	if err != nil {
+		log.Error().Caller().Err(err).Str("jti", jti).Msg("unable to check the access token revocation")
+		return false, err
+	}
+	if revoked || account == 0 {
+		return revoked, nil
+	}
+	revokedAt, err := (*s.revokedTokenRepository).AccountRevokedAt(ctx, account)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to check the account access tokens revocation")
+		return false, err
+	}
+	return revokedAt != nil && !issuedAt.After(*revokedAt), nil
+}
+
+// revokeAccess revokes every access token of the account issued up to now.
+// Tokens only tell the second they were issued at, so the ones issued within the same second are revoked as well
+func revokeAccess(ctx context.Context, revokedTokenRepository *repository.RevokedToken, account int64, now time.Time) error {
+	if err := (*revokedTokenRepository).RevokeAccount(ctx, account, now.Truncate(time.Second)); err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to revoke the account access tokens")
+		return err
+	}
+	return nil
+}
+
+// get returns the stored entity.RefreshToken of the given opaque token
+func (s *session) get(ctx context.Context, refreshToken string) (entity.RefreshToken, error) {
+	if refreshToken == "" {
+		return entity.RefreshToken{}, types.NewErr(types.ValidationErr, "field 'refresh_token' is required", nil)
+	}
+	current, err := (*s.refreshTokenRepository).Get(ctx, hashToken(refreshToken))
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return current, types.NewErr(types.AuthenticationErr, "invalid refresh token", nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Msg("unable to get the refresh token")
+	}
+	return current, err
+}
+
+// issue creates a refresh token within the family and returns its opaque value
+func (s *session) issue(ctx context.Context, account int64, family string, now time.Time) (string, error) {
+	b, err := randomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	e := entity.RefreshToken{
+		Hash:      hashToken(token),
+		Family:    family,
+		AccountID: account,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err = (*s.refreshTokenRepository).Create(ctx, e); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// revokeReused revokes the family of a refresh token presented after its rotation.
+// Access tokens don't tell the family they were issued within, so every one of the account is revoked
+func (s *session) revokeReused(ctx context.Context, current entity.RefreshToken, now time.Time) error {
+	log.Warn().Int64("account_id", current.AccountID).Str("family", current.Family).Msg("refresh token reuse detected")
+	if err := (*s.refreshTokenRepository).RevokeFamily(ctx, current.Family, now); err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", current.AccountID).Msg("unable to revoke the refresh token family")
+		return err
+	}
+	if err := revokeAccess(ctx, s.revokedTokenRepository, current.AccountID, now); err != nil {
+		return err
+	}
+	return types.NewErr(types.AuthenticationErr, "the refresh token was already used, the session has been revoked", nil)
+}
+
+// randomBytes returns n bytes read from a cryptographically secure source
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, types.NewErr(types.InternalErr, "unable to generate a random token", err)
+	}
+	return b, nil
+}
+
+// hashToken returns the hex encoded sha256 of the token, refresh tokens are never stored as they are
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,316 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestSessionServiceStart(t *testing.T) {
+	var created entity.RefreshToken
+	var refreshRepo repository.RefreshToken = &testutil.RefreshTokenRepoMock{
+		ExpectCreate: func(c context.Context, e entity.RefreshToken) error {
+			created = e
+			return nil
+		},
+	}
+	var revokedRepo repository.RevokedToken = &testutil.RevokedTokenRepoMock{}
+	s := service.NewSession(&txr, &refreshRepo, &revokedRepo, time.Hour)
+	token, err := s.Start(context.Background(), 7)
+	testutil.AssertNoErr(t, err)
+	testutil.AssertNotDefault(t, "token", token)
+	testutil.AssertEq(t, "account id", int64(7), created.AccountID)
+	testutil.AssertEq(t, "family length", 32, len(created.Family))
+	testutil.AssertEq(t, "hash length", 64, len(created.Hash))
+	if created.Hash == token {
+		t.Errorf("expected the refresh token to be stored hashed")
+	}
+	testutil.AssertEq(t, "ttl", time.Hour, created.ExpiresAt.Sub(created.CreatedAt))
+}
+
+func TestSessionServiceRefresh(t *testing.T) {
+	now := time.Now()
+	stored := func(mod func(*entity.RefreshToken)) func(context.Context, string) (entity.RefreshToken, error) {
+		return func(c context.Context, hash string) (entity.RefreshToken, error) {
+			e := entity.RefreshToken{Hash: hash, Family: "family", AccountID: 3, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+			if mod != nil {
+				mod(&e)
+			}
+			return e, nil
+		}
+	}
+	tt := []struct {
+		name      string
+		repo      func(revoked *bool) repository.RefreshToken
+		revoked   bool
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "refresh token successfully",
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet:      stored(nil),
+					ExpectMarkUsed: func(c context.Context, hash string, at time.Time) error { return nil },
+					ExpectCreate: func(c context.Context, e entity.RefreshToken) error {
+						testutil.AssertEq(t, "family", "family", e.Family)
+						testutil.AssertEq(t, "account id", int64(3), e.AccountID)
+						return nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "refresh unknown token",
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: func(c context.Context, hash string) (entity.RefreshToken, error) {
+						return entity.RefreshToken{}, types.NewErr(types.EmptyResultErr, "no result getting refresh token", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid refresh token")
+			},
+		},
+		{
+			name: "refresh expired token",
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: stored(func(e *entity.RefreshToken) { e.ExpiresAt = now.Add(-time.Minute) }),
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "expired refresh token")
+			},
+		},
+		{
+			name: "refresh revoked token",
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: stored(func(e *entity.RefreshToken) { e.RevokedAt = &now }),
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the refresh token was revoked")
+			},
+		},
+		{
+			name:    "refresh already used token",
+			revoked: true,
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: stored(func(e *entity.RefreshToken) { e.UsedAt = &now }),
+					ExpectRevokeFamily: func(c context.Context, family string, at time.Time) error {
+						testutil.AssertEq(t, "family", "family", family)
+						*revoked = true
+						return nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the refresh token was already used, the session has been revoked")
+			},
+		},
+		{
+			name:    "refresh token used concurrently",
+			revoked: true,
+			repo: func(revoked *bool) repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: stored(nil),
+					ExpectMarkUsed: func(c context.Context, hash string, at time.Time) error {
+						return types.NewErr(types.NoRowAffectedErr, "no rows affected by the mark refresh token as used stmt", nil)
+					},
+					ExpectRevokeFamily: func(c context.Context, family string, at time.Time) error {
+						*revoked = true
+						return nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the refresh token was already used, the session has been revoked")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var revoked, accessRevoked bool
+			refreshRepo := tc.repo(&revoked)
+			var revokedRepo repository.RevokedToken = &testutil.RevokedTokenRepoMock{
+				ExpectRevokeAccount: func(c context.Context, account int64, at time.Time) error {
+					testutil.AssertEq(t, "account id", int64(3), account)
+					testutil.AssertEq(t, "revoked at precision", 0, at.Nanosecond())
+					accessRevoked = true
+					return nil
+				},
+			}
+			s := service.NewSession(&txr, &refreshRepo, &revokedRepo, time.Hour)
+			account, rotated, err := s.Refresh(context.Background(), "token")
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "account id", int64(3), account)
+				testutil.AssertNotDefault(t, "rotated token", rotated)
+			}
+			testutil.AssertEq(t, "family revoked", tc.revoked, revoked)
+			testutil.AssertEq(t, "access tokens revoked", tc.revoked, accessRevoked)
+		})
+	}
+}
+
+func TestSessionServiceEnd(t *testing.T) {
+	expiresAt := time.Now().Add(time.Minute)
+	tt := []struct {
+		name         string
+		refreshToken string
+		refreshRepo  func() repository.RefreshToken
+		revokedRepo  func() repository.RevokedToken
+		assertErr    func(*testing.T, error)
+	}{
+		{
+			name:         "end session successfully",
+			refreshToken: "token",
+			refreshRepo: func() repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: func(c context.Context, hash string) (entity.RefreshToken, error) {
+						return entity.RefreshToken{Hash: hash, Family: "family", AccountID: 1}, nil
+					},
+					ExpectRevokeFamily: func(c context.Context, family string, at time.Time) error {
+						testutil.AssertEq(t, "family", "family", family)
+						return nil
+					},
+				}
+			},
+			revokedRepo: func() repository.RevokedToken {
+				return &testutil.RevokedTokenRepoMock{
+					ExpectCreate: func(c context.Context, e entity.RevokedToken) error {
+						testutil.AssertEq(t, "jti", "jti", e.JTI)
+						testutil.AssertEq(t, "expires at", expiresAt, e.ExpiresAt)
+						return nil
+					},
+					ExpectDeleteExpired: func(c context.Context, at time.Time) error { return nil },
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "end session with access token already revoked",
+			refreshRepo: func() repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{}
+			},
+			revokedRepo: func() repository.RevokedToken {
+				return &testutil.RevokedTokenRepoMock{
+					ExpectCreate: func(c context.Context, e entity.RevokedToken) error {
+						return types.NewErr(types.ConflictErr, "access token already revoked", nil)
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:         "end session with refresh token of another account",
+			refreshToken: "token",
+			refreshRepo: func() repository.RefreshToken {
+				return &testutil.RefreshTokenRepoMock{
+					ExpectGet: func(c context.Context, hash string) (entity.RefreshToken, error) {
+						return entity.RefreshToken{Hash: hash, Family: "family", AccountID: 2}, nil
+					},
+				}
+			},
+			revokedRepo: func() repository.RevokedToken {
+				return &testutil.RevokedTokenRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid refresh token")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			refreshRepo := tc.refreshRepo()
+			revokedRepo := tc.revokedRepo()
+			s := service.NewSession(&txr, &refreshRepo, &revokedRepo, time.Hour)
+			tc.assertErr(t, s.End(context.Background(), 1, tc.refreshToken, "jti", expiresAt))
+		})
+	}
+}
+
+func TestSessionServiceRevoked(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tt := []struct {
+		name      string
+		jti       string
+		account   int64
+		issuedAt  time.Time
+		revokedAt *time.Time
+		expected  bool
+	}{
+		{
+			name:     "check access token that was never revoked",
+			jti:      "jti",
+			account:  3,
+			issuedAt: revokedAt.Add(-time.Hour),
+		},
+		{
+			name:     "check revoked access token",
+			jti:      "revoked",
+			account:  3,
+			issuedAt: revokedAt.Add(time.Minute),
+			expected: true,
+		},
+		{
+			name:      "check access token issued before its account tokens were revoked",
+			jti:       "jti",
+			account:   3,
+			issuedAt:  revokedAt.Add(-time.Second),
+			revokedAt: &revokedAt,
+			expected:  true,
+		},
+		{
+			name:      "check access token issued within the second its account tokens were revoked",
+			jti:       "jti",
+			account:   3,
+			issuedAt:  revokedAt,
+			revokedAt: &revokedAt,
+			expected:  true,
+		},
+		{
+			name:      "check access token issued after its account tokens were revoked",
+			jti:       "jti",
+			account:   3,
+			issuedAt:  revokedAt.Add(time.Second),
+			revokedAt: &revokedAt,
+		},
+		{
+			name:     "check access token issued to a client",
+			jti:      "jti",
+			issuedAt: revokedAt.Add(-time.Hour),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var refreshRepo repository.RefreshToken = &testutil.RefreshTokenRepoMock{}
+			var revokedRepo repository.RevokedToken = &testutil.RevokedTokenRepoMock{
+				ExpectExists: func(c context.Context, jti string) (bool, error) {
+					return jti == "revoked", nil
+				},
+				ExpectAccountRevokedAt: func(c context.Context, account int64) (*time.Time, error) {
+					testutil.AssertEq(t, "account id", tc.account, account)
+					return tc.revokedAt, nil
+				},
+			}
+			s := service.NewSession(&txr, &refreshRepo, &revokedRepo, time.Hour)
+			revoked, err := s.Revoked(context.Background(), tc.jti, tc.account, tc.issuedAt)
+			testutil.AssertNoErr(t, err)
+			testutil.AssertEq(t, "revoked", tc.expected, revoked)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// StandingOrder represents the business operations available to entity.StandingOrder type
+type StandingOrder interface {
+	Create(ctx context.Context, origin int64, d dto.StandingOrderCreation) (dto.StandingOrderView, error)
+	Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]dto.StandingOrderView, error)
+	Get(ctx context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error)
+	Update(ctx context.Context, requester dto.Requester, id int64, d dto.StandingOrderUpdate) (dto.StandingOrderView, error)
+	Cancel(ctx context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error)
+	FetchExecutions(ctx context.Context, requester dto.Requester, id int64) ([]dto.StandingOrderExecutionView, error)
+	ExecuteDue(ctx context.Context) (int, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type standingOrder struct {
+	txr                              *repository.Transactioner
+	standingOrderRepository          *repository.StandingOrder
+	standingOrderExecutionRepository *repository.StandingOrderExecution
+	accountRepository                *repository.Account
+	transferService                  *Transfer
+	standingOrderValidator           *validation.StandingOrder
+}
+
+var _ StandingOrder = (*standingOrder)(nil)
+
+// NewStandingOrder returns a value responsible for managing standing orders and executing their occurrences once due.
+// Each occurrence is executed through the given Transfer service, within the tx that claims the order
+func NewStandingOrder(txr *repository.Transactioner, standingOrderRepository *repository.StandingOrder, standingOrderExecutionRepository *repository.StandingOrderExecution, accountRepository *repository.Account, transferService *Transfer) StandingOrder {
+	return &standingOrder{
+		txr:                              txr,
+		standingOrderRepository:          standingOrderRepository,
+		standingOrderExecutionRepository: standingOrderExecutionRepository,
+		accountRepository:                accountRepository,
+		transferService:                  transferService,
+		standingOrderValidator: &validation.StandingOrder{
+			AccountRepository: accountRepository,
+		},
+	}
+}
+
+// Create validates and persists an active entity.StandingOrder from the origin account, whose first execution
+// is the first occurrence of its schedule from d.StartAt on
+func (s *standingOrder) Create(ctx context.Context, origin int64, standingOrderCreation dto.StandingOrderCreation) (view dto.StandingOrderView, err error) {
+	now := time.Now()
+	e := entity.StandingOrder{
+		Origin:      origin,
+		Destination: standingOrderCreation.Destination,
+		Convert:     standingOrderCreation.Convert,
+		Frequency:   standingOrderCreation.Frequency,
+		Day:         standingOrderCreation.Day,
+		StartAt:     standingOrderCreation.StartAt.UTC().Truncate(time.Second),
+		Count:       standingOrderCreation.Count,
+		Status:      entity.StandingOrderActive,
+		CreatedAt:   now,
+	}
+	e.NextAt = e.Occurrence(0)
+	if err = s.standingOrderValidator.Creation(ctx, origin, standingOrderCreation, e.NextAt, now); err != nil {
+		return view, err
+	}
+	if standingOrderCreation.EndAt != nil {
+		endAt := standingOrderCreation.EndAt.UTC().Truncate(time.Second)
+		e.EndAt = &endAt
+	}
+	originAccount, err := (*s.accountRepository).Get(ctx, origin)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("account_origin_id", origin).Msg("unable to get the origin account")
+		return view, err
+	}
+	if e.Amount, err = originAccount.Currency.Currency(standingOrderCreation.Amount); err != nil {
+		return view, err
+	}
+	e.Currency = originAccount.Currency
+	if e.ID, err = (*s.standingOrderRepository).Create(ctx, e); err != nil {
+		log.Error().Caller().Err(err).
+			Int64("account_origin_id", origin).
+			Int64("account_destination_id", standingOrderCreation.Destination).
+			Msg("unable to create the standing order")
+		return view, err
+	}
+	return dto.NewStandingOrderView(e), nil
+}
+
+// Fetch returns the standing orders of the origin account sorted by creation, narrowed by status unless it's empty
+func (s *standingOrder) Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]dto.StandingOrderView, error) {
+	orders, err := (*s.standingOrderRepository).Fetch(ctx, origin, status)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_origin_id", origin).Msg("unable to fetch the standing orders")
+		return nil, err
+	}
+	views := make([]dto.StandingOrderView, 0, len(orders))
+	for _, e := range orders {
+		views = append(views, dto.NewStandingOrderView(e))
+	}
+	return views, nil
+}
+
+// Get returns the entity.StandingOrder stored at id as long as the requester is allowed to read its origin account.
+// Orders of other accounts are reported as not found so that their existence isn't disclosed
+func (s *standingOrder) Get(ctx context.Context, requester dto.Requester, id int64) (view dto.StandingOrderView, err error) {
+	e, err := s.get(ctx, requester, id)
+	if err != nil {
+		return view, err
+	}
+	return dto.NewStandingOrderView(e), nil
+}
+
+// Update replaces the amount and the end of the active entity.StandingOrder stored at id, which only its origin account owner is allowed to.
+// The order is locked meanwhile, so that an occurrence being executed at the same time is either executed before or after the change
+func (s *standingOrder) Update(ctx context.Context, requester dto.Requester, id int64, standingOrderUpdate dto.StandingOrderUpdate) (view dto.StandingOrderView, err error) {
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		e, err := s.lock(txCtx, requester, id, "change")
+		if err != nil {
+			return err
+		}
+		if e.Status != entity.StandingOrderActive {
+			return types.NewErr(types.ConflictErr, fmt.Sprintf("standing order '%d' is %s and can't be changed", id, e.Status), nil)
+		}
+		if err = s.standingOrderValidator.Update(e, standingOrderUpdate); err != nil {
+			return err
+		}
+		if e.Amount, err = e.Currency.Currency(standingOrderUpdate.Amount); err != nil {
+			return err
+		}
+		e.EndAt = nil
+		if standingOrderUpdate.EndAt != nil {
+			endAt := standingOrderUpdate.EndAt.UTC().Truncate(time.Second)
+			e.EndAt = &endAt
+		}
+		e.Count = standingOrderUpdate.Count
+		if err = (*s.standingOrderRepository).Update(txCtx, e); err != nil {
+			log.Error().Caller().Err(err).Int64("id", id).Msg("unable to update the standing order")
+			return err
+		}
+		view = dto.NewStandingOrderView(e)
+		return nil
+	})
+	return view, err
+}
+
+// Cancel cancels the active entity.StandingOrder stored at id, which only its origin account owner is allowed to.
+// The executions already made are kept along with their transfers
+func (s *standingOrder) Cancel(ctx context.Context, requester dto.Requester, id int64) (view dto.StandingOrderView, err error) {
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		e, err := s.lock(txCtx, requester, id, "cancel")
+		if err != nil {
+			return err
+		}
+		if e.Status != entity.StandingOrderActive {
+			return types.NewErr(types.ConflictErr, fmt.Sprintf("standing order '%d' is %s and can't be canceled", id, e.Status), nil)
+		}
+		now := time.Now()
+		e.Status = entity.StandingOrderCanceled
+		e.FinishedAt = &now
+		if err = (*s.standingOrderRepository).Update(txCtx, e); err != nil {
+			log.Error().Caller().Err(err).Int64("id", id).Msg("unable to cancel the standing order")
+			return err
+		}
+		view = dto.NewStandingOrderView(e)
+		return nil
+	})
+	return view, err
+}
+
+// FetchExecutions returns the history of the executions of the entity.StandingOrder stored at id, sorted by their scheduled date,
+// as long as the requester is allowed to read its origin account
+func (s *standingOrder) FetchExecutions(ctx context.Context, requester dto.Requester, id int64) ([]dto.StandingOrderExecutionView, error) {
+	if _, err := s.get(ctx, requester, id); err != nil {
+		return nil, err
+	}
+	executions, err := (*s.standingOrderExecutionRepository).Fetch(ctx, id)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to fetch the standing order executions")
+		return nil, err
+	}
+	views := make([]dto.StandingOrderExecutionView, 0, len(executions))
+	for _, e := range executions {
+		views = append(views, dto.NewStandingOrderExecutionView(e))
+	}
+	return views, nil
+}
+
+// ExecuteDue executes the occurrences due by now one at a time, returning how many of them were either executed or failed.
+// Occurrences missed while no executor was running are caught up in order.
+// It stops at the first error that isn't a refusal of the transfer, leaving the occurrence due to be retried
+func (s *standingOrder) ExecuteDue(ctx context.Context) (processed int, err error) {
+	for ctx.Err() == nil {
+		claimed, err := s.executeNext(ctx, time.Now())
+		if err != nil || !claimed {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, ctx.Err()
+}
+
+// Run executes the due occurrences every interval until ctx is done
+func (s *standingOrder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := s.ExecuteDue(ctx)
+			if err != nil {
+				log.Error().Caller().Err(err).Int("processed", processed).Msg("unable to execute the due standing orders")
+			} else if processed > 0 {
+				log.Info().Int("processed", processed).Msg("due standing orders executed")
+			}
+		}
+	}
+}
+
+// executeNext claims the order whose next occurrence is the earliest due by now and executes it within the same tx,
+// recording the execution and moving the order to its following occurrence, or finishing it once exhausted.
+// It tells whether there was an occurrence due
+func (s *standingOrder) executeNext(ctx context.Context, now time.Time) (claimed bool, err error) {
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		e, err := (*s.standingOrderRepository).ClaimDue(txCtx, now)
+		if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		claimed = true
+		execution := entity.StandingOrderExecution{
+			StandingOrder: e.ID,
+			ScheduledAt:   e.NextAt,
+			CreatedAt:     time.Now(),
+		}
+		view, err := (*s.transferService).Create(txCtx, e.Origin, dto.TransferCreation{
+			Destination: e.Destination,
+			Amount:      e.Currency.Decimal(e.Amount),
+			Convert:     e.Convert,
+		})
+		if err == nil {
+			execution.Status = entity.ScheduleExecuted
+			execution.TransferID = &view.ID
+		} else if reason, refused := failureReason(err); refused {
+			execution.Status = entity.ScheduleFailed
+			execution.FailureReason = reason
+		} else {
+			return err
+		}
+		if execution.ID, err = (*s.standingOrderExecutionRepository).Create(txCtx, execution); err != nil {
+			return err
+		}
+		e.Executions++
+		if e.Exhausted(e.Executions) {
+			e.Status = entity.StandingOrderFinished
+			e.FinishedAt = &execution.CreatedAt
+		} else {
+			e.NextAt = e.Occurrence(e.Executions)
+		}
+		if err = (*s.standingOrderRepository).Update(txCtx, e); err != nil {
+			return err
+		}
+		log.Info().
+			Int64("id", e.ID).
+			Int64("account_origin_id", e.Origin).
+			Time("scheduled_at", execution.ScheduledAt).
+			Str("status", string(execution.Status)).
+			Str("failure_reason", execution.FailureReason).
+			Msg("standing order executed")
+		return nil
+	})
+	return claimed, err
+}
+
+// lock locks the entity.StandingOrder stored at id on behalf of its origin account owner, the action being named by verb
+func (s *standingOrder) lock(ctx context.Context, requester dto.Requester, id int64, verb string) (entity.StandingOrder, error) {
+	e, err := (*s.standingOrderRepository).Lock(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to lock the standing order")
+		return e, err
+	}
+	if !requester.CanRead(e.Origin) {
+		return e, types.NewErr(types.NotFoundErr, fmt.Sprintf("standing order '%d' was not found", id), nil)
+	}
+	if requester.ID != e.Origin {
+		return e, types.NewErr(types.AuthorizationErr, fmt.Sprintf("account '%d' isn't allowed to %s standing order '%d'", requester.ID, verb, id), nil)
+	}
+	return e, nil
+}
+
+func (s *standingOrder) get(ctx context.Context, requester dto.Requester, id int64) (entity.StandingOrder, error) {
+	e, err := (*s.standingOrderRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the standing order")
+		return e, err
+	}
+	if !requester.CanRead(e.Origin) {
+		return e, types.NewErr(types.NotFoundErr, fmt.Sprintf("standing order '%d' was not found", id), nil)
+	}
+	return e, nil
+}
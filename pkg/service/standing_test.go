@@ -0,0 +1,428 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+// nextYear is the year after the current one, keeping the dates of the standing orders under test in the future
+var nextYear = time.Now().Year() + 1
+
+// inNextYear returns the given day of nextYear at 09:00 UTC, a day 0 standing for the last day of the previous month
+func inNextYear(month time.Month, day int) time.Time {
+	return time.Date(nextYear, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+// newStandingOrder returns an active order of 10.00 from account 1 to account 2 stored at id, run monthly on the 31st since January of nextYear
+func newStandingOrder(id int64) entity.StandingOrder {
+	return entity.StandingOrder{
+		ID:          id,
+		Origin:      1,
+		Destination: 2,
+		Amount:      types.NewCurrency(10),
+		Currency:    types.BRL,
+		Frequency:   entity.FrequencyMonthly,
+		Day:         31,
+		StartAt:     inNextYear(time.January, 10),
+		NextAt:      inNextYear(time.January, 31),
+		Status:      entity.StandingOrderActive,
+		CreatedAt:   inNextYear(time.January, 1),
+	}
+}
+
+func TestStandingOrderServiceCreate(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	tt := []struct {
+		name      string
+		d         dto.StandingOrderCreation
+		next      time.Time
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "create weekly standing order successfully",
+			d:         dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: start, Count: 4},
+			next:      start,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "create monthly standing order on a day already gone by",
+			d:         dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyMonthly, Day: 1, StartAt: inNextYear(time.January, 10)},
+			next:      inNextYear(time.February, 1),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "create monthly standing order on a day the month lacks",
+			d:         dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyMonthly, Day: 31, StartAt: inNextYear(time.February, 10)},
+			next:      inNextYear(time.March, 0),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "create monthly standing order without day",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyMonthly, StartAt: start},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'day_of_month' must be between 1 and 31")
+			},
+		},
+		{
+			name: "create standing order starting in the past",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: time.Now().Add(-time.Minute)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'start_at' must be in the future")
+			},
+		},
+		{
+			name: "create standing order ending before its first execution",
+			d: dto.StandingOrderCreation{
+				Destination: 2,
+				Amount:      "10.50",
+				Frequency:   entity.FrequencyMonthly,
+				Day:         20,
+				StartAt:     inNextYear(time.January, 25),
+				EndAt:       func() *time.Time { t := time.Date(nextYear, time.February, 1, 0, 0, 0, 0, time.UTC); return &t }(),
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, fmt.Sprintf("field 'end_at' can't be before the next execution at %s", inNextYear(time.February, 20).Format(time.RFC3339)))
+			},
+		},
+		{
+			name: "create standing order with both end date and count",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: start, EndAt: &start, Count: 2},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "fields 'end_at' and 'count' can't be set together")
+			},
+		},
+		{
+			name: "create standing order to the origin",
+			d:    dto.StandingOrderCreation{Destination: 1, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: start},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "fields 'origin id' and 'destination id' can't be the same")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.StandingOrder = &testutil.StandingOrderRepoMock{
+				ExpectCreate: func(ctx context.Context, e entity.StandingOrder) (int64, error) {
+					testutil.AssertEq(t, "origin", int64(1), e.Origin)
+					testutil.AssertEq(t, "amount", types.Currency(1050), e.Amount)
+					testutil.AssertEq(t, "status", entity.StandingOrderActive, e.Status)
+					testutil.AssertEq(t, "next at", tc.next, e.NextAt)
+					return 4, nil
+				},
+			}
+			var executionRepo repository.StandingOrderExecution = &testutil.StandingOrderExecutionRepoMock{}
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					// The origin balance isn't verified until each execution
+					return testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", 0), nil
+				},
+			}
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := service.NewStandingOrder(&txr, &repo, &executionRepo, &accRepo, &transferSrv)
+			view, err := s.Create(context.Background(), 1, tc.d)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "id", int64(4), view.ID)
+				testutil.AssertEq(t, "next at", tc.next, *view.NextAt)
+			}
+		})
+	}
+}
+
+func TestStandingOrderServiceUpdate(t *testing.T) {
+	canceled := newStandingOrder(3)
+	canceled.Status = entity.StandingOrderCanceled
+	executed := newStandingOrder(3)
+	executed.Executions = 2
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		stored    entity.StandingOrder
+		d         dto.StandingOrderUpdate
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "update standing order successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    newStandingOrder(3),
+			d:         dto.StandingOrderUpdate{Amount: "12.00", Count: 6},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "update standing order of another account",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			stored:    newStandingOrder(3),
+			d:         dto.StandingOrderUpdate{Amount: "12.00"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "standing order '3' was not found")
+			},
+		},
+		{
+			name:      "update standing order of another account as admin",
+			requester: dto.Requester{ID: 5, Role: entity.RoleAdmin},
+			stored:    newStandingOrder(3),
+			d:         dto.StandingOrderUpdate{Amount: "12.00"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '5' isn't allowed to change standing order '3'")
+			},
+		},
+		{
+			name:      "update canceled standing order",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    canceled,
+			d:         dto.StandingOrderUpdate{Amount: "12.00"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "standing order '3' is canceled and can't be changed")
+			},
+		},
+		{
+			name:      "update standing order count below its executions",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    executed,
+			d:         dto.StandingOrderUpdate{Amount: "12.00", Count: 2},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'count' must be greater than 2")
+			},
+		},
+		{
+			name:      "update standing order with zero amount",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    newStandingOrder(3),
+			d:         dto.StandingOrderUpdate{Amount: "0"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be greater than 0")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.StandingOrder = &testutil.StandingOrderRepoMock{
+				ExpectLock: func(ctx context.Context, id int64) (entity.StandingOrder, error) {
+					return tc.stored, nil
+				},
+				ExpectUpdate: func(ctx context.Context, e entity.StandingOrder) error {
+					testutil.AssertEq(t, "amount", types.Currency(1200), e.Amount)
+					testutil.AssertEq(t, "count", tc.d.Count, e.Count)
+					testutil.AssertEq(t, "next at", tc.stored.NextAt, e.NextAt)
+					return nil
+				},
+			}
+			var executionRepo repository.StandingOrderExecution = &testutil.StandingOrderExecutionRepoMock{}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := service.NewStandingOrder(&txr, &repo, &executionRepo, &accRepo, &transferSrv)
+			view, err := s.Update(context.Background(), tc.requester, 3, tc.d)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "amount", types.Decimal("12.00"), view.Amount)
+			}
+		})
+	}
+}
+
+func TestStandingOrderServiceCancel(t *testing.T) {
+	finished := newStandingOrder(3)
+	finished.Status = entity.StandingOrderFinished
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		stored    entity.StandingOrder
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "cancel standing order successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    newStandingOrder(3),
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "cancel standing order of another account as support",
+			requester: dto.Requester{ID: 5, Role: entity.RoleSupport},
+			stored:    newStandingOrder(3),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '5' isn't allowed to cancel standing order '3'")
+			},
+		},
+		{
+			name:      "cancel finished standing order",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			stored:    finished,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "standing order '3' is finished and can't be canceled")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.StandingOrder = &testutil.StandingOrderRepoMock{
+				ExpectLock: func(ctx context.Context, id int64) (entity.StandingOrder, error) {
+					return tc.stored, nil
+				},
+				ExpectUpdate: func(ctx context.Context, e entity.StandingOrder) error {
+					testutil.AssertEq(t, "status", entity.StandingOrderCanceled, e.Status)
+					testutil.AssertNotDefault(t, "finished at", e.FinishedAt)
+					return nil
+				},
+			}
+			var executionRepo repository.StandingOrderExecution = &testutil.StandingOrderExecutionRepoMock{}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var transferSrv service.Transfer = &testutil.TransferServMock{}
+			s := service.NewStandingOrder(&txr, &repo, &executionRepo, &accRepo, &transferSrv)
+			view, err := s.Cancel(context.Background(), tc.requester, 3)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "status", entity.StandingOrderCanceled, view.Status)
+				if view.NextAt != nil {
+					t.Errorf("expected no next execution of the canceled order, got %v", view.NextAt)
+				}
+			}
+		})
+	}
+}
+
+func TestStandingOrderServiceExecuteDue(t *testing.T) {
+	second := newStandingOrder(3)
+	second.Executions = 1
+	second.NextAt = inNextYear(time.March, 0)
+	last := newStandingOrder(3)
+	last.Count = 1
+	tt := []struct {
+		name      string
+		due       []entity.StandingOrder
+		create    func() (dto.TransferView, error)
+		expected  int
+		outcome   entity.ScheduleStatus
+		reason    string
+		next      time.Time
+		status    entity.StandingOrderStatus
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "execute due standing order on a day the next month lacks",
+			due:  []entity.StandingOrder{newStandingOrder(3)},
+			create: func() (dto.TransferView, error) {
+				return *testutil.NewTransferView(9, 2, 10), nil
+			},
+			expected:  1,
+			outcome:   entity.ScheduleExecuted,
+			next:      inNextYear(time.March, 0),
+			status:    entity.StandingOrderActive,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due standing order back on its day",
+			due:  []entity.StandingOrder{second},
+			create: func() (dto.TransferView, error) {
+				return *testutil.NewTransferView(9, 2, 10), nil
+			},
+			expected:  1,
+			outcome:   entity.ScheduleExecuted,
+			next:      inNextYear(time.March, 31),
+			status:    entity.StandingOrderActive,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute last occurrence of a standing order",
+			due:  []entity.StandingOrder{last},
+			create: func() (dto.TransferView, error) {
+				return *testutil.NewTransferView(9, 2, 10), nil
+			},
+			expected:  1,
+			outcome:   entity.ScheduleExecuted,
+			next:      last.NextAt,
+			status:    entity.StandingOrderFinished,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due standing order without funds",
+			due:  []entity.StandingOrder{newStandingOrder(3)},
+			create: func() (dto.TransferView, error) {
+				return dto.TransferView{}, types.NewErr(types.ValidationErr, "the origin must have a balance greater than or equal to 10.00", nil)
+			},
+			expected:  1,
+			outcome:   entity.ScheduleFailed,
+			reason:    "the origin must have a balance greater than or equal to 10.00",
+			next:      inNextYear(time.March, 0),
+			status:    entity.StandingOrderActive,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "execute due standing order while the rate provider is unavailable",
+			due:  []entity.StandingOrder{newStandingOrder(3)},
+			create: func() (dto.TransferView, error) {
+				return dto.TransferView{}, types.NewErr(types.InternalErr, "unable to get the exchange rate", nil)
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InternalErr, err, "unable to get the exchange rate")
+			},
+		},
+		{
+			name:      "execute without due standing orders",
+			assertErr: testutil.AssertNoErr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			due := tc.due
+			recorded, updated := 0, 0
+			var repo repository.StandingOrder = &testutil.StandingOrderRepoMock{
+				ExpectClaimDue: func(ctx context.Context, now time.Time) (entity.StandingOrder, error) {
+					if len(due) == 0 {
+						return entity.StandingOrder{}, types.NewErr(types.EmptyResultErr, "no standing order is due", nil)
+					}
+					e := due[0]
+					due = due[1:]
+					return e, nil
+				},
+				ExpectUpdate: func(ctx context.Context, e entity.StandingOrder) error {
+					updated++
+					testutil.AssertEq(t, "executions", tc.due[0].Executions+1, e.Executions)
+					testutil.AssertEq(t, "next at", tc.next, e.NextAt)
+					testutil.AssertEq(t, "status", tc.status, e.Status)
+					return nil
+				},
+			}
+			var executionRepo repository.StandingOrderExecution = &testutil.StandingOrderExecutionRepoMock{
+				ExpectCreate: func(ctx context.Context, e entity.StandingOrderExecution) (int64, error) {
+					recorded++
+					testutil.AssertEq(t, "standing order", int64(3), e.StandingOrder)
+					testutil.AssertEq(t, "scheduled at", tc.due[0].NextAt, e.ScheduledAt)
+					testutil.AssertEq(t, "status", tc.outcome, e.Status)
+					testutil.AssertEq(t, "failure reason", tc.reason, e.FailureReason)
+					if tc.outcome == entity.ScheduleExecuted {
+						testutil.AssertEq(t, "transfer id", int64(9), *e.TransferID)
+					}
+					return 7, nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var transferSrv service.Transfer = &testutil.TransferServMock{
+				ExpectCreate: func(ctx context.Context, origin int64, d dto.TransferCreation) (dto.TransferView, error) {
+					testutil.AssertEq(t, "origin", int64(1), origin)
+					testutil.AssertEq(t, "destination", int64(2), d.Destination)
+					testutil.AssertEq(t, "amount", types.Decimal("10.00"), d.Amount)
+					return tc.create()
+				},
+			}
+			s := service.NewStandingOrder(&txr, &repo, &executionRepo, &accRepo, &transferSrv)
+			processed, err := s.ExecuteDue(context.Background())
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "processed", tc.expected, processed)
+			testutil.AssertEq(t, "recorded", tc.expected, recorded)
+			testutil.AssertEq(t, "updated", tc.expected, updated)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rs/zerolog/log"
+)
+
+// Statement exposes the generation of account statements
+type Statement interface {
+	Get(ctx context.Context, requester dto.Requester, id int64, p dto.StatementPeriod) (dto.StatementView, error)
+}
+
+type statement struct {
+	accountRepository       *repository.Account
+	transferRepository      *repository.Transfer
+	ledgerRepository        *repository.Ledger
+	cashOperationRepository *repository.CashOperation
+	txr                     *repository.Transactioner
+	statementValidator      *validation.Statement
+}
+
+var _ Statement = (*statement)(nil)
+
+// NewStatement returns a value responsible for building the balance history of an entity.Account
+func NewStatement(txr *repository.Transactioner, accountRepository *repository.Account, transferRepository *repository.Transfer, ledgerRepository *repository.Ledger, cashOperationRepository *repository.CashOperation) Statement {
+	return &statement{
+		accountRepository:       accountRepository,
+		transferRepository:      transferRepository,
+		ledgerRepository:        ledgerRepository,
+		cashOperationRepository: cashOperationRepository,
+		txr:                     txr,
+		statementValidator:      &validation.Statement{},
+	}
+}
+
+// Get builds the statement of the entity.Account stored at id, which is only disclosed to the account owner and to the support staff.
+// The statement covers the current calendar month unless the period specifies otherwise. Its opening balance
+// derives from the ledger entries posted before the period, and each entry of the period carries the resulting balance
+func (s *statement) Get(ctx context.Context, requester dto.Requester, id int64, p dto.StatementPeriod) (view dto.StatementView, err error) {
+	if !requester.CanRead(id) {
+		return view, accountAccessErr(requester, id)
+	}
+	if p.From.IsZero() {
+		now := time.Now().UTC()
+		p.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if p.To.IsZero() {
+		p.To = p.From.AddDate(0, 1, 0)
+	}
+	if err = s.statementValidator.Period(p); err != nil {
+		return view, err
+	}
+
+	var account entity.Account
+	var opening types.Currency
+	var entries []entity.LedgerEntry
+	var transfers []entity.Transfer
+	var operations []entity.CashOperation
+	// Reading everything within a single tx keeps the balances consistent with the listed entries
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if account, err = (*s.accountRepository).Get(txCtx, id); err != nil {
+			return err
+		}
+		if opening, err = (*s.ledgerRepository).GetBalanceBefore(txCtx, id, p.From); err != nil {
+			return err
+		}
+		if entries, err = (*s.ledgerRepository).Fetch(txCtx, id, p.From, p.To); err != nil {
+			return err
+		}
+		if transfers, err = (*s.transferRepository).Fetch(txCtx, repository.TransferQuery{Account: id, CreatedFrom: p.From, CreatedTo: p.To}); err != nil {
+			return err
+		}
+		operations, err = (*s.cashOperationRepository).Fetch(txCtx, id, p.From, p.To)
+		return err
+	})
+	if err != nil {
+		log.Info().Caller().Err(err).
+			Int64("id", id).
+			Time("from", p.From).
+			Time("to", p.To).
+			Msg("unable to get the account statement")
+		return view, err
+	}
+
+	byID := make(map[int64]entity.Transfer, len(transfers))
+	for _, t := range transfers {
+		byID[t.ID] = t
+	}
+	operationByID := make(map[int64]entity.CashOperation, len(operations))
+	for _, o := range operations {
+		operationByID[o.ID] = o
+	}
+	view = dto.StatementView{
+		AccountID:      id,
+		Currency:       account.Currency,
+		From:           p.From,
+		To:             p.To,
+		OpeningBalance: account.Currency.Decimal(opening),
+		Entries:        make([]dto.StatementEntry, 0, len(entries)),
+	}
+	balance := opening
+	for _, e := range entries {
+		amount := e.Amount
+		if e.Type == entity.Debit {
+			amount = -amount
+		}
+		balance += amount
+		entry := dto.StatementEntry{
+			CreatedAt:       e.CreatedAt,
+			Type:            e.Type,
+			Description:     "initial balance",
+			TransferID:      e.TransferID,
+			CashOperationID: e.CashOperationID,
+			Amount:          account.Currency.Decimal(amount),
+			Balance:         account.Currency.Decimal(balance),
+		}
+		switch {
+		case e.TransferID != nil:
+			entry.Description = "transfer"
+			if t, ok := byID[*e.TransferID]; ok {
+				entry.Counterparty, entry.Description = counterparty(t, id)
+			}
+		case e.CashOperationID != nil:
+			entry.Description = "cash operation"
+			if o, ok := operationByID[*e.CashOperationID]; ok {
+				entry.Description = fmt.Sprintf("%s with reference %s", o.Type, o.Reference)
+			}
+		}
+		view.Entries = append(view.Entries, entry)
+	}
+	view.ClosingBalance = account.Currency.Decimal(balance)
+	return view, nil
+}
+
+// counterparty returns the other account of the transfer t along with a description from the account perspective
+func counterparty(t entity.Transfer, account int64) (*int64, string) {
+	kind := "transfer"
+	if t.ReversalOf != nil {
+		kind = fmt.Sprintf("reversal of transfer %d", *t.ReversalOf)
+	}
+	if t.Origin == account {
+		return &t.Destination, fmt.Sprintf("%s to account %d", kind, t.Destination)
+	}
+	return &t.Origin, fmt.Sprintf("%s from account %d", kind, t.Origin)
+}
@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStatementServiceGet(t *testing.T) {
+	march := dto.StatementPeriod{
+		From: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	ref := func(id int64) *int64 {
+		return &id
+	}
+	tt := []struct {
+		name         string
+		requester    dto.Requester
+		id           int64
+		period       dto.StatementPeriod
+		ledgerRepo   func() repository.Ledger
+		transferRepo func() repository.Transfer
+		assertView   func(*testing.T, dto.StatementView)
+		assertErr    func(*testing.T, error)
+	}{
+		{
+			name:      "get statement with running balance successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			period:    march,
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectGetBalanceBefore: func(c context.Context, account int64, before time.Time) (types.Currency, error) {
+						testutil.AssertEq(t, "account", int64(1), account)
+						testutil.AssertEq(t, "before", march.From, before)
+						return types.NewCurrency(100), nil
+					},
+					ExpectFetch: func(c context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error) {
+						testutil.AssertEq(t, "to", march.To, to)
+						return []entity.LedgerEntry{
+							{AccountID: 1, TransferID: ref(7), Type: entity.Debit, Amount: types.NewCurrency(30)},
+							{AccountID: 1, TransferID: ref(8), Type: entity.Credit, Amount: types.NewCurrency(5.5)},
+							{AccountID: 1, CashOperationID: ref(9), Type: entity.Credit, Amount: types.NewCurrency(20)},
+						}, nil
+					},
+				}
+			},
+			transferRepo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectFetch: func(c context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						testutil.AssertEq(t, "account", int64(1), q.Account)
+						testutil.AssertEq(t, "limit", 0, q.Limit)
+						return []entity.Transfer{
+							testutil.NewEntityTransfer(7, 1, 2, 30),
+							testutil.NewEntityTransfer(8, 3, 1, 5.5),
+						}, nil
+					},
+				}
+			},
+			assertView: func(t *testing.T, view dto.StatementView) {
+				testutil.AssertEq(t, "opening balance", types.Decimal("100.00"), view.OpeningBalance)
+				testutil.AssertEq(t, "closing balance", types.Decimal("95.50"), view.ClosingBalance)
+				lines := make([]string, 0, len(view.Entries))
+				for _, e := range view.Entries {
+					lines = append(lines, e.Description+" "+string(e.Amount)+" "+string(e.Balance))
+				}
+				testutil.AssertEq(t, "entries", "transfer to account 2 -30.00 70.00|transfer from account 3 5.50 75.50|deposit with reference atm-1 20.00 95.50", strings.Join(lines, "|"))
+			},
+		},
+		{
+			name:      "get statement of the current month by default",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectGetBalanceBefore: func(c context.Context, account int64, before time.Time) (types.Currency, error) {
+						testutil.AssertEq(t, "day", 1, before.Day())
+						return 0, nil
+					},
+					ExpectFetch: func(c context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error) {
+						testutil.AssertEq(t, "to", from.AddDate(0, 1, 0), to)
+						return []entity.LedgerEntry{{AccountID: 1, Type: entity.Credit, Amount: types.NewCurrency(10)}}, nil
+					},
+				}
+			},
+			transferRepo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectFetch: func(c context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						return []entity.Transfer{}, nil
+					},
+				}
+			},
+			assertView: func(t *testing.T, view dto.StatementView) {
+				testutil.AssertEq(t, "entries", 1, len(view.Entries))
+				testutil.AssertEq(t, "description", "initial balance", view.Entries[0].Description)
+				testutil.AssertEq(t, "closing balance", types.Decimal("10.00"), view.ClosingBalance)
+			},
+		},
+		{
+			name:      "get statement of another account",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			id:        1,
+			period:    march,
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			transferRepo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "account '1' was not found")
+			},
+		},
+		{
+			name:      "get statement of another account as support",
+			requester: dto.Requester{ID: 2, Role: entity.RoleSupport},
+			id:        1,
+			period:    march,
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectGetBalanceBefore: func(c context.Context, account int64, before time.Time) (types.Currency, error) {
+						testutil.AssertEq(t, "account", int64(1), account)
+						return types.NewCurrency(5), nil
+					},
+					ExpectFetch: func(c context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error) {
+						return []entity.LedgerEntry{}, nil
+					},
+				}
+			},
+			transferRepo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectFetch: func(c context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						return []entity.Transfer{}, nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+			assertView: func(t *testing.T, view dto.StatementView) {
+				testutil.AssertEq(t, "account id", int64(1), view.AccountID)
+				testutil.AssertEq(t, "closing balance", types.Decimal("5.00"), view.ClosingBalance)
+			},
+		},
+		{
+			name:      "get statement with inverted period",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        1,
+			period:    dto.StatementPeriod{From: march.To, To: march.From},
+			ledgerRepo: func() repository.Ledger {
+				return &testutil.LedgerRepoMock{}
+			},
+			transferRepo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'from' must be before 'to'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(c context.Context, id int64) (entity.Account, error) {
+					return testutil.NewEntityAccount(id, "Bia", "71453945024", "pw", 0), nil
+				},
+			}
+			var cashRepo repository.CashOperation = &testutil.CashOperationRepoMock{
+				ExpectFetch: func(c context.Context, account int64, from time.Time, to time.Time) ([]entity.CashOperation, error) {
+					return []entity.CashOperation{{ID: 9, AccountID: account, Type: entity.Deposit, Reference: "atm-1"}}, nil
+				},
+			}
+			transferRepo := tc.transferRepo()
+			ledgerRepo := tc.ledgerRepo()
+			s := service.NewStatement(&txr, &accRepo, &transferRepo, &ledgerRepo, &cashRepo)
+			view, err := s.Get(context.Background(), tc.requester, tc.id, tc.period)
+			if tc.assertErr != nil {
+				tc.assertErr(t, err)
+				return
+			}
+			testutil.AssertNoErr(t, err)
+			tc.assertView(t, view)
+		})
+	}
+}
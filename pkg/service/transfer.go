@@ -2,25 +2,31 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
 	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
 	"github.com/rs/zerolog/log"
 )
 
 // Transfer represents the business operations available to entity.Transfer type
 type Transfer interface {
-	Fetch(ctx context.Context, id int64) ([]dto.TransferView, error)
+	Fetch(ctx context.Context, id int64, f dto.TransferFilter) (dto.TransferPage, error)
+	Get(ctx context.Context, requester dto.Requester, id int64) (dto.TransferView, error)
 	Create(ctx context.Context, origin int64, d dto.TransferCreation) (dto.TransferView, error)
+	Reverse(ctx context.Context, requester dto.Requester, id int64, r dto.TransferReversal) (dto.TransferView, error)
 }
 
 type transfer struct {
 	transferRepository *repository.Transfer
 	accountRepository  *repository.Account
+	ledgerRepository   *repository.Ledger
+	rateProvider       *fx.RateProvider
 	txr                *repository.Transactioner
 	transferValidator  *validation.Transfer
 }
@@ -28,10 +34,12 @@ type transfer struct {
 var _ Transfer = (*transfer)(nil)
 
 // NewTransfer returns a value responsible for managing entity.Transfer integrity
-func NewTransfer(txr *repository.Transactioner, transferRepository *repository.Transfer, accountRepository *repository.Account) Transfer {
+func NewTransfer(txr *repository.Transactioner, transferRepository *repository.Transfer, accountRepository *repository.Account, ledgerRepository *repository.Ledger, rateProvider *fx.RateProvider) Transfer {
 	return &transfer{
 		transferRepository: transferRepository,
 		accountRepository:  accountRepository,
+		ledgerRepository:   ledgerRepository,
+		rateProvider:       rateProvider,
 		txr:                txr,
 		transferValidator: &validation.Transfer{
 			AccountRepository: accountRepository,
@@ -39,73 +47,187 @@ func NewTransfer(txr *repository.Transactioner, transferRepository *repository.T
 	}
 }
 
-// Fetch returns a list of entity.Transfer from the entity.Account stored at id.
-// It returns nil and an error in when not able to fetch the rows from the repository
-func (s *transfer) Fetch(ctx context.Context, id int64) ([]dto.TransferView, error) {
-	transfers, err := (*s.transferRepository).Fetch(ctx, id)
+// transferCursor is the position encoded into the cursor of a transfer history
+type transferCursor struct {
+	Sort      string    `json:"s"`
+	ID        int64     `json:"i"`
+	CreatedAt time.Time `json:"c"`
+}
+
+// Fetch returns a page of the transfers sent and received by the entity.Account stored at id, narrowed by the given filter.
+// Transfers are sorted from the newest to the oldest unless the filter specifies otherwise
+func (s *transfer) Fetch(ctx context.Context, id int64, transferFilter dto.TransferFilter) (page dto.TransferPage, err error) {
+	if transferFilter.Limit == 0 {
+		transferFilter.Limit = defaultPageLimit
+	}
+	if transferFilter.Sort == "" {
+		transferFilter.Sort = "-created_at"
+	}
+	q := repository.TransferQuery{
+		Account:      id,
+		Direction:    repository.TransferDirection(transferFilter.Direction),
+		Counterparty: transferFilter.Counterparty,
+		CreatedFrom:  transferFilter.CreatedFrom,
+		CreatedTo:    transferFilter.CreatedTo,
+		Limit:        transferFilter.Limit + 1,
+	}
+	_, q.Desc = parseSort(transferFilter.Sort, "created_at")
+	// Amount filters are expressed in the account currency
+	code := types.DefaultCurrencyCode
+	if transferFilter.MinAmount != "" || transferFilter.MaxAmount != "" {
+		acc, err := (*s.accountRepository).Get(ctx, id)
+		if err != nil {
+			log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the account of the transfer history")
+			return page, err
+		}
+		code = acc.Currency
+	}
+	if err = s.transferValidator.Filter(transferFilter, code); err != nil {
+		return page, err
+	}
+	if q.MinAmount, err = optionalAmount(transferFilter.MinAmount, code); err != nil {
+		return page, err
+	}
+	if q.MaxAmount, err = optionalAmount(transferFilter.MaxAmount, code); err != nil {
+		return page, err
+	}
+	if transferFilter.Cursor != "" {
+		var c transferCursor
+		if err = decodeCursor(transferFilter.Cursor, &c); err != nil {
+			return page, err
+		}
+		if c.Sort != transferFilter.Sort {
+			return page, types.NewErr(types.ValidationErr, "field 'cursor' doesn't belong to the requested sort", nil)
+		}
+		q.After = &repository.TransferKey{ID: c.ID, CreatedAt: c.CreatedAt}
+	}
+
+	transfers, err := (*s.transferRepository).Fetch(ctx, q)
 	if err != nil {
 		log.Error().Caller().Err(err).Int64("id", id).Msg("unable to fetch transfers")
-		return nil, err
+		return page, err
 	}
-
-	views := make([]dto.TransferView, 0, len(transfers))
+	// The extra row fetched beyond the limit only tells there's a next page
+	if len(transfers) > transferFilter.Limit {
+		transfers = transfers[:transferFilter.Limit]
+		last := transfers[len(transfers)-1]
+		page.NextCursor = encodeCursor(transferCursor{Sort: transferFilter.Sort, ID: last.ID, CreatedAt: last.CreatedAt})
+	}
+	page.Data = make([]dto.TransferView, 0, len(transfers))
 	for _, t := range transfers {
-		views = append(views, dto.NewTransferView(t))
+		page.Data = append(page.Data, dto.NewTransferView(t))
+	}
+	return page, nil
+}
+
+// Get returns the entity.Transfer stored at id as long as the requester is allowed to read either its origin or destination account.
+// Transfers of other accounts are reported as not found so that their existence isn't disclosed
+func (s *transfer) Get(ctx context.Context, requester dto.Requester, id int64) (view dto.TransferView, err error) {
+	transfer, err := (*s.transferRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get transfer")
+		return view, err
+	}
+	if !requester.CanRead(transfer.Origin) && !requester.CanRead(transfer.Destination) {
+		return view, types.NewErr(types.NotFoundErr, fmt.Sprintf("transfer '%d' was not found", id), nil)
+	}
+	return dto.NewTransferView(transfer), nil
+}
+
+// optionalAmount converts the amount into the minor unit of code, returning nil when it's empty
+func optionalAmount(amount types.Decimal, code types.CurrencyCode) (*types.Currency, error) {
+	if amount == "" {
+		return nil, nil
+	}
+	c, err := code.Currency(amount)
+	if err != nil {
+		return nil, err
 	}
-	return views, nil
+	return &c, nil
 }
 
-// Create validates, create, and persists an entity.Transfer from the values stored at d
+// Create validates, create, and persists an entity.Transfer from the values stored at d.
+// Transfers between different currencies exchange the amount at the rate quoted before the accounts get locked
 func (s *transfer) Create(ctx context.Context, origin int64, transferCreation dto.TransferCreation) (view dto.TransferView, err error) {
+	rate, err := s.quote(ctx, origin, transferCreation)
+	if err != nil {
+		log.Info().Caller().Err(err).
+			Int64("account_origin_id", origin).
+			Int64("account_destination_id", transferCreation.Destination).
+			Msg("unable to quote the transfer exchange rate")
+		return view, err
+	}
 	var transfer entity.Transfer
 	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		// Both rows stay locked until the tx ends, so the balances read below can't be changed by a concurrent transfer
+		if err := (*s.accountRepository).Lock(txCtx, origin, transferCreation.Destination); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_origin_id", origin).
+				Int64("account_destination_id", transferCreation.Destination).
+				Msg("unable to lock the transfer accounts")
+			return err
+		}
 		if err := s.transferValidator.Creation(txCtx, origin, transferCreation); err != nil {
 			return err
 		}
-		originBalance, err := (*s.accountRepository).GetBalance(txCtx, origin)
+		originAccount, err := (*s.accountRepository).Get(txCtx, origin)
 		if err != nil {
 			log.Info().Caller().Err(err).
 				Int64("account_origin_id", origin).
-				Msg("unable to get the origin account balance")
+				Msg("unable to get the origin account")
 			return err
 		}
-		destinationBalance, err := (*s.accountRepository).GetBalance(txCtx, transferCreation.Destination)
+		destinationAccount, err := (*s.accountRepository).Get(txCtx, transferCreation.Destination)
 		if err != nil {
 			log.Info().Caller().Err(err).
 				Int64("account_destination_id", transferCreation.Destination).
-				Msg("unable to get the destination account balance")
+				Msg("unable to get the destination account")
+			return err
+		}
+		amount, err := originAccount.Currency.Currency(transferCreation.Amount)
+		if err != nil {
+			return err
+		}
+		destinationAmount, err := s.convert(amount, originAccount.Currency, destinationAccount.Currency, rate)
+		if err != nil {
 			return err
 		}
-		amount := types.NewCurrency(transferCreation.Amount)
 
-		if err = (*s.accountRepository).UpdateBalance(txCtx, origin, originBalance-amount); err != nil {
+		if err = (*s.accountRepository).UpdateBalance(txCtx, origin, originAccount.Balance-amount); err != nil {
 			log.Info().Caller().Err(err).
 				Int64("account_origin_id", origin).
-				Int64("balance", int64(originBalance)).
+				Int64("balance", int64(originAccount.Balance)).
 				Int64("amount", int64(amount)).
 				Msg("unable to update the origin account balance")
 			return err
 		}
 
-		if err = (*s.accountRepository).UpdateBalance(txCtx, transferCreation.Destination, destinationBalance+amount); err != nil {
+		if err = (*s.accountRepository).UpdateBalance(txCtx, transferCreation.Destination, destinationAccount.Balance+destinationAmount); err != nil {
 			log.Info().
 				Caller().
 				Err(err).
 				Int64("account_destination_id", transferCreation.Destination).
-				Int64("balance", int64(destinationBalance)).
-				Int64("amount", int64(amount)).
+				Int64("balance", int64(destinationAccount.Balance)).
+				Int64("amount", int64(destinationAmount)).
 				Msg("unable to update the destination account balance")
 			return err
 		}
 		transfer = entity.Transfer{
-			Origin:      origin,
-			Destination: transferCreation.Destination,
-			Amount:      amount,
-			CreatedAt:   time.Now(),
+			Origin:              origin,
+			Destination:         transferCreation.Destination,
+			Amount:              amount,
+			Currency:            originAccount.Currency,
+			DestinationAmount:   destinationAmount,
+			DestinationCurrency: destinationAccount.Currency,
+			Rate:                rate,
+			CreatedAt:           time.Now(),
 		}
 		id, err := (*s.transferRepository).Create(txCtx, transfer)
+		if err != nil {
+			return err
+		}
 		transfer.ID = id
-		return err
+		return s.post(txCtx, transfer)
 	})
 	if err != nil {
 		log.Info().
@@ -113,9 +235,183 @@ func (s *transfer) Create(ctx context.Context, origin int64, transferCreation dt
 			Err(err).
 			Int64("account_origin_id", origin).
 			Int64("account_destination_id", transferCreation.Destination).
-			Int64("amount", int64(transferCreation.Amount)).
+			Str("amount", string(transferCreation.Amount)).
 			Msg("unable to transfer the currency amount")
 		return view, err
 	}
 	return dto.NewTransferView(transfer), nil
 }
+
+// Reverse creates a compensating entity.Transfer giving back to the origin of the transfer stored at id either the requested amount
+// or whatever wasn't reversed yet. Only the destination account owner and support staff reverse a transfer.
+// Reversals between different currencies credit the origin the share of the original amount matching the reversed one,
+// so that reversing every part of a transfer gives back exactly what was debited
+func (s *transfer) Reverse(ctx context.Context, requester dto.Requester, id int64, transferReversal dto.TransferReversal) (view dto.TransferView, err error) {
+	original, err := (*s.transferRepository).Get(ctx, id)
+	if err != nil {
+		log.Info().Caller().Err(err).Int64("id", id).Msg("unable to get the transfer to reverse")
+		return view, err
+	}
+	if !requester.CanRead(original.Origin) && !requester.CanRead(original.Destination) {
+		return view, types.NewErr(types.NotFoundErr, fmt.Sprintf("transfer '%d' was not found", id), nil)
+	}
+	if requester.ID != original.Destination && requester.Role != entity.RoleSupport && requester.Role != entity.RoleAdmin {
+		return view, types.NewErr(types.AuthorizationErr, fmt.Sprintf("account '%d' isn't allowed to reverse transfer '%d'", requester.ID, id), nil)
+	}
+	var reversal entity.Transfer
+	err = (*s.txr).WithTx(ctx, func(txCtx context.Context) error {
+		// Every reversal of the transfer locks the same accounts, so the reversed amount read below can't be changed concurrently
+		if err := (*s.accountRepository).Lock(txCtx, original.Origin, original.Destination); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_origin_id", original.Origin).
+				Int64("account_destination_id", original.Destination).
+				Msg("unable to lock the transfer accounts")
+			return err
+		}
+		if original, err = (*s.transferRepository).Get(txCtx, id); err != nil {
+			return err
+		}
+		if err := s.transferValidator.Reversal(txCtx, original, transferReversal); err != nil {
+			return err
+		}
+		amount := original.DestinationAmount - original.ReversedAmount
+		if transferReversal.Amount != "" {
+			if amount, err = original.DestinationCurrency.Currency(transferReversal.Amount); err != nil {
+				return err
+			}
+		}
+		reversed := original.ReversedAmount + amount
+		// The origin is credited the difference between the shares before and after the reversal, avoiding rounding drifts
+		credited := fx.Prorate(original.Amount, reversed, original.DestinationAmount) - fx.Prorate(original.Amount, original.ReversedAmount, original.DestinationAmount)
+		if credited <= 0 {
+			return types.NewErr(types.ValidationErr, fmt.Sprintf("the amount %s is too small to be converted into '%s'", original.DestinationCurrency.Decimal(amount), original.Currency), nil)
+		}
+		rate, err := fx.Inverse(original.Rate)
+		if err != nil {
+			return err
+		}
+		originAccount, err := (*s.accountRepository).Get(txCtx, original.Origin)
+		if err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_origin_id", original.Origin).
+				Msg("unable to get the origin account")
+			return err
+		}
+		destinationAccount, err := (*s.accountRepository).Get(txCtx, original.Destination)
+		if err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_destination_id", original.Destination).
+				Msg("unable to get the destination account")
+			return err
+		}
+		if err = (*s.accountRepository).UpdateBalance(txCtx, original.Destination, destinationAccount.Balance-amount); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_destination_id", original.Destination).
+				Int64("balance", int64(destinationAccount.Balance)).
+				Int64("amount", int64(amount)).
+				Msg("unable to update the destination account balance")
+			return err
+		}
+		if err = (*s.accountRepository).UpdateBalance(txCtx, original.Origin, originAccount.Balance+credited); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("account_origin_id", original.Origin).
+				Int64("balance", int64(originAccount.Balance)).
+				Int64("amount", int64(credited)).
+				Msg("unable to update the origin account balance")
+			return err
+		}
+		reversal = entity.Transfer{
+			Origin:              original.Destination,
+			Destination:         original.Origin,
+			Amount:              amount,
+			Currency:            original.DestinationCurrency,
+			DestinationAmount:   credited,
+			DestinationCurrency: original.Currency,
+			Rate:                rate,
+			ReversalOf:          &original.ID,
+			CreatedAt:           time.Now(),
+		}
+		if reversal.ID, err = (*s.transferRepository).Create(txCtx, reversal); err != nil {
+			return err
+		}
+		if err = (*s.transferRepository).UpdateReversedAmount(txCtx, original.ID, reversed); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("transfer_id", original.ID).
+				Int64("reversed_amount", int64(reversed)).
+				Msg("unable to update the transfer reversed amount")
+			return err
+		}
+		return s.post(txCtx, reversal)
+	})
+	if err != nil {
+		log.Info().
+			Caller().
+			Err(err).
+			Int64("transfer_id", id).
+			Int64("requester_id", requester.ID).
+			Str("amount", string(transferReversal.Amount)).
+			Msg("unable to reverse the transfer")
+		return view, err
+	}
+	return dto.NewTransferView(reversal), nil
+}
+
+// quote returns the exchange rate between the transfer accounts, or fx.Identity when no conversion takes place.
+// It runs outside the tx so that a slow rate provider doesn't hold the account locks, and validates the transfer
+// beforehand so that missing or unusable accounts are reported as such rather than as quoting failures
+func (s *transfer) quote(ctx context.Context, origin int64, transferCreation dto.TransferCreation) (types.Decimal, error) {
+	if !transferCreation.Convert {
+		return fx.Identity, nil
+	}
+	if err := s.transferValidator.Creation(ctx, origin, transferCreation); err != nil {
+		return "", err
+	}
+	originAccount, err := (*s.accountRepository).Get(ctx, origin)
+	if err != nil {
+		return "", err
+	}
+	destinationAccount, err := (*s.accountRepository).Get(ctx, transferCreation.Destination)
+	if err != nil {
+		return "", err
+	}
+	if originAccount.Currency == destinationAccount.Currency {
+		return fx.Identity, nil
+	}
+	return (*s.rateProvider).Rate(ctx, originAccount.Currency, destinationAccount.Currency)
+}
+
+// convert exchanges the amount into the destination currency, which must remain greater than 0 once rounded
+func (s *transfer) convert(amount types.Currency, from types.CurrencyCode, to types.CurrencyCode, rate types.Decimal) (types.Currency, error) {
+	if from == to {
+		return amount, nil
+	}
+	converted, err := fx.Convert(amount, from, to, rate)
+	if err != nil {
+		return 0, err
+	}
+	if converted <= 0 {
+		return 0, types.NewErr(types.ValidationErr, fmt.Sprintf("the amount %s is too small to be converted into '%s'", from.Decimal(amount), to), nil)
+	}
+	return converted, nil
+}
+
+// post records the debit and credit entries of the given transfer into the ledger, each in its own account currency
+func (s *transfer) post(ctx context.Context, transfer entity.Transfer) error {
+	entries := []entity.LedgerEntry{
+		{AccountID: transfer.Origin, Type: entity.Debit, Amount: transfer.Amount},
+		{AccountID: transfer.Destination, Type: entity.Credit, Amount: transfer.DestinationAmount},
+	}
+	for _, e := range entries {
+		e.TransferID = &transfer.ID
+		e.CreatedAt = transfer.CreatedAt
+		if _, err := (*s.ledgerRepository).Create(ctx, e); err != nil {
+			log.Info().Caller().Err(err).
+				Int64("transfer_id", transfer.ID).
+				Int64("account_id", e.AccountID).
+				Str("entry_type", string(e.Type)).
+				Msg("unable to post the transfer ledger entry")
+			return err
+		}
+	}
+	return nil
+}
@@ -9,13 +9,23 @@ import (
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/fx"
 	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
 )
 
 func TestTransferServiceFetch(t *testing.T) {
+	transfers := []entity.Transfer{
+		testutil.NewEntityTransfer(1, 3, 2, 10),
+		testutil.NewEntityTransfer(2, 2, 3, 20),
+		testutil.NewEntityTransfer(3, 3, 2, 30),
+		testutil.NewEntityTransfer(4, 3, 4, 40),
+		testutil.NewEntityTransfer(5, 5, 3, 50),
+	}
 	tt := []struct {
 		name         string
 		expectedSize int
+		hasNext      bool
+		filter       dto.TransferFilter
 		transferRepo func(int64) repository.Transfer
 		accountRepo  func() repository.Account
 		assertErr    func(*testing.T, error)
@@ -26,8 +36,10 @@ func TestTransferServiceFetch(t *testing.T) {
 			expectedSize: 0,
 			transferRepo: func(id int64) repository.Transfer {
 				return &testutil.TransferRepoMock{
-					ExpectFetch: func(ctx context.Context, currentID int64) ([]entity.Transfer, error) {
-						testutil.AssertEq(t, "id", id, currentID)
+					ExpectFetch: func(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						testutil.AssertEq(t, "id", id, q.Account)
+						testutil.AssertEq(t, "limit", 51, q.Limit)
+						testutil.AssertEq(t, "desc", true, q.Desc)
 						return []entity.Transfer{}, nil
 					},
 				}
@@ -42,7 +54,7 @@ func TestTransferServiceFetch(t *testing.T) {
 			expectedSize: 0,
 			transferRepo: func(id int64) repository.Transfer {
 				return &testutil.TransferRepoMock{
-					ExpectFetch: func(ctx context.Context, currentID int64) ([]entity.Transfer, error) {
+					ExpectFetch: func(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
 						return nil, types.NewErr(types.InternalErr, "internal error", nil)
 					},
 				}
@@ -58,17 +70,43 @@ func TestTransferServiceFetch(t *testing.T) {
 		{
 			name:         "fetch transfers successfully with results",
 			expectedSize: 5,
+			filter: dto.TransferFilter{
+				Direction:    "in",
+				Counterparty: 2,
+				MinAmount:    "10",
+				MaxAmount:    "50.5",
+			},
+			transferRepo: func(id int64) repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectFetch: func(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						testutil.AssertEq(t, "id", id, q.Account)
+						testutil.AssertEq(t, "direction", repository.TransferIn, q.Direction)
+						testutil.AssertEq(t, "counterparty", int64(2), q.Counterparty)
+						testutil.AssertEq(t, "min amount", types.NewCurrency(10), *q.MinAmount)
+						testutil.AssertEq(t, "max amount", types.NewCurrency(50.5), *q.MaxAmount)
+						return transfers, nil
+					},
+				}
+			},
+			accountRepo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(i, "Ana", "71453945024", "pw", 0), nil
+					},
+				}
+			},
+			id: 3,
+		},
+		{
+			name:         "fetch transfers with next page",
+			expectedSize: 4,
+			hasNext:      true,
+			filter:       dto.TransferFilter{PageRequest: dto.PageRequest{Limit: 4, Sort: "created_at"}},
 			transferRepo: func(id int64) repository.Transfer {
 				return &testutil.TransferRepoMock{
-					ExpectFetch: func(ctx context.Context, currentID int64) ([]entity.Transfer, error) {
-						testutil.AssertEq(t, "id", id, currentID)
-						return []entity.Transfer{
-							testutil.NewEntityTransfer(1, 3, 2, 10),
-							testutil.NewEntityTransfer(2, 3, 2, 20),
-							testutil.NewEntityTransfer(3, 3, 2, 30),
-							testutil.NewEntityTransfer(4, 3, 3, 40),
-							testutil.NewEntityTransfer(5, 3, 3, 50),
-						}, nil
+					ExpectFetch: func(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+						testutil.AssertEq(t, "desc", false, q.Desc)
+						return transfers, nil
 					},
 				}
 			},
@@ -77,18 +115,56 @@ func TestTransferServiceFetch(t *testing.T) {
 			},
 			id: 3,
 		},
+		{
+			name:   "fetch transfers with unknown direction",
+			filter: dto.TransferFilter{Direction: "sideways"},
+			transferRepo: func(id int64) repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			accountRepo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			id: 3,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'direction' must be one of 'in', 'out', 'all'")
+			},
+		},
+		{
+			name:   "fetch transfers with amount beyond the account currency precision",
+			filter: dto.TransferFilter{MinAmount: "10", MaxAmount: "10.5"},
+			transferRepo: func(id int64) repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			accountRepo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(i, "Ana", "71453945024", "pw", 0)
+						acc.Currency = types.JPY
+						return acc, nil
+					},
+				}
+			},
+			id: 3,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'max_amount' must have at most 0 decimal places")
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			transferRepo := tc.transferRepo(tc.id)
 			accRepo := tc.accountRepo()
-			s := service.NewTransfer(&txr, &transferRepo, &accRepo)
-			transfers, err := s.Fetch(context.Background(), tc.id)
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			var rateProvider fx.RateProvider = &testutil.RateProviderMock{}
+			s := service.NewTransfer(&txr, &transferRepo, &accRepo, &ledgerRepo, &rateProvider)
+			page, err := s.Fetch(context.Background(), tc.id, tc.filter)
 			if err == nil && tc.assertErr == nil {
-				testutil.AssertEq(t, "transfers size", len(transfers), tc.expectedSize)
-				for _, transfer := range transfers {
+				testutil.AssertEq(t, "transfers size", tc.expectedSize, len(page.Data))
+				testutil.AssertEq(t, "has next", tc.hasNext, page.NextCursor != "")
+				for _, transfer := range page.Data {
 					testutil.AssertNotDefault(t, "amount", transfer.Amount)
+					testutil.AssertNotDefault(t, "origin", transfer.Origin)
 				}
 			} else {
 				tc.assertErr(t, err)
@@ -97,12 +173,132 @@ func TestTransferServiceFetch(t *testing.T) {
 	}
 }
 
+func TestTransferServiceGet(t *testing.T) {
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		id        int64
+		repo      func() repository.Transfer
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "get outgoing transfer successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        3,
+			repo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+						testutil.AssertEq(t, "id", int64(3), id)
+						return testutil.NewEntityTransfer(id, 1, 2, 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get incoming transfer successfully",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			id:        3,
+			repo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+						return testutil.NewEntityTransfer(id, 1, 2, 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get transfer between other accounts",
+			requester: dto.Requester{ID: 5, Role: entity.RoleCustomer},
+			id:        3,
+			repo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+						return testutil.NewEntityTransfer(id, 1, 2, 10), nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "transfer '3' was not found")
+			},
+		},
+		{
+			name:      "get transfer between other accounts as support",
+			requester: dto.Requester{ID: 5, Role: entity.RoleSupport},
+			id:        3,
+			repo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+						return testutil.NewEntityTransfer(id, 1, 2, 10), nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "get nonexistent transfer",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			id:        9,
+			repo: func() repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+						return entity.Transfer{}, types.NewErr(types.EmptyResultErr, "no result getting transfer by id", nil)
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "no result getting transfer by id")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			transferRepo := tc.repo()
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			var rateProvider fx.RateProvider = &testutil.RateProviderMock{}
+			s := service.NewTransfer(&txr, &transferRepo, &accRepo, &ledgerRepo, &rateProvider)
+			view, err := s.Get(context.Background(), tc.requester, tc.id)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "id", tc.id, view.ID)
+			}
+		})
+	}
+}
+
 func TestTransferServiceCreate(t *testing.T) {
+	// getStack returns a repository.Account#Get mock that pops the given balances in order, failing once they are over
+	getStack := func(balances ...float64) func(context.Context, int64) (entity.Account, error) {
+		return func(c context.Context, i int64) (entity.Account, error) {
+			if len(balances) == 0 {
+				return entity.Account{}, types.NewErr(types.InternalErr, "internal error", nil)
+			}
+			acc := testutil.NewEntityAccount(i, "Ana", "71453945024", "pw", balances[0])
+			balances = balances[1:]
+			return acc, nil
+		}
+	}
+	lock := func(c context.Context, ids ...int64) error {
+		return nil
+	}
+	// getUSD returns a repository.Account#Get mock holding the destination account in USD
+	getUSD := func(c context.Context, i int64) (entity.Account, error) {
+		acc := testutil.NewEntityAccount(i, "Ana", "71453945024", "pw", 500)
+		if i == 2 {
+			acc.Currency = types.USD
+		}
+		return acc, nil
+	}
 	tt := []struct {
 		name         string
 		expected     *dto.TransferView
 		transferRepo func(int64, *dto.TransferCreation) repository.Transfer
 		accountRepo  func(int64, *dto.TransferCreation) repository.Account
+		ledgerRepo   func(int64, *dto.TransferCreation) repository.Ledger
+		rateProvider func() fx.RateProvider
 		assertErr    func(*testing.T, error)
 		origin       int64
 		d            *dto.TransferCreation
@@ -114,23 +310,20 @@ func TestTransferServiceCreate(t *testing.T) {
 					ExpectCreate: func(ctx context.Context, e entity.Transfer) (int64, error) {
 						testutil.AssertEq(t, "origin", origin, e.Origin)
 						testutil.AssertEq(t, "destination", d.Destination, e.Destination)
-						testutil.AssertEq(t, "amount", types.NewCurrency(d.Amount), e.Amount)
+						testutil.AssertEq(t, "amount", d.Amount, types.BRL.Decimal(e.Amount))
+						testutil.AssertEq(t, "currency", types.BRL, e.Currency)
 						testutil.AssertNotDefault(t, "created_at", e.CreatedAt)
 						return int64(1), nil
 					},
 				}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				balanceStack := []float64{500, 500, 0}
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						if len(balanceStack) == 0 {
-							t.Fatalf("unexpected method call")
-						}
-						b := types.NewCurrency(balanceStack[0])
-						balanceStack = balanceStack[1:]
-						return b, nil
+					ExpectLock: func(c context.Context, ids ...int64) error {
+						testutil.AssertEq(t, "locked ids", 2, len(ids))
+						return nil
 					},
+					ExpectGet: getStack(500, 0, 500, 0),
 					ExpectUpdateBalance: func(ctx context.Context, i int64, b types.Currency) error {
 						switch i {
 						case origin:
@@ -142,35 +335,167 @@ func TestTransferServiceCreate(t *testing.T) {
 						}
 						return nil
 					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", d.Destination, i)
-						return true, nil
+				}
+			},
+			ledgerRepo: func(origin int64, d *dto.TransferCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						switch e.AccountID {
+						case origin:
+							testutil.AssertEq(t, "origin entry type", entity.Debit, e.Type)
+						case d.Destination:
+							testutil.AssertEq(t, "destination entry type", entity.Credit, e.Type)
+						default:
+							t.Fatalf("unexpected method call")
+						}
+						testutil.AssertEq(t, "amount", d.Amount, types.BRL.Decimal(e.Amount))
+						if e.TransferID == nil || *e.TransferID != 1 {
+							t.Errorf("expected entry bound to transfer '1' but got '%v'", e.TransferID)
+						}
+						return int64(1), nil
 					},
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      500,
+				Amount:      "500.00",
 			},
 			assertErr: testutil.AssertNoErr,
 		},
 		{
-			name: "create transfer with insufficient funds",
+			name: "create transfer with currency conversion successfully",
+			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.Transfer) (int64, error) {
+						testutil.AssertEq(t, "amount", types.NewCurrency(100), e.Amount)
+						testutil.AssertEq(t, "currency", types.BRL, e.Currency)
+						testutil.AssertEq(t, "destination amount", types.NewCurrency(19.32), e.DestinationAmount)
+						testutil.AssertEq(t, "destination currency", types.USD, e.DestinationCurrency)
+						testutil.AssertEq(t, "rate", types.Decimal("0.19325"), e.Rate)
+						return int64(1), nil
+					},
+				}
+			},
+			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: lock,
+					ExpectGet:  getUSD,
+					ExpectUpdateBalance: func(ctx context.Context, i int64, b types.Currency) error {
+						switch i {
+						case origin:
+							testutil.AssertEq(t, "origin balance", types.NewCurrency(400), b)
+						case d.Destination:
+							testutil.AssertEq(t, "destination balance", types.NewCurrency(519.32), b)
+						default:
+							t.Fatalf("unexpected method call")
+						}
+						return nil
+					},
+				}
+			},
+			ledgerRepo: func(origin int64, d *dto.TransferCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						switch e.AccountID {
+						case origin:
+							testutil.AssertEq(t, "origin entry amount", types.NewCurrency(100), e.Amount)
+						case d.Destination:
+							testutil.AssertEq(t, "destination entry amount", types.NewCurrency(19.32), e.Amount)
+						default:
+							t.Fatalf("unexpected method call")
+						}
+						return int64(1), nil
+					},
+				}
+			},
+			rateProvider: func() fx.RateProvider {
+				return &testutil.RateProviderMock{
+					ExpectRate: func(ctx context.Context, from, to types.CurrencyCode) (types.Decimal, error) {
+						testutil.AssertEq(t, "from", types.BRL, from)
+						testutil.AssertEq(t, "to", types.USD, to)
+						return "0.19325", nil
+					},
+				}
+			},
+			origin: 1,
+			d: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "100",
+				Convert:     true,
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "create transfer with rate provider error",
 			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						return types.NewCurrency(0), nil
+					ExpectGet: getUSD,
+				}
+			},
+			rateProvider: func() fx.RateProvider {
+				return &testutil.RateProviderMock{
+					ExpectRate: func(ctx context.Context, from, to types.CurrencyCode) (types.Decimal, error) {
+						return "", types.NewErr(types.ValidationErr, "no exchange rate available from 'BRL' to 'USD'", nil)
 					},
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      500,
+				Amount:      "100",
+				Convert:     true,
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "no exchange rate available from 'BRL' to 'USD'")
+			},
+		},
+		{
+			name: "create transfer converting the amount to a missing account",
+			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						if i == d.Destination {
+							return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account by id", nil)
+						}
+						return testutil.NewEntityAccount(i, "Ana", "71453945024", "pw", 500), nil
+					},
+				}
+			},
+			rateProvider: func() fx.RateProvider {
+				return &testutil.RateProviderMock{}
+			},
+			origin: 1,
+			d: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "100",
+				Convert:     true,
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "record with 'destination' equals '2' was not found")
+			},
+		},
+		{
+			name: "create transfer with insufficient funds",
+			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: lock,
+					ExpectGet:  getStack(0),
+				}
+			},
+			origin: 1,
+			d: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "500.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.ValidationErr, err, "the origin must have a balance greater than or equal to 500.00")
@@ -182,74 +507,75 @@ func TestTransferServiceCreate(t *testing.T) {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				return &testutil.AccountRepoMock{}
+				return &testutil.AccountRepoMock{
+					ExpectLock: lock,
+				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 1,
-				Amount:      100,
+				Amount:      "100.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.ConflictErr, err, "fields 'origin id' and 'destination id' can't be the same")
 			},
 		},
 		{
-			name: "create transfer with repository error getting origin balance",
+			name: "create transfer with repository error locking accounts",
 			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				balanceStack := []float64{500}
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						if len(balanceStack) == 0 {
-							return 0, types.NewErr(types.InternalErr, "internal error", nil)
-						}
-						b := types.NewCurrency(balanceStack[0])
-						balanceStack = balanceStack[1:]
-						return b, nil
-					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", d.Destination, i)
-						return true, nil
+					ExpectLock: func(c context.Context, ids ...int64) error {
+						return types.NewErr(types.SelectStmtErr, "locking account rows", nil)
 					},
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      100,
+				Amount:      "100.00",
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.SelectStmtErr, err, "locking account rows")
+			},
+		},
+		{
+			name: "create transfer with repository error getting origin account",
+			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
+				return &testutil.TransferRepoMock{}
+			},
+			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: lock,
+					ExpectGet:  getStack(500, 500),
+				}
+			},
+			origin: 1,
+			d: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "100.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.InternalErr, err, "internal error")
 			},
 		},
 		{
-			name: "create transfer with repository error getting destination balance",
+			name: "create transfer with repository error getting destination account",
 			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				balanceStack := []float64{500, 500}
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						if len(balanceStack) == 0 {
-							return 0, types.NewErr(types.InternalErr, "internal error", nil)
-						}
-						b := types.NewCurrency(balanceStack[0])
-						balanceStack = balanceStack[1:]
-						return b, nil
-					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", d.Destination, i)
-						return true, nil
-					},
+					ExpectLock: lock,
+					ExpectGet:  getStack(500, 500, 500),
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      100,
+				Amount:      "100.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.InternalErr, err, "internal error")
@@ -261,16 +587,9 @@ func TestTransferServiceCreate(t *testing.T) {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				balanceStack := []float64{500, 500, 0}
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						if len(balanceStack) == 0 {
-							return 0, types.NewErr(types.InternalErr, "internal error", nil)
-						}
-						b := types.NewCurrency(balanceStack[0])
-						balanceStack = balanceStack[1:]
-						return b, nil
-					},
+					ExpectLock: lock,
+					ExpectGet:  getStack(500, 500, 500, 500),
 					ExpectUpdateBalance: func(ctx context.Context, i int64, b types.Currency) error {
 						if i == origin {
 							return types.NewErr(types.InternalErr, "internal error", nil)
@@ -278,16 +597,12 @@ func TestTransferServiceCreate(t *testing.T) {
 						t.Fatalf("unexpected method call")
 						return nil
 					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", d.Destination, i)
-						return true, nil
-					},
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      100,
+				Amount:      "100.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.InternalErr, err, "internal error")
@@ -299,16 +614,9 @@ func TestTransferServiceCreate(t *testing.T) {
 				return &testutil.TransferRepoMock{}
 			},
 			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
-				balanceStack := []float64{500, 500, 0}
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						if len(balanceStack) == 0 {
-							return 0, types.NewErr(types.InternalErr, "internal error", nil)
-						}
-						b := types.NewCurrency(balanceStack[0])
-						balanceStack = balanceStack[1:]
-						return b, nil
-					},
+					ExpectLock: lock,
+					ExpectGet:  getStack(500, 500, 500, 500),
 					ExpectUpdateBalance: func(ctx context.Context, i int64, b types.Currency) error {
 						switch i {
 						case origin:
@@ -320,28 +628,66 @@ func TestTransferServiceCreate(t *testing.T) {
 						}
 						return nil
 					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", d.Destination, i)
-						return true, nil
-					},
 				}
 			},
 			origin: 1,
 			d: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      100,
+				Amount:      "100.00",
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.InternalErr, err, "internal error")
 			},
 		},
+		{
+			name: "create transfer with ledger repository error",
+			transferRepo: func(origin int64, d *dto.TransferCreation) repository.Transfer {
+				return &testutil.TransferRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.Transfer) (int64, error) {
+						return int64(1), nil
+					},
+				}
+			},
+			accountRepo: func(origin int64, d *dto.TransferCreation) repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectLock: lock,
+					ExpectGet:  getStack(500, 500, 500, 500),
+					ExpectUpdateBalance: func(ctx context.Context, i int64, b types.Currency) error {
+						return nil
+					},
+				}
+			},
+			ledgerRepo: func(origin int64, d *dto.TransferCreation) repository.Ledger {
+				return &testutil.LedgerRepoMock{
+					ExpectCreate: func(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+						return 0, types.NewErr(types.InsertStmtErr, "exec ledger entry insert stmt", nil)
+					},
+				}
+			},
+			origin: 1,
+			d: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "100.00",
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.InsertStmtErr, err, "exec ledger entry insert stmt")
+			},
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			transferRepo := tc.transferRepo(tc.origin, tc.d)
 			accRepo := tc.accountRepo(tc.origin, tc.d)
-			s := service.NewTransfer(&txr, &transferRepo, &accRepo)
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{}
+			if tc.ledgerRepo != nil {
+				ledgerRepo = tc.ledgerRepo(tc.origin, tc.d)
+			}
+			var rateProvider fx.RateProvider = &testutil.RateProviderMock{}
+			if tc.rateProvider != nil {
+				rateProvider = tc.rateProvider()
+			}
+			s := service.NewTransfer(&txr, &transferRepo, &accRepo, &ledgerRepo, &rateProvider)
 			view, err := s.Create(context.Background(), tc.origin, *tc.d)
 			if err == nil && tc.assertErr == nil {
 				testutil.AssertNotDefault(t, "id", view.ID)
@@ -354,3 +700,177 @@ func TestTransferServiceCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestTransferServiceReverse(t *testing.T) {
+	// partial returns a transfer of 10.00 from account 1 to account 2 of which reversed was already given back
+	partial := func(reversed float64) entity.Transfer {
+		e := testutil.NewEntityTransfer(3, 1, 2, 10)
+		e.ReversedAmount = types.NewCurrency(reversed)
+		return e
+	}
+	// converted returns a transfer of 100.00 BRL exchanged into 18.75 USD, of which 10.00 USD were already given back
+	converted := testutil.NewEntityTransfer(3, 1, 2, 100)
+	converted.DestinationAmount, converted.DestinationCurrency, converted.Rate = 1875, types.USD, "0.1875"
+	converted.ReversedAmount = 1000
+	reversal := testutil.NewEntityTransfer(4, 2, 1, 10)
+	reversal.ReversalOf = &converted.ID
+	tt := []struct {
+		name             string
+		requester        dto.Requester
+		original         entity.Transfer
+		d                dto.TransferReversal
+		balances         map[int64]float64
+		destinationUSD   bool
+		expectedBalances map[int64]types.Currency
+		expectedCredit   types.Currency
+		expectedReversed types.Currency
+		expectedRate     types.Decimal
+		assertErr        func(*testing.T, error)
+	}{
+		{
+			name:             "reverse transfer wholly as the destination owner",
+			requester:        dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			original:         partial(0),
+			balances:         map[int64]float64{1: 0, 2: 50},
+			expectedBalances: map[int64]types.Currency{1: 1000, 2: 4000},
+			expectedCredit:   1000,
+			expectedReversed: 1000,
+			expectedRate:     "1",
+			assertErr:        testutil.AssertNoErr,
+		},
+		{
+			name:             "reverse transfer partially as support",
+			requester:        dto.Requester{ID: 5, Role: entity.RoleSupport},
+			original:         partial(4),
+			d:                dto.TransferReversal{Amount: "2.50"},
+			balances:         map[int64]float64{1: 0, 2: 50},
+			expectedBalances: map[int64]types.Currency{1: 250, 2: 4750},
+			expectedCredit:   250,
+			expectedReversed: 650,
+			expectedRate:     "1",
+			assertErr:        testutil.AssertNoErr,
+		},
+		{
+			name:             "reverse the remaining of a converted transfer",
+			requester:        dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			original:         converted,
+			balances:         map[int64]float64{1: 0, 2: 50},
+			destinationUSD:   true,
+			expectedBalances: map[int64]types.Currency{1: 4667, 2: 4125},
+			expectedCredit:   4667,
+			expectedReversed: 1875,
+			expectedRate:     "5.333333333333",
+			assertErr:        testutil.AssertNoErr,
+		},
+		{
+			name:      "reverse transfer as the origin owner",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			original:  partial(0),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '1' isn't allowed to reverse transfer '3'")
+			},
+		},
+		{
+			name:      "reverse transfer between other accounts",
+			requester: dto.Requester{ID: 5, Role: entity.RoleCustomer},
+			original:  partial(0),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "transfer '3' was not found")
+			},
+		},
+		{
+			name:      "reverse transfer without the destination funds",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			original:  partial(0),
+			balances:  map[int64]float64{1: 0, 2: 5},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the destination must have a balance greater than or equal to 10.00")
+			},
+		},
+		{
+			name:      "reverse more than the remaining amount",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			original:  partial(4),
+			d:         dto.TransferReversal{Amount: "6.01"},
+			balances:  map[int64]float64{1: 0, 2: 50},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be less than or equal to 6.00")
+			},
+		},
+		{
+			name:      "reverse transfer already fully reversed",
+			requester: dto.Requester{ID: 2, Role: entity.RoleCustomer},
+			original:  partial(10),
+			balances:  map[int64]float64{1: 0, 2: 50},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "transfer '3' was already fully reversed")
+			},
+		},
+		{
+			name:      "reverse a reversal",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			original:  reversal,
+			balances:  map[int64]float64{1: 50, 2: 0},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "transfer '4' is a reversal and can't be reversed")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var transferRepo repository.Transfer = &testutil.TransferRepoMock{
+				ExpectGet: func(c context.Context, id int64) (entity.Transfer, error) {
+					testutil.AssertEq(t, "id", tc.original.ID, id)
+					return tc.original, nil
+				},
+				ExpectCreate: func(c context.Context, e entity.Transfer) (int64, error) {
+					testutil.AssertEq(t, "origin", tc.original.Destination, e.Origin)
+					testutil.AssertEq(t, "destination", tc.original.Origin, e.Destination)
+					testutil.AssertEq(t, "currency", tc.original.DestinationCurrency, e.Currency)
+					testutil.AssertEq(t, "credited amount", tc.expectedCredit, e.DestinationAmount)
+					testutil.AssertEq(t, "rate", tc.expectedRate, e.Rate)
+					testutil.AssertEq(t, "reversal of", tc.original.ID, *e.ReversalOf)
+					return 7, nil
+				},
+				ExpectUpdateReversedAmount: func(c context.Context, id int64, reversed types.Currency) error {
+					testutil.AssertEq(t, "id", tc.original.ID, id)
+					testutil.AssertEq(t, "reversed amount", tc.expectedReversed, reversed)
+					return nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectLock: func(c context.Context, ids ...int64) error {
+					testutil.AssertEq(t, "locked ids", 2, len(ids))
+					return nil
+				},
+				ExpectGet: func(c context.Context, id int64) (entity.Account, error) {
+					acc := testutil.NewEntityAccount(id, "Ana", "71453945024", "pw", tc.balances[id])
+					if tc.destinationUSD && id == tc.original.Destination {
+						acc.Currency = types.USD
+					}
+					return acc, nil
+				},
+				ExpectUpdateBalance: func(c context.Context, id int64, b types.Currency) error {
+					testutil.AssertEq(t, "balance", tc.expectedBalances[id], b)
+					return nil
+				},
+			}
+			var ledgerRepo repository.Ledger = &testutil.LedgerRepoMock{
+				ExpectCreate: func(c context.Context, e entity.LedgerEntry) (int64, error) {
+					testutil.AssertEq(t, "transfer id", int64(7), *e.TransferID)
+					return 1, nil
+				},
+			}
+			var rateProvider fx.RateProvider = &testutil.RateProviderMock{}
+			s := service.NewTransfer(&txr, &transferRepo, &accRepo, &ledgerRepo, &rateProvider)
+			view, err := s.Reverse(context.Background(), tc.requester, tc.original.ID, tc.d)
+			tc.assertErr(t, err)
+			if err == nil {
+				testutil.AssertEq(t, "id", int64(7), view.ID)
+				testutil.AssertEq(t, "reversal of", tc.original.ID, *view.ReversalOf)
+				testutil.AssertEq(t, "reversal status", entity.ReversalNone, view.ReversalStatus)
+			}
+		})
+	}
+}
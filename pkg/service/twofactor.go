@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/otp"
+	"github.com/rs/zerolog/log"
+)
+
+// recoveryCodeCount is the number of recovery codes issued when the second factor is enabled
+const recoveryCodeCount = 10
+
+// StepUpPolicy maps each currency to the transfer amount above which a fresh authenticator code is required.
+// Transfers from accounts in currencies left out of the policy don't require it
+type StepUpPolicy map[types.CurrencyCode]types.Decimal
+
+// ParseStepUpPolicy reads a comma separated list of amounts prefixed by their currency codes, such as "BRL:5000,USD:1000".
+// An amount without a currency code applies to the default account currency
+func ParseStepUpPolicy(s string) (StepUpPolicy, error) {
+	policy := StepUpPolicy{}
+	if strings.TrimSpace(s) == "" {
+		return policy, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		code, amount := types.DefaultCurrencyCode, strings.TrimSpace(entry)
+		if i := strings.Index(amount, ":"); i >= 0 {
+			code, amount = types.CurrencyCode(strings.ToUpper(strings.TrimSpace(amount[:i]))), strings.TrimSpace(amount[i+1:])
+		}
+		if !code.Supported() {
+			return nil, fmt.Errorf("unsupported currency code '%s'", code)
+		}
+		if _, ok := policy[code]; ok {
+			return nil, fmt.Errorf("duplicated step-up amount of '%s'", code)
+		}
+		d, err := types.ParseDecimal(amount)
+		if err != nil {
+			return nil, err
+		}
+		policy[code] = d
+	}
+	return policy, nil
+}
+
+// TwoFactor exposes the business operations related to the TOTP second factor of the accounts.
+// Once enabled, it's required to log in and to transfer amounts above the step-up amount
+type TwoFactor interface {
+	Enroll(ctx context.Context, requester dto.Requester, id int64) (dto.TOTPEnrollment, error)
+	Activate(ctx context.Context, requester dto.Requester, id int64, code string) (dto.RecoveryCodes, error)
+	Verify(ctx context.Context, account int64, code string) error
+	StepUp(ctx context.Context, account int64, amount types.Decimal, code string) error
+}
+
+type twoFactor struct {
+	totpRepository         *repository.TOTP
+	recoveryCodeRepository *repository.RecoveryCode
+	accountRepository      *repository.Account
+	loginAttemptRepository *repository.LoginAttempt
+	txr                    *repository.Transactioner
+	issuer                 string
+	stepUp                 StepUpPolicy
+	loginPolicy            LoginPolicy
+}
+
+var _ TwoFactor = (*twoFactor)(nil)
+
+// NewTwoFactor returns a value responsible for managing entity.TOTP and entity.RecoveryCode integrity.
+// Key URIs are labeled after the issuer, and transfers above the step-up amount of the origin account currency require a fresh code.
+// Wrong step-up codes are counted as failed logins are, locking the step-up out according to loginPolicy
+func NewTwoFactor(txr *repository.Transactioner, totpRepository *repository.TOTP, recoveryCodeRepository *repository.RecoveryCode, accountRepository *repository.Account, loginAttemptRepository *repository.LoginAttempt, issuer string, stepUp StepUpPolicy, loginPolicy LoginPolicy) TwoFactor {
+	return &twoFactor{
+		totpRepository:         totpRepository,
+		recoveryCodeRepository: recoveryCodeRepository,
+		accountRepository:      accountRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		txr:                    txr,
+		issuer:                 issuer,
+		stepUp:                 stepUp,
+		loginPolicy:            loginPolicy,
+	}
+}
+
+// Enroll generates a new secret for the account, replacing any pending enrollment. The second factor stays disabled until activated
+func (srv *twoFactor) Enroll(ctx context.Context, requester dto.Requester, id int64) (view dto.TOTPEnrollment, err error) {
+	if requester.ID != id {
+		return view, accountAccessErr(requester, id)
+	}
+	current, err := srv.get(ctx, id)
+	if err != nil {
+		return view, err
+	}
+	if current.EnabledAt != nil {
+		return view, types.NewErr(types.ConflictErr, "the second factor is already enabled", nil)
+	}
+	secret, err := otp.NewSecret()
+	if err != nil {
+		return view, types.NewErr(types.InternalErr, "unable to generate the totp secret", err)
+	}
+	if err = (*srv.totpRepository).Save(ctx, entity.TOTP{AccountID: id, Secret: secret, CreatedAt: time.Now()}); err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", id).Msg("unable to save the totp enrollment")
+		return view, err
+	}
+	return dto.TOTPEnrollment{Secret: secret, URI: otp.URI(srv.issuer, strconv.FormatInt(id, 10), secret)}, nil
+}
+
+// Activate enables the enrolled second factor given a code generated from its secret, issuing a new set of recovery codes
+func (srv *twoFactor) Activate(ctx context.Context, requester dto.Requester, id int64, code string) (view dto.RecoveryCodes, err error) {
+	if requester.ID != id {
+		return view, accountAccessErr(requester, id)
+	}
+	current, err := srv.get(ctx, id)
+	if err != nil {
+		return view, err
+	}
+	if current.Secret == "" {
+		return view, types.NewErr(types.NotFoundErr, "the second factor wasn't enrolled", nil)
+	}
+	if current.EnabledAt != nil {
+		return view, types.NewErr(types.ConflictErr, "the second factor is already enabled", nil)
+	}
+	now := time.Now()
+	step, ok, err := otp.Validate(current.Secret, code, now)
+	if err != nil {
+		return view, types.NewErr(types.InternalErr, "unable to validate the totp code", err)
+	}
+	if !ok {
+		return view, types.NewErr(types.ValidationErr, "field 'code' doesn't match the enrolled secret", nil)
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b, err := randomBytes(5)
+		if err != nil {
+			return view, err
+		}
+		c := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes = append(codes, c[:4]+"-"+c[4:])
+		hashes = append(hashes, hashToken(c))
+	}
+	err = (*srv.txr).WithTx(ctx, func(txCtx context.Context) error {
+		if err := (*srv.totpRepository).Enable(txCtx, id, step, now); err != nil {
+			return err
+		}
+		return (*srv.recoveryCodeRepository).Replace(txCtx, id, hashes)
+	})
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", id).Msg("unable to enable the second factor")
+		return view, err
+	}
+	return dto.RecoveryCodes{Codes: codes}, nil
+}
+
+// Verify checks the second factor of a login, which is either a code generated by the authenticator or an unused recovery code.
+// Accounts that haven't enabled the second factor are let through
+func (srv *twoFactor) Verify(ctx context.Context, account int64, code string) error {
+	current, err := srv.get(ctx, account)
+	if err != nil || current.EnabledAt == nil {
+		return err
+	}
+	if code == "" {
+		return types.NewErr(types.AuthenticationErr, "the second factor code is required", nil)
+	}
+	if _, err = strconv.Atoi(code); err == nil && len(code) == otp.Digits {
+		return srv.accept(ctx, current, code, types.AuthenticationErr)
+	}
+	normalized := strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	err = (*srv.recoveryCodeRepository).Use(ctx, account, hashToken(normalized), time.Now())
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return types.NewErr(types.AuthenticationErr, "invalid second factor code", nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to use the recovery code")
+		return err
+	}
+	log.Info().Int64("account_id", account).Msg("recovery code used to log in")
+	return nil
+}
+
+// StepUp requires a fresh code from the authenticator to transfer an amount above the step-up amount of the account currency.
+// Recovery codes aren't accepted, and accounts that haven't enabled the second factor can't transfer such amounts.
+// The step-up of an account presenting too many wrong codes in a row is refused with a types.LockedErr for a while
+func (srv *twoFactor) StepUp(ctx context.Context, account int64, amount types.Decimal, code string) error {
+	if len(srv.stepUp) == 0 {
+		return nil
+	}
+	acc, err := (*srv.accountRepository).Get(ctx, account)
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to get the account of the step-up")
+		return err
+	}
+	limit, ok := srv.stepUp[acc.Currency]
+	if !ok {
+		return nil
+	}
+	exceeds, err := greater(amount, limit)
+	if err != nil || !exceeds {
+		return err
+	}
+	current, err := srv.get(ctx, account)
+	if err != nil {
+		return err
+	}
+	if current.EnabledAt == nil {
+		return types.NewErr(types.AuthorizationErr, fmt.Sprintf("transfers above %s %s require the second factor to be enabled", limit, acc.Currency), nil)
+	}
+	if code == "" {
+		return types.NewErr(types.AuthorizationErr, fmt.Sprintf("transfers above %s %s require a second factor code", limit, acc.Currency), nil)
+	}
+	key := stepUpKey(account)
+	attempt, err := (*srv.loginAttemptRepository).Get(ctx, key)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		err = nil
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Str("key", key).Msg("unable to get the step-up attempts")
+		return err
+	}
+	now := time.Now()
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		return types.NewErr(types.LockedErr, fmt.Sprintf("too many wrong second factor codes, locked out until %s", attempt.LockedUntil.UTC().Format(time.RFC3339)), nil)
+	}
+	err = srv.accept(ctx, current, code, types.AuthorizationErr)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.AuthorizationErr {
+		// The code is still refused as such if the failure can't be counted
+		srv.failStepUp(ctx, key, now)
+		return err
+	}
+	if err == nil && attempt.Failures > 0 {
+		if err := (*srv.loginAttemptRepository).Delete(ctx, key); err != nil {
+			log.Error().Caller().Err(err).Str("key", key).Msg("unable to reset the step-up attempts")
+		}
+	}
+	return err
+}
+
+// failStepUp counts a wrong step-up code, locking the step-up out once it reaches the policy max attempts.
+// Failures older than the lockout duration are forgotten
+func (srv *twoFactor) failStepUp(ctx context.Context, key string, now time.Time) {
+	attempt, err := (*srv.loginAttemptRepository).Increment(ctx, key, now, now.Add(-srv.loginPolicy.Lockout))
+	if err != nil {
+		log.Error().Caller().Err(err).Str("key", key).Msg("unable to count the wrong step-up code")
+		return
+	}
+	if attempt.Failures < srv.loginPolicy.MaxAttempts {
+		return
+	}
+	if err = (*srv.loginAttemptRepository).Lock(ctx, key, now.Add(srv.loginPolicy.Lockout)); err != nil {
+		log.Error().Caller().Err(err).Str("key", key).Msg("unable to lock the step-up out")
+		return
+	}
+	log.Warn().Str("key", key).Int("failures", attempt.Failures).Msg("step-up locked out after too many wrong second factor codes")
+}
+
+// get returns the second factor of the account, which is zero'd when it was never enrolled
+func (srv *twoFactor) get(ctx context.Context, account int64) (entity.TOTP, error) {
+	e, err := (*srv.totpRepository).Get(ctx, account)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return entity.TOTP{}, nil
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", account).Msg("unable to get the totp")
+		return e, err
+	}
+	return e, nil
+}
+
+// accept validates the authenticator code, rejecting it with errCode when it doesn't match or was already accepted
+func (srv *twoFactor) accept(ctx context.Context, current entity.TOTP, code string, errCode types.ErrCode) error {
+	step, ok, err := otp.Validate(current.Secret, code, time.Now())
+	if err != nil {
+		return types.NewErr(types.InternalErr, "unable to validate the totp code", err)
+	}
+	if !ok {
+		return types.NewErr(errCode, "invalid second factor code", nil)
+	}
+	if step <= current.LastStep {
+		return types.NewErr(errCode, "the second factor code was already used", nil)
+	}
+	// The last step is compared again by the update, as the same code may be presented concurrently
+	err = (*srv.totpRepository).Accept(ctx, current.AccountID, step)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.NoRowAffectedErr {
+		return types.NewErr(errCode, "the second factor code was already used", nil)
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Int64("account_id", current.AccountID).Msg("unable to accept the totp code")
+	}
+	return err
+}
+
+// greater tells whether the amount a is greater than b
+func greater(a types.Decimal, b types.Decimal) (bool, error) {
+	exp := a.Scale()
+	if b.Scale() > exp {
+		exp = b.Scale()
+	}
+	x, err := a.Currency(exp)
+	if err != nil {
+		return false, err
+	}
+	y, err := b.Currency(exp)
+	if err != nil {
+		return false, err
+	}
+	return x > y, nil
+}
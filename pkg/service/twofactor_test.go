@@ -0,0 +1,499 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/otp"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+const totpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func currentCode(t *testing.T) string {
+	code, err := otp.Code(totpSecret, otp.Step(time.Now()))
+	testutil.AssertNoErr(t, err)
+	return code
+}
+
+func enabledTOTP(lastStep int64) entity.TOTP {
+	enabledAt := time.Now()
+	return entity.TOTP{AccountID: 1, Secret: totpSecret, EnabledAt: &enabledAt, LastStep: lastStep}
+}
+
+func TestTwoFactorServiceEnroll(t *testing.T) {
+	tt := []struct {
+		name      string
+		requester dto.Requester
+		current   entity.TOTP
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "enroll successfully",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "enroll again while pending",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			current:   entity.TOTP{AccountID: 1, Secret: totpSecret},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "enroll while enabled",
+			requester: dto.Requester{ID: 1, Role: entity.RoleCustomer},
+			current:   enabledTOTP(0),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "the second factor is already enabled")
+			},
+		},
+		{
+			name:      "enroll another account as admin",
+			requester: dto.Requester{ID: 2, Role: entity.RoleAdmin},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "account '2' isn't allowed to access account '1'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var saved entity.TOTP
+			var repo repository.TOTP = &testutil.TOTPRepoMock{
+				ExpectGet: func(ctx context.Context, account int64) (entity.TOTP, error) {
+					if tc.current.AccountID == 0 {
+						return entity.TOTP{}, types.NewErr(types.EmptyResultErr, "no result getting totp", nil)
+					}
+					return tc.current, nil
+				},
+				ExpectSave: func(ctx context.Context, e entity.TOTP) error {
+					saved = e
+					return nil
+				},
+			}
+			var recoveryRepo repository.RecoveryCode = &testutil.RecoveryCodeRepoMock{}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var attemptRepo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{}
+			s := service.NewTwoFactor(&txr, &repo, &recoveryRepo, &accRepo, &attemptRepo, "stn-accounts", nil, loginPolicy)
+			view, err := s.Enroll(context.Background(), tc.requester, 1)
+			tc.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			testutil.AssertEq(t, "account id", int64(1), saved.AccountID)
+			testutil.AssertEq(t, "secret", saved.Secret, view.Secret)
+			if saved.Secret == tc.current.Secret {
+				t.Errorf("expected a new secret")
+			}
+			if !strings.HasPrefix(view.URI, "otpauth://totp/stn-accounts:1?") {
+				t.Errorf("unexpected key uri '%s'", view.URI)
+			}
+		})
+	}
+}
+
+func TestTwoFactorServiceActivate(t *testing.T) {
+	tt := []struct {
+		name      string
+		current   entity.TOTP
+		code      func(*testing.T) string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "activate successfully",
+			current:   entity.TOTP{AccountID: 1, Secret: totpSecret},
+			code:      currentCode,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:    "activate with wrong code",
+			current: entity.TOTP{AccountID: 1, Secret: totpSecret},
+			code:    func(*testing.T) string { return "abcdef" },
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'code' doesn't match the enrolled secret")
+			},
+		},
+		{
+			name: "activate without enrollment",
+			code: currentCode,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.NotFoundErr, err, "the second factor wasn't enrolled")
+			},
+		},
+		{
+			name:    "activate while enabled",
+			current: enabledTOTP(0),
+			code:    currentCode,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "the second factor is already enabled")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var hashes []string
+			var repo repository.TOTP = &testutil.TOTPRepoMock{
+				ExpectGet: func(ctx context.Context, account int64) (entity.TOTP, error) {
+					if tc.current.AccountID == 0 {
+						return entity.TOTP{}, types.NewErr(types.EmptyResultErr, "no result getting totp", nil)
+					}
+					return tc.current, nil
+				},
+				ExpectEnable: func(ctx context.Context, account int64, step int64, at time.Time) error {
+					testutil.AssertEq(t, "account", int64(1), account)
+					testutil.AssertEq(t, "step", otp.Step(time.Now()), step)
+					return nil
+				},
+			}
+			var recoveryRepo repository.RecoveryCode = &testutil.RecoveryCodeRepoMock{
+				ExpectReplace: func(ctx context.Context, account int64, h []string) error {
+					hashes = h
+					return nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var attemptRepo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{}
+			s := service.NewTwoFactor(&txr, &repo, &recoveryRepo, &accRepo, &attemptRepo, "stn-accounts", nil, loginPolicy)
+			view, err := s.Activate(context.Background(), dto.Requester{ID: 1}, 1, tc.code(t))
+			tc.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			testutil.AssertEq(t, "recovery codes", 10, len(view.Codes))
+			testutil.AssertEq(t, "recovery code hashes", 10, len(hashes))
+			testutil.AssertEq(t, "recovery code length", 9, len(view.Codes[0]))
+		})
+	}
+}
+
+func TestTwoFactorServiceVerify(t *testing.T) {
+	tt := []struct {
+		name      string
+		current   entity.TOTP
+		code      func(*testing.T) string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "verify account without second factor",
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "verify pending enrollment",
+			current:   entity.TOTP{AccountID: 1, Secret: totpSecret},
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "verify authenticator code successfully",
+			current:   enabledTOTP(0),
+			code:      currentCode,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:    "verify without code",
+			current: enabledTOTP(0),
+			code:    func(*testing.T) string { return "" },
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the second factor code is required")
+			},
+		},
+		{
+			name:    "verify replayed authenticator code",
+			current: enabledTOTP(otp.Step(time.Now()) + 1),
+			code:    currentCode,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "the second factor code was already used")
+			},
+		},
+		{
+			name:      "verify recovery code successfully",
+			current:   enabledTOTP(0),
+			code:      func(*testing.T) string { return "ABCD-EFGH" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:    "verify used recovery code",
+			current: enabledTOTP(0),
+			code:    func(*testing.T) string { return "abcd-efgz" },
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthenticationErr, err, "invalid second factor code")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.TOTP = &testutil.TOTPRepoMock{
+				ExpectGet: func(ctx context.Context, account int64) (entity.TOTP, error) {
+					if tc.current.AccountID == 0 {
+						return entity.TOTP{}, types.NewErr(types.EmptyResultErr, "no result getting totp", nil)
+					}
+					return tc.current, nil
+				},
+				ExpectAccept: func(ctx context.Context, account int64, step int64) error {
+					return nil
+				},
+			}
+			var recoveryRepo repository.RecoveryCode = &testutil.RecoveryCodeRepoMock{
+				ExpectUse: func(ctx context.Context, account int64, hash string, at time.Time) error {
+					// sha256 of "abcdefgh"
+					if hash != "9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab" {
+						return types.NewErr(types.NoRowAffectedErr, "no rows affected by the use recovery code stmt", nil)
+					}
+					return nil
+				},
+			}
+			var accRepo repository.Account = &testutil.AccountRepoMock{}
+			var attemptRepo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{}
+			s := service.NewTwoFactor(&txr, &repo, &recoveryRepo, &accRepo, &attemptRepo, "stn-accounts", nil, loginPolicy)
+			tc.assertErr(t, s.Verify(context.Background(), 1, tc.code(t)))
+		})
+	}
+}
+
+func TestTwoFactorServiceStepUp(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+	tt := []struct {
+		name      string
+		policy    service.StepUpPolicy
+		currency  types.CurrencyCode
+		amount    types.Decimal
+		current   entity.TOTP
+		attempt   entity.LoginAttempt
+		code      func(*testing.T) string
+		calls     string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "step up disabled",
+			amount:    "100000",
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "step up of an amount below the limit",
+			policy:    service.StepUpPolicy{types.BRL: "1000"},
+			amount:    "1000.00",
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "step up with a fresh code",
+			policy:    service.StepUpPolicy{types.BRL: "1000"},
+			amount:    "1000.01",
+			current:   enabledTOTP(0),
+			code:      currentCode,
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "step up without second factor",
+			policy: service.StepUpPolicy{types.BRL: "1000"},
+			amount: "1000.01",
+			code:   currentCode,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "transfers above 1000 BRL require the second factor to be enabled")
+			},
+		},
+		{
+			name:    "step up without code",
+			policy:  service.StepUpPolicy{types.BRL: "1000"},
+			amount:  "5000",
+			current: enabledTOTP(0),
+			code:    func(*testing.T) string { return "" },
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "transfers above 1000 BRL require a second factor code")
+			},
+		},
+		{
+			name:      "step up of an account in a currency without limit",
+			policy:    service.StepUpPolicy{types.BRL: "1000"},
+			currency:  types.JPY,
+			amount:    "5000",
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "step up of an account below the limit of its currency",
+			policy:    service.StepUpPolicy{types.BRL: "5000", types.USD: "1000"},
+			currency:  types.USD,
+			amount:    "1000.00",
+			code:      func(*testing.T) string { return "" },
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:     "step up of an account above the limit of its currency",
+			policy:   service.StepUpPolicy{types.BRL: "5000", types.USD: "1000"},
+			currency: types.USD,
+			amount:   "1500",
+			current:  enabledTOTP(0),
+			code:     func(*testing.T) string { return "" },
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "transfers above 1000 USD require a second factor code")
+			},
+		},
+		{
+			name:    "step up with a recovery code",
+			policy:  service.StepUpPolicy{types.BRL: "1000"},
+			amount:  "5000",
+			current: enabledTOTP(0),
+			code:    func(*testing.T) string { return "abcd-efgh" },
+			calls:   "increment",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "invalid second factor code")
+			},
+		},
+		{
+			name:    "step up with a wrong code reaching the max attempts",
+			policy:  service.StepUpPolicy{types.BRL: "1000"},
+			amount:  "5000",
+			current: enabledTOTP(0),
+			attempt: entity.LoginAttempt{Key: "step-up:1", Failures: 2},
+			code:    func(*testing.T) string { return "abcd-efgh" },
+			calls:   "increment lock",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.AuthorizationErr, err, "invalid second factor code")
+			},
+		},
+		{
+			name:    "step up locked out after too many wrong codes",
+			policy:  service.StepUpPolicy{types.BRL: "1000"},
+			amount:  "5000",
+			current: enabledTOTP(0),
+			attempt: entity.LoginAttempt{Key: "step-up:1", Failures: 3, LockedUntil: &lockedUntil},
+			code:    currentCode,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.LockedErr, err, "too many wrong second factor codes, locked out until "+lockedUntil.UTC().Format(time.RFC3339))
+			},
+		},
+		{
+			name:      "step up with a fresh code after wrong ones",
+			policy:    service.StepUpPolicy{types.BRL: "1000"},
+			amount:    "5000",
+			current:   enabledTOTP(0),
+			attempt:   entity.LoginAttempt{Key: "step-up:1", Failures: 2},
+			code:      currentCode,
+			calls:     "delete",
+			assertErr: testutil.AssertNoErr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.TOTP = &testutil.TOTPRepoMock{
+				ExpectGet: func(ctx context.Context, account int64) (entity.TOTP, error) {
+					if tc.current.AccountID == 0 {
+						return entity.TOTP{}, types.NewErr(types.EmptyResultErr, "no result getting totp", nil)
+					}
+					return tc.current, nil
+				},
+				ExpectAccept: func(ctx context.Context, account int64, step int64) error {
+					return nil
+				},
+			}
+			var recoveryRepo repository.RecoveryCode = &testutil.RecoveryCodeRepoMock{}
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					acc := testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0)
+					if tc.currency != "" {
+						acc.Currency = tc.currency
+					}
+					return acc, nil
+				},
+			}
+			var calls []string
+			var attemptRepo repository.LoginAttempt = &testutil.LoginAttemptRepoMock{
+				ExpectGet: func(ctx context.Context, key string) (entity.LoginAttempt, error) {
+					testutil.AssertEq(t, "key", "step-up:1", key)
+					if tc.attempt.Key == "" {
+						return entity.LoginAttempt{}, types.NewErr(types.EmptyResultErr, "no result getting login attempt", nil)
+					}
+					return tc.attempt, nil
+				},
+				ExpectIncrement: func(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error) {
+					testutil.AssertEq(t, "since", loginPolicy.Lockout, at.Sub(since))
+					calls = append(calls, "increment")
+					return entity.LoginAttempt{Key: key, Failures: tc.attempt.Failures + 1, LastFailureAt: at}, nil
+				},
+				ExpectLock: func(ctx context.Context, key string, until time.Time) error {
+					calls = append(calls, "lock")
+					return nil
+				},
+				ExpectDelete: func(ctx context.Context, key string) error {
+					calls = append(calls, "delete")
+					return nil
+				},
+			}
+			s := service.NewTwoFactor(&txr, &repo, &recoveryRepo, &accRepo, &attemptRepo, "stn-accounts", tc.policy, loginPolicy)
+			tc.assertErr(t, s.StepUp(context.Background(), 1, tc.amount, tc.code(t)))
+			testutil.AssertEq(t, "calls", tc.calls, strings.Join(calls, " "))
+		})
+	}
+}
+
+func TestParseStepUpPolicy(t *testing.T) {
+	tt := []struct {
+		name      string
+		s         string
+		expected  service.StepUpPolicy
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "parse empty step-up policy",
+			expected:  service.StepUpPolicy{},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "parse step-up amount without currency",
+			s:         "1000",
+			expected:  service.StepUpPolicy{types.BRL: "1000"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "parse step-up amounts of several currencies",
+			s:         "BRL:5000, usd:1000.50,JPY:150000",
+			expected:  service.StepUpPolicy{types.BRL: "5000", types.USD: "1000.50", types.JPY: "150000"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "parse step-up amount of an unsupported currency",
+			s:    "BRL:5000,XYZ:10",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertEq(t, "err", "unsupported currency code 'XYZ'", err.Error())
+			},
+		},
+		{
+			name: "parse duplicated step-up amount",
+			s:    "1000,BRL:5000",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertEq(t, "err", "duplicated step-up amount of 'BRL'", err.Error())
+			},
+		},
+		{
+			name: "parse invalid step-up amount",
+			s:    "USD:1k",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "invalid decimal value '1k'")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := service.ParseStepUpPolicy(tc.s)
+			tc.assertErr(t, err)
+			testutil.AssertEq(t, "size", len(tc.expected), len(policy))
+			for code, amount := range tc.expected {
+				testutil.AssertEq(t, string(code), amount, policy[code])
+			}
+		})
+	}
+}
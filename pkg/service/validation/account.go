@@ -10,6 +10,7 @@ import (
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 )
 
@@ -18,6 +19,18 @@ var _hasElevenDigits = regexp.MustCompile(`^([\d]{11})$`).MatchString
 // Account keeps the validation for operations related to entity.Account
 type Account struct {
 	AccountRepository *repository.Account
+	SecretStrength    SecretStrength
+}
+
+// Filter validates the values narrowing and paging an account listing
+func (v *Account) Filter(accountFilter dto.AccountFilter) error {
+	if err := verifyPage(accountFilter.PageRequest, "created_at", "name"); err != nil {
+		return err
+	}
+	if !accountFilter.CreatedFrom.IsZero() && !accountFilter.CreatedTo.IsZero() && !accountFilter.CreatedFrom.Before(accountFilter.CreatedTo) {
+		return types.NewErr(types.ValidationErr, "field 'created_from' must be before 'created_to'", nil)
+	}
+	return nil
 }
 
 // Creation validates the creation of a new entity.Account
@@ -28,10 +41,17 @@ func (v *Account) Creation(ctx context.Context, accountCreation dto.AccountCreat
 	if err := verifyCPF(accountCreation.CPF); err != nil {
 		return err
 	}
-	if err := verifySecret(accountCreation.Secret); err != nil {
+	if err := v.SecretStrength.verifyStrength(accountCreation.Secret, accountCreation.CPF, accountCreation.Name); err != nil {
+		return err
+	}
+	if !accountCreation.Currency.Supported() {
+		return unsupportedCurrencyErr("currency", accountCreation.Currency)
+	}
+	balance, err := verifyAmount("balance", accountCreation.Balance, accountCreation.Currency)
+	if err != nil {
 		return err
 	}
-	if accountCreation.Balance < 0 {
+	if balance < 0 {
 		return greaterOrEqualErr("balance", 0)
 	}
 
@@ -42,6 +62,14 @@ func (v *Account) Creation(ctx context.Context, accountCreation dto.AccountCreat
 	return nil
 }
 
+// Patch validates the changes to the mutable entity.Account fields
+func (v *Account) Patch(patch dto.AccountPatch) error {
+	if patch.Name != nil {
+		return verifyName(*patch.Name)
+	}
+	return nil
+}
+
 // Login validates the creation of a new entity.Account
 func (v *Account) Login(cpf string, secret string) error {
 	if err := verifyCPF(cpf); err != nil {
@@ -53,6 +81,48 @@ func (v *Account) Login(cpf string, secret string) error {
 	return nil
 }
 
+// StatusChange validates the change of the given entity.Account status, which must be justified by a reason.
+// Closed accounts are never reopened, and only accounts with a zero balance are closed
+func (v *Account) StatusChange(account entity.Account, status entity.Status, reason string) error {
+	if !status.Supported() {
+		return oneOfErr("status", []string{string(entity.StatusActive), string(entity.StatusBlocked), string(entity.StatusClosed)})
+	}
+	switch reasonLength := len(reason); {
+	case reasonLength == 0:
+		return requiredFieldErr("reason")
+	case reasonLength > entity.StatusReasonSize:
+		return maxSizeErr("reason", entity.StatusReasonSize)
+	}
+	if account.Status == entity.StatusClosed {
+		return types.NewErr(types.ConflictErr, fmt.Sprintf("account '%d' is closed and can't be reopened", account.ID), nil)
+	}
+	if account.Status == status {
+		return types.NewErr(types.ConflictErr, fmt.Sprintf("account '%d' is already %s", account.ID, status), nil)
+	}
+	if status == entity.StatusClosed && account.Balance != 0 {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("account '%d' must have a zero balance to be closed", account.ID), nil)
+	}
+	return nil
+}
+
+// SecretChange validates the replacement of the given entity.Account secret by its owner
+func (v *Account) SecretChange(current string, secret string, account entity.Account) error {
+	if len(current) == 0 {
+		return requiredFieldErr("current_secret")
+	}
+	return v.SecretStrength.verifyStrength(secret, account.CPF, account.Name)
+}
+
+// ResetRequest validates the request of a secret reset token
+func (v *Account) ResetRequest(cpf string) error {
+	return verifyCPF(cpf)
+}
+
+// SecretReset validates the replacement of the given entity.Account secret through a reset token
+func (v *Account) SecretReset(secret string, account entity.Account) error {
+	return v.SecretStrength.verifyStrength(secret, account.CPF, account.Name)
+}
+
 func verifyName(name string) error {
 	fieldName := "name"
 	nameLength := len(name)
@@ -106,6 +176,21 @@ func verifyCPF(cpf string) error {
 	return nil
 }
 
+func verifyAmount(fieldName string, amount types.Decimal, code types.CurrencyCode) (types.Currency, error) {
+	exp, err := code.Exponent()
+	if err != nil {
+		return 0, err
+	}
+	if amount.Scale() > exp {
+		return 0, maxPrecisionErr(fieldName, exp)
+	}
+	c, err := code.Currency(amount)
+	if err != nil {
+		return 0, invalidFormatErr(fieldName)
+	}
+	return c, nil
+}
+
 func verifySecret(secret string) error {
 	fieldName := "secret"
 	nLen := len(secret)
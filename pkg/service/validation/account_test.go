@@ -2,7 +2,10 @@ package validation_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
@@ -91,6 +94,36 @@ func TestAccountCreation(t *testing.T) {
 			},
 			accountCreation: testutil.NewAccountCreation("Paul", "44206294011", "pw", -0.01),
 		},
+		{
+			name: "validate account creation with sub-cent balance",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'balance' must have at most 2 decimal places")
+			},
+			accountCreation: dto.AccountCreation{Name: "Paul", CPF: "44206294011", Secret: "pw", Currency: types.BRL, Balance: "10.005"},
+		},
+		{
+			name: "validate account creation with fractional balance for currency without minor unit",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'balance' must have at most 0 decimal places")
+			},
+			accountCreation: dto.AccountCreation{Name: "Paul", CPF: "44206294011", Secret: "pw", Currency: types.JPY, Balance: "10.5"},
+		},
+		{
+			name: "validate account creation with unsupported currency",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'currency' has an unsupported currency code 'XYZ'")
+			},
+			accountCreation: dto.AccountCreation{Name: "Paul", CPF: "44206294011", Secret: "pw", Currency: "XYZ", Balance: "10"},
+		},
 		{
 			name: "validate account creation with existing cpf",
 			repo: func() repository.Account {
@@ -156,3 +189,269 @@ func TestAccountLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountSecretStrength(t *testing.T) {
+	account := testutil.NewEntityAccount(1, "Maria da Silva", "41112075020", "", 0)
+	strict := validation.SecretStrength{MinLength: 8, MinClasses: 3, RejectCommon: true}
+	tt := []struct {
+		name      string
+		strength  validation.SecretStrength
+		secret    string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate secret successfully",
+			strength:  strict,
+			secret:    "Tr0ub4dor&3",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "validate short secret without policy",
+			secret:    "pw",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:     "validate secret with no value",
+			strength: strict,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' is required")
+			},
+		},
+		{
+			name:     "validate long secret",
+			strength: strict,
+			secret:   strings.Repeat("sS1", entity.AccountSecretSize),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, fmt.Sprintf("field 'secret' must have at most %d characters", entity.AccountSecretSize))
+			},
+		},
+		{
+			name:     "validate short secret",
+			strength: strict,
+			secret:   "Ab1!",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' must have at least 8 characters")
+			},
+		},
+		{
+			name:     "validate secret lacking character classes",
+			strength: strict,
+			secret:   "abcdefgh12",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' must combine at least 3 of lowercase letters, uppercase letters, digits and symbols")
+			},
+		},
+		{
+			name:     "validate common secret",
+			strength: strict,
+			secret:   "P@ssw0rd",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' is too common")
+			},
+		},
+		{
+			name:      "validate common secret allowed by the policy",
+			strength:  validation.SecretStrength{MinLength: 6},
+			secret:    "123456",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "validate secret containing the cpf",
+			secret: "x411.120.750-20",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' must not contain the cpf")
+			},
+		},
+		{
+			name:   "validate secret containing the account name",
+			secret: "Silva#2021",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'secret' must not contain the account name")
+			},
+		},
+		{
+			name:      "validate secret containing a short name part",
+			secret:    "Da#2021",
+			assertErr: testutil.AssertNoErr,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Account{SecretStrength: tc.strength}
+			err := v.SecretReset(tc.secret, account)
+			tc.assertErr(t, err)
+		})
+	}
+}
+
+func TestAccountPatch(t *testing.T) {
+	name := func(n string) *string { return &n }
+	tt := []struct {
+		name      string
+		patch     dto.AccountPatch
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate account name patch successfully",
+			patch:     dto.AccountPatch{Name: name("Maria Sousa")},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "validate empty account patch",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:  "validate account name patch with empty name",
+			patch: dto.AccountPatch{Name: name("")},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'name' is required")
+			},
+		},
+		{
+			name:  "validate account name patch with trailing white space",
+			patch: dto.AccountPatch{Name: name("Maria ")},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'name' can't have trailing whitespace")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Account{}
+			tc.assertErr(t, v.Patch(tc.patch))
+		})
+	}
+}
+
+func TestAccountStatusChange(t *testing.T) {
+	active := testutil.NewEntityAccount(1, "Maria", "41112075020", "", 10)
+	empty := testutil.NewEntityAccount(2, "Maria", "41112075020", "", 0)
+	closed := testutil.NewEntityAccount(3, "Maria", "41112075020", "", 0)
+	closed.Status = entity.StatusClosed
+	tt := []struct {
+		name      string
+		account   entity.Account
+		status    entity.Status
+		reason    string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate account block successfully",
+			account:   active,
+			status:    entity.StatusBlocked,
+			reason:    "fraud investigation",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:      "validate account closing successfully",
+			account:   empty,
+			status:    entity.StatusClosed,
+			reason:    "requested by the holder",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:    "validate unsupported status",
+			account: active,
+			status:  "frozen",
+			reason:  "fraud investigation",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'status' must be one of 'active', 'blocked', 'closed'")
+			},
+		},
+		{
+			name:    "validate status change without reason",
+			account: active,
+			status:  entity.StatusBlocked,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'reason' is required")
+			},
+		},
+		{
+			name:    "validate status change with long reason",
+			account: active,
+			status:  entity.StatusBlocked,
+			reason:  strings.Repeat("r", entity.StatusReasonSize+1),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, fmt.Sprintf("field 'reason' must have at most %d characters", entity.StatusReasonSize))
+			},
+		},
+		{
+			name:    "validate status change to the current status",
+			account: active,
+			status:  entity.StatusActive,
+			reason:  "no reason",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "account '1' is already active")
+			},
+		},
+		{
+			name:    "validate closing of an account with balance",
+			account: active,
+			status:  entity.StatusClosed,
+			reason:  "requested by the holder",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "account '1' must have a zero balance to be closed")
+			},
+		},
+		{
+			name:    "validate reopening of a closed account",
+			account: closed,
+			status:  entity.StatusActive,
+			reason:  "requested by the holder",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ConflictErr, err, "account '3' is closed and can't be reopened")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Account{}
+			tc.assertErr(t, v.StatusChange(tc.account, tc.status, tc.reason))
+		})
+	}
+}
+
+func TestAccountFilter(t *testing.T) {
+	tt := []struct {
+		name      string
+		filter    dto.AccountFilter
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate account filter successfully",
+			filter:    dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 10, Sort: "-created_at"}},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "validate account filter with zero limit",
+			filter: dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 0}},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'limit' must be between 1 and 200")
+			},
+		},
+		{
+			name:   "validate account filter with unknown sort",
+			filter: dto.AccountFilter{PageRequest: dto.PageRequest{Limit: 10, Sort: "cpf"}},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'sort' must be one of 'created_at', 'name'")
+			},
+		},
+		{
+			name: "validate account filter with inverted date range",
+			filter: dto.AccountFilter{
+				PageRequest: dto.PageRequest{Limit: 10},
+				CreatedFrom: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'created_from' must be before 'created_to'")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Account{}
+			tc.assertErr(t, v.Filter(tc.filter))
+		})
+	}
+}
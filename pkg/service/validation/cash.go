@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+// Cash keeps the validation for operations related to entity.CashOperation
+type Cash struct {
+	AccountRepository *repository.Account
+}
+
+// Creation validates a deposit or a withdrawal of the given account, whose amount is expressed in the account currency.
+// Blocked and closed accounts take no cash operation, and withdrawals can't exceed the account balance
+func (v *Cash) Creation(ctx context.Context, t entity.CashType, account int64, cashCreation dto.CashOperationCreation) error {
+	if err := verifyReference(cashCreation.Reference); err != nil {
+		return err
+	}
+	acc, err := (*v.AccountRepository).Get(ctx, account)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return notFoundErr("account", account)
+	}
+	if err != nil {
+		return err
+	}
+	if err = verifyOperating(string(t), acc); err != nil {
+		return err
+	}
+	amount, err := verifyAmount("amount", cashCreation.Amount, acc.Currency)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return greaterThanErr("amount", 0)
+	}
+	if t == entity.Withdrawal && acc.Balance-amount < 0 {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the account must have a balance greater than or equal to %s", acc.Currency.Decimal(amount)), nil)
+	}
+	return nil
+}
+
+func verifyReference(reference string) error {
+	fieldName := "reference"
+	refLength := len(reference)
+	switch {
+	case refLength == 0:
+		return requiredFieldErr(fieldName)
+	case refLength > entity.CashReferenceSize:
+		return maxSizeErr(fieldName, entity.CashReferenceSize)
+	case len(strings.TrimSpace(reference)) != refLength:
+		return trailingWhiteSpaceErr(fieldName)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package validation_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestCashCreation(t *testing.T) {
+	tt := []struct {
+		name         string
+		cashType     entity.CashType
+		cashCreation dto.CashOperationCreation
+		account      func(int64) (entity.Account, error)
+		assertErr    func(*testing.T, error)
+	}{
+		{
+			name:         "validate deposit successfully",
+			cashType:     entity.Deposit,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50", Reference: "atm-1"},
+			account: func(id int64) (entity.Account, error) {
+				return testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0), nil
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:         "validate withdrawal of the whole balance",
+			cashType:     entity.Withdrawal,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50", Reference: "atm-1"},
+			account: func(id int64) (entity.Account, error) {
+				return testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 10.5), nil
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:         "validate cash operation without reference",
+			cashType:     entity.Deposit,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'reference' is required")
+			},
+		},
+		{
+			name:         "validate cash operation with long reference",
+			cashType:     entity.Deposit,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50", Reference: strings.Repeat("r", entity.CashReferenceSize+1)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, fmt.Sprintf("field 'reference' must have at most %d characters", entity.CashReferenceSize))
+			},
+		},
+		{
+			name:         "validate cash operation of a nonexistent account",
+			cashType:     entity.Deposit,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50", Reference: "atm-1"},
+			account: func(id int64) (entity.Account, error) {
+				return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account by id", nil)
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "record with 'account' equals '1' was not found")
+			},
+		},
+		{
+			name:         "validate withdrawal from a closed account",
+			cashType:     entity.Withdrawal,
+			cashCreation: dto.CashOperationCreation{Amount: "10.50", Reference: "atm-1"},
+			account: func(id int64) (entity.Account, error) {
+				acc := testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0)
+				acc.Status = entity.StatusClosed
+				return acc, nil
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the withdrawal account is closed")
+			},
+		},
+		{
+			name:         "validate cash operation with zero amount",
+			cashType:     entity.Deposit,
+			cashCreation: dto.CashOperationCreation{Amount: "0", Reference: "atm-1"},
+			account: func(id int64) (entity.Account, error) {
+				return testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0), nil
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be greater than 0")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(c context.Context, id int64) (entity.Account, error) {
+					return tc.account(id)
+				},
+			}
+			v := validation.Cash{AccountRepository: &repo}
+			tc.assertErr(t, v.Creation(context.Background(), tc.cashType, 1, tc.cashCreation))
+		})
+	}
+}
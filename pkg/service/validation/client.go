@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+)
+
+// Client keeps the validation for operations related to entity.Client
+type Client struct{}
+
+// Creation validates the registration of a new entity.Client, which must be granted at least one of the entity.ClientScopes
+func (v *Client) Creation(clientCreation dto.ClientCreation) error {
+	if err := verifyName(clientCreation.Name); err != nil {
+		return err
+	}
+	if len(clientCreation.Scopes) == 0 {
+		return requiredFieldErr("scopes")
+	}
+	for _, scope := range clientCreation.Scopes {
+		if !isClientScope(scope) {
+			return oneOfErr("scopes", entity.ClientScopes)
+		}
+	}
+	return nil
+}
+
+func isClientScope(scope string) bool {
+	for _, s := range entity.ClientScopes {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
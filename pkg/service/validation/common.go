@@ -0,0 +1,33 @@
+package validation
+
+// commonSecrets bundles widely used passwords, which are refused regardless of their length and character classes
+var commonSecrets = map[string]bool{}
+
+func init() {
+	for _, s := range []string{
+		"123456", "123456789", "12345678", "password", "qwerty123", "qwerty", "1q2w3e", "12345", "111111",
+		"1234567890", "1234567", "123123", "000000", "abc123", "password1", "iloveyou", "1234", "qwertyuiop",
+		"654321", "123321", "666666", "987654321", "121212", "555555", "7777777", "11111111", "dragon", "monkey",
+		"letmein", "football", "baseball", "welcome", "admin", "admin123", "login", "princess", "sunshine",
+		"master", "shadow", "superman", "batman", "trustno1", "passw0rd", "password123", "qazwsx", "1qaz2wsx",
+		"zaq12wsx", "qwe123", "asdfgh", "asdfghjkl", "zxcvbnm", "zxcvbn", "112233", "123qwe", "1q2w3e4r",
+		"1q2w3e4r5t", "123abc", "michael", "jessica", "charlie", "jordan", "hunter", "ashley", "bailey", "harley",
+		"ranger", "thomas", "robert", "daniel", "jennifer", "andrew", "joshua", "matthew", "hello", "hello123",
+		"freedom", "whatever", "mustang", "access", "flower", "loveme", "lovely", "123654", "159753", "147258369",
+		"123456a", "123456q", "12345a", "a123456", "aa123456", "aa12345678", "abcd1234", "abcdef", "abc12345",
+		"1234qwer", "qwer1234", "q1w2e3r4", "q1w2e3r4t5", "987654", "7654321", "88888888", "99999999", "00000000",
+		"0987654321", "11223344", "131313", "123456789a", "1111111", "222222", "333333", "444444", "senha",
+		"senha123", "mudar123", "brasil", "brasil123", "flamengo", "corinthians", "palmeiras", "saopaulo", "vasco",
+		"gremio", "internacional", "cruzeiro", "botafogo", "santos", "fluminense", "amor", "amorzinho", "meuamor",
+		"jesus", "jesus123", "deus", "deusefiel", "familia", "felicidade", "gabriel", "lucas", "mateus", "pedro",
+		"rafael", "bruna", "juliana", "mariana", "fernanda", "amanda", "beatriz", "carolina", "camila", "102030",
+		"10203040", "123mudar", "changeme", "default", "guest", "test", "test123", "root", "toor", "pass",
+		"1password", "secret", "secret123", "starwars", "pokemon", "computer", "internet", "samsung", "iphone",
+		"google", "facebook", "linkedin", "killer", "soccer", "hockey", "tigger", "summer", "winter", "autumn",
+		"spring", "maggie", "buster", "cookie", "chocolate", "banana", "orange", "pepper", "ginger", "purple",
+		"yellow", "silver", "golden", "diamond", "money", "blessed", "naruto", "asdf1234", "qwerty1", "qwerty12",
+		"qwerty1234", "1qazxsw2", "zxcvbnm123", "mypassword", "password01", "p@ssw0rd", "p@ssword", "pa55word",
+	} {
+		commonSecrets[s] = true
+	}
+}
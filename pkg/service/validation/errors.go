@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 )
@@ -18,6 +19,10 @@ func greaterOrEqualErr(n string, v interface{}) error {
 	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must be greater than or equal to %v", n, v), nil)
 }
 
+func lessOrEqualErr(n string, v interface{}) error {
+	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must be less than or equal to %v", n, v), nil)
+}
+
 func uniqErr(n string, v interface{}) error {
 	return types.NewErr(types.ConflictErr, fmt.Sprintf("field '%s' with value '%v' is already in use", n, v), nil)
 }
@@ -26,10 +31,26 @@ func notFoundErr(n string, v interface{}) error {
 	return types.NewErr(types.EmptyResultErr, fmt.Sprintf("record with '%s' equals '%v' was not found", n, v), nil)
 }
 
+func rangeErr(n string, min interface{}, max interface{}) error {
+	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must be between %v and %v", n, min, max), nil)
+}
+
+func oneOfErr(n string, v []string) error {
+	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must be one of '%s'", n, strings.Join(v, "', '")), nil)
+}
+
 func maxSizeErr(n string, s int) error {
 	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must have at most %d characters", n, s), nil)
 }
 
+func maxPrecisionErr(n string, d int) error {
+	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' must have at most %d decimal places", n, d), nil)
+}
+
+func unsupportedCurrencyErr(n string, v interface{}) error {
+	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' has an unsupported currency code '%v'", n, v), nil)
+}
+
 func trailingWhiteSpaceErr(n string) error {
 	return types.NewErr(types.ValidationErr, fmt.Sprintf("field '%s' can't have trailing whitespace", n), nil)
 }
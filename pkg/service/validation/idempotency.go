@@ -0,0 +1,23 @@
+package validation
+
+// maxIdempotencyKeySize is the maximum length of an idempotency key
+const maxIdempotencyKeySize int = 128
+
+// Idempotency keeps the validation for operations related to entity.IdempotencyKey
+type Idempotency struct{}
+
+// Key validates the idempotency key sent by the client, which must be made of up to 128 printable ascii characters
+func (v *Idempotency) Key(key string) error {
+	if key == "" {
+		return requiredFieldErr("Idempotency-Key")
+	}
+	if len(key) > maxIdempotencyKeySize {
+		return maxSizeErr("Idempotency-Key", maxIdempotencyKeySize)
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return invalidFormatErr("Idempotency-Key")
+		}
+	}
+	return nil
+}
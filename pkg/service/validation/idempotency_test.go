@@ -0,0 +1,51 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	tt := []struct {
+		name      string
+		key       string
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate idempotency key successfully",
+			key:       "3f1c9a52-7d1e-4bb6-9f0e-5a1c2d3e4f50",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "validate empty idempotency key",
+			key:  "",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'Idempotency-Key' is required")
+			},
+		},
+		{
+			name: "validate too long idempotency key",
+			key:  strings.Repeat("k", 129),
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'Idempotency-Key' must have at most 128 characters")
+			},
+		},
+		{
+			name: "validate idempotency key with non ascii characters",
+			key:  "chave-ação",
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'Idempotency-Key' has an invalid format")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Idempotency{}
+			tc.assertErr(t, v.Key(tc.key))
+		})
+	}
+}
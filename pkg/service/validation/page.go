@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+)
+
+// MaxPageLimit is the maximum number of items a listing page can hold
+const MaxPageLimit int = 200
+
+// verifyPage checks the page limit and that the sort is one of the given columns, optionally prefixed by '-'
+func verifyPage(p dto.PageRequest, sorts ...string) error {
+	if p.Limit < 1 || p.Limit > MaxPageLimit {
+		return rangeErr("limit", 1, MaxPageLimit)
+	}
+	if p.Sort == "" {
+		return nil
+	}
+	for _, s := range sorts {
+		if p.Sort == s || p.Sort == "-"+s {
+			return nil
+		}
+	}
+	return oneOfErr("sort", sorts)
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// SecretStrength configures the strength required from new account secrets.
+// Its zero value only bounds the secret size and keeps it from containing the CPF or the name of the account
+type SecretStrength struct {
+	MinLength    int  // MinLength is the least number of characters
+	MinClasses   int  // MinClasses is the least number of classes among lowercase letters, uppercase letters, digits and symbols
+	RejectCommon bool // RejectCommon refuses the secrets found in the bundled list of common passwords
+}
+
+// verifyStrength validates the secret of the account identified by the given cpf and name against the strength policy
+func (s SecretStrength) verifyStrength(secret string, cpf string, name string) error {
+	if err := verifySecret(secret); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(secret) < s.MinLength {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("field 'secret' must have at least %d characters", s.MinLength), nil)
+	}
+	if charClasses(secret) < s.MinClasses {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("field 'secret' must combine at least %d of lowercase letters, uppercase letters, digits and symbols", s.MinClasses), nil)
+	}
+	lower := strings.ToLower(secret)
+	if s.RejectCommon && commonSecrets[lower] {
+		return types.NewErr(types.ValidationErr, "field 'secret' is too common", nil)
+	}
+	// The cpf is also looked for among the digits alone, as it's often written with punctuation
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, secret)
+	if cpf != "" && strings.Contains(digits, cpf) {
+		return types.NewErr(types.ValidationErr, "field 'secret' must not contain the cpf", nil)
+	}
+	// Short name parts, such as prepositions, are too likely to be contained by chance
+	for _, part := range strings.Fields(strings.ToLower(name)) {
+		if utf8.RuneCountInString(part) >= 3 && strings.Contains(lower, part) {
+			return types.NewErr(types.ValidationErr, "field 'secret' must not contain the account name", nil)
+		}
+	}
+	return nil
+}
+
+// charClasses counts the classes of characters the secret is made of
+func charClasses(secret string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range secret {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
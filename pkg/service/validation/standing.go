@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+)
+
+// StandingOrder keeps the validation for operations related to entity.StandingOrder
+type StandingOrder struct {
+	AccountRepository *repository.Account
+}
+
+// Creation validates the creation of a new entity.StandingOrder, whose first execution is given by first.
+// The accounts are verified as on a scheduled transfer, the origin balance being verified on each execution
+func (v *StandingOrder) Creation(ctx context.Context, origin int64, standingOrderCreation dto.StandingOrderCreation, first time.Time, now time.Time) error {
+	switch standingOrderCreation.Frequency {
+	case "":
+		return requiredFieldErr("frequency")
+	case entity.FrequencyMonthly:
+		if standingOrderCreation.Day < 1 || standingOrderCreation.Day > 31 {
+			return rangeErr("day_of_month", 1, 31)
+		}
+	case entity.FrequencyWeekly:
+		if standingOrderCreation.Day != 0 {
+			return types.NewErr(types.ValidationErr, "field 'day_of_month' is only allowed on monthly orders", nil)
+		}
+	default:
+		return oneOfErr("frequency", []string{string(entity.FrequencyWeekly), string(entity.FrequencyMonthly)})
+	}
+	if standingOrderCreation.StartAt.IsZero() {
+		return requiredFieldErr("start_at")
+	}
+	if !standingOrderCreation.StartAt.After(now) {
+		return types.NewErr(types.ValidationErr, "field 'start_at' must be in the future", nil)
+	}
+	if err := verifyEnd(standingOrderCreation.EndAt, standingOrderCreation.Count, 0, first); err != nil {
+		return err
+	}
+	transfer := Transfer{AccountRepository: v.AccountRepository}
+	return transfer.verifyCreation(ctx, origin, dto.TransferCreation{
+		Destination: standingOrderCreation.Destination,
+		Amount:      standingOrderCreation.Amount,
+		Convert:     standingOrderCreation.Convert,
+	}, false)
+}
+
+// Update validates the new values of the active entity.StandingOrder stored at e, which must leave it at least its next execution
+func (v *StandingOrder) Update(e entity.StandingOrder, standingOrderUpdate dto.StandingOrderUpdate) error {
+	amount, err := verifyAmount("amount", standingOrderUpdate.Amount, e.Currency)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return greaterThanErr("amount", 0)
+	}
+	return verifyEnd(standingOrderUpdate.EndAt, standingOrderUpdate.Count, e.Executions, e.NextAt)
+}
+
+// verifyEnd validates the optional end of an order that already made the given executions, the next one being at next
+func verifyEnd(endAt *time.Time, count int, executions int, next time.Time) error {
+	if endAt != nil && count != 0 {
+		return types.NewErr(types.ValidationErr, "fields 'end_at' and 'count' can't be set together", nil)
+	}
+	if endAt != nil && endAt.Before(next) {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("field 'end_at' can't be before the next execution at %s", next.Format(time.RFC3339)), nil)
+	}
+	if count < 0 || (count != 0 && count <= executions) {
+		return greaterThanErr("count", executions)
+	}
+	return nil
+}
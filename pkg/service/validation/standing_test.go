@@ -0,0 +1,140 @@
+package validation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStandingOrderCreation(t *testing.T) {
+	now := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	start := now.Add(24 * time.Hour)
+	tt := []struct {
+		name      string
+		d         dto.StandingOrderCreation
+		account   func(int64) (entity.Account, error)
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "validate monthly standing order successfully",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyMonthly, Day: 5, StartAt: start, Count: 12},
+			account: func(id int64) (entity.Account, error) {
+				return testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0), nil
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "validate standing order without frequency",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", StartAt: start},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'frequency' is required")
+			},
+		},
+		{
+			name: "validate standing order with unsupported frequency",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: "daily", StartAt: start},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'frequency' must be one of 'weekly', 'monthly'")
+			},
+		},
+		{
+			name: "validate weekly standing order with day of month",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, Day: 5, StartAt: start},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'day_of_month' is only allowed on monthly orders")
+			},
+		},
+		{
+			name: "validate standing order without start date",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'start_at' is required")
+			},
+		},
+		{
+			name: "validate standing order with negative count",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: start, Count: -1},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'count' must be greater than 0")
+			},
+		},
+		{
+			name: "validate standing order to a blocked account",
+			d:    dto.StandingOrderCreation{Destination: 2, Amount: "10.50", Frequency: entity.FrequencyWeekly, StartAt: start},
+			account: func(id int64) (entity.Account, error) {
+				acc := testutil.NewEntityAccount(id, "Lia", "71453945024", "pw", 0)
+				if id == 2 {
+					acc.Status = entity.StatusBlocked
+				}
+				return acc, nil
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the destination account is blocked")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var accRepo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(ctx context.Context, id int64) (entity.Account, error) {
+					return tc.account(id)
+				},
+			}
+			v := validation.StandingOrder{AccountRepository: &accRepo}
+			tc.assertErr(t, v.Creation(context.Background(), 1, tc.d, tc.d.StartAt, now))
+		})
+	}
+}
+
+func TestStandingOrderUpdate(t *testing.T) {
+	next := time.Date(2030, 3, 5, 9, 0, 0, 0, time.UTC)
+	before := next.Add(-time.Hour)
+	tt := []struct {
+		name      string
+		d         dto.StandingOrderUpdate
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate standing order update successfully",
+			d:         dto.StandingOrderUpdate{Amount: "12.00", EndAt: &next},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "validate standing order update with invalid amount",
+			d:    dto.StandingOrderUpdate{Amount: "12.001"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must have at most 2 decimal places")
+			},
+		},
+		{
+			name: "validate standing order update ending before the next execution",
+			d:    dto.StandingOrderUpdate{Amount: "12.00", EndAt: &before},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'end_at' can't be before the next execution at 2030-03-05T09:00:00Z")
+			},
+		},
+		{
+			name: "validate standing order update with count already reached",
+			d:    dto.StandingOrderUpdate{Amount: "12.00", Count: 2},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'count' must be greater than 2")
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			e := entity.StandingOrder{ID: 3, Currency: types.BRL, Frequency: entity.FrequencyMonthly, Day: 5, Executions: 2, NextAt: next, Status: entity.StandingOrderActive}
+			v := validation.StandingOrder{}
+			tc.assertErr(t, v.Update(e, tc.d))
+		})
+	}
+}
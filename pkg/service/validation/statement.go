@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+)
+
+// MaxStatementPeriod is the longest period a single statement can cover, as statements aren't paged
+const MaxStatementPeriod = 366 * 24 * time.Hour
+
+// Statement keeps the validation for the account statement generation
+type Statement struct{}
+
+// Period validates the time range covered by a statement
+func (v *Statement) Period(p dto.StatementPeriod) error {
+	if !p.From.Before(p.To) {
+		return types.NewErr(types.ValidationErr, "field 'from' must be before 'to'", nil)
+	}
+	if p.To.Sub(p.From) > MaxStatementPeriod {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the statement period can't be longer than %d days", MaxStatementPeriod/(24*time.Hour)), nil)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package validation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
+	"github.com/rafael-sousa/stn-accounts/pkg/testutil"
+)
+
+func TestStatementPeriod(t *testing.T) {
+	tt := []struct {
+		name      string
+		period    dto.StatementPeriod
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name: "validate statement period successfully",
+			period: dto.StatementPeriod{
+				From: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name: "validate statement period with inverted range",
+			period: dto.StatementPeriod{
+				From: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'from' must be before 'to'")
+			},
+		},
+		{
+			name: "validate statement period longer than allowed",
+			period: dto.StatementPeriod{
+				From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the statement period can't be longer than 366 days")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Statement{}
+			tc.assertErr(t, v.Period(tc.period))
+		})
+	}
+}
@@ -3,8 +3,10 @@ package validation
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 )
@@ -14,32 +16,152 @@ type Transfer struct {
 	AccountRepository *repository.Account
 }
 
-// Creation validates the creation of a new entity.Transfer
+// Creation validates the creation of a new entity.Transfer.
+// The amount is expressed in the origin account currency, which must match the destination account currency
+// unless the conversion is requested
 func (v *Transfer) Creation(ctx context.Context, origin int64, transferCreation dto.TransferCreation) error {
-	if transferCreation.Amount <= 0 {
-		return greaterThanErr("amount", 0)
+	return v.verifyCreation(ctx, origin, transferCreation, true)
+}
+
+// Scheduling validates the scheduling of an entity.Transfer for a future date.
+// The origin balance is only verified once the transfer is executed
+func (v *Transfer) Scheduling(ctx context.Context, origin int64, transferCreation dto.TransferCreation, now time.Time) error {
+	if transferCreation.ExecuteAt.IsZero() {
+		return requiredFieldErr("execute_at")
+	}
+	if !transferCreation.ExecuteAt.After(now) {
+		return types.NewErr(types.ValidationErr, "field 'execute_at' must be in the future", nil)
 	}
+	return v.verifyCreation(ctx, origin, transferCreation, false)
+}
+
+// verifyCreation validates the values of a transfer, verifying the origin balance covers its amount when funded is set
+func (v *Transfer) verifyCreation(ctx context.Context, origin int64, transferCreation dto.TransferCreation, funded bool) error {
 	if transferCreation.Destination <= 0 {
 		return requiredFieldErr("destination_id")
 	}
 	if transferCreation.Destination == origin {
 		return sameFieldErr("origin id", "destination id")
 	}
-	originBalance, err := (*v.AccountRepository).GetBalance(ctx, origin)
+	originAccount, err := v.getAccount(ctx, "origin", origin)
+	if err != nil {
+		return err
+	}
+	if err = verifyOperating("origin", originAccount); err != nil {
+		return err
+	}
+	amount, err := verifyAmount("amount", transferCreation.Amount, originAccount.Currency)
 	if err != nil {
-		if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
-			return notFoundErr("origin", origin)
+		return err
+	}
+	if amount <= 0 {
+		return greaterThanErr("amount", 0)
+	}
+	if funded && originAccount.Balance-amount < 0 {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the origin must have a balance greater than or equal to %s", originAccount.Currency.Decimal(amount)), nil)
+	}
+	destinationAccount, err := v.getAccount(ctx, "destination", transferCreation.Destination)
+	if err != nil {
+		return err
+	}
+	if err = verifyOperating("destination", destinationAccount); err != nil {
+		return err
+	}
+	if originAccount.Currency != destinationAccount.Currency && !transferCreation.Convert {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the origin currency '%s' doesn't match the destination currency '%s' and no conversion was requested", originAccount.Currency, destinationAccount.Currency), nil)
+	}
+	return nil
+}
+
+// Reversal validates the reversal of the given entity.Transfer, which gives back to its origin either
+// the requested amount or whatever wasn't reversed yet, expressed in the destination currency
+func (v *Transfer) Reversal(ctx context.Context, original entity.Transfer, transferReversal dto.TransferReversal) error {
+	if original.ReversalOf != nil {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("transfer '%d' is a reversal and can't be reversed", original.ID), nil)
+	}
+	remaining := original.DestinationAmount - original.ReversedAmount
+	if remaining <= 0 {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("transfer '%d' was already fully reversed", original.ID), nil)
+	}
+	amount := remaining
+	if transferReversal.Amount != "" {
+		var err error
+		if amount, err = verifyAmount("amount", transferReversal.Amount, original.DestinationCurrency); err != nil {
+			return err
 		}
+		if amount <= 0 {
+			return greaterThanErr("amount", 0)
+		}
+		if amount > remaining {
+			return lessOrEqualErr("amount", original.DestinationCurrency.Decimal(remaining))
+		}
+	}
+	destinationAccount, err := v.getAccount(ctx, "destination", original.Destination)
+	if err != nil {
 		return err
 	}
-	if originBalance-types.NewCurrency(transferCreation.Amount) < 0 {
-		return types.NewErr(types.ValidationErr, fmt.Sprintf("the origin must have a balance greater than or equal to %.2f", transferCreation.Amount), nil)
+	if err = verifyOperating("destination", destinationAccount); err != nil {
+		return err
+	}
+	if destinationAccount.Balance-amount < 0 {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the destination must have a balance greater than or equal to %s", destinationAccount.Currency.Decimal(amount)), nil)
 	}
-	exists, err := (*v.AccountRepository).Exists(ctx, transferCreation.Destination)
+	originAccount, err := v.getAccount(ctx, "origin", original.Origin)
 	if err != nil {
 		return err
-	} else if !exists {
-		return notFoundErr("destination", transferCreation.Destination)
+	}
+	return verifyOperating("origin", originAccount)
+}
+
+// Filter validates the values narrowing and paging the transfer history of an account held in the given currency
+func (v *Transfer) Filter(transferFilter dto.TransferFilter, code types.CurrencyCode) error {
+	if err := verifyPage(transferFilter.PageRequest, "created_at"); err != nil {
+		return err
+	}
+	switch repository.TransferDirection(transferFilter.Direction) {
+	case "", repository.TransferIn, repository.TransferOut, repository.TransferAll:
+	default:
+		return oneOfErr("direction", []string{string(repository.TransferIn), string(repository.TransferOut), string(repository.TransferAll)})
+	}
+	if transferFilter.Counterparty < 0 {
+		return greaterThanErr("counterparty", 0)
+	}
+	if !transferFilter.CreatedFrom.IsZero() && !transferFilter.CreatedTo.IsZero() && !transferFilter.CreatedFrom.Before(transferFilter.CreatedTo) {
+		return types.NewErr(types.ValidationErr, "field 'created_from' must be before 'created_to'", nil)
+	}
+	var min, max types.Currency
+	var err error
+	if transferFilter.MinAmount != "" {
+		if min, err = verifyAmount("min_amount", transferFilter.MinAmount, code); err != nil {
+			return err
+		}
+		if min < 0 {
+			return greaterOrEqualErr("min_amount", 0)
+		}
+	}
+	if transferFilter.MaxAmount != "" {
+		if max, err = verifyAmount("max_amount", transferFilter.MaxAmount, code); err != nil {
+			return err
+		}
+		if max < min {
+			return greaterOrEqualErr("max_amount", code.Decimal(min))
+		}
 	}
 	return nil
 }
+
+// verifyOperating tells that blocked and closed accounts neither send nor receive transfers
+func verifyOperating(n string, acc entity.Account) error {
+	if acc.Status == entity.StatusBlocked || acc.Status == entity.StatusClosed {
+		return types.NewErr(types.ValidationErr, fmt.Sprintf("the %s account is %s", n, acc.Status), nil)
+	}
+	return nil
+}
+
+func (v *Transfer) getAccount(ctx context.Context, n string, id int64) (entity.Account, error) {
+	acc, err := (*v.AccountRepository).Get(ctx, id)
+	if customErr, ok := err.(*types.Err); ok && customErr.Code == types.EmptyResultErr {
+		return acc, notFoundErr(n, id)
+	}
+	return acc, err
+}
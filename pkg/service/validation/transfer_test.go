@@ -3,8 +3,10 @@ package validation_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
+	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
 	"github.com/rafael-sousa/stn-accounts/pkg/service/validation"
@@ -23,13 +25,11 @@ func TestTransferCreation(t *testing.T) {
 			name: "validate transfer creation successfully",
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						testutil.AssertEq(t, "origin id", int64(1), i)
-						return types.NewCurrency(1000), nil
-					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", int64(2), i)
-						return true, nil
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						if i != 1 && i != 2 {
+							t.Errorf("unexpected account id '%d'", i)
+						}
+						return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000), nil
 					},
 				}
 			},
@@ -37,7 +37,49 @@ func TestTransferCreation(t *testing.T) {
 			origin:    1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      500,
+				Amount:      "500",
+			},
+		},
+		{
+			name: "validate transfer creation from a blocked account",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000)
+						acc.Status = entity.StatusBlocked
+						return acc, nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the origin account is blocked")
+			},
+			origin: 1,
+			transferCreation: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "500",
+			},
+		},
+		{
+			name: "validate transfer creation to a closed account",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000)
+						if i == 2 {
+							acc.Status = entity.StatusClosed
+						}
+						return acc, nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the destination account is closed")
+			},
+			origin: 1,
+			transferCreation: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "500",
 			},
 		},
 		{
@@ -51,13 +93,17 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 1,
-				Amount:      500,
+				Amount:      "500",
 			},
 		},
 		{
 			name: "validate transfer creation with no amount",
 			repo: func() repository.Account {
-				return &testutil.AccountRepoMock{}
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000), nil
+					},
+				}
 			},
 			assertErr: func(t *testing.T, err error) {
 				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be greater than 0")
@@ -65,7 +111,25 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      0,
+				Amount:      "0",
+			},
+		},
+		{
+			name: "validate transfer creation with sub-cent amount",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000), nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must have at most 2 decimal places")
+			},
+			origin: 1,
+			transferCreation: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "0.291",
 			},
 		},
 		{
@@ -79,15 +143,15 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 0,
-				Amount:      0.01,
+				Amount:      "0.01",
 			},
 		},
 		{
 			name: "validate transfer creation with no funds",
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						return 0, nil
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 0), nil
 					},
 				}
 			},
@@ -97,15 +161,15 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 3,
-				Amount:      50,
+				Amount:      "50",
 			},
 		},
 		{
 			name: "validate transfer creation from non existent origin",
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						return 0, types.NewErr(types.EmptyResultErr, "no row return", nil)
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "no row return", nil)
 					},
 				}
 			},
@@ -115,19 +179,18 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 2,
-				Amount:      10,
+				Amount:      "10",
 			},
 		},
 		{
 			name: "validate transfer creation to non existent destination",
 			repo: func() repository.Account {
 				return &testutil.AccountRepoMock{
-					ExpectGetBalance: func(c context.Context, i int64) (types.Currency, error) {
-						return types.NewCurrency(500), nil
-					},
-					ExpectExists: func(c context.Context, i int64) (bool, error) {
-						testutil.AssertEq(t, "destination id", int64(3), i)
-						return false, nil
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						if i == 3 {
+							return entity.Account{}, types.NewErr(types.EmptyResultErr, "no row return", nil)
+						}
+						return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 500), nil
 					},
 				}
 			},
@@ -137,7 +200,50 @@ func TestTransferCreation(t *testing.T) {
 			origin: 1,
 			transferCreation: &dto.TransferCreation{
 				Destination: 3,
-				Amount:      10,
+				Amount:      "10",
+			},
+		},
+		{
+			name: "validate transfer creation between different currencies",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 500)
+						if i == 2 {
+							acc.Currency = types.USD
+						}
+						return acc, nil
+					},
+				}
+			},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the origin currency 'BRL' doesn't match the destination currency 'USD' and no conversion was requested")
+			},
+			origin: 1,
+			transferCreation: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "10",
+			},
+		},
+		{
+			name: "validate transfer creation between different currencies with conversion",
+			repo: func() repository.Account {
+				return &testutil.AccountRepoMock{
+					ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+						acc := testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 500)
+						if i == 2 {
+							acc.Currency = types.JPY
+						}
+						return acc, nil
+					},
+				}
+			},
+			assertErr: testutil.AssertNoErr,
+			origin:    1,
+			transferCreation: &dto.TransferCreation{
+				Destination: 2,
+				Amount:      "10",
+				Convert:     true,
 			},
 		},
 	}
@@ -152,3 +258,169 @@ func TestTransferCreation(t *testing.T) {
 		})
 	}
 }
+
+func TestTransferScheduling(t *testing.T) {
+	now := time.Now()
+	tt := []struct {
+		name             string
+		transferCreation dto.TransferCreation
+		assertErr        func(*testing.T, error)
+	}{
+		{
+			name:             "validate transfer scheduling beyond the origin balance successfully",
+			transferCreation: dto.TransferCreation{Destination: 2, Amount: "5000", ExecuteAt: now.Add(time.Hour)},
+			assertErr:        testutil.AssertNoErr,
+		},
+		{
+			name:             "validate transfer scheduling without execution date",
+			transferCreation: dto.TransferCreation{Destination: 2, Amount: "10"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'execute_at' is required")
+			},
+		},
+		{
+			name:             "validate transfer scheduling for now",
+			transferCreation: dto.TransferCreation{Destination: 2, Amount: "10", ExecuteAt: now},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'execute_at' must be in the future")
+			},
+		},
+		{
+			name:             "validate transfer scheduling with zero amount",
+			transferCreation: dto.TransferCreation{Destination: 2, Amount: "0", ExecuteAt: now.Add(time.Hour)},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be greater than 0")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+					return testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 10), nil
+				},
+			}
+			v := validation.Transfer{
+				AccountRepository: &repo,
+			}
+			tc.assertErr(t, v.Scheduling(context.Background(), 1, tc.transferCreation, now))
+		})
+	}
+}
+
+func TestTransferReversal(t *testing.T) {
+	original := testutil.NewEntityTransfer(3, 1, 2, 10)
+	tt := []struct {
+		name             string
+		status           map[int64]entity.Status
+		missing          int64
+		transferReversal dto.TransferReversal
+		assertErr        func(*testing.T, error)
+	}{
+		{
+			name:      "validate whole transfer reversal successfully",
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:             "validate partial transfer reversal successfully",
+			transferReversal: dto.TransferReversal{Amount: "0.01"},
+			assertErr:        testutil.AssertNoErr,
+		},
+		{
+			name:             "validate transfer reversal with zero amount",
+			transferReversal: dto.TransferReversal{Amount: "0"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must be greater than 0")
+			},
+		},
+		{
+			name:             "validate transfer reversal with too many decimal places",
+			transferReversal: dto.TransferReversal{Amount: "1.001"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'amount' must have at most 2 decimal places")
+			},
+		},
+		{
+			name:   "validate transfer reversal from a blocked destination",
+			status: map[int64]entity.Status{2: entity.StatusBlocked},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the destination account is blocked")
+			},
+		},
+		{
+			name:   "validate transfer reversal into a closed origin",
+			status: map[int64]entity.Status{1: entity.StatusClosed},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "the origin account is closed")
+			},
+		},
+		{
+			name:    "validate transfer reversal of a nonexistent destination",
+			missing: 2,
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.EmptyResultErr, err, "record with 'destination' equals '2' was not found")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var repo repository.Account = &testutil.AccountRepoMock{
+				ExpectGet: func(c context.Context, i int64) (entity.Account, error) {
+					if i == tc.missing {
+						return entity.Account{}, types.NewErr(types.EmptyResultErr, "no result getting account by id", nil)
+					}
+					acc := testutil.NewEntityAccount(i, "Lia", "71453945024", "pw", 1000)
+					if status, ok := tc.status[i]; ok {
+						acc.Status = status
+					}
+					return acc, nil
+				},
+			}
+			v := validation.Transfer{
+				AccountRepository: &repo,
+			}
+			tc.assertErr(t, v.Reversal(context.Background(), original, tc.transferReversal))
+		})
+	}
+}
+
+func TestTransferFilter(t *testing.T) {
+	tt := []struct {
+		name      string
+		filter    dto.TransferFilter
+		assertErr func(*testing.T, error)
+	}{
+		{
+			name:      "validate transfer filter successfully",
+			filter:    dto.TransferFilter{PageRequest: dto.PageRequest{Limit: 10, Sort: "-created_at"}, Direction: "out", MinAmount: "1", MaxAmount: "1.5"},
+			assertErr: testutil.AssertNoErr,
+		},
+		{
+			name:   "validate transfer filter with sort by amount",
+			filter: dto.TransferFilter{PageRequest: dto.PageRequest{Limit: 10, Sort: "amount"}},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'sort' must be one of 'created_at'")
+			},
+		},
+		{
+			name:   "validate transfer filter with negative min amount",
+			filter: dto.TransferFilter{PageRequest: dto.PageRequest{Limit: 10}, MinAmount: "-1"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'min_amount' must be greater than or equal to 0")
+			},
+		},
+		{
+			name:   "validate transfer filter with inverted amount range",
+			filter: dto.TransferFilter{PageRequest: dto.PageRequest{Limit: 10}, MinAmount: "10", MaxAmount: "9.99"},
+			assertErr: func(t *testing.T, err error) {
+				testutil.AssertCustomErr(t, types.ValidationErr, err, "field 'max_amount' must be greater than or equal to 10.00")
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validation.Transfer{}
+			tc.assertErr(t, v.Filter(tc.filter, types.BRL))
+		})
+	}
+}
@@ -43,6 +43,10 @@ func AssertNotDefault(t *testing.T, n string, v interface{}) {
 		if len(v.(string)) == 0 {
 			t.Errorf("expected %s not empty", n)
 		}
+	case types.Decimal:
+		if len(v.(types.Decimal)) == 0 {
+			t.Errorf("expected %s not empty", n)
+		}
 	case int, int32, int64, float32, float64:
 		if v == 0 {
 			t.Errorf("expected %s not zero", n)
@@ -15,7 +15,11 @@ func NewEntityAccount(id int64, n, cpf, s string, b float64) entity.Account {
 		Name:      n,
 		CPF:       cpf,
 		Secret:    s,
+		Currency:  types.BRL,
 		Balance:   types.NewCurrency(b),
+		Role:      entity.RoleCustomer,
+		Status:    entity.StatusActive,
+		Version:   1,
 		CreatedAt: time.Now().UTC().Truncate(time.Second),
 	}
 }
@@ -23,21 +27,26 @@ func NewEntityAccount(id int64, n, cpf, s string, b float64) entity.Account {
 // NewAccountCreation returns a new *dto.AccountCreation value from the given args
 func NewAccountCreation(n, cpf, s string, b float64) dto.AccountCreation {
 	return dto.AccountCreation{
-		Name:    n,
-		CPF:     cpf,
-		Secret:  s,
-		Balance: b,
+		Name:     n,
+		CPF:      cpf,
+		Secret:   s,
+		Currency: types.BRL,
+		Balance:  types.BRL.Decimal(types.NewCurrency(b)),
 	}
 }
 
 // NewEntityTransfer returns a new entity.Transfer value from the given args
 func NewEntityTransfer(id, origin, destination int64, b float64) entity.Transfer {
 	return entity.Transfer{
-		ID:          id,
-		Origin:      origin,
-		Destination: destination,
-		Amount:      types.NewCurrency(b),
-		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		ID:                  id,
+		Origin:              origin,
+		Destination:         destination,
+		Amount:              types.NewCurrency(b),
+		Currency:            types.BRL,
+		DestinationAmount:   types.NewCurrency(b),
+		DestinationCurrency: types.BRL,
+		Rate:                "1",
+		CreatedAt:           time.Now().UTC().Truncate(time.Second),
 	}
 }
 
@@ -47,7 +56,11 @@ func NewAccountView(id int64, name, cpf string, balance float64, createdAt time.
 		ID:        id,
 		Name:      name,
 		CPF:       cpf,
-		Balance:   balance,
+		Balance:   types.BRL.Decimal(types.NewCurrency(balance)),
+		Currency:  types.BRL,
+		Role:      entity.RoleCustomer,
+		Status:    entity.StatusActive,
+		Version:   1,
 		CreatedAt: createdAt,
 	}
 }
@@ -56,16 +69,20 @@ func NewAccountView(id int64, name, cpf string, balance float64, createdAt time.
 func NewTransferCreation(dest int64, amt float64) dto.TransferCreation {
 	return dto.TransferCreation{
 		Destination: dest,
-		Amount:      amt,
+		Amount:      types.BRL.Decimal(types.NewCurrency(amt)),
 	}
 }
 
 // NewTransferView returns a new *dto.TransferView value from the given args
 func NewTransferView(id, destination int64, amount float64) *dto.TransferView {
 	return &dto.TransferView{
-		ID:          id,
-		Destination: destination,
-		Amount:      amount,
-		CreatedAt:   time.Now(),
+		ID:                  id,
+		Destination:         destination,
+		Amount:              types.BRL.Decimal(types.NewCurrency(amount)),
+		Currency:            types.BRL,
+		DestinationAmount:   types.BRL.Decimal(types.NewCurrency(amount)),
+		DestinationCurrency: types.BRL,
+		Rate:                "1",
+		CreatedAt:           time.Now(),
 	}
 }
@@ -2,11 +2,13 @@ package testutil
 
 import (
 	"context"
+	"time"
 
 	"github.com/rafael-sousa/stn-accounts/pkg/model/dto"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/entity"
 	"github.com/rafael-sousa/stn-accounts/pkg/model/types"
 	"github.com/rafael-sousa/stn-accounts/pkg/repository"
+	"github.com/rafael-sousa/stn-accounts/pkg/service/notify"
 )
 
 // TransactionerMock mocks the repository.Transactioner interface
@@ -24,17 +26,23 @@ func (manager *TransactionerMock) GetConn(ctx context.Context) repository.Connec
 
 // AccountRepoMock mock structure for repository.Account interface
 type AccountRepoMock struct {
-	ExpectFetch         func(context.Context) ([]entity.Account, error)
+	ExpectFetch         func(context.Context, repository.AccountQuery) ([]entity.Account, error)
 	ExpectCreate        func(context.Context, entity.Account) (int64, error)
 	ExpectGetBalance    func(context.Context, int64) (types.Currency, error)
 	ExpectFindBy        func(context.Context, string) (entity.Account, error)
+	ExpectGet           func(context.Context, int64) (entity.Account, error)
 	ExpectUpdateBalance func(context.Context, int64, types.Currency) error
+	ExpectUpdateRole    func(context.Context, int64, entity.Role) error
+	ExpectUpdateSecret  func(context.Context, int64, string) error
+	ExpectUpdateStatus  func(context.Context, int64, entity.Status) error
+	ExpectUpdateProfile func(context.Context, entity.Account) error
 	ExpectExists        func(context.Context, int64) (bool, error)
+	ExpectLock          func(context.Context, ...int64) error
 }
 
 // Fetch mocks the functionality of repository.Account#Fetch
-func (r *AccountRepoMock) Fetch(ctx context.Context) ([]entity.Account, error) {
-	return r.ExpectFetch(ctx)
+func (r *AccountRepoMock) Fetch(ctx context.Context, q repository.AccountQuery) ([]entity.Account, error) {
+	return r.ExpectFetch(ctx, q)
 }
 
 // Create mocks the functionality of repository.Account#Create
@@ -52,26 +60,63 @@ func (r *AccountRepoMock) FindBy(ctx context.Context, cpf string) (entity.Accoun
 	return r.ExpectFindBy(ctx, cpf)
 }
 
+// Get mocks the functionality of repository.Account#Get
+func (r *AccountRepoMock) Get(ctx context.Context, id int64) (entity.Account, error) {
+	return r.ExpectGet(ctx, id)
+}
+
 // UpdateBalance mocks the functionality of repository.Account#UpdateBalance
 func (r *AccountRepoMock) UpdateBalance(ctx context.Context, id int64, b types.Currency) error {
 	return r.ExpectUpdateBalance(ctx, id, b)
 }
 
+// UpdateRole mocks the functionality of repository.Account#UpdateRole
+func (r *AccountRepoMock) UpdateRole(ctx context.Context, id int64, role entity.Role) error {
+	return r.ExpectUpdateRole(ctx, id, role)
+}
+
+// UpdateSecret mocks the functionality of repository.Account#UpdateSecret
+func (r *AccountRepoMock) UpdateSecret(ctx context.Context, id int64, secret string) error {
+	return r.ExpectUpdateSecret(ctx, id, secret)
+}
+
+// UpdateStatus mocks the functionality of repository.Account#UpdateStatus
+func (r *AccountRepoMock) UpdateStatus(ctx context.Context, id int64, status entity.Status) error {
+	return r.ExpectUpdateStatus(ctx, id, status)
+}
+
+// UpdateProfile mocks the functionality of repository.Account#UpdateProfile
+func (r *AccountRepoMock) UpdateProfile(ctx context.Context, e entity.Account) error {
+	return r.ExpectUpdateProfile(ctx, e)
+}
+
 // Exists mocks the functionality of repository.Account#Exists
 func (r *AccountRepoMock) Exists(ctx context.Context, id int64) (bool, error) {
 	return r.ExpectExists(ctx, id)
 }
 
+// Lock mocks the functionality of repository.Account#Lock
+func (r *AccountRepoMock) Lock(ctx context.Context, ids ...int64) error {
+	return r.ExpectLock(ctx, ids...)
+}
+
 // TransferRepoMock mocks the repository.Transfer interface
 type TransferRepoMock struct {
-	ExpectFetch  func(ctx context.Context, id int64) ([]entity.Transfer, error)
-	ExpectCreate func(ctx context.Context, e entity.Transfer) (int64, error)
+	ExpectFetch                func(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error)
+	ExpectGet                  func(ctx context.Context, id int64) (entity.Transfer, error)
+	ExpectCreate               func(ctx context.Context, e entity.Transfer) (int64, error)
+	ExpectUpdateReversedAmount func(ctx context.Context, id int64, reversed types.Currency) error
 }
 
 // Fetch mocks the functionality of repository.Transfer#Fetch
-func (r *TransferRepoMock) Fetch(ctx context.Context, id int64) ([]entity.Transfer, error) {
-	return r.ExpectFetch(ctx, id)
+func (r *TransferRepoMock) Fetch(ctx context.Context, q repository.TransferQuery) ([]entity.Transfer, error) {
+	return r.ExpectFetch(ctx, q)
+
+}
 
+// Get mocks the functionality of repository.Transfer#Get
+func (r *TransferRepoMock) Get(ctx context.Context, id int64) (entity.Transfer, error) {
+	return r.ExpectGet(ctx, id)
 }
 
 // Create mocks the functionality of repository.Transfer#Create
@@ -79,22 +124,431 @@ func (r *TransferRepoMock) Create(ctx context.Context, e entity.Transfer) (int64
 	return r.ExpectCreate(ctx, e)
 }
 
+// UpdateReversedAmount mocks the functionality of repository.Transfer#UpdateReversedAmount
+func (r *TransferRepoMock) UpdateReversedAmount(ctx context.Context, id int64, reversed types.Currency) error {
+	return r.ExpectUpdateReversedAmount(ctx, id, reversed)
+}
+
+// ScheduledTransferRepoMock mocks the repository.ScheduledTransfer interface
+type ScheduledTransferRepoMock struct {
+	ExpectCreate   func(ctx context.Context, e entity.ScheduledTransfer) (int64, error)
+	ExpectGet      func(ctx context.Context, id int64) (entity.ScheduledTransfer, error)
+	ExpectFetch    func(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]entity.ScheduledTransfer, error)
+	ExpectClaimDue func(ctx context.Context, now time.Time) (entity.ScheduledTransfer, error)
+	ExpectFinish   func(ctx context.Context, e entity.ScheduledTransfer) error
+}
+
+// Create mocks the functionality of repository.ScheduledTransfer#Create
+func (r *ScheduledTransferRepoMock) Create(ctx context.Context, e entity.ScheduledTransfer) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.ScheduledTransfer#Get
+func (r *ScheduledTransferRepoMock) Get(ctx context.Context, id int64) (entity.ScheduledTransfer, error) {
+	return r.ExpectGet(ctx, id)
+}
+
+// Fetch mocks the functionality of repository.ScheduledTransfer#Fetch
+func (r *ScheduledTransferRepoMock) Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]entity.ScheduledTransfer, error) {
+	return r.ExpectFetch(ctx, origin, status)
+}
+
+// ClaimDue mocks the functionality of repository.ScheduledTransfer#ClaimDue
+func (r *ScheduledTransferRepoMock) ClaimDue(ctx context.Context, now time.Time) (entity.ScheduledTransfer, error) {
+	return r.ExpectClaimDue(ctx, now)
+}
+
+// Finish mocks the functionality of repository.ScheduledTransfer#Finish
+func (r *ScheduledTransferRepoMock) Finish(ctx context.Context, e entity.ScheduledTransfer) error {
+	return r.ExpectFinish(ctx, e)
+}
+
+// StandingOrderRepoMock mocks the repository.StandingOrder interface
+type StandingOrderRepoMock struct {
+	ExpectCreate   func(ctx context.Context, e entity.StandingOrder) (int64, error)
+	ExpectGet      func(ctx context.Context, id int64) (entity.StandingOrder, error)
+	ExpectLock     func(ctx context.Context, id int64) (entity.StandingOrder, error)
+	ExpectFetch    func(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]entity.StandingOrder, error)
+	ExpectClaimDue func(ctx context.Context, now time.Time) (entity.StandingOrder, error)
+	ExpectUpdate   func(ctx context.Context, e entity.StandingOrder) error
+}
+
+// Create mocks the functionality of repository.StandingOrder#Create
+func (r *StandingOrderRepoMock) Create(ctx context.Context, e entity.StandingOrder) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.StandingOrder#Get
+func (r *StandingOrderRepoMock) Get(ctx context.Context, id int64) (entity.StandingOrder, error) {
+	return r.ExpectGet(ctx, id)
+}
+
+// Lock mocks the functionality of repository.StandingOrder#Lock
+func (r *StandingOrderRepoMock) Lock(ctx context.Context, id int64) (entity.StandingOrder, error) {
+	return r.ExpectLock(ctx, id)
+}
+
+// Fetch mocks the functionality of repository.StandingOrder#Fetch
+func (r *StandingOrderRepoMock) Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]entity.StandingOrder, error) {
+	return r.ExpectFetch(ctx, origin, status)
+}
+
+// ClaimDue mocks the functionality of repository.StandingOrder#ClaimDue
+func (r *StandingOrderRepoMock) ClaimDue(ctx context.Context, now time.Time) (entity.StandingOrder, error) {
+	return r.ExpectClaimDue(ctx, now)
+}
+
+// Update mocks the functionality of repository.StandingOrder#Update
+func (r *StandingOrderRepoMock) Update(ctx context.Context, e entity.StandingOrder) error {
+	return r.ExpectUpdate(ctx, e)
+}
+
+// StandingOrderExecutionRepoMock mocks the repository.StandingOrderExecution interface
+type StandingOrderExecutionRepoMock struct {
+	ExpectCreate func(ctx context.Context, e entity.StandingOrderExecution) (int64, error)
+	ExpectFetch  func(ctx context.Context, standingOrder int64) ([]entity.StandingOrderExecution, error)
+}
+
+// Create mocks the functionality of repository.StandingOrderExecution#Create
+func (r *StandingOrderExecutionRepoMock) Create(ctx context.Context, e entity.StandingOrderExecution) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Fetch mocks the functionality of repository.StandingOrderExecution#Fetch
+func (r *StandingOrderExecutionRepoMock) Fetch(ctx context.Context, standingOrder int64) ([]entity.StandingOrderExecution, error) {
+	return r.ExpectFetch(ctx, standingOrder)
+}
+
+// LedgerRepoMock mocks the repository.Ledger interface
+type LedgerRepoMock struct {
+	ExpectCreate           func(ctx context.Context, e entity.LedgerEntry) (int64, error)
+	ExpectGetBalance       func(ctx context.Context, account int64) (types.Currency, error)
+	ExpectGetBalanceBefore func(ctx context.Context, account int64, t time.Time) (types.Currency, error)
+	ExpectFetch            func(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error)
+}
+
+// Create mocks the functionality of repository.Ledger#Create
+func (r *LedgerRepoMock) Create(ctx context.Context, e entity.LedgerEntry) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// GetBalance mocks the functionality of repository.Ledger#GetBalance
+func (r *LedgerRepoMock) GetBalance(ctx context.Context, account int64) (types.Currency, error) {
+	return r.ExpectGetBalance(ctx, account)
+}
+
+// GetBalanceBefore mocks the functionality of repository.Ledger#GetBalanceBefore
+func (r *LedgerRepoMock) GetBalanceBefore(ctx context.Context, account int64, t time.Time) (types.Currency, error) {
+	return r.ExpectGetBalanceBefore(ctx, account, t)
+}
+
+// Fetch mocks the functionality of repository.Ledger#Fetch
+func (r *LedgerRepoMock) Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.LedgerEntry, error) {
+	return r.ExpectFetch(ctx, account, from, to)
+}
+
+// RateProviderMock mocks the fx.RateProvider interface
+type RateProviderMock struct {
+	ExpectRate func(ctx context.Context, from types.CurrencyCode, to types.CurrencyCode) (types.Decimal, error)
+}
+
+// Rate mocks the functionality of fx.RateProvider#Rate
+func (p *RateProviderMock) Rate(ctx context.Context, from types.CurrencyCode, to types.CurrencyCode) (types.Decimal, error) {
+	return p.ExpectRate(ctx, from, to)
+}
+
+// IdempotencyRepoMock mocks the repository.Idempotency interface
+type IdempotencyRepoMock struct {
+	ExpectCreate func(ctx context.Context, e entity.IdempotencyKey) error
+	ExpectGet    func(ctx context.Context, scope string, key string) (entity.IdempotencyKey, error)
+	ExpectUpdate func(ctx context.Context, e entity.IdempotencyKey) error
+	ExpectDelete func(ctx context.Context, scope string, key string) error
+}
+
+// Create mocks the functionality of repository.Idempotency#Create
+func (r *IdempotencyRepoMock) Create(ctx context.Context, e entity.IdempotencyKey) error {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.Idempotency#Get
+func (r *IdempotencyRepoMock) Get(ctx context.Context, scope string, key string) (entity.IdempotencyKey, error) {
+	return r.ExpectGet(ctx, scope, key)
+}
+
+// Update mocks the functionality of repository.Idempotency#Update
+func (r *IdempotencyRepoMock) Update(ctx context.Context, e entity.IdempotencyKey) error {
+	return r.ExpectUpdate(ctx, e)
+}
+
+// Delete mocks the functionality of repository.Idempotency#Delete
+func (r *IdempotencyRepoMock) Delete(ctx context.Context, scope string, key string) error {
+	return r.ExpectDelete(ctx, scope, key)
+}
+
+// RefreshTokenRepoMock mocks the repository.RefreshToken interface
+type RefreshTokenRepoMock struct {
+	ExpectCreate        func(ctx context.Context, e entity.RefreshToken) error
+	ExpectGet           func(ctx context.Context, hash string) (entity.RefreshToken, error)
+	ExpectMarkUsed      func(ctx context.Context, hash string, at time.Time) error
+	ExpectRevokeFamily  func(ctx context.Context, family string, at time.Time) error
+	ExpectRevokeAccount func(ctx context.Context, account int64, at time.Time) error
+}
+
+// Create mocks the functionality of repository.RefreshToken#Create
+func (r *RefreshTokenRepoMock) Create(ctx context.Context, e entity.RefreshToken) error {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.RefreshToken#Get
+func (r *RefreshTokenRepoMock) Get(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	return r.ExpectGet(ctx, hash)
+}
+
+// MarkUsed mocks the functionality of repository.RefreshToken#MarkUsed
+func (r *RefreshTokenRepoMock) MarkUsed(ctx context.Context, hash string, at time.Time) error {
+	return r.ExpectMarkUsed(ctx, hash, at)
+}
+
+// RevokeFamily mocks the functionality of repository.RefreshToken#RevokeFamily
+func (r *RefreshTokenRepoMock) RevokeFamily(ctx context.Context, family string, at time.Time) error {
+	return r.ExpectRevokeFamily(ctx, family, at)
+}
+
+// RevokeAccount mocks the functionality of repository.RefreshToken#RevokeAccount
+func (r *RefreshTokenRepoMock) RevokeAccount(ctx context.Context, account int64, at time.Time) error {
+	return r.ExpectRevokeAccount(ctx, account, at)
+}
+
+// SecretResetTokenRepoMock mocks the repository.SecretResetToken interface
+type SecretResetTokenRepoMock struct {
+	ExpectCreate     func(ctx context.Context, e entity.SecretResetToken) error
+	ExpectGet        func(ctx context.Context, hash string) (entity.SecretResetToken, error)
+	ExpectMarkUsed   func(ctx context.Context, hash string, at time.Time) error
+	ExpectInvalidate func(ctx context.Context, account int64, at time.Time) error
+}
+
+// Create mocks the functionality of repository.SecretResetToken#Create
+func (r *SecretResetTokenRepoMock) Create(ctx context.Context, e entity.SecretResetToken) error {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.SecretResetToken#Get
+func (r *SecretResetTokenRepoMock) Get(ctx context.Context, hash string) (entity.SecretResetToken, error) {
+	return r.ExpectGet(ctx, hash)
+}
+
+// MarkUsed mocks the functionality of repository.SecretResetToken#MarkUsed
+func (r *SecretResetTokenRepoMock) MarkUsed(ctx context.Context, hash string, at time.Time) error {
+	return r.ExpectMarkUsed(ctx, hash, at)
+}
+
+// Invalidate mocks the functionality of repository.SecretResetToken#Invalidate
+func (r *SecretResetTokenRepoMock) Invalidate(ctx context.Context, account int64, at time.Time) error {
+	return r.ExpectInvalidate(ctx, account, at)
+}
+
+// ClientRepoMock mocks the repository.Client interface
+type ClientRepoMock struct {
+	ExpectCreate  func(ctx context.Context, e entity.Client) error
+	ExpectGet     func(ctx context.Context, id string) (entity.Client, error)
+	ExpectDisable func(ctx context.Context, id string, at time.Time) error
+}
+
+// Create mocks the functionality of repository.Client#Create
+func (r *ClientRepoMock) Create(ctx context.Context, e entity.Client) error {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Get mocks the functionality of repository.Client#Get
+func (r *ClientRepoMock) Get(ctx context.Context, id string) (entity.Client, error) {
+	return r.ExpectGet(ctx, id)
+}
+
+// Disable mocks the functionality of repository.Client#Disable
+func (r *ClientRepoMock) Disable(ctx context.Context, id string, at time.Time) error {
+	return r.ExpectDisable(ctx, id, at)
+}
+
+// StatusChangeRepoMock mocks the repository.StatusChange interface
+type StatusChangeRepoMock struct {
+	ExpectCreate func(ctx context.Context, e entity.StatusChange) (int64, error)
+	ExpectFetch  func(ctx context.Context, account int64) ([]entity.StatusChange, error)
+}
+
+// Create mocks the functionality of repository.StatusChange#Create
+func (r *StatusChangeRepoMock) Create(ctx context.Context, e entity.StatusChange) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Fetch mocks the functionality of repository.StatusChange#Fetch
+func (r *StatusChangeRepoMock) Fetch(ctx context.Context, account int64) ([]entity.StatusChange, error) {
+	return r.ExpectFetch(ctx, account)
+}
+
+// CashOperationRepoMock mocks the repository.CashOperation interface
+type CashOperationRepoMock struct {
+	ExpectCreate func(ctx context.Context, e entity.CashOperation) (int64, error)
+	ExpectFetch  func(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.CashOperation, error)
+}
+
+// Create mocks the functionality of repository.CashOperation#Create
+func (r *CashOperationRepoMock) Create(ctx context.Context, e entity.CashOperation) (int64, error) {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Fetch mocks the functionality of repository.CashOperation#Fetch
+func (r *CashOperationRepoMock) Fetch(ctx context.Context, account int64, from time.Time, to time.Time) ([]entity.CashOperation, error) {
+	return r.ExpectFetch(ctx, account, from, to)
+}
+
+// NotifierMock mocks the notify.Notifier interface
+type NotifierMock struct {
+	ExpectNotify func(ctx context.Context, m notify.Message) error
+}
+
+// Notify mocks the functionality of notify.Notifier#Notify
+func (n *NotifierMock) Notify(ctx context.Context, m notify.Message) error {
+	return n.ExpectNotify(ctx, m)
+}
+
+// RevokedTokenRepoMock mocks the repository.RevokedToken interface
+type RevokedTokenRepoMock struct {
+	ExpectCreate           func(ctx context.Context, e entity.RevokedToken) error
+	ExpectExists           func(ctx context.Context, jti string) (bool, error)
+	ExpectDeleteExpired    func(ctx context.Context, at time.Time) error
+	ExpectRevokeAccount    func(ctx context.Context, account int64, at time.Time) error
+	ExpectAccountRevokedAt func(ctx context.Context, account int64) (*time.Time, error)
+}
+
+// Create mocks the functionality of repository.RevokedToken#Create
+func (r *RevokedTokenRepoMock) Create(ctx context.Context, e entity.RevokedToken) error {
+	return r.ExpectCreate(ctx, e)
+}
+
+// Exists mocks the functionality of repository.RevokedToken#Exists
+func (r *RevokedTokenRepoMock) Exists(ctx context.Context, jti string) (bool, error) {
+	return r.ExpectExists(ctx, jti)
+}
+
+// DeleteExpired mocks the functionality of repository.RevokedToken#DeleteExpired
+func (r *RevokedTokenRepoMock) DeleteExpired(ctx context.Context, at time.Time) error {
+	return r.ExpectDeleteExpired(ctx, at)
+}
+
+// RevokeAccount mocks the functionality of repository.RevokedToken#RevokeAccount
+func (r *RevokedTokenRepoMock) RevokeAccount(ctx context.Context, account int64, at time.Time) error {
+	return r.ExpectRevokeAccount(ctx, account, at)
+}
+
+// AccountRevokedAt mocks the functionality of repository.RevokedToken#AccountRevokedAt
+func (r *RevokedTokenRepoMock) AccountRevokedAt(ctx context.Context, account int64) (*time.Time, error) {
+	return r.ExpectAccountRevokedAt(ctx, account)
+}
+
+// LoginAttemptRepoMock mocks the repository.LoginAttempt interface
+type LoginAttemptRepoMock struct {
+	ExpectGet       func(ctx context.Context, key string) (entity.LoginAttempt, error)
+	ExpectIncrement func(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error)
+	ExpectLock      func(ctx context.Context, key string, until time.Time) error
+	ExpectDelete    func(ctx context.Context, key string) error
+}
+
+// Get mocks the functionality of repository.LoginAttempt#Get
+func (r *LoginAttemptRepoMock) Get(ctx context.Context, key string) (entity.LoginAttempt, error) {
+	return r.ExpectGet(ctx, key)
+}
+
+// Increment mocks the functionality of repository.LoginAttempt#Increment
+func (r *LoginAttemptRepoMock) Increment(ctx context.Context, key string, at time.Time, since time.Time) (entity.LoginAttempt, error) {
+	return r.ExpectIncrement(ctx, key, at, since)
+}
+
+// Lock mocks the functionality of repository.LoginAttempt#Lock
+func (r *LoginAttemptRepoMock) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.ExpectLock(ctx, key, until)
+}
+
+// Delete mocks the functionality of repository.LoginAttempt#Delete
+func (r *LoginAttemptRepoMock) Delete(ctx context.Context, key string) error {
+	return r.ExpectDelete(ctx, key)
+}
+
+// TOTPRepoMock mocks the repository.TOTP interface
+type TOTPRepoMock struct {
+	ExpectGet    func(ctx context.Context, account int64) (entity.TOTP, error)
+	ExpectSave   func(ctx context.Context, e entity.TOTP) error
+	ExpectEnable func(ctx context.Context, account int64, step int64, at time.Time) error
+	ExpectAccept func(ctx context.Context, account int64, step int64) error
+}
+
+// Get mocks the functionality of repository.TOTP#Get
+func (r *TOTPRepoMock) Get(ctx context.Context, account int64) (entity.TOTP, error) {
+	return r.ExpectGet(ctx, account)
+}
+
+// Save mocks the functionality of repository.TOTP#Save
+func (r *TOTPRepoMock) Save(ctx context.Context, e entity.TOTP) error {
+	return r.ExpectSave(ctx, e)
+}
+
+// Enable mocks the functionality of repository.TOTP#Enable
+func (r *TOTPRepoMock) Enable(ctx context.Context, account int64, step int64, at time.Time) error {
+	return r.ExpectEnable(ctx, account, step, at)
+}
+
+// Accept mocks the functionality of repository.TOTP#Accept
+func (r *TOTPRepoMock) Accept(ctx context.Context, account int64, step int64) error {
+	return r.ExpectAccept(ctx, account, step)
+}
+
+// RecoveryCodeRepoMock mocks the repository.RecoveryCode interface
+type RecoveryCodeRepoMock struct {
+	ExpectReplace func(ctx context.Context, account int64, hashes []string) error
+	ExpectUse     func(ctx context.Context, account int64, hash string, at time.Time) error
+}
+
+// Replace mocks the functionality of repository.RecoveryCode#Replace
+func (r *RecoveryCodeRepoMock) Replace(ctx context.Context, account int64, hashes []string) error {
+	return r.ExpectReplace(ctx, account, hashes)
+}
+
+// Use mocks the functionality of repository.RecoveryCode#Use
+func (r *RecoveryCodeRepoMock) Use(ctx context.Context, account int64, hash string, at time.Time) error {
+	return r.ExpectUse(ctx, account, hash, at)
+}
+
 // AccountServMock mocks the service.Account interface
 type AccountServMock struct {
-	ExpectFetch      func(context.Context) ([]dto.AccountView, error)
-	ExpectGetBalance func(context.Context, int64) (float64, error)
-	ExpectCreate     func(context.Context, dto.AccountCreation) (dto.AccountView, error)
-	ExpectLogin      func(context.Context, string, string) (dto.AccountView, error)
+	ExpectFetch              func(context.Context, dto.AccountFilter) (dto.AccountPage, error)
+	ExpectGet                func(context.Context, dto.Requester, int64) (dto.AccountView, error)
+	ExpectGetBalance         func(context.Context, dto.Requester, int64) (dto.BalanceView, error)
+	ExpectReconcile          func(context.Context, int64) (dto.ReconciliationView, error)
+	ExpectCreate             func(context.Context, dto.AccountCreation) (dto.AccountView, error)
+	ExpectLogin              func(context.Context, string, string) (dto.AccountView, error)
+	ExpectUpdateRole         func(context.Context, int64, entity.Role) (dto.AccountView, error)
+	ExpectUpdateStatus       func(context.Context, dto.Requester, int64, entity.Status, string) (dto.AccountView, error)
+	ExpectFetchStatusChanges func(context.Context, int64) ([]dto.StatusChangeView, error)
+	ExpectPatch              func(context.Context, dto.Requester, int64, int64, dto.AccountPatch) (dto.AccountView, error)
 }
 
 // Fetch mocks the functionality of service.Account#Fetch
-func (s *AccountServMock) Fetch(ctx context.Context) ([]dto.AccountView, error) {
-	return s.ExpectFetch(ctx)
+func (s *AccountServMock) Fetch(ctx context.Context, f dto.AccountFilter) (dto.AccountPage, error) {
+	return s.ExpectFetch(ctx, f)
+}
+
+// Get mocks the functionality of service.Account#Get
+func (s *AccountServMock) Get(ctx context.Context, requester dto.Requester, id int64) (dto.AccountView, error) {
+	return s.ExpectGet(ctx, requester, id)
 }
 
 // GetBalance mocks the functionality of service.Account#GetBalance
-func (s *AccountServMock) GetBalance(ctx context.Context, id int64) (float64, error) {
-	return s.ExpectGetBalance(ctx, id)
+func (s *AccountServMock) GetBalance(ctx context.Context, requester dto.Requester, id int64) (dto.BalanceView, error) {
+	return s.ExpectGetBalance(ctx, requester, id)
+}
+
+// Reconcile mocks the functionality of service.Account#Reconcile
+func (s *AccountServMock) Reconcile(ctx context.Context, id int64) (dto.ReconciliationView, error) {
+	return s.ExpectReconcile(ctx, id)
 }
 
 // Create mocks the functionality of service.Account#Create
@@ -107,18 +561,312 @@ func (s *AccountServMock) Login(ctx context.Context, cpf string, secret string)
 	return s.ExpectLogin(ctx, cpf, secret)
 }
 
+// UpdateRole mocks the functionality of service.Account#UpdateRole
+func (s *AccountServMock) UpdateRole(ctx context.Context, id int64, role entity.Role) (dto.AccountView, error) {
+	return s.ExpectUpdateRole(ctx, id, role)
+}
+
+// UpdateStatus mocks the functionality of service.Account#UpdateStatus
+func (s *AccountServMock) UpdateStatus(ctx context.Context, requester dto.Requester, id int64, status entity.Status, reason string) (dto.AccountView, error) {
+	return s.ExpectUpdateStatus(ctx, requester, id, status, reason)
+}
+
+// FetchStatusChanges mocks the functionality of service.Account#FetchStatusChanges
+func (s *AccountServMock) FetchStatusChanges(ctx context.Context, id int64) ([]dto.StatusChangeView, error) {
+	return s.ExpectFetchStatusChanges(ctx, id)
+}
+
+// Patch mocks the functionality of service.Account#Patch
+func (s *AccountServMock) Patch(ctx context.Context, requester dto.Requester, id int64, version int64, patch dto.AccountPatch) (dto.AccountView, error) {
+	return s.ExpectPatch(ctx, requester, id, version, patch)
+}
+
 // TransferServMock mocks the service.Transfer interface
 type TransferServMock struct {
-	ExpectFetch  func(context.Context, int64) ([]dto.TransferView, error)
-	ExpectCreate func(context.Context, int64, dto.TransferCreation) (dto.TransferView, error)
+	ExpectFetch   func(context.Context, int64, dto.TransferFilter) (dto.TransferPage, error)
+	ExpectGet     func(context.Context, dto.Requester, int64) (dto.TransferView, error)
+	ExpectCreate  func(context.Context, int64, dto.TransferCreation) (dto.TransferView, error)
+	ExpectReverse func(context.Context, dto.Requester, int64, dto.TransferReversal) (dto.TransferView, error)
 }
 
 // Fetch mocks the functionality of service.Transfer#Fetch
-func (s *TransferServMock) Fetch(ctx context.Context, id int64) ([]dto.TransferView, error) {
-	return s.ExpectFetch(ctx, id)
+func (s *TransferServMock) Fetch(ctx context.Context, id int64, f dto.TransferFilter) (dto.TransferPage, error) {
+	return s.ExpectFetch(ctx, id, f)
+}
+
+// Get mocks the functionality of service.Transfer#Get
+func (s *TransferServMock) Get(ctx context.Context, requester dto.Requester, id int64) (dto.TransferView, error) {
+	return s.ExpectGet(ctx, requester, id)
 }
 
 // Create mocks the functionality of service.Transfer#Create
 func (s *TransferServMock) Create(ctx context.Context, origin int64, d dto.TransferCreation) (dto.TransferView, error) {
 	return s.ExpectCreate(ctx, origin, d)
 }
+
+// Reverse mocks the functionality of service.Transfer#Reverse
+func (s *TransferServMock) Reverse(ctx context.Context, requester dto.Requester, id int64, r dto.TransferReversal) (dto.TransferView, error) {
+	return s.ExpectReverse(ctx, requester, id, r)
+}
+
+// ScheduledTransferServMock mocks the service.ScheduledTransfer interface
+type ScheduledTransferServMock struct {
+	ExpectSchedule   func(context.Context, int64, dto.TransferCreation) (dto.ScheduledTransferView, error)
+	ExpectFetch      func(context.Context, int64, entity.ScheduleStatus) ([]dto.ScheduledTransferView, error)
+	ExpectGet        func(context.Context, dto.Requester, int64) (dto.ScheduledTransferView, error)
+	ExpectCancel     func(context.Context, dto.Requester, int64) (dto.ScheduledTransferView, error)
+	ExpectExecuteDue func(context.Context) (int, error)
+}
+
+// Schedule mocks the functionality of service.ScheduledTransfer#Schedule
+func (s *ScheduledTransferServMock) Schedule(ctx context.Context, origin int64, d dto.TransferCreation) (dto.ScheduledTransferView, error) {
+	return s.ExpectSchedule(ctx, origin, d)
+}
+
+// Fetch mocks the functionality of service.ScheduledTransfer#Fetch
+func (s *ScheduledTransferServMock) Fetch(ctx context.Context, origin int64, status entity.ScheduleStatus) ([]dto.ScheduledTransferView, error) {
+	return s.ExpectFetch(ctx, origin, status)
+}
+
+// Get mocks the functionality of service.ScheduledTransfer#Get
+func (s *ScheduledTransferServMock) Get(ctx context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error) {
+	return s.ExpectGet(ctx, requester, id)
+}
+
+// Cancel mocks the functionality of service.ScheduledTransfer#Cancel
+func (s *ScheduledTransferServMock) Cancel(ctx context.Context, requester dto.Requester, id int64) (dto.ScheduledTransferView, error) {
+	return s.ExpectCancel(ctx, requester, id)
+}
+
+// ExecuteDue mocks the functionality of service.ScheduledTransfer#ExecuteDue
+func (s *ScheduledTransferServMock) ExecuteDue(ctx context.Context) (int, error) {
+	return s.ExpectExecuteDue(ctx)
+}
+
+// Run mocks the functionality of service.ScheduledTransfer#Run, which does nothing
+func (s *ScheduledTransferServMock) Run(ctx context.Context, interval time.Duration) {}
+
+// StandingOrderServMock mocks the service.StandingOrder interface
+type StandingOrderServMock struct {
+	ExpectCreate          func(context.Context, int64, dto.StandingOrderCreation) (dto.StandingOrderView, error)
+	ExpectFetch           func(context.Context, int64, entity.StandingOrderStatus) ([]dto.StandingOrderView, error)
+	ExpectGet             func(context.Context, dto.Requester, int64) (dto.StandingOrderView, error)
+	ExpectUpdate          func(context.Context, dto.Requester, int64, dto.StandingOrderUpdate) (dto.StandingOrderView, error)
+	ExpectCancel          func(context.Context, dto.Requester, int64) (dto.StandingOrderView, error)
+	ExpectFetchExecutions func(context.Context, dto.Requester, int64) ([]dto.StandingOrderExecutionView, error)
+	ExpectExecuteDue      func(context.Context) (int, error)
+}
+
+// Create mocks the functionality of service.StandingOrder#Create
+func (s *StandingOrderServMock) Create(ctx context.Context, origin int64, d dto.StandingOrderCreation) (dto.StandingOrderView, error) {
+	return s.ExpectCreate(ctx, origin, d)
+}
+
+// Fetch mocks the functionality of service.StandingOrder#Fetch
+func (s *StandingOrderServMock) Fetch(ctx context.Context, origin int64, status entity.StandingOrderStatus) ([]dto.StandingOrderView, error) {
+	return s.ExpectFetch(ctx, origin, status)
+}
+
+// Get mocks the functionality of service.StandingOrder#Get
+func (s *StandingOrderServMock) Get(ctx context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error) {
+	return s.ExpectGet(ctx, requester, id)
+}
+
+// Update mocks the functionality of service.StandingOrder#Update
+func (s *StandingOrderServMock) Update(ctx context.Context, requester dto.Requester, id int64, d dto.StandingOrderUpdate) (dto.StandingOrderView, error) {
+	return s.ExpectUpdate(ctx, requester, id, d)
+}
+
+// Cancel mocks the functionality of service.StandingOrder#Cancel
+func (s *StandingOrderServMock) Cancel(ctx context.Context, requester dto.Requester, id int64) (dto.StandingOrderView, error) {
+	return s.ExpectCancel(ctx, requester, id)
+}
+
+// FetchExecutions mocks the functionality of service.StandingOrder#FetchExecutions
+func (s *StandingOrderServMock) FetchExecutions(ctx context.Context, requester dto.Requester, id int64) ([]dto.StandingOrderExecutionView, error) {
+	return s.ExpectFetchExecutions(ctx, requester, id)
+}
+
+// ExecuteDue mocks the functionality of service.StandingOrder#ExecuteDue
+func (s *StandingOrderServMock) ExecuteDue(ctx context.Context) (int, error) {
+	return s.ExpectExecuteDue(ctx)
+}
+
+// Run mocks the functionality of service.StandingOrder#Run, which does nothing
+func (s *StandingOrderServMock) Run(ctx context.Context, interval time.Duration) {}
+
+// StatementServMock mocks the service.Statement interface
+type StatementServMock struct {
+	ExpectGet func(context.Context, dto.Requester, int64, dto.StatementPeriod) (dto.StatementView, error)
+}
+
+// Get mocks the functionality of service.Statement#Get
+func (s *StatementServMock) Get(ctx context.Context, requester dto.Requester, id int64, p dto.StatementPeriod) (dto.StatementView, error) {
+	return s.ExpectGet(ctx, requester, id, p)
+}
+
+// IdempotencyServMock mocks the service.Idempotency interface
+type IdempotencyServMock struct {
+	ExpectBegin    func(ctx context.Context, scope string, key string, requestHash string) (*entity.IdempotencyKey, error)
+	ExpectComplete func(ctx context.Context, e entity.IdempotencyKey) error
+	ExpectRelease  func(ctx context.Context, scope string, key string) error
+}
+
+// Begin mocks the functionality of service.Idempotency#Begin
+func (s *IdempotencyServMock) Begin(ctx context.Context, scope string, key string, requestHash string) (*entity.IdempotencyKey, error) {
+	return s.ExpectBegin(ctx, scope, key, requestHash)
+}
+
+// Complete mocks the functionality of service.Idempotency#Complete
+func (s *IdempotencyServMock) Complete(ctx context.Context, e entity.IdempotencyKey) error {
+	return s.ExpectComplete(ctx, e)
+}
+
+// Release mocks the functionality of service.Idempotency#Release
+func (s *IdempotencyServMock) Release(ctx context.Context, scope string, key string) error {
+	return s.ExpectRelease(ctx, scope, key)
+}
+
+// SessionServMock mocks the service.Session interface
+type SessionServMock struct {
+	ExpectStart   func(ctx context.Context, account int64) (string, error)
+	ExpectRefresh func(ctx context.Context, refreshToken string) (int64, string, error)
+	ExpectEnd     func(ctx context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error
+	ExpectRevoked func(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error)
+}
+
+// Start mocks the functionality of service.Session#Start
+func (s *SessionServMock) Start(ctx context.Context, account int64) (string, error) {
+	return s.ExpectStart(ctx, account)
+}
+
+// Refresh mocks the functionality of service.Session#Refresh
+func (s *SessionServMock) Refresh(ctx context.Context, refreshToken string) (int64, string, error) {
+	return s.ExpectRefresh(ctx, refreshToken)
+}
+
+// End mocks the functionality of service.Session#End
+func (s *SessionServMock) End(ctx context.Context, account int64, refreshToken string, jti string, expiresAt time.Time) error {
+	return s.ExpectEnd(ctx, account, refreshToken, jti, expiresAt)
+}
+
+// Revoked mocks the functionality of service.Session#Revoked
+func (s *SessionServMock) Revoked(ctx context.Context, jti string, account int64, issuedAt time.Time) (bool, error) {
+	return s.ExpectRevoked(ctx, jti, account, issuedAt)
+}
+
+// LoginGuardServMock mocks the service.LoginGuard interface
+type LoginGuardServMock struct {
+	ExpectCheck   func(ctx context.Context, cpf string, ip string) error
+	ExpectFail    func(ctx context.Context, cpf string, ip string) error
+	ExpectSucceed func(ctx context.Context, cpf string, ip string) error
+	ExpectUnlock  func(ctx context.Context, account int64) error
+}
+
+// Check mocks the functionality of service.LoginGuard#Check
+func (s *LoginGuardServMock) Check(ctx context.Context, cpf string, ip string) error {
+	return s.ExpectCheck(ctx, cpf, ip)
+}
+
+// Fail mocks the functionality of service.LoginGuard#Fail
+func (s *LoginGuardServMock) Fail(ctx context.Context, cpf string, ip string) error {
+	return s.ExpectFail(ctx, cpf, ip)
+}
+
+// Succeed mocks the functionality of service.LoginGuard#Succeed
+func (s *LoginGuardServMock) Succeed(ctx context.Context, cpf string, ip string) error {
+	return s.ExpectSucceed(ctx, cpf, ip)
+}
+
+// Unlock mocks the functionality of service.LoginGuard#Unlock
+func (s *LoginGuardServMock) Unlock(ctx context.Context, account int64) error {
+	return s.ExpectUnlock(ctx, account)
+}
+
+// TwoFactorServMock mocks the service.TwoFactor interface
+type TwoFactorServMock struct {
+	ExpectEnroll   func(ctx context.Context, requester dto.Requester, id int64) (dto.TOTPEnrollment, error)
+	ExpectActivate func(ctx context.Context, requester dto.Requester, id int64, code string) (dto.RecoveryCodes, error)
+	ExpectVerify   func(ctx context.Context, account int64, code string) error
+	ExpectStepUp   func(ctx context.Context, account int64, amount types.Decimal, code string) error
+}
+
+// Enroll mocks the functionality of service.TwoFactor#Enroll
+func (s *TwoFactorServMock) Enroll(ctx context.Context, requester dto.Requester, id int64) (dto.TOTPEnrollment, error) {
+	return s.ExpectEnroll(ctx, requester, id)
+}
+
+// Activate mocks the functionality of service.TwoFactor#Activate
+func (s *TwoFactorServMock) Activate(ctx context.Context, requester dto.Requester, id int64, code string) (dto.RecoveryCodes, error) {
+	return s.ExpectActivate(ctx, requester, id, code)
+}
+
+// Verify mocks the functionality of service.TwoFactor#Verify
+func (s *TwoFactorServMock) Verify(ctx context.Context, account int64, code string) error {
+	return s.ExpectVerify(ctx, account, code)
+}
+
+// StepUp mocks the functionality of service.TwoFactor#StepUp
+func (s *TwoFactorServMock) StepUp(ctx context.Context, account int64, amount types.Decimal, code string) error {
+	return s.ExpectStepUp(ctx, account, amount, code)
+}
+
+// SecretServMock mocks the service.Secret interface
+type SecretServMock struct {
+	ExpectChange       func(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error
+	ExpectRequestReset func(ctx context.Context, cpf string) error
+	ExpectReset        func(ctx context.Context, token string, secret string) error
+}
+
+// Change mocks the functionality of service.Secret#Change
+func (s *SecretServMock) Change(ctx context.Context, requester dto.Requester, id int64, current string, secret string) error {
+	return s.ExpectChange(ctx, requester, id, current, secret)
+}
+
+// RequestReset mocks the functionality of service.Secret#RequestReset
+func (s *SecretServMock) RequestReset(ctx context.Context, cpf string) error {
+	return s.ExpectRequestReset(ctx, cpf)
+}
+
+// Reset mocks the functionality of service.Secret#Reset
+func (s *SecretServMock) Reset(ctx context.Context, token string, secret string) error {
+	return s.ExpectReset(ctx, token, secret)
+}
+
+// ClientServMock mocks the service.Client interface
+type ClientServMock struct {
+	ExpectCreate       func(ctx context.Context, clientCreation dto.ClientCreation) (dto.ClientCredentials, error)
+	ExpectAuthenticate func(ctx context.Context, id string, secret string) (dto.ClientView, error)
+	ExpectDisable      func(ctx context.Context, id string) error
+}
+
+// Create mocks the functionality of service.Client#Create
+func (s *ClientServMock) Create(ctx context.Context, clientCreation dto.ClientCreation) (dto.ClientCredentials, error) {
+	return s.ExpectCreate(ctx, clientCreation)
+}
+
+// Authenticate mocks the functionality of service.Client#Authenticate
+func (s *ClientServMock) Authenticate(ctx context.Context, id string, secret string) (dto.ClientView, error) {
+	return s.ExpectAuthenticate(ctx, id, secret)
+}
+
+// Disable mocks the functionality of service.Client#Disable
+func (s *ClientServMock) Disable(ctx context.Context, id string) error {
+	return s.ExpectDisable(ctx, id)
+}
+
+// CashServMock mocks the service.Cash interface
+type CashServMock struct {
+	ExpectDeposit  func(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error)
+	ExpectWithdraw func(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error)
+}
+
+// Deposit mocks the functionality of service.Cash#Deposit
+func (s *CashServMock) Deposit(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error) {
+	return s.ExpectDeposit(ctx, requester, account, c)
+}
+
+// Withdraw mocks the functionality of service.Cash#Withdraw
+func (s *CashServMock) Withdraw(ctx context.Context, requester dto.Requester, account int64, c dto.CashOperationCreation) (dto.CashOperationView, error) {
+	return s.ExpectWithdraw(ctx, requester, account, c)
+}